@@ -2,17 +2,30 @@ package Context
 
 import (
 	"context"
-	"fmt"
 	"time"
 
+	"github.com/neerajchowdary889/GoRoutinesManager/Logging"
 )
 
 type AppContext struct {
 	GlobalContext *GlobalContext
 	App           string
+	Parent        string // app the context is derived from, empty to derive it from the global context
 }
 
+// GetAppContext returns the handle of the app-level context registered under app
 func GetAppContext(app string) ContextInterface {
+	return newAppContext(app)
+}
+
+// GetRefCountedAppContext is GetAppContext with the reference counting of Acquire and Release exposed,
+// for owners sharing the app-level context
+func GetRefCountedAppContext(app string) RefCountedContextInterface {
+	return newAppContext(app)
+}
+
+// newAppContext builds the handle of the app-level context registered under app
+func newAppContext(app string) *AppContext {
 	// Get global context and ensure it's initialized
 	// If app context is in the map, then return the existing one
 	gc := GetGlobalContext()
@@ -32,6 +45,15 @@ func GetAppContext(app string) ContextInterface {
 	}
 }
 
+// GetChildAppContext returns the handle of the context registered under app, derived from the context
+// registered under parent instead of the global one. Cancelling the parent cancels it too, while
+// cancelling it leaves the parent and its other children running.
+func GetChildAppContext(app, parent string) ContextInterface {
+	ac := newAppContext(app)
+	ac.Parent = parent
+	return ac
+}
+
 // SetAppName sets the name of the app for the app context.
 func (ac *AppContext) SetAppName(app string) {
 	ac.App = app
@@ -43,6 +65,11 @@ func (ac *AppContext) Init() context.Context {
 	ctxMu.Lock()
 	defer ctxMu.Unlock()
 
+	return ac.initLocked()
+}
+
+// initLocked is Init with ctxMu held
+func (ac *AppContext) initLocked() context.Context {
 	// Ensure global context is initialized
 	if globalContext == nil {
 		globalContext, globalCancel = context.WithCancel(context.Background())
@@ -57,16 +84,21 @@ func (ac *AppContext) Init() context.Context {
 
 	// Check if app context already exists and is valid
 	if ctx, exists := appContexts[ac.App]; exists && ctx.Err() == nil {
-		fmt.Printf("App context for '%s' already initialized\n", ac.App)
+		logMessage(Logging.Debug, "app context already initialized", "app", ac.App)
 		return ctx
 	}
 
-	// Create new app-level context
-	appCtx, appCancel := context.WithCancel(globalContext)
+	// Create new app-level context, under the parent's if it has one
+	parentCtx, kv := globalContext, []any{"app", ac.App}
+	if ac.Parent != "" {
+		parentCtx = (&AppContext{GlobalContext: ac.GlobalContext, App: ac.Parent}).initLocked()
+		kv = append(kv, "parent", ac.Parent)
+	}
+	appCtx, appCancel := context.WithCancel(parentCtx)
 	appContexts[ac.App] = appCtx
 	appCancels[ac.App] = appCancel
 
-	fmt.Printf("Initialized app-level context for: %s\n", ac.App)
+	logMessage(Logging.Debug, "initialized app-level context", kv...)
 	return appCtx
 }
 
@@ -84,12 +116,17 @@ func (ac *AppContext) Get() context.Context {
 }
 
 // ShutdownApp cancels the app-level context for the current app.
+// While references taken with Acquire are held the context is kept, the last Release cancels it.
 func (ac *AppContext) Shutdown() {
 	ctxMu.Lock()
 	defer ctxMu.Unlock()
 
+	if refs := appRefs[ac.App]; refs > 0 {
+		logMessage(Logging.Debug, "keeping app-level context alive for its references", "app", ac.App, "refs", refs)
+		return
+	}
 	if cancel, exists := appCancels[ac.App]; exists && cancel != nil {
-		fmt.Printf("Shutting down app-level context for: %s\n", ac.App)
+		logMessage(Logging.Debug, "shutting down app-level context", "app", ac.App)
 		cancel()
 		delete(appCancels, ac.App)
 		delete(appContexts, ac.App)
@@ -97,10 +134,117 @@ func (ac *AppContext) Shutdown() {
 	}
 }
 
-func (ac *AppContext) Done(ctx context.Context) {
-	// Close that particular background context
-	ctx.Done()
+// ForceShutdown cancels the app-level context like Shutdown, ignoring the references taken with Acquire.
+// It is meant for the owner of the context ending everything derived from it, the references are dropped
+// and releasing them later is a no-op.
+func (ac *AppContext) ForceShutdown() {
+	ctxMu.Lock()
+	defer ctxMu.Unlock()
+
+	delete(appRefs, ac.App)
+	if cancel, exists := appCancels[ac.App]; exists && cancel != nil {
+		logMessage(Logging.Debug, "force shutting down app-level context", "app", ac.App)
+		cancel()
+	}
+	delete(appCancels, ac.App)
+	delete(appContexts, ac.App)
+}
+
+// RenameAppContext moves the app context registered under oldApp to newApp.
+// The context itself is kept, so everything derived from it keeps running.
+// Returns false if oldApp isn't registered or newApp is already taken.
+func RenameAppContext(oldApp, newApp string) bool {
+	ctxMu.Lock()
+	defer ctxMu.Unlock()
+
+	ctx, exists := appContexts[oldApp]
+	if !exists {
+		return false
+	}
+	if _, taken := appContexts[newApp]; taken {
+		return false
+	}
+	appContexts[newApp] = ctx
+	appCancels[newApp] = appCancels[oldApp]
+	delete(appContexts, oldApp)
+	delete(appCancels, oldApp)
+	if refs, ok := appRefs[oldApp]; ok {
+		appRefs[newApp] = refs
+		delete(appRefs, oldApp)
+	}
+	return true
+}
+
+// CancelAppContext cancels the context registered under app and removes it from the registry,
+// provided it is still ctx. Returns false if app has no context or a different one by now,
+// or if references taken with Acquire keep it alive.
+func CancelAppContext(app string, ctx context.Context) bool {
+	ctxMu.Lock()
+	defer ctxMu.Unlock()
+
+	if appContexts[app] != ctx || appRefs[app] > 0 {
+		return false
+	}
+	if cancel := appCancels[app]; cancel != nil {
+		cancel()
+	}
+	delete(appCancels, app)
+	delete(appContexts, app)
+	return true
+}
 
+// Acquire takes a reference on the app-level context, creating it if needed, and returns it.
+// The context survives Shutdown, Done and CancelAppContext while references are held, so local
+// managers sharing it can't cancel it under each other; the Release of the last reference cancels it.
+// A global shutdown still cancels it regardless.
+func (ac *AppContext) Acquire() context.Context {
+	ctxMu.Lock()
+	defer ctxMu.Unlock()
+
+	ctx := ac.initLocked()
+	if appRefs == nil {
+		appRefs = make(map[string]int)
+	}
+	appRefs[ac.App]++
+	return ctx
+}
+
+// Release drops a reference taken with Acquire and cancels the app-level context once none is left.
+// Returns true if this released the last reference. Releasing without a reference is a no-op.
+func (ac *AppContext) Release() bool {
+	ctxMu.Lock()
+	defer ctxMu.Unlock()
+
+	refs, ok := appRefs[ac.App]
+	if !ok {
+		return false
+	}
+	if refs > 1 {
+		appRefs[ac.App] = refs - 1
+		return false
+	}
+	delete(appRefs, ac.App)
+	if cancel := appCancels[ac.App]; cancel != nil {
+		logMessage(Logging.Debug, "released the last reference of app-level context", "app", ac.App)
+		cancel()
+	}
+	delete(appCancels, ac.App)
+	delete(appContexts, ac.App)
+	return true
+}
+
+// Refs returns how many references taken with Acquire the app-level context has
+func (ac *AppContext) Refs() int {
+	ctxMu.RLock()
+	defer ctxMu.RUnlock()
+	return appRefs[ac.App]
+}
+
+// Done cancels ctx if it is still the context registered for the app and removes it from the registry,
+// so the next Get creates a fresh one. A context replaced in the meantime, or kept alive by references
+// taken with Acquire, is left alone.
+func (ac *AppContext) Done(ctx context.Context) {
+	CancelAppContext(ac.App, ctx)
 }
 
 // NewChildContextForAppWithTimeout creates a child context with timeout from the app-level context.
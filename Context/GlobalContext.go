@@ -2,13 +2,14 @@ package Context
 
 import (
 	"context"
-	"log"
 	"os"
 	"os/signal"
+	"slices"
 	"sync"
 	"syscall"
 	"time"
 
+	"github.com/neerajchowdary889/GoRoutinesManager/Logging"
 )
 
 type GlobalContext struct{}
@@ -59,10 +60,16 @@ func (gc *GlobalContext) Get() context.Context {
 	return gc.Init()
 }
 
+// Done shuts down the global context like Shutdown if ctx is still the current global context.
+// A global context that was already shut down and re-initialized in the meantime is left alone.
 func (gc *GlobalContext) Done(ctx context.Context) {
-	// Close that particular background context
-	ctx.Done()
+	ctxMu.Lock()
+	defer ctxMu.Unlock()
 
+	if ctx == nil || ctx != globalContext {
+		return
+	}
+	shutdownLocked()
 }
 
 // Shutdown triggers the cancellation of the global context and all app-level contexts.
@@ -70,15 +77,21 @@ func (gc *GlobalContext) Shutdown() {
 	ctxMu.Lock()
 	defer ctxMu.Unlock()
 
+	shutdownLocked()
+}
+
+// shutdownLocked cancels the global context and all app-level contexts and resets the state, with ctxMu held
+func shutdownLocked() {
 	// Cancel all app-level contexts first
 	for appName, cancel := range appCancels {
 		if cancel != nil {
-			log.Printf("Shutting down app-level context for: %s", appName)
+			logMessage(Logging.Debug, "shutting down app-level context", "app", appName)
 			cancel()
 		}
 	}
 	appCancels = make(map[string]context.CancelFunc)
 	appContexts = make(map[string]context.Context)
+	appRefs = nil
 
 	// Cancel global context
 	if globalCancel != nil {
@@ -88,7 +101,6 @@ func (gc *GlobalContext) Shutdown() {
 	globalContext = nil
 	isInitialized = false
 	signalOnce = sync.Once{}
-
 }
 
 // NewChildContext creates a child context derived from the global context.
@@ -124,15 +136,91 @@ func (gc *GlobalContext) ListActiveApps() []string {
 	return apps
 }
 
+// DisableSignalHandling keeps the global context from shutting down on signals, for applications
+// that manage their own signals. Shutdown is then only driven by explicit Shutdown calls.
+// It applies from the next initialization of the global context, so call it before anything creates a manager.
+func DisableSignalHandling() {
+	ctxMu.Lock()
+	defer ctxMu.Unlock()
+	signalsOff = true
+}
+
+// EnableSignalHandling undoes DisableSignalHandling from the next initialization of the global context
+func EnableSignalHandling() {
+	ctxMu.Lock()
+	defer ctxMu.Unlock()
+	signalsOff = false
+}
+
+// SetSignalChannel makes the global context shut down on the first signal received from ch instead of
+// subscribing to the process signals itself, e.g. for an application that forwards the signals it wants
+// to end with. Pass nil to go back to os/signal. Like DisableSignalHandling, it applies from the next
+// initialization of the global context.
+func SetSignalChannel(ch <-chan os.Signal) {
+	ctxMu.Lock()
+	defer ctxMu.Unlock()
+	signalSource = ch
+}
+
+// SetShutdownSignals replaces the signals that shut down the global context, SIGINT and SIGTERM by default.
+// E.g. SetShutdownSignals(syscall.SIGTERM) in a container, or add syscall.SIGHUP. Calling it without
+// signals restores the defaults. Signals of a channel set with SetSignalChannel are filtered the same way.
+// Since the handler is only set up once per global context, it applies from the next initialization of
+// the global context, so call it before anything creates a manager.
+func SetShutdownSignals(sigs ...os.Signal) {
+	ctxMu.Lock()
+	defer ctxMu.Unlock()
+	if len(sigs) == 0 {
+		signalList = nil
+		return
+	}
+	signalList = append([]os.Signal(nil), sigs...)
+}
+
+// shutdownSignals returns the signals that shut down the global context, it is called with ctxMu held
+func shutdownSignals() []os.Signal {
+	if signalList == nil {
+		return []os.Signal{syscall.SIGINT, syscall.SIGTERM}
+	}
+	return signalList
+}
+
+// setupSignalHandler shuts the global context down on a shutdown signal, it is called with ctxMu held
+// right after the global context was created. The handler stops when the global context ends otherwise.
 func (gc *GlobalContext) setupSignalHandler() {
 	signalOnce.Do(func() {
-		sigCh := make(chan os.Signal, 1)
-		signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+		if signalsOff {
+			return
+		}
+		ctx := globalContext
+		sigs := shutdownSignals()
+		sigCh := signalSource
+		stop := func() {}
+		if sigCh == nil {
+			notifyCh := make(chan os.Signal, 1)
+			signal.Notify(notifyCh, sigs...)
+			sigCh = notifyCh
+			stop = func() { signal.Stop(notifyCh) }
+		}
 		go func() {
-			sig := <-sigCh
-			log.Printf("Global context received shutdown signal: %s", sig)
-			gc.Shutdown()
-			signal.Stop(sigCh)
+			defer stop()
+			for {
+				select {
+				case sig, ok := <-sigCh:
+					if !ok {
+						return
+					}
+					if !slices.Contains(sigs, sig) {
+						continue
+					}
+					logMessage(Logging.Info, "global context received shutdown signal", "signal", sig.String())
+					gc.Shutdown()
+					return
+				case <-ctx.Done():
+					// Shut down without a signal, the next Init sets up a new handler
+					return
+				}
+			}
 		}()
 	})
 }
@@ -0,0 +1,73 @@
+package Context
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"time"
+)
+
+// ContextNode describes one registered context and its cancellation state
+type ContextNode struct {
+	Name     string     `json:"name"`
+	Alive    bool       `json:"alive"`
+	Err      string     `json:"err,omitempty"`      // context.Canceled / context.DeadlineExceeded once cancelled
+	Deadline *time.Time `json:"deadline,omitempty"` // nil if the context has no deadline
+	Refs     int        `json:"refs,omitempty"`     // references taken with Acquire, they keep the context alive
+}
+
+// ContextTree is a snapshot of the raw context layer: the global context and every app context under it.
+// App contexts include the manager keys, e.g. "AppManager.<app>" and "LocalManager.<app>/<local>".
+// Contexts removed with Shutdown are no longer registered and don't appear in the tree.
+type ContextTree struct {
+	Initialized bool          `json:"initialized"`
+	Global      *ContextNode  `json:"global,omitempty"` // nil until the global context is created
+	Apps        []ContextNode `json:"apps"`             // sorted by name
+}
+
+// Tree returns a snapshot of the global context and all registered app contexts.
+// It's meant for debugging cancellation, e.g. finding an app context that is still alive after a shutdown.
+func Tree() ContextTree {
+	ctxMu.RLock()
+	defer ctxMu.RUnlock()
+
+	tree := ContextTree{
+		Initialized: isInitialized,
+		Apps:        make([]ContextNode, 0, len(appContexts)),
+	}
+	if globalContext != nil {
+		node := newContextNode("global", globalContext)
+		tree.Global = &node
+	}
+	for name, ctx := range appContexts {
+		node := newContextNode(name, ctx)
+		node.Refs = appRefs[name]
+		tree.Apps = append(tree.Apps, node)
+	}
+	sort.Slice(tree.Apps, func(i, j int) bool {
+		return tree.Apps[i].Name < tree.Apps[j].Name
+	})
+	return tree
+}
+
+// TreeHandler serves the context tree as JSON
+func TreeHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(Tree()); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
+
+func newContextNode(name string, ctx context.Context) ContextNode {
+	node := ContextNode{Name: name, Alive: ctx.Err() == nil}
+	if err := ctx.Err(); err != nil {
+		node.Err = err.Error()
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		node.Deadline = &deadline
+	}
+	return node
+}
@@ -0,0 +1,36 @@
+package Context
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+)
+
+// verbose makes the context layer print its messages to stdout as well
+var verbose atomic.Bool
+
+// SetVerbose makes the context layer print its messages (app contexts initialized and shut down,
+// shutdown signals) to stdout, on top of the logger set with Logging.SetLogger. Off by default,
+// servers creating many app contexts would otherwise flood their output.
+func SetVerbose(enabled bool) {
+	verbose.Store(enabled)
+}
+
+// IsVerbose returns whether the context layer prints its messages to stdout
+func IsVerbose() bool {
+	return verbose.Load()
+}
+
+// logMessage sends a message of the context layer to the logger at the given level, and to stdout when verbose
+func logMessage(level func(msg string, kv ...any), msg string, kv ...any) {
+	level(msg, kv...)
+	if !verbose.Load() {
+		return
+	}
+	var line strings.Builder
+	line.WriteString(msg)
+	for i := 0; i+1 < len(kv); i += 2 {
+		fmt.Fprintf(&line, " %v=%v", kv[i], kv[i+1])
+	}
+	fmt.Println(line.String())
+}
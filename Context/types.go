@@ -2,6 +2,7 @@ package Context
 
 import (
 	"context"
+	"os"
 	"sync"
 	"time"
 )
@@ -11,9 +12,14 @@ var (
 	globalCancel  context.CancelFunc            // GlobalCancel cancels the GlobalContext.
 	appContexts   map[string]context.Context    // appContexts stores app-level contexts
 	appCancels    map[string]context.CancelFunc // appCancels stores app-level cancel functions
+	appRefs       map[string]int                // appRefs counts the references taken with Acquire per app context
 	ctxMu         sync.RWMutex                  // ctxMu protects concurrent access to all context maps
 	signalOnce    sync.Once                     // signalOnce ensures the os signal handler is only set up once.
 	isInitialized bool                          // isInitialized tracks if the global context has been initialized.
+
+	signalsOff   bool             // signalsOff skips the signal handler, see DisableSignalHandling. Guarded by ctxMu.
+	signalSource <-chan os.Signal // signalSource replaces os/signal as the source of signals when set. Guarded by ctxMu.
+	signalList   []os.Signal      // signalList holds the signals that shut down the global context, nil for the defaults. Guarded by ctxMu.
 )
 
 type ContextInterface interface {
@@ -25,3 +31,12 @@ type ContextInterface interface {
 	NewChildContextWithTimeout(timeout time.Duration) (context.Context, context.CancelFunc)
 	SetAppName(app string)
 }
+
+// RefCountedContextInterface is an app-level context shared by several owners, see Acquire and Release
+type RefCountedContextInterface interface {
+	ContextInterface
+	Acquire() context.Context
+	Release() bool
+	ForceShutdown()
+	Refs() int
+}
@@ -0,0 +1,96 @@
+// Package Sync lets tests wait on goroutines spawned through a local manager without sleeping.
+//
+// Example:
+//
+//	h := Sync.New(t, localMgr)
+//	gate := Sync.NewGate()
+//	for i := 0; i < 3; i++ {
+//	    h.Go("worker", func(ctx context.Context) error { return gate.Wait(ctx) })
+//	}
+//	h.WaitStarted("worker", 3) // all three are running and blocked on the gate
+//	// ... assert on the running routines ...
+//	gate.Open()
+//	h.WaitDone("worker")
+package Sync
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/neerajchowdary889/GoRoutinesManager/Manager/Interface"
+	"github.com/neerajchowdary889/GoRoutinesManager/Manager/Local"
+)
+
+// DefaultTimeout bounds every wait of a Harness so a broken test fails instead of hanging
+var DefaultTimeout = 5 * time.Second
+
+// Harness spawns workers on a local manager for a test and waits on them deterministically
+type Harness struct {
+	t     testing.TB
+	local Interface.LocalGoroutineManagerInterface
+	// Timeout bounds each wait, defaults to DefaultTimeout
+	Timeout time.Duration
+}
+
+// New returns a harness spawning on the given local manager, failures are reported to t
+func New(t testing.TB, local Interface.LocalGoroutineManagerInterface) *Harness {
+	return &Harness{
+		t:       t,
+		local:   local,
+		Timeout: DefaultTimeout,
+	}
+}
+
+// Go spawns the worker and fails the test if the spawn is rejected.
+// The routine joins the function wait group named after functionName so WaitDone can wait for it.
+func (h *Harness) Go(functionName string, worker func(ctx context.Context) error, opts ...Interface.GoroutineOption) {
+	h.t.Helper()
+	opts = append([]Interface.GoroutineOption{Local.AddToWaitGroup(functionName)}, opts...)
+	if err := h.local.Go(functionName, worker, opts...); err != nil {
+		h.t.Fatalf("Go(%q) failed: %v", functionName, err)
+	}
+}
+
+// WaitStarted blocks until at least n routines of the function have begun running their worker
+func (h *Harness) WaitStarted(functionName string, n int) {
+	h.t.Helper()
+	if !h.local.WaitForFunctionStartedWithTimeout(functionName, n, h.Timeout) {
+		h.t.Fatalf("%d routines of %q did not start within %v", n, functionName, h.Timeout)
+	}
+}
+
+// WaitDone blocks until every routine of the function has finished
+func (h *Harness) WaitDone(functionName string) {
+	h.t.Helper()
+	if !h.local.WaitForFunctionWithTimeout(functionName, h.Timeout) {
+		h.t.Fatalf("routines of %q did not finish within %v", functionName, h.Timeout)
+	}
+}
+
+// Gate is a barrier workers block on until the test opens it
+type Gate struct {
+	once sync.Once
+	ch   chan struct{}
+}
+
+// NewGate returns a closed gate
+func NewGate() *Gate {
+	return &Gate{ch: make(chan struct{})}
+}
+
+// Wait blocks until the gate is opened or ctx is done, returning ctx's error in the latter case
+func (g *Gate) Wait(ctx context.Context) error {
+	select {
+	case <-g.ch:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Open releases every current and future Wait, opening twice is a no-op
+func (g *Gate) Open() {
+	g.once.Do(func() { close(g.ch) })
+}
@@ -0,0 +1,64 @@
+// Package Logging routes the library's log messages to a logger of the host application.
+// Nothing is logged until SetLogger is called.
+package Logging
+
+import "sync/atomic"
+
+// Logger receives the library's log messages. kv holds alternating keys and values,
+// e.g. Debug("initialized app-level context", "app", "payments"). *slog.Logger implements it as is,
+// zap's SugaredLogger or zerolog need a small adapter.
+type Logger interface {
+	Debug(msg string, kv ...any)
+	Info(msg string, kv ...any)
+	Warn(msg string, kv ...any)
+	Error(msg string, kv ...any)
+}
+
+// NopLogger discards every message, it is the default logger
+type NopLogger struct{}
+
+func (NopLogger) Debug(string, ...any) {}
+func (NopLogger) Info(string, ...any)  {}
+func (NopLogger) Warn(string, ...any)  {}
+func (NopLogger) Error(string, ...any) {}
+
+// loggerHolder lets any Logger be stored in an atomic.Pointer
+type loggerHolder struct {
+	logger Logger
+}
+
+var current atomic.Pointer[loggerHolder]
+
+// SetLogger routes the library's log messages to logger, replacing any previous one.
+// Pass nil to go back to discarding them. The logger is called from many goroutines concurrently.
+//
+// Example:
+//
+//	Logging.SetLogger(slog.Default())
+func SetLogger(logger Logger) {
+	if logger == nil {
+		current.Store(nil)
+		return
+	}
+	current.Store(&loggerHolder{logger: logger})
+}
+
+// GetLogger returns the logger set with SetLogger, or a NopLogger
+func GetLogger() Logger {
+	if holder := current.Load(); holder != nil {
+		return holder.logger
+	}
+	return NopLogger{}
+}
+
+// Debug logs msg at debug level on the current logger
+func Debug(msg string, kv ...any) { GetLogger().Debug(msg, kv...) }
+
+// Info logs msg at info level on the current logger
+func Info(msg string, kv ...any) { GetLogger().Info(msg, kv...) }
+
+// Warn logs msg at warn level on the current logger
+func Warn(msg string, kv ...any) { GetLogger().Warn(msg, kv...) }
+
+// Error logs msg at error level on the current logger
+func Error(msg string, kv ...any) { GetLogger().Error(msg, kv...) }
@@ -1,8 +1,14 @@
 package App
 
 import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"sync"
 	"time"
 
+	"github.com/neerajchowdary889/GoRoutinesManager/Context"
 	LocalHelper "github.com/neerajchowdary889/GoRoutinesManager/Helper/Local"
 	"github.com/neerajchowdary889/GoRoutinesManager/Manager/Interface"
 	"github.com/neerajchowdary889/GoRoutinesManager/Manager/Local"
@@ -21,6 +27,35 @@ func NewAppManager(Appname string) Interface.AppGoroutineManagerInterface {
 	}
 }
 
+// Rename re-keys the app manager from oldName to newName while its routines keep running.
+// The contexts of the app and its locals are moved to the new keys and its metric series are migrated to the new label.
+// Handles created with the old name stop resolving, create new ones with NewAppManager(newName).
+// Returns ErrAppManagerExists if newName is already taken.
+func Rename(oldName, newName string) error {
+	global, err := types.GetGlobalManager()
+	if err != nil {
+		return err
+	}
+	if err := global.RenameAppManager(oldName, newName); err != nil {
+		metrics.RecordOperationError("manager", "rename_app", "rename_failed")
+		return err
+	}
+
+	Context.RenameAppContext(types.Prefix_AppManager+oldName, types.Prefix_AppManager+newName)
+	// Local contexts are keyed by their app's name too
+	if app, err := global.GetAppManager(newName); err == nil {
+		for _, local := range app.LocalManagersSnapshot() {
+			Context.RenameAppContext(types.LocalContextKey(oldName, local.LocalName), types.LocalContextKey(newName, local.LocalName))
+		}
+		// The metrics opt-out is remembered by app name too
+		metrics.SetAppMetricsEnabled(oldName, true)
+		metrics.SetAppMetricsEnabled(newName, app.GetMetricsEnabled())
+	}
+	metrics.RenameAppSeries(oldName, newName)
+	metrics.RecordManagerOperation("app", "rename", newName)
+	return nil
+}
+
 func (AM *AppManagerStruct) CreateApp() (*types.AppManager, error) {
 	startTime := time.Now()
 	defer func() {
@@ -41,15 +76,27 @@ func (AM *AppManagerStruct) CreateApp() (*types.AppManager, error) {
 		return types.GetAppManager(AM.AppName)
 	}
 
-	app := types.NewAppManager(AM.AppName).SetAppContext().SetAppMutex()
-	types.SetAppManager(AM.AppName, app)
+	// CreateAppManager registers the app and fails with ErrMaxAppsExceeded when the app cap is reached
+	app, err := types.CreateAppManager(AM.AppName)
+	if err != nil {
+		if errors.Is(err, Errors.ErrMaxAppsExceeded) {
+			metrics.RecordOperationError("manager", "create_app", "max_apps_exceeded")
+		}
+		return nil, err
+	}
+
+	// A new app records metrics, even if an earlier app of the same name opted out
+	metrics.SetAppMetricsEnabled(AM.AppName, app.GetMetricsEnabled())
 
 	// Record operation
 	metrics.RecordManagerOperation("app", "create", AM.AppName)
+	types.PublishEvent(types.EventAppCreated, AM.AppName, "", "", "", nil)
 
 	return app, nil
 }
 
+// Shutdown shuts down every local manager of the app. The errors of the local shutdowns are joined,
+// stuck functions of all local managers end up in one *types.ShutdownError.
 func (AM *AppManagerStruct) Shutdown(safe bool) error {
 	startTime := time.Now()
 	shutdownType := "unsafe"
@@ -60,6 +107,9 @@ func (AM *AppManagerStruct) Shutdown(safe bool) error {
 	defer func() {
 		duration := time.Since(startTime)
 		metrics.RecordShutdownDuration("app", shutdownType, duration, AM.AppName, "")
+
+		// Flush so the final state is visible right away instead of on the next collector tick
+		metrics.FlushNow()
 	}()
 
 	appManager, err := types.GetAppManager(AM.AppName)
@@ -78,6 +128,14 @@ func (AM *AppManagerStruct) Shutdown(safe bool) error {
 	// Record shutdown operation
 	metrics.RecordManagerOperation("app", "shutdown", AM.AppName)
 
+	types.FireShutdownStage(types.ShutdownStageBeginDrain, AM.AppName, "", "")
+	defer types.FireShutdownStage(types.ShutdownStageDone, AM.AppName, "", "")
+	defer metrics.RecordAppDraining(AM.AppName)()
+
+	// Errors of the local shutdowns, joined into the returned error
+	var errsMu sync.Mutex
+	var errs []error
+
 	if safe {
 		// Safe shutdown: trigger shutdown on all local managers and wait
 		if appManager.Wg != nil {
@@ -92,7 +150,11 @@ func (AM *AppManagerStruct) Shutdown(safe bool) error {
 
 					// Call Shutdown on the local manager
 					// This will trigger the improved safe shutdown logic (graceful -> timeout -> force)
-					_ = lmInstance.Shutdown(true)
+					if err := lmInstance.Shutdown(true); err != nil {
+						errsMu.Lock()
+						errs = append(errs, err)
+						errsMu.Unlock()
+					}
 
 					// Wait for local manager's wait group (redundant but safe)
 					if lm.Wg != nil {
@@ -110,7 +172,9 @@ func (AM *AppManagerStruct) Shutdown(safe bool) error {
 			lmInstance := Local.NewLocalManager(AM.AppName, localMgr.LocalName)
 
 			// Call Shutdown(false) which handles cancellation
-			_ = lmInstance.Shutdown(false)
+			if err := lmInstance.Shutdown(false); err != nil {
+				errs = append(errs, err)
+			}
 		}
 
 		// Cancel the app manager's context
@@ -119,7 +183,7 @@ func (AM *AppManagerStruct) Shutdown(safe bool) error {
 		}
 	}
 
-	return nil
+	return types.JoinShutdownErrors(errs...)
 }
 
 func (AM *AppManagerStruct) CreateLocal(localName string) (*types.LocalManager, error) {
@@ -147,6 +211,27 @@ func (AM *AppManagerStruct) CreateLocal(localName string) (*types.LocalManager,
 	return Manager, nil
 }
 
+// NextLocal creates a local manager named "prefix-N" with the next free N for the prefix and returns its handle.
+// Use it when local managers are created programmatically instead of formatting and tracking names by hand.
+//
+// Example:
+//
+//	for i := 0; i < workers; i++ {
+//	    localMgr, err := appMgr.NextLocal("shard") // shard-1, shard-2, ...
+//	    ...
+//	}
+func (AM *AppManagerStruct) NextLocal(prefix string) (Interface.LocalGoroutineManagerInterface, error) {
+	appManager, err := types.GetAppManager(AM.AppName)
+	if err != nil {
+		return nil, err
+	}
+	localName := appManager.NextLocalName(prefix)
+	if _, err := AM.CreateLocal(localName); err != nil {
+		return nil, err
+	}
+	return Local.NewLocalManager(AM.AppName, localName), nil
+}
+
 func (AM *AppManagerStruct) GetAllLocalManagers() ([]*types.LocalManager, error) {
 	appManager, err := types.GetAppManager(AM.AppName)
 	if err != nil {
@@ -195,6 +280,110 @@ func (AM *AppManagerStruct) GetGoroutineCount() int {
 	return count
 }
 
+// CancelContext cancels the app's context, which cancels the contexts of all its local managers derived from it,
+// signalling every routine of the app to stop. Unlike Shutdown it doesn't wait, force-remove routines or remove managers:
+// the routines clean up through their own defers and the app and its local managers stay registered.
+// The contexts stay cancelled, so routines spawned afterwards start cancelled.
+func (AM *AppManagerStruct) CancelContext() error {
+	appManager, err := types.GetAppManager(AM.AppName)
+	if err != nil {
+		return err
+	}
+	appManager.CancelContext()
+	metrics.RecordManagerOperation("app", "cancel_context", AM.AppName)
+	return nil
+}
+
+// Drain puts every local manager of the app in drain mode, see LocalManagerStruct.Drain.
+// Local managers created afterwards accept goroutines.
+func (AM *AppManagerStruct) Drain() error {
+	appManager, err := types.GetAppManager(AM.AppName)
+	if err != nil {
+		return err
+	}
+	for _, localManager := range appManager.LocalManagersSnapshot() {
+		localManager.SetDraining(true)
+	}
+	metrics.RecordManagerOperation("app", "drain", AM.AppName)
+	return nil
+}
+
+// WaitForAll waits for every goroutine across the app's local managers, waiting on each local wait group concurrently.
+// The local managers are snapshotted when it's called, ones created during the wait aren't waited for.
+func (AM *AppManagerStruct) WaitForAll() error {
+	appManager, err := types.GetAppManager(AM.AppName)
+	if err != nil {
+		return err
+	}
+	var wg sync.WaitGroup
+	for _, localManager := range appManager.LocalManagersSnapshot() {
+		if localManager.Wg == nil {
+			continue
+		}
+		wg.Add(1)
+		go func(lm *types.LocalManager) {
+			defer wg.Done()
+			lm.Wg.Wait()
+		}(localManager)
+	}
+	wg.Wait()
+	return nil
+}
+
+// WaitForAllWithTimeout is WaitForAll giving up after timeout, it returns true if everything completed in time.
+func (AM *AppManagerStruct) WaitForAllWithTimeout(timeout time.Duration) bool {
+	done := make(chan struct{}, 1) // Buffered so the waiter never blocks after a timeout
+
+	go func() {
+		if err := AM.WaitForAll(); err == nil {
+			select {
+			case done <- struct{}{}:
+			default:
+			}
+		}
+	}()
+
+	select {
+	case <-done:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
+
+// Stats returns a snapshot of the app: local managers, live and peak routines, total spawned and uptime.
+// Routine counts add up the app's local managers. Returns zero stats if the app doesn't exist.
+func (AM *AppManagerStruct) Stats() types.ManagerStats {
+	appManager, err := types.GetAppManager(AM.AppName)
+	if err != nil {
+		return types.ManagerStats{}
+	}
+	LocalManagers := appManager.GetLocalManagers()
+	stats := types.ManagerStats{
+		LocalManagers:  len(LocalManagers),
+		PeakGoroutines: appManager.GetPeakRoutines(),
+		Uptime:         appManager.GetUptime(),
+	}
+	for _, localManager := range LocalManagers {
+		stats.Goroutines += localManager.GetRoutineCount()
+		stats.TotalSpawned += localManager.GetTotalSpawned()
+	}
+	return stats
+}
+
+// SetMetricsEnabled turns metrics on or off for this app only, e.g. to instrument just the apps that matter.
+// It narrows the global metrics switch: with metrics disabled globally nothing is recorded either way.
+// Series already exported for the app are kept until the metrics are reset.
+func (AM *AppManagerStruct) SetMetricsEnabled(enabled bool) error {
+	appManager, err := types.GetAppManager(AM.AppName)
+	if err != nil {
+		return err
+	}
+	appManager.SetMetricsEnabled(enabled)
+	metrics.SetAppMetricsEnabled(AM.AppName, enabled)
+	return nil
+}
+
 func (AM *AppManagerStruct) GetLocalManagerCount() int {
 	// Return the Local Manager count for the particular app manager
 	appManager, err := types.GetAppManager(AM.AppName)
@@ -210,4 +399,96 @@ func (AM *AppManagerStruct) GetLocalManagerByName(localName string) (*types.Loca
 		return nil, err
 	}
 	return appManager.GetLocalManager(localName)
-}
\ No newline at end of file
+}
+
+// GetRoutinesByFunctionName returns every routine of the given function across all local managers of the app.
+// Each reference carries the owning local manager's name so callers can act on the routine app-wide.
+func (AM *AppManagerStruct) GetRoutinesByFunctionName(functionName string) ([]types.RoutineRef, error) {
+	appManager, err := types.GetAppManager(AM.AppName)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]types.RoutineRef, 0)
+	for localName, localManager := range appManager.GetLocalManagers() {
+		for _, routine := range localManager.GetRoutines() {
+			if routine.GetFunctionName() == functionName {
+				result = append(result, types.RoutineRef{
+					AppName:   AM.AppName,
+					LocalName: localName,
+					Routine:   routine,
+				})
+			}
+		}
+	}
+	return result, nil
+}
+
+// ManageHTTPServer runs srv under the given local manager and ties its lifetime to the manager.
+// The listener is bound before returning, so an address that can't be bound is reported to the caller.
+// Two routines are spawned under functionName, both in the function's wait group:
+//   - one waiting for cancellation and then draining srv with srv.Shutdown for up to drainTimeout,
+//     falling back to srv.Close if connections are still open after that
+//   - one running srv.Serve on the bound listener
+//
+// Shutting down the app, the local manager or the function stops the server.
+// The local manager is created if it doesn't exist yet.
+//
+// Example:
+//
+//	srv := &http.Server{Addr: ":8080", Handler: mux}
+//	appMgr.ManageHTTPServer("http", "api-server", srv, 5*time.Second)
+func (AM *AppManagerStruct) ManageHTTPServer(localName, functionName string, srv *http.Server, drainTimeout time.Duration) error {
+	if _, err := AM.CreateLocal(localName); err != nil {
+		return err
+	}
+	localManager := Local.NewLocalManager(AM.AppName, localName)
+
+	addr := srv.Addr
+	if addr == "" {
+		addr = ":http"
+	}
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	// Closed when Serve returns so the drain routine doesn't outlive a failed server
+	serveDone := make(chan struct{})
+
+	// The drain routine goes first, a server is never left running without it
+	err = localManager.Go(functionName, func(ctx context.Context) error {
+		select {
+		case <-serveDone:
+			return nil
+		case <-ctx.Done():
+		}
+
+		drainCtx, cancel := context.WithTimeout(context.Background(), drainTimeout)
+		defer cancel()
+		if err := srv.Shutdown(drainCtx); err != nil {
+			// Drain budget exhausted, drop the remaining connections
+			return srv.Close()
+		}
+		return nil
+	}, Local.AddToWaitGroup(functionName))
+	if err != nil {
+		listener.Close()
+		return err
+	}
+
+	err = localManager.Go(functionName, func(ctx context.Context) error {
+		defer close(serveDone)
+		if err := srv.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+		return nil
+	}, Local.AddToWaitGroup(functionName))
+	if err != nil {
+		// Let the drain routine return and release the address
+		close(serveDone)
+		listener.Close()
+		return err
+	}
+	return nil
+}
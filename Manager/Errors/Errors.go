@@ -3,15 +3,48 @@ package Errors
 import "fmt"
 
 var (
-	ErrGlobalManagerNotFound = fmt.Errorf("global manager not found")
-	ErrAppManagerNotFound    = fmt.Errorf("app manager not found")
-	ErrLocalManagerNotFound  = fmt.Errorf("local manager not found")
-	ErrLockContextCancelled  = fmt.Errorf("lock acquisition cancelled due to context cancellation")
-	ErrRoutineNotFound       = fmt.Errorf("routine not found")
-	ErrFunctionWgNotFound    = fmt.Errorf("function wg not found")
+	ErrGlobalManagerNotFound  = fmt.Errorf("global manager not found")
+	ErrAppManagerNotFound     = fmt.Errorf("app manager not found")
+	ErrLocalManagerNotFound   = fmt.Errorf("local manager not found")
+	ErrLockContextCancelled   = fmt.Errorf("lock acquisition cancelled due to context cancellation")
+	ErrRoutineNotFound        = fmt.Errorf("routine not found")
+	ErrFunctionWgNotFound     = fmt.Errorf("function wg not found")
+	ErrReservedFunctionName   = fmt.Errorf("function name is reserved for internal use")
+	ErrMaxAppsExceeded        = fmt.Errorf("maximum number of app managers exceeded")
+	ErrMaxLocalsExceeded      = fmt.Errorf("maximum number of local managers per app exceeded")
+	ErrMaxRoutinesExceeded    = fmt.Errorf("maximum number of routines exceeded")
+	ErrMaxConcurrencyExceeded = fmt.Errorf("maximum concurrency of function exceeded")
+	ErrAppManagerExists       = fmt.Errorf("app manager already exists")
+	ErrLocalManagerExists     = fmt.Errorf("local manager already exists")
+	ErrInvalidInterval        = fmt.Errorf("schedule interval must be positive")
+	ErrInvalidErrorRate       = fmt.Errorf("error rate threshold needs a ratio in [0, 1) and a positive window")
+	ErrWorkerPanicked         = fmt.Errorf("worker panicked")
+	// ErrRoutineCompleted wraps ErrRoutineNotFound, the routine finished and is only known from the retention buffer
+	ErrRoutineCompleted     = fmt.Errorf("%w: routine already completed", ErrRoutineNotFound)
+	ErrSemaphoreNotFound    = fmt.Errorf("semaphore not found")
+	ErrSemaphoreExists      = fmt.Errorf("semaphore already exists")
+	ErrInvalidSemaphoreSize = fmt.Errorf("semaphore size must be positive")
+	ErrInvalidWeight        = fmt.Errorf("function weight must be positive")
+	ErrInvalidMetadata      = fmt.Errorf("invalid metadata value")
+	ErrMemoryBudgetExceeded = fmt.Errorf("memory budget exceeded")
+	ErrInvalidMemoryBudget  = fmt.Errorf("memory budget can't be negative")
+	ErrNilParentContext     = fmt.Errorf("parent context can't be nil")
+	ErrShutdownInProgress   = fmt.Errorf("shutdown already in progress")
+	ErrShutdownTimedOut     = fmt.Errorf("goroutines didn't stop within the shutdown timeout")
+	ErrDraining             = fmt.Errorf("local manager is draining, no new goroutines accepted")
+	// ErrSpawnQueueFull wraps ErrMaxRoutinesExceeded, the spawn was over the limit and the spawn queue had no room left
+	ErrSpawnQueueFull        = fmt.Errorf("%w, spawn queue full", ErrMaxRoutinesExceeded)
+	ErrSpawnQueueClosed      = fmt.Errorf("spawn queue stopped by shutdown")
+	ErrInvalidSpawnQueueSize = fmt.Errorf("spawn queue size can't be negative")
+	ErrInvalidRateLimit      = fmt.Errorf("rate limit needs a positive rate and a burst of at least 1")
+	ErrRateLimited           = fmt.Errorf("rate limit has no token available")
+	ErrSemaphoreFull         = fmt.Errorf("semaphore has no free slot")
+	// Returned when spawning through a manager that was named but never created, they wrap the not found errors
+	ErrAppManagerNotCreated   = fmt.Errorf("%w, call CreateApp before spawning", ErrAppManagerNotFound)
+	ErrLocalManagerNotCreated = fmt.Errorf("%w, call CreateLocal before spawning", ErrLocalManagerNotFound)
 )
 
 // this is for warnings
 var (
 	WrngLocalManagerAlreadyExists = fmt.Errorf("local manager already exists")
-)
\ No newline at end of file
+)
@@ -0,0 +1,93 @@
+package Global
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/neerajchowdary889/GoRoutinesManager/Manager/Errors"
+	"github.com/neerajchowdary889/GoRoutinesManager/Manager/Local"
+	"github.com/neerajchowdary889/GoRoutinesManager/types"
+)
+
+// adminRoutine is one entry of the GET /routines list
+type adminRoutine struct {
+	AppName          string    `json:"app_name"`
+	LocalName        string    `json:"local_name"`
+	ID               string    `json:"id"`
+	FunctionName     string    `json:"function_name"`
+	State            string    `json:"state"`
+	StartedAt        time.Time `json:"started_at"`
+	Uptime           string    `json:"uptime"`
+	ContextCancelled bool      `json:"context_cancelled"`
+}
+
+// GetAdminHandler returns a handler to inspect and cancel routines, to be mounted next to the metrics handler:
+//
+//	GET  /routines              JSON list of every tracked routine with its app and local manager
+//	POST /routines/{id}/cancel  cancels the routine like CancelRoutine, 404 if it isn't tracked
+//
+// The owning local manager of a routine is found through the global routine index. The handler has no
+// authentication, only expose it on an internal listener.
+//
+// Example:
+//
+//	mux.Handle("/routines", Global.GetAdminHandler())
+//	mux.Handle("/routines/", Global.GetAdminHandler())
+func GetAdminHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /routines", func(w http.ResponseWriter, r *http.Request) {
+		routines := []adminRoutine{}
+		for _, app := range NewGlobalManager().Snapshot().Apps {
+			for _, local := range app.Locals {
+				for _, routine := range local.Routines {
+					routines = append(routines, adminRoutine{
+						AppName:          app.Name,
+						LocalName:        local.Name,
+						ID:               routine.ID,
+						FunctionName:     routine.FunctionName,
+						State:            routine.State.String(),
+						StartedAt:        routine.StartedAt,
+						Uptime:           routine.Uptime.String(),
+						ContextCancelled: routine.ContextCancelled,
+					})
+				}
+			}
+		}
+		writeAdminJSON(w, http.StatusOK, routines)
+	})
+	mux.HandleFunc("POST /routines/{id}/cancel", func(w http.ResponseWriter, r *http.Request) {
+		routineID := r.PathValue("id")
+		err := cancelRoutineByID(routineID)
+		switch {
+		case errors.Is(err, Errors.ErrRoutineNotFound), errors.Is(err, Errors.ErrLocalManagerNotFound),
+			errors.Is(err, Errors.ErrGlobalManagerNotFound):
+			writeAdminJSON(w, http.StatusNotFound, map[string]string{"error": err.Error()})
+		case err != nil:
+			writeAdminJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		default:
+			writeAdminJSON(w, http.StatusOK, map[string]string{"cancelled": routineID})
+		}
+	})
+	return mux
+}
+
+// cancelRoutineByID cancels a routine through the local manager the routine index says owns it
+func cancelRoutineByID(routineID string) error {
+	globalManager, err := types.GetGlobalManager()
+	if err != nil {
+		return err
+	}
+	owner, err := globalManager.GetRoutineOwner(routineID)
+	if err != nil {
+		return err
+	}
+	return Local.NewLocalManager(owner.AppName, owner.LocalName).CancelRoutine(routineID)
+}
+
+func writeAdminJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
@@ -1,10 +1,14 @@
 package Global
 
 import (
+	"context"
+	"sync"
 	"time"
 
+	"github.com/neerajchowdary889/GoRoutinesManager/Context"
 	AppHelper "github.com/neerajchowdary889/GoRoutinesManager/Helper/App"
 	LocalHelper "github.com/neerajchowdary889/GoRoutinesManager/Helper/Local"
+	"github.com/neerajchowdary889/GoRoutinesManager/Logging"
 	"github.com/neerajchowdary889/GoRoutinesManager/Manager/App"
 	"github.com/neerajchowdary889/GoRoutinesManager/Manager/Interface"
 	"github.com/neerajchowdary889/GoRoutinesManager/metrics"
@@ -37,7 +41,25 @@ func (GM *GlobalManagerStruct) Init() (*types.GlobalManager, error) {
 	return types.GetGlobalManager()
 }
 
+// Shutdown shuts down every app manager. The errors of the app shutdowns are joined,
+// stuck functions across the whole tree end up in one *types.ShutdownError.
 func (GM *GlobalManagerStruct) Shutdown(safe bool) error {
+	return GM.shutdown(safe, nil)
+}
+
+// ShutdownOrdered shuts down the apps named in order one after the other, each finishing before the next starts,
+// then the remaining apps concurrently like Shutdown. Use it to stop the apps feeding work first, e.g. the API
+// before the workers. Names of apps that don't exist are skipped with a logged warning.
+//
+// Example:
+//
+//	globalMgr.ShutdownOrdered(true, []string{"api", "workers"})
+func (GM *GlobalManagerStruct) ShutdownOrdered(safe bool, order []string) error {
+	return GM.shutdown(safe, order)
+}
+
+// shutdown runs Shutdown and ShutdownOrdered, order lists the apps to shut down sequentially first
+func (GM *GlobalManagerStruct) shutdown(safe bool, order []string) error {
 	startTime := time.Now()
 	shutdownType := "unsafe"
 	if safe {
@@ -47,6 +69,9 @@ func (GM *GlobalManagerStruct) Shutdown(safe bool) error {
 	defer func() {
 		duration := time.Since(startTime)
 		metrics.RecordShutdownDuration("global", shutdownType, duration, "", "")
+
+		// Flush so the final state is visible right away instead of on the next collector tick
+		metrics.FlushNow()
 	}()
 
 	globalMgr, err := types.GetGlobalManager()
@@ -65,6 +90,39 @@ func (GM *GlobalManagerStruct) Shutdown(safe bool) error {
 	// Record shutdown operation
 	metrics.RecordManagerOperation("global", "shutdown", "")
 
+	// Queued spawns would start in apps being shut down, drop them and stop the dispatcher first
+	globalMgr.StopSpawnQueue()
+
+	types.FireShutdownStage(types.ShutdownStageBeginDrain, "", "", "")
+	defer types.FireShutdownStage(types.ShutdownStageDone, "", "", "")
+
+	// Errors of the app shutdowns, joined into the returned error
+	var errsMu sync.Mutex
+	var errs []error
+
+	// The ordered apps go down one at a time, the rest are left for the concurrent pass
+	if len(order) > 0 {
+		remaining := make(map[string]*types.AppManager, len(appManagers))
+		for _, appMgr := range appManagers {
+			remaining[appMgr.AppName] = appMgr
+		}
+		for _, appName := range order {
+			if _, ok := remaining[appName]; !ok {
+				metrics.RecordOperationError("manager", "shutdown", "ordered_app_not_found")
+				Logging.Warn("ordered shutdown: app not found, skipping it", "app", appName)
+				continue
+			}
+			delete(remaining, appName)
+			if err := App.NewAppManager(appName).Shutdown(safe); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		appManagers = appManagers[:0]
+		for _, appMgr := range remaining {
+			appManagers = append(appManagers, appMgr)
+		}
+	}
+
 	if safe {
 		// Safe shutdown: trigger shutdown on all app managers and wait
 		if globalMgr.Wg != nil {
@@ -79,7 +137,11 @@ func (GM *GlobalManagerStruct) Shutdown(safe bool) error {
 
 					// Call Shutdown on the app manager
 					// This will trigger AppManager.Shutdown -> LocalManager.Shutdown
-					_ = amInstance.Shutdown(true)
+					if err := amInstance.Shutdown(true); err != nil {
+						errsMu.Lock()
+						errs = append(errs, err)
+						errsMu.Unlock()
+					}
 
 					// Wait for app manager's wait group (redundant but safe)
 					// Lock to safely read Wg pointer to avoid race condition
@@ -101,7 +163,9 @@ func (GM *GlobalManagerStruct) Shutdown(safe bool) error {
 			amInstance := App.NewAppManager(appMgr.AppName)
 
 			// Call Shutdown(false) which handles cancellation
-			_ = amInstance.Shutdown(false)
+			if err := amInstance.Shutdown(false); err != nil {
+				errs = append(errs, err)
+			}
 		}
 
 		// Cancel the global manager's context
@@ -110,7 +174,67 @@ func (GM *GlobalManagerStruct) Shutdown(safe bool) error {
 		}
 	}
 
-	return nil
+	// The whole tree is down, cancel the global context so Done() fires for both shutdown modes
+	Context.GetGlobalContext().Shutdown()
+
+	return types.JoinShutdownErrors(errs...)
+}
+
+// ShutdownWithProgress runs Shutdown(safe) in the background and streams its progress on the returned channel:
+// apps remaining, local managers drained and routines cancelled, starting with a snapshot of the tree.
+// The last snapshot has Done set and carries the Shutdown error, then the channel is closed.
+// Snapshots a slow reader hasn't made room for are dropped, the last one is always delivered.
+//
+// Example:
+//
+//	progress, _ := globalMgr.ShutdownWithProgress(true)
+//	for p := range progress {
+//	    fmt.Printf("%d/%d locals drained\n", p.LocalsDrained, p.LocalsTotal)
+//	}
+func (GM *GlobalManagerStruct) ShutdownWithProgress(safe bool) (<-chan types.ShutdownProgress, error) {
+	globalMgr, err := types.GetGlobalManager()
+	if err != nil {
+		return nil, err
+	}
+	progress, err := globalMgr.BeginShutdownProgress()
+	if err != nil {
+		return nil, err
+	}
+	go func() {
+		globalMgr.EndShutdownProgress(GM.Shutdown(safe))
+	}()
+	return progress, nil
+}
+
+// Run blocks until ctx is done or the SIGINT/SIGTERM handler cancels the global context, then shuts
+// the whole tree down safely, within the configured shutdown timeout, and returns the Shutdown error.
+// It replaces the wait-for-signal-then-shutdown boilerplate at the end of main.
+//
+// Example:
+//
+//	if err := globalMgr.Run(context.Background()); err != nil {
+//	    log.Printf("shutdown failed: %v", err)
+//	}
+func (GM *GlobalManagerStruct) Run(ctx context.Context) error {
+	if _, err := types.GetGlobalManager(); err != nil {
+		return err
+	}
+	select {
+	case <-ctx.Done():
+	case <-GM.Done():
+	}
+	return GM.Shutdown(true)
+}
+
+// Done returns a channel that is closed once the global context is cancelled,
+// by Shutdown or by the SIGINT/SIGTERM handler. Use it as the "the process is shutting down" signal,
+// e.g. to stop accepting new requests. Returns nil, which blocks forever, before Init.
+func (GM *GlobalManagerStruct) Done() <-chan struct{} {
+	globalManager, err := types.GetGlobalManager()
+	if err != nil || globalManager.Ctx == nil {
+		return nil
+	}
+	return globalManager.Ctx.Done()
 }
 
 func (GM *GlobalManagerStruct) GetAllAppManagers() ([]*types.AppManager, error) {
@@ -124,6 +248,14 @@ func (GM *GlobalManagerStruct) GetAllAppManagers() ([]*types.AppManager, error)
 	return helper.MapToSlice(mapValue), nil
 }
 
+// GetAppManagerByName returns the app manager with the given name, ready to operate on through its interface
+func (GM *GlobalManagerStruct) GetAppManagerByName(appName string) (Interface.AppGoroutineManagerInterface, error) {
+	if _, err := types.GetAppManager(appName); err != nil {
+		return nil, err
+	}
+	return App.NewAppManager(appName), nil
+}
+
 func (GM *GlobalManagerStruct) GetAppManagerCount() int {
 	Global, err := types.GetGlobalManager()
 	if err != nil {
@@ -172,15 +304,22 @@ func (GM *GlobalManagerStruct) GetAllGoroutines() ([]*types.Routine, error) {
 		return nil, err
 	}
 
-	// Get all goroutines from each app manager - would run on O(n*m)
-	var goroutines []*types.Routine
+	// Size the result from the lock-free counts first so the tree is copied without regrowing the slice.
+	// Routines spawned in between just grow it, ones that finished leave spare capacity.
+	var localManagers []*types.LocalManager
+	total := 0
 	for _, appManager := range appManagers {
-		LocalManagers := appManager.GetLocalManagers()
-		for _, localManager := range LocalManagers {
-			Routines := localManager.GetRoutines()
-			goroutines = append(goroutines, LocalHelper.NewLocalHelper().RoutinesMapToSlice(Routines)...)
+		for _, localManager := range appManager.GetLocalManagers() {
+			localManagers = append(localManagers, localManager)
+			total += localManager.GetRoutineCount()
 		}
 	}
+
+	// Get all goroutines from each local manager - would run on O(n*m)
+	goroutines := make([]*types.Routine, 0, total)
+	for _, localManager := range localManagers {
+		goroutines = localManager.AppendRoutines(goroutines)
+	}
 	return goroutines, nil
 }
 
@@ -202,6 +341,156 @@ func (GM *GlobalManagerStruct) GetGoroutineCount() int {
 	return i
 }
 
+// StateCounts returns how many routines are in each lifecycle state across the whole tree.
+// Every state is present in the map, empty states count 0.
+func (GM *GlobalManagerStruct) StateCounts() map[types.RoutineState]int {
+	globalManager, err := types.GetGlobalManager()
+	if err != nil {
+		return map[types.RoutineState]int{}
+	}
+	return globalManager.GetStateCounts()
+}
+
+// Stats returns a snapshot of the whole tree: app and local managers, live and peak routines, total spawned and uptime.
+// Returns zero stats before Init.
+func (GM *GlobalManagerStruct) Stats() types.ManagerStats {
+	globalManager, err := types.GetGlobalManager()
+	if err != nil {
+		return types.ManagerStats{}
+	}
+	AppManagers := globalManager.GetAppManagers()
+	stats := types.ManagerStats{
+		AppManagers:    len(AppManagers),
+		PeakGoroutines: globalManager.GetPeakRoutines(),
+		Uptime:         globalManager.GetUptime(),
+	}
+	for _, appManager := range AppManagers {
+		LocalManagers := appManager.GetLocalManagers()
+		stats.LocalManagers += len(LocalManagers)
+		for _, localManager := range LocalManagers {
+			stats.Goroutines += localManager.GetRoutineCount()
+			stats.TotalSpawned += localManager.GetTotalSpawned()
+		}
+	}
+	return stats
+}
+
+// ConsistentSnapshot counts app managers, local managers and routines across the tree at a single instant.
+// Unlike Stats, whose counts are read one manager at a time, its counts always add up.
+// It briefly locks the whole tree, see types.GlobalManager.ConsistentSnapshot for the lock order.
+func (GM *GlobalManagerStruct) ConsistentSnapshot() types.TreeSnapshot {
+	globalManager, err := types.GetGlobalManager()
+	if err != nil {
+		return types.TreeSnapshot{}
+	}
+	return globalManager.ConsistentSnapshot()
+}
+
+// Drain puts every local manager of every app in drain mode, see LocalManagerStruct.Drain.
+// Local managers created afterwards accept goroutines.
+func (GM *GlobalManagerStruct) Drain() error {
+	appManagers, err := GM.GetAllAppManagers()
+	if err != nil {
+		return err
+	}
+	for _, appManager := range appManagers {
+		for _, localManager := range appManager.LocalManagersSnapshot() {
+			localManager.SetDraining(true)
+		}
+	}
+	metrics.RecordManagerOperation("global", "drain", "")
+	return nil
+}
+
+// Snapshot returns every app, local manager and tracked routine, ready to be encoded as JSON for a dashboard.
+// Each manager is only read locked while it is copied, so unlike ConsistentSnapshot it doesn't hold up spawns
+// across the tree. Returns an empty snapshot before Init.
+//
+// Example:
+//
+//	http.HandleFunc("/routines", func(w http.ResponseWriter, r *http.Request) {
+//	    json.NewEncoder(w).Encode(globalMgr.Snapshot())
+//	})
+func (GM *GlobalManagerStruct) Snapshot() types.GlobalSnapshot {
+	globalManager, err := types.GetGlobalManager()
+	if err != nil {
+		return types.GlobalSnapshot{TakenAt: time.Now()}
+	}
+	return globalManager.Snapshot()
+}
+
+// SetMemoryBudget caps the total memory estimate of running routines across the whole tree, 0 means unlimited.
+// Only routines spawned with Local.WithMemoryEstimate count against it.
+func (GM *GlobalManagerStruct) SetMemoryBudget(bytes int64) error {
+	globalManager, err := types.GetGlobalManager()
+	if err != nil {
+		return err
+	}
+	return globalManager.SetMemoryBudget(bytes)
+}
+
+// GetMemoryInUse returns the memory estimate reserved by running routines across the whole tree
+func (GM *GlobalManagerStruct) GetMemoryInUse() int64 {
+	globalManager, err := types.GetGlobalManager()
+	if err != nil {
+		return 0
+	}
+	return globalManager.GetMemoryInUse()
+}
+
+// NewGlobalSemaphore registers a named semaphore with n slots, shared by workers of every app.
+// Workers take a slot before running when spawned with Local.WithGlobalSemaphore(name).
+func (GM *GlobalManagerStruct) NewGlobalSemaphore(name string, n int) error {
+	globalManager, err := types.GetGlobalManager()
+	if err != nil {
+		return err
+	}
+	_, err = globalManager.NewSemaphore(name, n)
+	return err
+}
+
+// SetSpawnQueueSize lets up to n spawns over the SET_MAX_ROUTINES limit wait for a routine slot instead of failing,
+// 0 (the default) rejects them with ErrMaxRoutinesExceeded. Queued spawns start as running routines finish,
+// highest Local.WithPriority first, and Go returns nil for them right away. Once the queue is full, spawns fail
+// with ErrSpawnQueueFull. A queued spawn isn't tracked, nor counted in wait groups, until it starts.
+// Shutdown drops the queued spawns and stops the queue for good.
+//
+// Example:
+//
+//	globalMgr.UpdateMetadata(Global.SET_MAX_ROUTINES, 100)
+//	globalMgr.SetSpawnQueueSize(1000)
+func (GM *GlobalManagerStruct) SetSpawnQueueSize(n int) error {
+	globalManager, err := types.GetGlobalManager()
+	if err != nil {
+		return err
+	}
+	return globalManager.SetSpawnQueueSize(n)
+}
+
+// GetSpawnQueueLength returns how many spawns wait in the spawn queue for a routine slot
+func (GM *GlobalManagerStruct) GetSpawnQueueLength() int {
+	globalManager, err := types.GetGlobalManager()
+	if err != nil {
+		return 0
+	}
+	return globalManager.GetSpawnQueueLength()
+}
+
+// Subscribe calls fn with every lifecycle event from now on: apps and local managers created,
+// routines started and completed, and shutdowns started, until the returned unsubscribe is called.
+// Events are delivered in order on a goroutine of the subscriber, a subscriber that falls more than
+// types.EventBufferSize events behind misses events instead of slowing down spawning.
+//
+// Example:
+//
+//	unsubscribe := globalMgr.Subscribe(func(e types.Event) {
+//		log.Printf("%s %s/%s/%s %s", e.Type, e.App, e.Local, e.Function, e.RoutineID)
+//	})
+//	defer unsubscribe()
+func (GM *GlobalManagerStruct) Subscribe(fn func(types.Event)) (unsubscribe func()) {
+	return types.Events().Subscribe(fn)
+}
+
 func (GM *GlobalManagerStruct) UpdateMetadata(flag string, value interface{}) (*types.Metadata, error) {
 	return GM.UpdateGlobalMetadata(flag, value)
 }
@@ -2,9 +2,11 @@ package Global
 
 import (
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/neerajchowdary889/GoRoutinesManager/Context"
+	"github.com/neerajchowdary889/GoRoutinesManager/Manager/Errors"
 	"github.com/neerajchowdary889/GoRoutinesManager/metrics"
 	"github.com/neerajchowdary889/GoRoutinesManager/types"
 )
@@ -14,8 +16,18 @@ const (
 	SET_SHUTDOWN_TIMEOUT = "SET_SHUTDOWN_TIMEOUT"
 	SET_MAX_ROUTINES     = "SET_MAX_ROUTINES"
 	SET_UPDATE_INTERVAL  = "SET_UPDATE_INTERVAL"
+	// Topology caps, 0 means unlimited
+	SET_MAX_APPS           = "SET_MAX_APPS"
+	SET_MAX_LOCALS_PER_APP = "SET_MAX_LOCALS_PER_APP"
+	// Times manager mutex acquisition into the lock wait histogram, off by default
+	SET_LOCK_INSTRUMENTATION = "SET_LOCK_INSTRUMENTATION"
+	// Default panic recovery for goroutines spawned without WithPanicRecovery, on by default
+	SET_PANIC_RECOVERY = "SET_PANIC_RECOVERY"
 )
 
+// MinUpdateInterval is the shortest accepted metrics update interval, anything below would spin the collector
+const MinUpdateInterval = time.Millisecond
+
 type metricsConfig struct {
 	Enabled  bool
 	URL      string
@@ -86,6 +98,9 @@ func (GM *GlobalManagerStruct) UpdateGlobalMetadata(flag string, value interface
 				if !ok1 || !ok2 || !ok3 {
 					return nil, errors.New("metrics: expected [bool, string, time.Duration] in slice")
 				}
+				if err := validateUpdateInterval(intervalVal); err != nil {
+					return nil, err
+				}
 				enabled = enabledVal
 				url = urlVal
 				interval = intervalVal
@@ -112,6 +127,9 @@ func (GM *GlobalManagerStruct) UpdateGlobalMetadata(flag string, value interface
 			if !ok1 || !ok2 || !ok3 {
 				return nil, errors.New("metrics: expected [bool, string, time.Duration] array")
 			}
+			if err := validateUpdateInterval(intervalVal); err != nil {
+				return nil, err
+			}
 			enabled = enabledVal
 			url = urlVal
 			interval = intervalVal
@@ -163,38 +181,109 @@ func (GM *GlobalManagerStruct) UpdateGlobalMetadata(flag string, value interface
 		}
 
 	case SET_SHUTDOWN_TIMEOUT:
+		var timeout time.Duration
 		switch t := value.(type) {
 		case time.Duration:
-			metadata.SetShutdownTimeout(t)
+			timeout = t
 		case *time.Duration:
-			metadata.SetShutdownTimeout(*t)
+			timeout = *t
 		default:
 			return nil, errors.New("shutdown timeout: expected time.Duration")
 		}
+		// A zero timeout would force-remove every routine without giving it a chance to stop
+		if timeout <= 0 {
+			return nil, fmt.Errorf("%w: shutdown timeout must be positive, got %v", Errors.ErrInvalidMetadata, timeout)
+		}
+		metadata.SetShutdownTimeout(timeout)
 
 	case SET_MAX_ROUTINES:
+		var limit int
 		switch n := value.(type) {
 		case int:
-			metadata.SetMaxRoutines(n)
+			limit = n
 		case int32:
-			metadata.SetMaxRoutines(int(n))
+			limit = int(n)
 		case int64:
-			metadata.SetMaxRoutines(int(n))
+			limit = int(n)
 		case *int:
-			metadata.SetMaxRoutines(*n)
+			limit = *n
 		default:
 			return nil, errors.New("max routines: expected integer type")
 		}
+		if err := validateLimit("max routines", limit); err != nil {
+			return nil, err
+		}
+		metadata.SetMaxRoutines(limit)
+		// A raised limit frees slots for the queued spawns
+		g.WakeSpawnQueue()
+
+	case SET_MAX_APPS:
+		var limit int
+		switch n := value.(type) {
+		case int:
+			limit = n
+		case int32:
+			limit = int(n)
+		case int64:
+			limit = int(n)
+		case *int:
+			limit = *n
+		default:
+			return nil, errors.New("max apps: expected integer type")
+		}
+		if err := validateLimit("max apps", limit); err != nil {
+			return nil, err
+		}
+		metadata.SetMaxApps(limit)
+
+	case SET_MAX_LOCALS_PER_APP:
+		var limit int
+		switch n := value.(type) {
+		case int:
+			limit = n
+		case int32:
+			limit = int(n)
+		case int64:
+			limit = int(n)
+		case *int:
+			limit = *n
+		default:
+			return nil, errors.New("max locals per app: expected integer type")
+		}
+		if err := validateLimit("max locals per app", limit); err != nil {
+			return nil, err
+		}
+		metadata.SetMaxLocalsPerApp(limit)
+
+	case SET_LOCK_INSTRUMENTATION:
+		enabled, ok := value.(bool)
+		if !ok {
+			return nil, errors.New("lock instrumentation: expected bool")
+		}
+		metadata.SetLockInstrumentation(enabled)
+		metrics.EnableLockInstrumentation(enabled)
+
+	case SET_PANIC_RECOVERY:
+		enabled, ok := value.(bool)
+		if !ok {
+			return nil, errors.New("panic recovery: expected bool")
+		}
+		metadata.SetPanicRecovery(enabled)
 
 	case SET_UPDATE_INTERVAL:
+		var interval time.Duration
 		switch t := value.(type) {
 		case time.Duration:
-			metadata.UpdateIntervalTime(t)
+			interval = t
 		case *time.Duration:
-			metadata.UpdateIntervalTime(*t)
+			interval = *t
 		default:
 			return nil, errors.New("update interval: expected time.Duration")
 		}
+		if err := validateUpdateInterval(interval); err != nil {
+			return nil, err
+		}
+		metadata.UpdateIntervalTime(interval)
 
 	default:
 		return nil, errors.New("unknown update flag")
@@ -203,6 +292,22 @@ func (GM *GlobalManagerStruct) UpdateGlobalMetadata(flag string, value interface
 	return metadata, nil
 }
 
+// validateLimit rejects negative limits, 0 means unlimited
+func validateLimit(label string, limit int) error {
+	if limit < 0 {
+		return fmt.Errorf("%w: %s can't be negative, got %d", Errors.ErrInvalidMetadata, label, limit)
+	}
+	return nil
+}
+
+// validateUpdateInterval rejects intervals shorter than MinUpdateInterval
+func validateUpdateInterval(interval time.Duration) error {
+	if interval < MinUpdateInterval {
+		return fmt.Errorf("%w: update interval must be at least %v, got %v", Errors.ErrInvalidMetadata, MinUpdateInterval, interval)
+	}
+	return nil
+}
+
 func (GM *GlobalManagerStruct) GetGlobalMetadata() (*types.Metadata, error) {
 	g, err := types.GetGlobalManager()
 	if err != nil {
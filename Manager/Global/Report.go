@@ -0,0 +1,157 @@
+package Global
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"runtime/pprof"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/neerajchowdary889/GoRoutinesManager/Manager/Local"
+	"github.com/neerajchowdary889/GoRoutinesManager/types"
+)
+
+// labelPair matches one "key":"value" pair of a goroutine profile label line
+var labelPair = regexp.MustCompile(`"((?:[^"\\]|\\.)*)":"((?:[^"\\]|\\.)*)"`)
+
+// reportKey groups goroutines by the manager's pprof labels
+type reportKey struct {
+	app, local, function string
+}
+
+// GoroutineReport returns a human-readable report of every goroutine in the process, read from the goroutine
+// profile and grouped by the pprof labels the manager sets on spawned goroutines.
+//
+// Each app/local/function lists the goroutines running with its labels next to the routines the manager tracks
+// for it. Goroutines started by a worker inherit its labels, so running can exceed tracked. Goroutines without
+// the labels are listed as untracked, grouped by their first frame outside the runtime, which exposes the ones
+// the manager isn't aware of.
+func (GM *GlobalManagerStruct) GoroutineReport() string {
+	var profile bytes.Buffer
+	if err := pprof.Lookup("goroutine").WriteTo(&profile, 1); err != nil {
+		return fmt.Sprintf("goroutine report unavailable: %v\n", err)
+	}
+
+	running := make(map[reportKey]int)
+	untracked := make(map[string]int)
+	total, labelled, untrackedTotal := 0, 0, 0
+	// Records are separated by blank lines, the profile header shares its block with the first record
+	for _, record := range bytes.Split(profile.Bytes(), []byte("\n\n")) {
+		count, labels, frame, ok := parseProfileRecord(record)
+		if !ok {
+			continue
+		}
+		total += count
+		if app, ok := labels[Local.Label_AppName]; ok {
+			running[reportKey{app, labels[Local.Label_LocalName], labels[Local.Label_FunctionName]}] += count
+			labelled += count
+			continue
+		}
+		untracked[frame] += count
+		untrackedTotal += count
+	}
+
+	tracked := make(map[reportKey]int)
+	if globalManager, err := types.GetGlobalManager(); err == nil {
+		for appName, appManager := range globalManager.GetAppManagers() {
+			for _, localManager := range appManager.LocalManagersSnapshot() {
+				for _, routine := range localManager.AppendRoutines(nil) {
+					tracked[reportKey{appName, localManager.LocalName, routine.GetFunctionName()}]++
+				}
+			}
+		}
+	}
+
+	keys := make([]reportKey, 0, len(running)+len(tracked))
+	for key := range running {
+		keys = append(keys, key)
+	}
+	for key := range tracked {
+		if _, ok := running[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].app != keys[j].app {
+			return keys[i].app < keys[j].app
+		}
+		if keys[i].local != keys[j].local {
+			return keys[i].local < keys[j].local
+		}
+		return keys[i].function < keys[j].function
+	})
+
+	var report strings.Builder
+	fmt.Fprintf(&report, "Goroutine report: %d goroutines, %d labelled by the manager, %d untracked\n", total, labelled, untrackedTotal)
+	group := ""
+	for _, key := range keys {
+		if name := key.app + "/" + key.local; name != group {
+			group = name
+			fmt.Fprintf(&report, "\n%s\n", group)
+		}
+		fmt.Fprintf(&report, "  %s: %d running, %d tracked\n", key.function, running[key], tracked[key])
+	}
+
+	frames := make([]string, 0, len(untracked))
+	for frame := range untracked {
+		frames = append(frames, frame)
+	}
+	sort.Slice(frames, func(i, j int) bool {
+		if untracked[frames[i]] != untracked[frames[j]] {
+			return untracked[frames[i]] > untracked[frames[j]]
+		}
+		return frames[i] < frames[j]
+	})
+	fmt.Fprintf(&report, "\nUntracked: %d\n", untrackedTotal)
+	for _, frame := range frames {
+		fmt.Fprintf(&report, "  %d @ %s\n", untracked[frame], frame)
+	}
+	return report.String()
+}
+
+// parseProfileRecord reads one record of a debug=1 goroutine profile: the number of goroutines sharing the stack,
+// their pprof labels and the first frame outside the runtime. Lines before the "count @ pcs" line, like the
+// profile header, are skipped. ok is false for malformed records.
+func parseProfileRecord(record []byte) (count int, labels map[string]string, frame string, ok bool) {
+	lines := strings.Split(strings.TrimSpace(string(record)), "\n")
+	for len(lines) > 0 && !strings.Contains(lines[0], " @ ") {
+		lines = lines[1:]
+	}
+	if len(lines) == 0 {
+		return 0, nil, "", false
+	}
+	countField, _, _ := strings.Cut(lines[0], " @ ")
+	count, err := strconv.Atoi(countField)
+	if err != nil {
+		return 0, nil, "", false
+	}
+
+	labels = make(map[string]string)
+	for _, line := range lines[1:] {
+		if labelLine, isLabels := strings.CutPrefix(line, "# labels: "); isLabels {
+			for _, pair := range labelPair.FindAllStringSubmatch(labelLine, -1) {
+				key, errKey := strconv.Unquote(`"` + pair[1] + `"`)
+				value, errValue := strconv.Unquote(`"` + pair[2] + `"`)
+				if errKey == nil && errValue == nil {
+					labels[key] = value
+				}
+			}
+			continue
+		}
+		// Frames look like "#\t0x47a1c4\tpkg.function+0x24\t/path/file.go:12"
+		fields := strings.Split(line, "\t")
+		if len(fields) < 3 || fields[0] != "#" {
+			continue
+		}
+		function, _, _ := strings.Cut(fields[2], "+0x")
+		if frame == "" && !strings.HasPrefix(function, "runtime.") {
+			frame = function
+		}
+	}
+	if frame == "" {
+		frame = "unknown"
+	}
+	return count, labels, frame, true
+}
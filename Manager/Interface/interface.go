@@ -2,6 +2,8 @@ package Interface
 
 import (
 	"context"
+	"io"
+	"net/http"
 	"sync"
 	"time"
 
@@ -23,6 +25,31 @@ type Shutdowner interface {
 	Shutdown(safe bool) error
 }
 
+// Runner blocks until shutdown is requested, then shuts down
+type Runner interface {
+	Run(ctx context.Context) error
+}
+
+// ProgressShutdowner shuts down while streaming the progress
+type ProgressShutdowner interface {
+	ShutdownWithProgress(safe bool) (<-chan types.ShutdownProgress, error)
+}
+
+// RoutineSnapshotter lists every routine of the tree in a serializable snapshot
+type RoutineSnapshotter interface {
+	Snapshot() types.GlobalSnapshot
+}
+
+// GoroutineReporter reports the process's goroutines grouped by the manager's pprof labels
+type GoroutineReporter interface {
+	GoroutineReport() string
+}
+
+// TreeSnapshotter reads the counts of the whole tree at a single instant
+type TreeSnapshotter interface {
+	ConsistentSnapshot() types.TreeSnapshot
+}
+
 // MetadataManager handles metadata of the Global manager
 type MetadataManager interface {
 	// NewMetadata() *types.Metadata
@@ -36,6 +63,23 @@ type GoroutineSpawner interface {
 	// Options can be provided for timeout, panic recovery, and wait group management.
 	// The Local package provides WithTimeout, WithPanicRecovery, and AddToWaitGroup option functions.
 	Go(functionName string, workerFunc func(ctx context.Context) error, opts ...GoroutineOption) error
+	// GoWithContext spawns like Go with a context derived from parent, keeping its values
+	GoWithContext(parent context.Context, functionName string, workerFunc func(ctx context.Context) error, opts ...GoroutineOption) error
+	// TryGo spawns like Go only if every limiter admits it right away, returning false when one has nothing free
+	TryGo(functionName string, workerFunc func(ctx context.Context) error, opts ...GoroutineOption) (bool, error)
+}
+
+// ResultSpawner spawns goroutines whose worker result is kept on the routine
+type ResultSpawner interface {
+	GoWithResult(functionName string, workerFunc func(ctx context.Context) (interface{}, error), opts ...GoroutineOption) (string, error)
+	GetRoutineResult(routineID string) (interface{}, error, bool)
+}
+
+// BatchSpawner runs a batch of workers concurrently and collects their results
+type BatchSpawner interface {
+	GoWait(functionName string, workers []func(ctx context.Context) error, timeout time.Duration) []error
+	GoN(n int, functionName string, workerFunc func(ctx context.Context, index int) error, opts ...GoroutineOption) ([]string, error)
+	Map(functionName string, inputs []interface{}, fn func(ctx context.Context, in interface{}) (interface{}, error), opts ...GoroutineOption) ([]interface{}, []error)
 }
 
 // FunctionShutdowner handles shutdown of specific functions
@@ -60,6 +104,98 @@ type LocalManagerGetter interface {
 	GetLocalManagerByName(localName string) (*types.LocalManager, error)
 }
 
+// AppManagerGetter gets an app manager by name
+type AppManagerGetter interface {
+	GetAppManagerByName(appName string) (AppGoroutineManagerInterface, error)
+}
+
+// AppRoutineFinder finds routines across all local managers of an app
+type AppRoutineFinder interface {
+	GetRoutinesByFunctionName(functionName string) ([]types.RoutineRef, error)
+}
+
+// AppMetricsToggler enables or disables metrics for a single app
+type AppMetricsToggler interface {
+	SetMetricsEnabled(enabled bool) error
+}
+
+// HTTPServerManager runs HTTP servers whose lifetime is tied to the manager
+type HTTPServerManager interface {
+	ManageHTTPServer(localName, functionName string, srv *http.Server, drainTimeout time.Duration) error
+}
+
+// LocalNamer creates local managers under generated names
+type LocalNamer interface {
+	NextLocal(prefix string) (LocalGoroutineManagerInterface, error)
+}
+
+// StateCounter breaks down routines by lifecycle state
+type StateCounter interface {
+	StateCounts() map[types.RoutineState]int
+}
+
+// RoutineReparenter moves the cancellation of live routines to another parent context
+type RoutineReparenter interface {
+	ReparentRoutines(newParent context.Context) error
+}
+
+// ContextCanceller signals every routine of a manager to stop without shutting the manager down
+type ContextCanceller interface {
+	CancelContext() error
+}
+
+// RoutineLogReader reads the lines a routine logged with Local.Logf
+type RoutineLogReader interface {
+	GetRoutineLogs(routineID string) ([]string, error)
+}
+
+// RestartCounter reads how many times a supervised routine was restarted
+type RestartCounter interface {
+	GetRoutineRestartCount(routineID string) int
+}
+
+// ContextMisuseReporter lists routines that don't respond to cancellation
+type ContextMisuseReporter interface {
+	GetContextIgnoringRoutines() ([]*types.Routine, error)
+}
+
+// StatsReader returns a snapshot of a manager level in one call
+type StatsReader interface {
+	Stats() types.ManagerStats
+}
+
+// FunctionWeighter weights functions competing for the slots of a shared semaphore
+type FunctionWeighter interface {
+	SetFunctionWeight(functionName string, weight int) error
+}
+
+// MemoryBudgeter caps the memory estimate of running routines
+type MemoryBudgeter interface {
+	SetMemoryBudget(bytes int64) error
+	GetMemoryInUse() int64
+}
+
+// ShutdownNotifier signals when the whole tree is shutting down
+type ShutdownNotifier interface {
+	Done() <-chan struct{}
+}
+
+// SemaphoreCreator registers process-wide semaphores shared across apps
+type SemaphoreCreator interface {
+	NewGlobalSemaphore(name string, n int) error
+}
+
+// SpawnQueuer queues spawns over the routine limit instead of rejecting them
+type SpawnQueuer interface {
+	SetSpawnQueueSize(n int) error
+	GetSpawnQueueLength() int
+}
+
+// EventSubscriber subscribes to the lifecycle events of the managers
+type EventSubscriber interface {
+	Subscribe(fn func(types.Event)) (unsubscribe func())
+}
+
 // AppManagerLister lists all app managers
 type AppManagerLister interface {
 	GetAllAppManagers() ([]*types.AppManager, error)
@@ -74,6 +210,7 @@ type AppManagerCreator interface {
 // LocalManagerCreator creates new local managers
 type LocalManagerCreator interface {
 	CreateLocal(localName string) (*types.LocalManager, error)
+	CreateLocalWithContext(localName string) (*types.LocalManager, context.Context, error)
 }
 
 type FunctionWaitGroupCreator interface {
@@ -84,9 +221,69 @@ type FunctionWaitGroupCreator interface {
 type FunctionWaitGroupManager interface {
 	WaitForFunction(functionName string) error
 	WaitForFunctionWithTimeout(functionName string, timeout time.Duration) bool
+	WaitForFunctionStarted(functionName string, n int) error
+	WaitForFunctionStartedWithTimeout(functionName string, n int, timeout time.Duration) bool
 	GetFunctionGoroutineCount(functionName string) int
 }
 
+// Drainer stops a manager and its descendants from accepting new goroutines
+type Drainer interface {
+	Drain() error
+}
+
+// DrainChecker reports whether a local manager is draining
+type DrainChecker interface {
+	IsDraining() bool
+}
+
+// FunctionPauser pauses and resumes the goroutines of a function cooperatively
+type FunctionPauser interface {
+	Pause(functionName string) error
+	Resume(functionName string) error
+	IsFunctionPaused(functionName string) bool
+}
+
+// OrderedShutdowner shuts apps down one after the other in a given order
+type OrderedShutdowner interface {
+	ShutdownOrdered(safe bool, order []string) error
+}
+
+// AllWaiter waits for every goroutine of a local or app manager
+type AllWaiter interface {
+	WaitForAll() error
+	WaitForAllWithTimeout(timeout time.Duration) bool
+}
+
+// FunctionWaitGroupLister lists function wait groups with their pending counts
+type FunctionWaitGroupLister interface {
+	GetFunctionWaitGroups() []types.FunctionWaitGroupInfo
+}
+
+// ReconnectingSpawner spawns routines that keep a connection alive across failures
+type ReconnectingSpawner interface {
+	GoReconnecting(functionName string, connect func(ctx context.Context) (io.Closer, error), run func(ctx context.Context, conn io.Closer) error, cfg types.ReconnectConfig, opts ...GoroutineOption) error
+}
+
+// ScheduledSpawner spawns routines that run a function periodically
+type ScheduledSpawner interface {
+	GoEvery(functionName string, cfg types.ScheduleConfig, fn func(ctx context.Context) error, opts ...GoroutineOption) error
+}
+
+// FunctionStatsReader reports aggregate stats for a function
+type FunctionStatsReader interface {
+	GetFunctionStats(functionName string) types.FunctionStats
+}
+
+// ErrorRateWatcher alerts when a function's error rate goes above a threshold
+type ErrorRateWatcher interface {
+	SetErrorRateThreshold(functionName string, ratio float64, window time.Duration, onBreach func(functionName string, rate float64)) error
+}
+
+// StackDumper captures goroutine stacks for debugging
+type StackDumper interface {
+	DumpFunctionStacks(functionName string) ([]byte, error)
+}
+
 // RoutineManager defines methods for managing individual routines
 type RoutineManager interface {
 	CancelRoutine(routineID string) error
@@ -98,6 +295,7 @@ type RoutineManager interface {
 	IsRoutineContextCancelled(routineID string) bool
 	GetRoutine(routineID string) (*types.Routine, error)
 	GetRoutinesByFunctionName(functionName string) ([]*types.Routine, error)
+	GetRoutinesByLabel(key, value string) ([]*types.Routine, error)
 }
 
 // ----------------------
@@ -108,14 +306,36 @@ type RoutineManager interface {
 type GlobalGoroutineManagerInterface interface {
 	GlobalInitializer
 	Shutdowner
+	ProgressShutdowner
+	OrderedShutdowner
+	Runner
 
 	MetadataManager
 
 	AppManagerLister
+	AppManagerGetter
 
 	LocalManagerLister
 
 	GoroutineLister
+
+	StateCounter
+
+	SemaphoreCreator
+	SpawnQueuer
+
+	EventSubscriber
+
+	ShutdownNotifier
+
+	MemoryBudgeter
+
+	StatsReader
+	TreeSnapshotter
+	RoutineSnapshotter
+	GoroutineReporter
+
+	Drainer
 }
 
 // AppGoroutineManagerInterface defines the complete interface for app manager
@@ -129,6 +349,21 @@ type AppGoroutineManagerInterface interface {
 	GoroutineLister
 
 	LocalManagerGetter
+
+	AppRoutineFinder
+
+	HTTPServerManager
+
+	AppMetricsToggler
+
+	LocalNamer
+
+	StatsReader
+
+	ContextCanceller
+
+	AllWaiter
+	Drainer
 }
 
 // LocalGoroutineManagerInterface defines the complete interface for local manager
@@ -139,10 +374,31 @@ type LocalGoroutineManagerInterface interface {
 	LocalManagerCreator
 
 	GoroutineSpawner
+	ResultSpawner
+	BatchSpawner
+	ReconnectingSpawner
+	ScheduledSpawner
 
 	RoutineManager
 
 	GoroutineLister
 	FunctionWaitGroupCreator
 	FunctionWaitGroupManager
+	AllWaiter
+	Drainer
+	DrainChecker
+	FunctionPauser
+	FunctionWaitGroupLister
+	FunctionStatsReader
+	ErrorRateWatcher
+	MemoryBudgeter
+	FunctionWeighter
+	RoutineReparenter
+	StatsReader
+	ContextCanceller
+	RoutineLogReader
+	ContextMisuseReporter
+	RestartCounter
+
+	StackDumper
 }
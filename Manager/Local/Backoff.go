@@ -0,0 +1,51 @@
+package Local
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// backoffRand draws the retry jitter, SeedBackoff makes it reproducible
+var (
+	backoffMu   sync.Mutex
+	backoffRand = rand.New(rand.NewSource(time.Now().UnixNano()))
+)
+
+// SeedBackoff reseeds the random source of the backoff jitter, so ComputeBackoff returns the same
+// sequence after the same seed. Meant for tests.
+func SeedBackoff(seed int64) {
+	backoffMu.Lock()
+	defer backoffMu.Unlock()
+	backoffRand = rand.New(rand.NewSource(seed))
+}
+
+// ComputeBackoff returns the delay before retry number attempt, counting from 0:
+// min(max, initial * 2^attempt), moved by a uniform random fraction in [-jitter, +jitter] of itself.
+// jitter is clamped to [0, 1], 0 gives the exact exponential sequence.
+func ComputeBackoff(attempt int, initial, max time.Duration, jitter float64) time.Duration {
+	if initial <= 0 {
+		return 0
+	}
+	if max < initial {
+		max = initial
+	}
+	delay := initial
+	for i := 0; i < attempt && delay < max; i++ {
+		delay *= 2
+	}
+	if delay > max {
+		delay = max
+	}
+
+	if jitter <= 0 {
+		return delay
+	}
+	if jitter > 1 {
+		jitter = 1
+	}
+	backoffMu.Lock()
+	r := backoffRand.Float64()
+	backoffMu.Unlock()
+	return time.Duration(float64(delay) * (1 + jitter*(2*r-1)))
+}
@@ -0,0 +1,66 @@
+package Local
+
+import (
+	"context"
+	"time"
+)
+
+// ShouldStop reports whether the worker's context was cancelled or timed out.
+// It never blocks, so it can be called on every iteration of a tight loop.
+//
+// Example:
+//
+//	localMgr.Go("scanner", func(ctx context.Context) error {
+//	    for !Local.ShouldStop(ctx) {
+//	        scanNext()
+//	    }
+//	    return nil
+//	})
+func ShouldStop(ctx context.Context) bool {
+	return ctx.Err() != nil
+}
+
+// CheckStop returns ctx.Err() once the worker's context is cancelled or timed out, nil before.
+// It replaces the select on ctx.Done() with a default case.
+//
+// Example:
+//
+//	localMgr.Go("importer", func(ctx context.Context) error {
+//	    for _, record := range records {
+//	        if err := Local.CheckStop(ctx); err != nil {
+//	            return err
+//	        }
+//	        importRecord(record)
+//	    }
+//	    return nil
+//	})
+func CheckStop(ctx context.Context) error {
+	return ctx.Err()
+}
+
+// Sleep pauses the worker for d, returning ctx.Err() early if the context is cancelled meanwhile.
+// Use it instead of time.Sleep so a sleeping worker doesn't hold up shutdown. Returns nil after a full sleep.
+//
+// Example:
+//
+//	localMgr.Go("poller", func(ctx context.Context) error {
+//	    for {
+//	        poll()
+//	        if err := Local.Sleep(ctx, 5*time.Second); err != nil {
+//	            return err
+//	        }
+//	    }
+//	})
+func Sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
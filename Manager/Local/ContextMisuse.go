@@ -0,0 +1,66 @@
+package Local
+
+import (
+	"context"
+
+	"github.com/neerajchowdary889/GoRoutinesManager/Logging"
+	"github.com/neerajchowdary889/GoRoutinesManager/metrics"
+	"github.com/neerajchowdary889/GoRoutinesManager/types"
+)
+
+// observedContext is handed to workers while types.DetectIgnoredContexts is on.
+// It records on the routine that the worker called Done, Err or Value; lookups of the routine itself
+// by the package helpers don't count.
+type observedContext struct {
+	context.Context
+	routine *types.Routine
+}
+
+func (c *observedContext) Done() <-chan struct{} {
+	c.routine.MarkContextObserved()
+	return c.Context.Done()
+}
+
+func (c *observedContext) Err() error {
+	c.routine.MarkContextObserved()
+	return c.Context.Err()
+}
+
+func (c *observedContext) Value(key any) any {
+	if key != (routineContextKey{}) {
+		c.routine.MarkContextObserved()
+	}
+	return c.Context.Value(key)
+}
+
+// observeContext wraps the worker's context and flags the routine if it is cancelled while the worker
+// is running without having looked at its context. Called right before the worker starts.
+func (LM *LocalManagerStruct) observeContext(routineCtx context.Context, routine *types.Routine) context.Context {
+	if routineCtx.Err() != nil {
+		// Already cancelled, the worker never had a chance to look
+		return routineCtx
+	}
+	context.AfterFunc(routineCtx, func() {
+		if routine.GetState() != types.RoutineStateCancelling || routine.ContextObserved() {
+			return
+		}
+		if !routine.MarkIgnoringContext() {
+			return
+		}
+		metrics.RecordContextIgnored(LM.AppName, LM.LocalName, routine.GetFunctionName())
+		Logging.Warn("goroutine was cancelled without ever looking at its context, it won't respond to cancellation",
+			"routine", routine.GetID(), "function", routine.GetFunctionName())
+	})
+	return &observedContext{Context: routineCtx, routine: routine}
+}
+
+// GetContextIgnoringRoutines returns the tracked routines that were cancelled while running
+// without ever calling Done, Err or Value on their context. Only routines spawned while
+// types.DetectIgnoredContexts is on are checked.
+func (LM *LocalManagerStruct) GetContextIgnoringRoutines() ([]*types.Routine, error) {
+	localManager, err := types.GetLocalManager(LM.AppName, LM.LocalName)
+	if err != nil {
+		return nil, err
+	}
+	return localManager.GetContextIgnoringRoutines(), nil
+}
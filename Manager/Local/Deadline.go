@@ -0,0 +1,68 @@
+package Local
+
+import (
+	"context"
+	"time"
+
+	"github.com/neerajchowdary889/GoRoutinesManager/types"
+)
+
+// routineContextKey is used to find the owning routine from a worker's context
+type routineContextKey struct{}
+
+// routineContext is the context handed to workers.
+// A context's deadline is fixed when it is created, but the shutdown deadline is only known later,
+// so Deadline is resolved on every call against the routine's announced shutdown deadline.
+// Done and Err are untouched: the deadline is advisory, the shutdown itself cancels the context.
+type routineContext struct {
+	context.Context
+	routine *types.Routine
+}
+
+func newRoutineContext(parent context.Context, routine *types.Routine) context.Context {
+	return &routineContext{Context: parent, routine: routine}
+}
+
+// Deadline returns the earlier of the parent's deadline and the shutdown deadline
+func (c *routineContext) Deadline() (time.Time, bool) {
+	deadline, ok := c.Context.Deadline()
+	if shutdown, set := c.routine.GetShutdownDeadline(); set && (!ok || shutdown.Before(deadline)) {
+		return shutdown, true
+	}
+	return deadline, ok
+}
+
+func (c *routineContext) Value(key any) any {
+	if key == (routineContextKey{}) {
+		return c.routine
+	}
+	return c.Context.Value(key)
+}
+
+// ShutdownDeadline returns the time by which the worker owning ctx has to finish once shutdown began.
+// ok is false while no shutdown is in progress or if ctx wasn't handed out by Go.
+// Contexts derived from the worker's context resolve to the same routine.
+//
+// Example:
+//
+//	localMgr.Go("flusher", func(ctx context.Context) error {
+//	    <-ctx.Done()
+//	    if deadline, ok := Local.ShutdownDeadline(ctx); ok {
+//	        flushWithin(time.Until(deadline))
+//	    }
+//	    return nil
+//	})
+func ShutdownDeadline(ctx context.Context) (time.Time, bool) {
+	routine, ok := ctx.Value(routineContextKey{}).(*types.Routine)
+	if !ok {
+		return time.Time{}, false
+	}
+	return routine.GetShutdownDeadline()
+}
+
+// announceShutdownDeadline hands the shutdown budget to every given routine before they are cancelled
+func announceShutdownDeadline(routines []*types.Routine, deadline time.Time) {
+	for _, routine := range routines {
+		routine.SetShutdownDeadline(deadline)
+	}
+}
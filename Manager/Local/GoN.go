@@ -0,0 +1,68 @@
+package Local
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/neerajchowdary889/GoRoutinesManager/Manager/Interface"
+	"github.com/neerajchowdary889/GoRoutinesManager/types"
+)
+
+// GoN spawns n routines of functionName running workerFunc, each given its index from 0 to n-1,
+// and returns their routine IDs in index order. The options apply to every routine.
+// The local wait group is added to once for the whole batch. If a spawn fails part way, e.g. over
+// SET_MAX_ROUTINES, the routines already spawned are cancelled and the error tells how many were.
+// GoN spawns are never queued in the spawn queue, the IDs have to exist when it returns.
+//
+// Example:
+//
+//	ids, err := localMgr.GoN(8, "shard", func(ctx context.Context, index int) error {
+//	    return processShard(ctx, index)
+//	}, AddToWaitGroup("shard"))
+func (LM *LocalManagerStruct) GoN(n int, functionName string, workerFunc func(ctx context.Context, index int) error, opts ...Interface.GoroutineOption) ([]string, error) {
+	if n <= 0 {
+		return []string{}, nil
+	}
+	localManager, err := types.GetLocalManager(LM.AppName, LM.LocalName)
+	if err != nil {
+		return nil, LM.notCreatedError(err)
+	}
+
+	options := defaultGoroutineOptions()
+	for _, opt := range opts {
+		if localOpt, ok := opt.(Option); ok {
+			localOpt(options)
+		}
+	}
+	options.noQueue = true
+	wg := localManager.Wg
+	if wg != nil {
+		wg.Add(n)
+		options.wgReserved = true
+	}
+
+	ids := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		index := i
+		id, err := LM.spawnGoroutine(functionName, func(ctx context.Context) error {
+			return workerFunc(ctx, index)
+		}, options)
+		if err != nil {
+			// The slots of this spawn and the ones never attempted were added up front
+			if wg != nil {
+				wg.Add(-(n - i))
+			}
+			for _, spawned := range ids {
+				// Routines that already finished are no longer found, nothing to cancel for them
+				if routine, err := localManager.GetRoutine(spawned); err == nil {
+					if cancel := routine.GetCancel(); cancel != nil {
+						cancel()
+					}
+				}
+			}
+			return nil, fmt.Errorf("GoN %s: spawn %d of %d failed, cancelled the %d already spawned: %w", functionName, i+1, n, len(ids), err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
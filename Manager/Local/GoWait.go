@@ -0,0 +1,91 @@
+package Local
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/neerajchowdary889/GoRoutinesManager/Manager/Errors"
+	"github.com/neerajchowdary889/GoRoutinesManager/Manager/Interface"
+)
+
+// GoWait runs the workers concurrently as routines of functionName and waits for all of them,
+// returning their errors in the order of workers.
+//
+// With a positive timeout every worker gets it as its context timeout and GoWait stops waiting
+// once it has passed: workers still running at that point report context.DeadlineExceeded.
+// A timeout of 0 or less waits for the workers without limit.
+// A worker that panics reports Errors.ErrWorkerPanicked, one that couldn't be spawned its spawn error.
+//
+// Example:
+//
+//	errs := localMgr.GoWait("fetch", []func(ctx context.Context) error{fetchUsers, fetchOrders}, 5*time.Second)
+//	for i, err := range errs { ... }
+func (LM *LocalManagerStruct) GoWait(functionName string, workers []func(ctx context.Context) error, timeout time.Duration) []error {
+	var mu sync.Mutex
+	errs := make([]error, len(workers))
+	finished := make([]bool, len(workers))
+	finish := func(i int, err error) {
+		mu.Lock()
+		errs[i] = err
+		finished[i] = true
+		mu.Unlock()
+	}
+
+	var opts []Interface.GoroutineOption
+	if timeout > 0 {
+		opts = append(opts, WithTimeout(timeout))
+	}
+
+	var wg sync.WaitGroup
+	for i, worker := range workers {
+		wg.Add(1)
+		err := LM.Go(functionName, func(ctx context.Context) (err error) {
+			// Panics are left to the routine's own recovery, only the outcome is recorded here
+			panicked := true
+			defer func() {
+				if panicked {
+					err = Errors.ErrWorkerPanicked
+				}
+				finish(i, err)
+				wg.Done()
+			}()
+			err = worker(ctx)
+			panicked = false
+			return err
+		}, opts...)
+		if err != nil {
+			finish(i, err)
+			wg.Done()
+		}
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		select {
+		case <-done:
+		case <-timer.C:
+		}
+	} else {
+		<-done
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	result := make([]error, len(workers))
+	for i := range workers {
+		if finished[i] {
+			result[i] = errs[i]
+		} else {
+			result[i] = context.DeadlineExceeded
+		}
+	}
+	return result
+}
@@ -2,10 +2,19 @@ package Local
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"path"
+	"reflect"
+	"runtime"
+	"runtime/debug"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/neerajchowdary889/GoRoutinesManager/Context"
+	"github.com/neerajchowdary889/GoRoutinesManager/Logging"
 	"github.com/neerajchowdary889/GoRoutinesManager/Manager/Interface"
 	"github.com/neerajchowdary889/GoRoutinesManager/metrics"
 	"github.com/neerajchowdary889/GoRoutinesManager/types"
@@ -48,18 +57,56 @@ func (LM *LocalManagerStruct) CreateLocal(localName string) (*types.LocalManager
 	case Errors.WrngLocalManagerAlreadyExists:
 		// Return the existing local manager and also return error as nil
 		return localManager, nil
+	case nil:
+		// Freshly created, already fully initialized before it was published
 	default:
-		// Fill the structs
-		localManager.SetLocalContext().
-			SetLocalMutex().
-			SetLocalWaitGroup()
-		// Record operation
-		metrics.RecordManagerOperation("local", "create", LM.AppName)
+		if errors.Is(err, Errors.ErrMaxLocalsExceeded) {
+			metrics.RecordOperationError("manager", "create_local", "max_locals_exceeded")
+		}
+		return nil, err
 	}
+
+	// Record operation
+	metrics.RecordManagerOperation("local", "create", LM.AppName)
+	types.PublishEvent(types.EventLocalCreated, LM.AppName, localName, "", "", nil)
+
 	return localManager, nil
 }
 
+// CreateLocalWithContext creates (or fetches) the local manager and returns its context alongside it.
+// This saves callers a separate GetLocalContext call right after creation.
+func (LM *LocalManagerStruct) CreateLocalWithContext(localName string) (*types.LocalManager, context.Context, error) {
+	localManager, err := LM.CreateLocal(localName)
+	if err != nil {
+		return nil, nil, err
+	}
+	ctx, _ := localManager.GetLocalContext()
+	return localManager, ctx, nil
+}
+
+// Rename re-keys the local manager from oldName to newName within its app while its routines keep running.
+// The local context is moved to the new key and its metric series are migrated to the new label.
+// Handles created with the old name stop resolving, create new ones with NewLocalManager(appName, newName).
+// Returns ErrLocalManagerExists if newName is already taken in the app.
+func Rename(appName, oldName, newName string) error {
+	appManager, err := types.GetAppManager(appName)
+	if err != nil {
+		return err
+	}
+	if err := appManager.RenameLocalManager(oldName, newName); err != nil {
+		metrics.RecordOperationError("manager", "rename_local", "rename_failed")
+		return err
+	}
+
+	Context.RenameAppContext(types.LocalContextKey(appName, oldName), types.LocalContextKey(appName, newName))
+	metrics.RenameLocalSeries(appName, oldName, newName)
+	metrics.RecordManagerOperation("local", "rename", appName)
+	return nil
+}
+
 // Shutdowner
+// A safe shutdown that has to force cancel goroutines still running after the shutdown timeout
+// returns a *types.ShutdownError listing their functions.
 func (LM *LocalManagerStruct) Shutdown(safe bool) error {
 	startTime := time.Now()
 	shutdownType := "unsafe"
@@ -70,6 +117,9 @@ func (LM *LocalManagerStruct) Shutdown(safe bool) error {
 	defer func() {
 		duration := time.Since(startTime)
 		metrics.RecordShutdownDuration("local", shutdownType, duration, LM.AppName, LM.LocalName)
+
+		// Flush so the final state is visible right away instead of on the next collector tick
+		metrics.FlushNow()
 	}()
 
 	localManager, err := types.GetLocalManager(LM.AppName, LM.LocalName)
@@ -80,6 +130,13 @@ func (LM *LocalManagerStruct) Shutdown(safe bool) error {
 
 	// Record shutdown operation
 	metrics.RecordManagerOperation("local", "shutdown", LM.AppName)
+	// The local no longer keeps its app's context alive once it is shut down
+	defer localManager.ReleaseAppContext()
+
+	types.FireShutdownStage(types.ShutdownStageBeginDrain, LM.AppName, LM.LocalName, "")
+	// Registered before the cleanup defers so it fires after them
+	defer types.FireShutdownStage(types.ShutdownStageDone, LM.AppName, LM.LocalName, "")
+	defer metrics.RecordLocalDraining(LM.AppName)()
 
 	// Track all function names for cleanup
 	var functionNames map[string]bool
@@ -125,10 +182,16 @@ func (LM *LocalManagerStruct) Shutdown(safe bool) error {
 
 		// Step 2: Try to shutdown each function gracefully with timeout
 		shutdownTimeout := types.ShutdownTimeout
+		// Let workers know how long they have before they are force cancelled
+		announceShutdownDeadline(routines, startTime.Add(shutdownTimeout))
+		var stuck []types.StuckFunction
 		for functionName := range functionNames {
 			// Try graceful shutdown with timeout
-			_ = LM.ShutdownFunction(functionName, shutdownTimeout)
 			// Note: ShutdownFunction handles cleanup on success, but we'll clean up all in defer
+			var shutdownErr *types.ShutdownError
+			if err := LM.ShutdownFunction(functionName, shutdownTimeout); errors.As(err, &shutdownErr) {
+				stuck = append(stuck, shutdownErr.Functions...)
+			}
 		}
 
 		// Step 3: Wait for main wait group with timeout
@@ -146,7 +209,8 @@ func (LM *LocalManagerStruct) Shutdown(safe bool) error {
 		case <-done:
 			// All goroutines completed gracefully
 			// Cleanup will happen in defer
-			return nil
+			types.FireShutdownStage(types.ShutdownStageGracefulComplete, LM.AppName, LM.LocalName, "")
+			return shutdownError(stuck)
 		case <-time.After(shutdownTimeout):
 			// Timeout - some goroutines are still hanging
 			// Fall through to force cancel
@@ -157,6 +221,8 @@ func (LM *LocalManagerStruct) Shutdown(safe bool) error {
 		if err == nil {
 			// Record remaining goroutines after timeout
 			metrics.RecordShutdownGoroutinesRemaining("local", LM.AppName, LM.LocalName, len(remainingRoutines))
+			stuck = append(stuck, LM.stuckFunctions(remainingRoutines)...)
+			types.FireShutdownStage(types.ShutdownStageCancel, LM.AppName, LM.LocalName, "")
 			for _, routine := range remainingRoutines {
 				cancel := routine.GetCancel()
 				if cancel != nil {
@@ -164,13 +230,17 @@ func (LM *LocalManagerStruct) Shutdown(safe bool) error {
 				}
 				// Remove routine from map to prevent memory leak
 				localManager.RemoveRoutine(routine, false)
+				// Release its wait group slots now, its own completion later is then a no-op
+				routine.ReleaseWaitGroups()
 			}
+			types.FireShutdownStage(types.ShutdownStageForceRemove, LM.AppName, LM.LocalName, "")
 		}
 
 		// Cancel the local manager's context
 		if localManager.Cancel != nil {
 			localManager.Cancel()
 		}
+		return shutdownError(stuck)
 
 	} else {
 		// Unsafe shutdown: cancel all contexts immediately
@@ -188,6 +258,7 @@ func (LM *LocalManagerStruct) Shutdown(safe bool) error {
 		}
 
 		// Cancel all routine contexts and remove from map
+		types.FireShutdownStage(types.ShutdownStageCancel, LM.AppName, LM.LocalName, "")
 		for _, routine := range routines {
 			cancel := routine.GetCancel()
 			if cancel != nil {
@@ -195,7 +266,10 @@ func (LM *LocalManagerStruct) Shutdown(safe bool) error {
 			}
 			// Remove routine from map to prevent memory leak
 			localManager.RemoveRoutine(routine, false)
+			// Release its wait group slots now, its own completion later is then a no-op
+			routine.ReleaseWaitGroups()
 		}
+		types.FireShutdownStage(types.ShutdownStageForceRemove, LM.AppName, LM.LocalName, "")
 
 		// Cancel the local manager's context
 		if localManager.Cancel != nil {
@@ -206,6 +280,38 @@ func (LM *LocalManagerStruct) Shutdown(safe bool) error {
 	return nil
 }
 
+// stuckFunctions groups the routines still running after the shutdown timeout by function,
+// routines that already finished and are only waiting to be untracked aren't counted
+func (LM *LocalManagerStruct) stuckFunctions(routines []*types.Routine) []types.StuckFunction {
+	counts := make(map[string]int)
+	for _, routine := range routines {
+		select {
+		case <-routine.DoneChan():
+		default:
+			counts[routine.GetFunctionName()]++
+		}
+	}
+	functions := make([]types.StuckFunction, 0, len(counts))
+	for functionName, count := range counts {
+		functions = append(functions, types.StuckFunction{
+			AppName:      LM.AppName,
+			LocalName:    LM.LocalName,
+			FunctionName: functionName,
+			Remaining:    count,
+		})
+	}
+	sort.Slice(functions, func(i, j int) bool { return functions[i].FunctionName < functions[j].FunctionName })
+	return functions
+}
+
+// shutdownError returns the *types.ShutdownError for the stuck functions, nil if there are none
+func shutdownError(stuck []types.StuckFunction) error {
+	if len(stuck) == 0 {
+		return nil
+	}
+	return types.NewShutdownError(stuck)
+}
+
 // FunctionShutdowner
 func (LM *LocalManagerStruct) ShutdownFunction(functionName string, timeout time.Duration) error {
 	startTime := time.Now()
@@ -223,6 +329,11 @@ func (LM *LocalManagerStruct) ShutdownFunction(functionName string, timeout time
 	// Record operation
 	metrics.RecordFunctionOperation("shutdown", LM.AppName, LM.LocalName, functionName)
 
+	types.FireShutdownStage(types.ShutdownStageBeginDrain, LM.AppName, LM.LocalName, functionName)
+	defer types.FireShutdownStage(types.ShutdownStageDone, LM.AppName, LM.LocalName, functionName)
+	localManager.SetFunctionWgDraining(functionName, true)
+	defer localManager.SetFunctionWgDraining(functionName, false)
+
 	// Get all routines
 	routines, err := LM.GetAllGoroutines()
 	if err != nil {
@@ -233,32 +344,50 @@ func (LM *LocalManagerStruct) ShutdownFunction(functionName string, timeout time
 	// Track routines for this function for cleanup
 	var functionRoutines []*types.Routine
 
-	// Cancel all routines with this function name
+	// The deadline is announced before cancelling so workers see it as soon as ctx.Done fires
+	deadline := time.Now().Add(timeout)
 	for _, routine := range routines {
 		if routine.GetFunctionName() == functionName {
 			functionRoutines = append(functionRoutines, routine)
-			cancel := routine.GetCancel()
-			if cancel != nil {
-				cancel()
-			}
+			routine.SetShutdownDeadline(deadline)
+		}
+	}
+
+	// Let in-flight requests finish before cancelling, within the same budget
+	waitForRequests(functionRoutines, deadline)
+
+	// Cancel all routines with this function name
+	types.FireShutdownStage(types.ShutdownStageCancel, LM.AppName, LM.LocalName, functionName)
+	for _, routine := range functionRoutines {
+		cancel := routine.GetCancel()
+		if cancel != nil {
+			cancel()
 		}
 	}
 
-	// Wait for completion with timeout
-	completed := LM.WaitForFunctionWithTimeout(functionName, timeout)
+	// Wait for completion with what is left of the timeout
+	completed := LM.WaitForFunctionWithTimeout(functionName, time.Until(deadline))
 	if !completed {
 		// Timeout occurred - clean up routines and wait group
+		stuck := LM.stuckFunctions(functionRoutines)
 		for _, routine := range functionRoutines {
 			// Remove routine from map to prevent memory leak
 			localManager.RemoveRoutine(routine, false)
+			// Release its wait group slots now, its own completion later is then a no-op
+			routine.ReleaseWaitGroups()
 		}
+		types.FireShutdownStage(types.ShutdownStageForceRemove, LM.AppName, LM.LocalName, functionName)
 		// Clean up the wait group even on timeout
 		localManager.RemoveFunctionWg(functionName)
+		if len(stuck) > 0 {
+			return types.NewShutdownError(stuck)
+		}
 		return fmt.Errorf("shutdown timeout for function: %s", functionName)
 	}
 
 	// Clean up the wait group on success
 	localManager.RemoveFunctionWg(functionName)
+	types.FireShutdownStage(types.ShutdownStageGracefulComplete, LM.AppName, LM.LocalName, functionName)
 
 	return nil
 }
@@ -270,8 +399,24 @@ func (LM *LocalManagerStruct) ShutdownFunction(functionName string, timeout time
 //
 // Options can be provided to configure the goroutine:
 //   - WithTimeout(duration): Sets a timeout for the goroutine. Context is cancelled on timeout.
+//   - WithDeadline(t): Cancels the context at the wall-clock time t, the earlier of it and a timeout wins.
 //   - WithPanicRecovery(enabled): Enables panic recovery. Panics are logged and goroutine completes normally.
+//   - WithPanicHandler(fn): Calls fn with the function name, recovered value and stack when a panic is recovered.
 //   - AddToWaitGroup(functionName): Adds the goroutine to a function wait group for coordinated shutdown.
+//   - WithForceKillOnTimeout(grace): Reports the goroutine as leaked if it ignores its timeout for longer than grace.
+//   - WithOnComplete(fn): Calls fn with the classified outcome once the goroutine finishes.
+//   - WithRequestTracker(): Makes graceful shutdown wait for in-flight requests before cancelling.
+//   - WithGlobalSemaphore(name): Takes a slot of the named global semaphore before running the worker.
+//   - WithSoftTimeout(d, fn): Calls fn if the goroutine is still running after d, without cancelling it.
+//   - WithMemoryEstimate(bytes): Reserves the estimate against the memory budgets while the goroutine runs.
+//   - WithLogBuffer(size): Keeps the last size lines the worker logs with Logf.
+//   - WithRetry(maxAttempts, backoff): Runs the worker again while it returns an error.
+//   - WithRetryBackoff(maxAttempts, initial, max, jitter): WithRetry with an exponential backoff and jitter.
+//   - WithRestart(policy): Runs the worker again under a fresh child context as the restart policy allows.
+//   - WithMaxConcurrency(name, n): Blocks, or fails WithRejectOverConcurrency, while n routines of name are tracked.
+//   - WithLabels(labels): Tags the goroutine with key/value labels for GetRoutinesByLabel and the labeled metric.
+//   - WithRateLimit(name, perSecond, burst): Waits for a token of the function's spawn rate limit before launching.
+//   - WithPriority(p): Orders the goroutine in the spawn queue when MaxRoutines is reached, higher first.
 //
 // Example:
 //
@@ -288,41 +433,262 @@ func (LM *LocalManagerStruct) Go(functionName string, workerFunc func(ctx contex
 			localOpt(options)
 		}
 	}
-	return LM.spawnGoroutine(functionName, workerFunc, options)
+	_, err := LM.spawnGoroutine(functionName, workerFunc, options)
+	return err
+}
+
+// GoWithContext spawns a goroutine like Go, with a context derived from parent instead of the local context.
+// The worker sees the values of parent, such as request or trace IDs, and is cancelled when parent is done.
+// It is still cancelled by the local manager's shutdown and tracked exactly like a routine spawned with Go.
+// Pass context.WithoutCancel(parent) to keep the values without tying the routine's lifetime to parent.
+//
+// Example:
+//
+//	func handle(w http.ResponseWriter, r *http.Request) {
+//	    localMgr.GoWithContext(r.Context(), "audit", func(ctx context.Context) error {
+//	        return audit(ctx, ctx.Value(requestIDKey{}))
+//	    })
+//	}
+func (LM *LocalManagerStruct) GoWithContext(parent context.Context, functionName string, workerFunc func(ctx context.Context) error, opts ...Interface.GoroutineOption) error {
+	if parent == nil {
+		return Errors.ErrNilParentContext
+	}
+	options := defaultGoroutineOptions()
+	for _, opt := range opts {
+		if localOpt, ok := opt.(Option); ok {
+			localOpt(options)
+		}
+	}
+	options.parentCtx = parent
+	_, err := LM.spawnGoroutine(functionName, workerFunc, options)
+	return err
 }
 
 // spawnGoroutine is the internal implementation for spawning goroutines.
 // It accepts options to configure timeout, panic recovery, and wait group behavior.
-func (LM *LocalManagerStruct) spawnGoroutine(functionName string, workerFunc func(ctx context.Context) error, opts *goroutineOptions) error {
+// Returns the ID of the spawned routine.
+func (LM *LocalManagerStruct) spawnGoroutine(functionName string, workerFunc func(ctx context.Context) error, opts *goroutineOptions) (_ string, spawnErr error) {
+	// Reject names in the reserved namespace so user routines can't collide with internal ones
+	if types.IsReservedFunctionName(functionName) {
+		metrics.RecordOperationError("goroutine", "spawn", "reserved_function_name")
+		return "", fmt.Errorf("%w: %s", Errors.ErrReservedFunctionName, functionName)
+	}
+
 	// Get the types.LocalManager instance
 	localManager, err := types.GetLocalManager(LM.AppName, LM.LocalName)
 	if err != nil {
-		return err
+		return "", LM.notCreatedError(err)
+	}
+	if localManager.IsDraining() {
+		metrics.RecordOperationError("goroutine", "spawn", "draining")
+		return "", fmt.Errorf("%w: %s/%s", Errors.ErrDraining, LM.AppName, LM.LocalName)
 	}
 
-	var wg *sync.WaitGroup
-	if opts.waitGroupName != "" {
-		// Get or create function wait group using the specified function name
-		wg, err = LM.NewFunctionWaitGroup(context.Background(), opts.waitGroupName)
+	// Resolve the semaphore up front so an unknown name fails the call instead of the routine
+	var semaphore *types.Semaphore
+	if opts.semaphoreName != "" {
+		globalManager, err := types.GetGlobalManager()
 		if err != nil {
-			return err
+			return "", err
+		}
+		semaphore, err = globalManager.GetSemaphore(opts.semaphoreName)
+		if err != nil {
+			metrics.RecordOperationError("goroutine", "spawn", "semaphore_not_found")
+			return "", err
+		}
+	}
+
+	// Wait for a token of the spawn rate limit first, nothing is reserved while waiting
+	if opts.rateName != "" {
+		if opts.try {
+			if err := localManager.TryRateLimit(opts.rateName, opts.ratePerSecond, opts.rateBurst); err != nil {
+				metrics.RecordOperationError("goroutine", "spawn", "rate_limited")
+				return "", err
+			}
+			// A TryGo refused by a later limiter didn't start, its token isn't spent
+			defer func() {
+				if spawnErr != nil {
+					localManager.ReturnRateLimit(opts.rateName)
+				}
+			}()
+		} else if err := LM.waitRateLimit(localManager, opts); err != nil {
+			metrics.RecordOperationError("goroutine", "spawn", "rate_limit_wait_failed")
+			return "", err
 		}
-		// Increment wait group BEFORE spawning goroutine
-		wg.Add(1)
 	}
 
-	// Always add to LocalManager's main wait group for safe shutdown
-	if localManager.Wg != nil {
+	// Wait for the function's concurrency limit before taking any reservation, blocking while holding one would starve others
+	var concurrency *types.Semaphore
+	if opts.limitName != "" {
+		concurrency, err = localManager.AcquireConcurrency(opts.limitName, functionName, opts.limitSize, !opts.limitReject && !opts.try)
+		if err != nil {
+			metrics.RecordOperationError("goroutine", "spawn", "max_concurrency_exceeded")
+			return "", err
+		}
+	}
+	// TryGo can't wait for the semaphore in the routine either, its slot is taken here and released with the concurrency slot
+	var heldSemaphore *types.Semaphore
+	if opts.try && semaphore != nil {
+		if !semaphore.TryAcquireFor(LM.semaphoreGroup(functionName)) {
+			if concurrency != nil {
+				concurrency.ReleaseFor(functionName)
+			}
+			metrics.RecordOperationError("goroutine", "spawn", "semaphore_full")
+			return "", fmt.Errorf("%w: %s", Errors.ErrSemaphoreFull, opts.semaphoreName)
+		}
+		heldSemaphore, semaphore = semaphore, nil
+	}
+	releaseConcurrency := func() {
+		if concurrency != nil {
+			concurrency.ReleaseFor(functionName)
+		}
+		if heldSemaphore != nil {
+			heldSemaphore.ReleaseFor(LM.semaphoreGroup(functionName))
+		}
+	}
+
+	// Capture the spawn site only when requested, walking the stack isn't free
+	var spawnSite string
+	if (opts.timeout != nil || opts.deadline != nil) && opts.leakGrace != nil {
+		spawnSite = callerSpawnSite()
+	}
+
+	// Admit the routine and its memory estimate last, nothing below can fail the spawn and leak the reservation
+	if err := localManager.ReserveRoutineSlot(); err != nil {
+		if errors.Is(err, Errors.ErrMaxRoutinesExceeded) && !opts.noQueue {
+			err = LM.enqueueSpawn(localManager, functionName, workerFunc, opts, semaphore, releaseConcurrency, spawnSite, err)
+			if err == nil {
+				metrics.RecordGoroutineOperation("queue", LM.AppName, LM.LocalName, functionName)
+				return "", nil
+			}
+		}
+		releaseConcurrency()
+		switch {
+		case errors.Is(err, Errors.ErrSpawnQueueFull):
+			metrics.RecordOperationError("goroutine", "spawn", "spawn_queue_full")
+		case errors.Is(err, Errors.ErrMaxRoutinesExceeded):
+			metrics.RecordOperationError("goroutine", "spawn", "max_routines_exceeded")
+		}
+		return "", err
+	}
+	return LM.launch(localManager, functionName, workerFunc, opts, semaphore, releaseConcurrency, spawnSite)
+}
+
+// semaphoreGroup is the group the routines of functionName take global semaphore slots for.
+// Shared across apps, the group has to tell apart functions of the same name in other local managers.
+func (LM *LocalManagerStruct) semaphoreGroup(functionName string) string {
+	return LM.AppName + "/" + LM.LocalName + "/" + functionName
+}
+
+// waitRateLimit waits for a token of the WithRateLimit limiter, until the local context or the GoWithContext parent is done
+func (LM *LocalManagerStruct) waitRateLimit(localManager *types.LocalManager, opts *goroutineOptions) error {
+	ctx, _ := localManager.GetLocalContext()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if opts.parentCtx != nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithCancel(ctx)
+		defer cancel()
+		stop := context.AfterFunc(opts.parentCtx, cancel)
+		defer stop()
+	}
+	err := localManager.WaitRateLimit(ctx, opts.rateName, opts.ratePerSecond, opts.rateBurst)
+	if err != nil && opts.parentCtx != nil && opts.parentCtx.Err() != nil {
+		// Report why the parent ended rather than the cancellation of the merged context
+		return opts.parentCtx.Err()
+	}
+	return err
+}
+
+// managerPkgPrefix is the import path prefix shared by the manager packages
+var managerPkgPrefix = path.Dir(reflect.TypeOf(LocalManagerStruct{}).PkgPath()) + "/"
+
+// callerSpawnSite returns the file:line of the first caller outside the manager packages,
+// so a spawn through GoN, Map, TryGo or any other wrapper points at the user's call rather than the wrapper
+func callerSpawnSite() string {
+	pcs := make([]uintptr, 32)
+	// Skip runtime.Callers and callerSpawnSite
+	n := runtime.Callers(2, pcs)
+	if n == 0 {
+		return ""
+	}
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		if !strings.HasPrefix(frame.Function, managerPkgPrefix) {
+			return fmt.Sprintf("%s:%d", frame.File, frame.Line)
+		}
+		if !more {
+			return ""
+		}
+	}
+}
+
+// enqueueSpawn queues a spawn rejected by the routine limit in the global spawn queue, limitErr is returned
+// while queueing is off. Once dequeued it is launched like a direct spawn, unless the local manager was shut
+// down or put in drain mode meanwhile.
+func (LM *LocalManagerStruct) enqueueSpawn(localManager *types.LocalManager, functionName string, workerFunc func(ctx context.Context) error, opts *goroutineOptions, semaphore *types.Semaphore, releaseConcurrency func(), spawnSite string, limitErr error) error {
+	start := func() {
+		// The routine slot was reserved by the dispatcher
+		localCtx, _ := localManager.GetLocalContext()
+		if localManager.IsDraining() || (localCtx != nil && localCtx.Err() != nil) {
+			localManager.ReleaseRoutineSlot()
+			releaseConcurrency()
+			metrics.RecordOperationError("goroutine", "spawn", "spawn_queue_dropped")
+			return
+		}
+		if _, err := LM.launch(localManager, functionName, workerFunc, opts, semaphore, releaseConcurrency, spawnSite); err != nil {
+			Logging.Error("queued spawn failed", "app", LM.AppName, "local", LM.LocalName, "function", functionName, "error", err)
+		}
+	}
+	drop := func(err error) {
+		releaseConcurrency()
+		metrics.RecordOperationError("goroutine", "spawn", "spawn_queue_dropped")
+	}
+	return localManager.EnqueueSpawn(opts.priority, limitErr, start, drop)
+}
+
+// launch starts a routine admitted against the routine limit, the slot is released if it can't be started
+func (LM *LocalManagerStruct) launch(localManager *types.LocalManager, functionName string, workerFunc func(ctx context.Context) error, opts *goroutineOptions, semaphore *types.Semaphore, releaseConcurrency func(), spawnSite string) (string, error) {
+	if opts.memoryBytes > 0 {
+		if err := localManager.ReserveMemory(opts.memoryBytes); err != nil {
+			localManager.ReleaseRoutineSlot()
+			releaseConcurrency()
+			metrics.RecordOperationError("goroutine", "spawn", "memory_budget_exceeded")
+			return "", err
+		}
+	}
+
+	// Always add to LocalManager's main wait group for safe shutdown, GoN adds the whole batch up front
+	if localManager.Wg != nil && !opts.wgReserved {
 		localManager.Wg.Add(1)
 	}
 
-	// Create a child context with cancel for this routine
-	routineCtx, cancel := localManager.SpawnChild()
+	// Create a new Routine instance
+	routine := localManager.NewGoRoutine(functionName)
 
-	// Apply timeout if specified
-	var timeoutCancel context.CancelFunc
+	// Create the routine's context, cancelled with the local context until ReparentRoutines moves it
+	var routineCtx context.Context
+	var cancel context.CancelFunc
+	if opts.parentCtx != nil {
+		// GoWithContext, the caller's context provides the values and cancels the routine too
+		routineCtx, cancel = localManager.SpawnLinkedChildOf(opts.parentCtx, routine)
+	} else {
+		routineCtx, cancel = localManager.SpawnLinkedChild(routine)
+	}
+
+	// Apply the timeout and the deadline if specified, whichever comes first wins
+	var deadline time.Time
 	if opts.timeout != nil {
-		routineCtx, timeoutCancel = context.WithTimeout(routineCtx, *opts.timeout)
+		deadline = time.Now().Add(*opts.timeout)
+	}
+	if opts.deadline != nil && (deadline.IsZero() || opts.deadline.Before(deadline)) {
+		deadline = *opts.deadline
+	}
+	var timeoutCancel context.CancelFunc
+	if !deadline.IsZero() {
+		routineCtx, timeoutCancel = context.WithDeadline(routineCtx, deadline)
 		// Combine cancellations: when timeout expires or explicit cancel is called
 		originalCancel := cancel
 		cancel = func() {
@@ -337,44 +703,111 @@ func (LM *LocalManagerStruct) spawnGoroutine(functionName string, workerFunc fun
 	// This allows non-blocking close even if nothing is reading
 	doneChan := make(chan struct{}, 1)
 
-	// Create a new Routine instance
-	routine := localManager.NewGoRoutine(functionName).
-		SetContext(routineCtx).
+	var wg *sync.WaitGroup
+	if opts.waitGroupName != "" {
+		// Get or create the function wait group and increment it BEFORE spawning the goroutine
+		var created bool
+		wg, created = localManager.JoinFunctionWg(routine, opts.waitGroupName)
+		if created {
+			metrics.RecordFunctionOperation("wait_group_create", LM.AppName, LM.LocalName, opts.waitGroupName)
+		}
+	}
+	// Run the routine in its own span when a tracer is set, the worker's spans nest under it
+	routineCtx, span := LM.startRoutineSpan(routineCtx, routine)
+	// Wrap the context so workers can read the shutdown deadline once shutdown begins
+	routineCtx = newRoutineContext(routineCtx, routine)
+	routine.SetContext(routineCtx).
 		SetCancel(cancel).
-		SetDone(doneChan) // Override the channel created in NewGoRoutine
+		SetDone(doneChan). // Override the channel created in NewGoRoutine
+		SetWaitGroups(wg, localManager.Wg)
+	if opts.trackRequests {
+		routine.SetRequestTracker(types.NewRequestTracker())
+	}
+	if opts.logBufferSize > 0 {
+		routine.SetLogBuffer(types.NewLogBuffer(opts.logBufferSize))
+	}
+	routine.SetLabels(opts.labels)
+	localManager.AttachPauseGate(routine)
+
+	// Leak detection only makes sense together with a timeout or deadline
+	if !deadline.IsZero() && opts.leakGrace != nil {
+		routine.SetSpawnSite(spawnSite)
+		LM.watchForLeak(localManager, routine, doneChan, *opts.leakGrace)
+	}
+
+	var softTimer *time.Timer
+	if opts.softTimeout != nil && opts.onSoftTimeout != nil {
+		softTimer = LM.watchSoftTimeout(routine, doneChan, *opts.softTimeout, opts.onSoftTimeout)
+	}
+
+	// Read the diagnostic switch once, at spawn time
+	detectIgnoredContext := types.DetectIgnoredContexts
 
 	// Record goroutine creation and measure creation duration
 	createStartTime := time.Now()
 	metrics.RecordGoroutineOperation("create", LM.AppName, LM.LocalName, functionName)
+	metrics.RecordLabeledOperation("create", LM.AppName, LM.LocalName, functionName, opts.labels)
+	localManager.RecordFunctionSpawn(functionName)
 
 	// Spawn the goroutine
+	spawnTimeNano := time.Now().UnixNano()
 	go func() {
 		startTimeNano := time.Now().UnixNano()
+		metrics.RecordGoroutineScheduleLatency(LM.AppName, LM.LocalName, functionName, spawnTimeNano)
+		// Outcome of the worker, panicked stays true unless workerFunc returns normally
+		var workerErr error
+		panicked := true
+		// Tag the goroutine so profiles and stack dumps can be filtered per function
+		LM.labelRoutine(functionName)
+		routine.SetState(types.RoutineStateRunning)
+		types.PublishEvent(types.EventRoutineStarted, LM.AppName, LM.LocalName, functionName, routine.GetID(), nil)
 		defer func() {
+			routine.SetState(types.RoutineStateCompleted)
+			if softTimer != nil {
+				softTimer.Stop()
+			}
+			localManager.ReleaseRoutineSlot()
+			if opts.memoryBytes > 0 {
+				localManager.ReleaseMemory(opts.memoryBytes)
+			}
+
 			// Handle panic recovery (enabled by default for production safety)
+			var panicValue any
 			if opts.panicRecovery {
 				if r := recover(); r != nil {
+					panicValue = r
 					// Log panic details via metrics
 					metrics.RecordOperationError("goroutine", "panic", fmt.Sprintf("function: %s, panic: %v", functionName, r))
+					if opts.onPanic != nil {
+						LM.runOnPanic(opts.onPanic, functionName, r, debug.Stack())
+					}
 					// Panic is recovered, continue with normal cleanup
 				}
 			}
 
 			// Record goroutine completion
+			duration := time.Duration(time.Now().UnixNano() - startTimeNano)
 			metrics.RecordGoroutineCompletion(LM.AppName, LM.LocalName, functionName, startTimeNano)
 			metrics.RecordGoroutineOperation("complete", LM.AppName, LM.LocalName, functionName)
-
-			if opts.waitGroupName != "" && wg != nil {
-				// Decrement function wait group when routine completes
-				wg.Done()
+			metrics.RecordLabeledOperation("complete", LM.AppName, LM.LocalName, functionName, opts.labels)
+			errorClass := types.ErrorClassFailure
+			if !panicked {
+				errorClass = LM.classifyError(opts.classifyError, workerErr)
 			}
-			// Always decrement LocalManager's main wait group
-			if localManager.Wg != nil {
-				localManager.Wg.Done()
+			localManager.RecordFunctionFinish(functionName, duration, errorClass, panicked)
+			endRoutineSpan(span, workerErr, errorClass, panicked, panicValue)
+
+			// Classify before the context is cancelled below, otherwise every routine looks cancelled
+			if opts.onComplete != nil {
+				outcomeType, outcomeErr := classifyOutcome(routineCtx, workerErr, panicked, panicValue)
+				LM.runOnComplete(opts.onComplete, Outcome{
+					Type:      outcomeType,
+					Err:       outcomeErr,
+					Class:     errorClass,
+					Duration:  duration,
+					RoutineID: routine.GetID(),
+				})
 			}
-			// Close the done channel when routine completes
-			// The done channel is buffered (size 1) so this won't block
-			close(doneChan)
 
 			// Explicitly cancel the routine's context to ensure proper cleanup
 			// This ensures any resources tied to the context are released immediately
@@ -384,23 +817,152 @@ func (LM *LocalManagerStruct) spawnGoroutine(functionName string, workerFunc fun
 			}
 
 			// CRITICAL: Remove routine from tracking map to prevent memory leak
-			// This must be done after all cleanup to ensure the routine is fully completed
+			// This is done before signalling the wait groups so that anyone woken up by them
+			// (e.g. Shutdown followed by a metrics flush) observes a tree without this routine
 			// Using safe=false since the routine is already completing naturally
 			// Note: RemoveRoutine also cancels the context, but we've already done it above
 			// for explicit cleanup. RemoveRoutine's cancel is idempotent (safe to call twice).
 			localManager.RemoveRoutine(routine, false)
+			// Drop the age series once untracked, so the collector doesn't recreate it
+			metrics.RemoveGoroutineAge(LM.AppName, LM.LocalName, functionName, routine.GetID())
+			eventErr := workerErr
+			if panicked {
+				eventErr = fmt.Errorf("%w: %v", Errors.ErrWorkerPanicked, panicValue)
+			}
+			types.PublishEvent(types.EventRoutineCompleted, LM.AppName, LM.LocalName, functionName, routine.GetID(), eventErr)
+			// Free the concurrency slot only once untracked, so the function never counts more than its limit
+			releaseConcurrency()
+
+			// Decrement the function and LocalManager wait groups
+			// No-op if a shutdown already force-removed this routine and released them
+			routine.ReleaseWaitGroups()
+			// Close the done channel when routine completes
+			// The done channel is buffered (size 1) so this won't block
+			close(doneChan)
 		}()
 
+		// Wait for a slot of the shared semaphore, giving up if the routine is cancelled meanwhile
+		if semaphore != nil {
+			group := LM.semaphoreGroup(functionName)
+			if err := semaphore.AcquireFor(routineCtx, group, localManager.GetFunctionWeight(functionName)); err != nil {
+				workerErr = err
+				panicked = false
+				return
+			}
+			defer semaphore.ReleaseFor(group)
+		}
+
 		// Execute the worker function with the routine's context
 		// Panics will be caught and recovered by the defer block above (enabled by default)
-		_ = workerFunc(routineCtx)
+		localManager.RecordFunctionStart(functionName)
+		workerCtx := routineCtx
+		if detectIgnoredContext {
+			workerCtx = LM.observeContext(routineCtx, routine)
+		}
+		if opts.restart.Mode == types.RestartNever {
+			workerErr = LM.runWorker(workerCtx, routineCtx, functionName, workerFunc, opts)
+		} else {
+			workerErr = LM.superviseWorker(routineCtx, routine, detectIgnoredContext, workerFunc, opts)
+		}
+		panicked = false
 	}()
 
 	// Record creation operation duration (time to spawn goroutine, should be very fast)
 	createDuration := time.Since(createStartTime)
 	metrics.RecordGoroutineOperationDuration("create", createDuration, LM.AppName, LM.LocalName, functionName)
 
-	return nil
+	return routine.GetID(), nil
+}
+
+// notCreatedError tells which Create call is missing when the managers of a spawn can't be found
+func (LM *LocalManagerStruct) notCreatedError(err error) error {
+	switch {
+	case errors.Is(err, Errors.ErrAppManagerNotFound):
+		metrics.RecordOperationError("goroutine", "spawn", "app_manager_not_created")
+		return fmt.Errorf("%w: app %s", Errors.ErrAppManagerNotCreated, LM.AppName)
+	case errors.Is(err, Errors.ErrLocalManagerNotFound):
+		metrics.RecordOperationError("goroutine", "spawn", "local_manager_not_created")
+		return fmt.Errorf("%w: local %s in app %s", Errors.ErrLocalManagerNotCreated, LM.LocalName, LM.AppName)
+	default:
+		return err
+	}
+}
+
+// classifyError applies the error classifier, a panicking classifier counts the error as a failure
+func (LM *LocalManagerStruct) classifyError(classify func(err error) types.ErrorClass, err error) (class types.ErrorClass) {
+	defer func() {
+		if r := recover(); r != nil {
+			metrics.RecordOperationError("goroutine", "error_classifier_panic", fmt.Sprintf("error: %v, panic: %v", err, r))
+			class = types.ErrorClassFailure
+		}
+	}()
+	return classify(err)
+}
+
+// runOnComplete invokes the completion callback, a panicking callback must not skip the routine's cleanup
+func (LM *LocalManagerStruct) runOnComplete(onComplete func(Outcome), outcome Outcome) {
+	defer func() {
+		if r := recover(); r != nil {
+			metrics.RecordOperationError("goroutine", "on_complete_panic", fmt.Sprintf("routine: %s, panic: %v", outcome.RoutineID, r))
+		}
+	}()
+	onComplete(outcome)
+}
+
+// runOnPanic invokes the panic handler, a panicking handler must not skip the routine's cleanup
+func (LM *LocalManagerStruct) runOnPanic(onPanic func(functionName string, recovered interface{}, stack []byte), functionName string, recovered interface{}, stack []byte) {
+	defer func() {
+		if r := recover(); r != nil {
+			metrics.RecordOperationError("goroutine", "panic_handler_panic", fmt.Sprintf("function: %s, panic: %v", functionName, r))
+		}
+	}()
+	onPanic(functionName, recovered, stack)
+}
+
+// watchSoftTimeout calls onExceed once if the routine hasn't finished after d, the routine itself is left running
+func (LM *LocalManagerStruct) watchSoftTimeout(routine *types.Routine, done <-chan struct{}, d time.Duration, onExceed func(routineID string)) *time.Timer {
+	return time.AfterFunc(d, func() {
+		select {
+		case <-done:
+			return
+		default:
+		}
+		defer func() {
+			if r := recover(); r != nil {
+				metrics.RecordOperationError("goroutine", "soft_timeout_panic", fmt.Sprintf("routine: %s, panic: %v", routine.GetID(), r))
+			}
+		}()
+		onExceed(routine.GetID())
+	})
+}
+
+// watchForLeak reports the routine as leaked if it is still running grace after its timeout fired.
+// The goroutine itself can't be stopped, so it is marked, counted, logged and dropped from the active map.
+// No goroutine is parked for this: the check is scheduled from the context's own cancellation.
+func (LM *LocalManagerStruct) watchForLeak(localManager *types.LocalManager, routine *types.Routine, done <-chan struct{}, grace time.Duration) {
+	routineCtx := routine.GetContext()
+	context.AfterFunc(routineCtx, func() {
+		// Only a fired timeout counts, explicit cancellation and normal completion don't
+		if !errors.Is(routineCtx.Err(), context.DeadlineExceeded) {
+			return
+		}
+		time.AfterFunc(grace, func() {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			if !routine.MarkLeaked() {
+				return
+			}
+			metrics.RecordGoroutineLeak(LM.AppName, LM.LocalName, routine.GetFunctionName())
+			Logging.Warn("goroutine ignored its context and kept running past its timeout",
+				"routine", routine.GetID(), "function", routine.GetFunctionName(), "grace", grace, "spawn_site", routine.GetSpawnSite())
+			localManager.RemoveRoutine(routine, false)
+			// Release its wait group slots now, its own completion later is then a no-op
+			routine.ReleaseWaitGroups()
+		})
+	})
 }
 
 // GoroutineLister
@@ -428,29 +990,109 @@ func (LM *LocalManagerStruct) GetGoroutineCount() int {
 	return localManager.GetRoutineCount()
 }
 
+// Stats returns a snapshot of the local manager: live and peak routines, total spawned, per function stats and uptime.
+// Returns zero stats if the local manager doesn't exist.
+func (LM *LocalManagerStruct) Stats() types.ManagerStats {
+	localManager, err := types.GetLocalManager(LM.AppName, LM.LocalName)
+	if err != nil {
+		return types.ManagerStats{}
+	}
+	return types.ManagerStats{
+		Goroutines:     localManager.GetRoutineCount(),
+		PeakGoroutines: localManager.GetPeakRoutines(),
+		TotalSpawned:   localManager.GetTotalSpawned(),
+		Functions:      localManager.GetAllFunctionStats(),
+		Uptime:         localManager.GetUptime(),
+	}
+}
+
 // FunctionWaitGroupCreator
 func (LM *LocalManagerStruct) NewFunctionWaitGroup(ctx context.Context, functionName string) (*sync.WaitGroup, error) {
 	localManager, err := types.GetLocalManager(LM.AppName, LM.LocalName)
 	if err != nil {
+		// The only way this can fail, the local manager was never created or has been removed
 		metrics.RecordOperationError("function", "wait_group_create", "get_local_manager_failed")
-		return nil, err
+		return nil, fmt.Errorf("can't create function wait group %s for local %s in app %s: %w", functionName, LM.LocalName, LM.AppName, err)
+	}
+
+	// Get or create in one step so concurrent callers can't replace each other's group
+	wg, created := localManager.GetOrCreateFunctionWg(functionName)
+	if created {
+		metrics.RecordFunctionOperation("wait_group_create", LM.AppName, LM.LocalName, functionName)
 	}
+	return wg, nil
+}
 
-	// Check if wait group already exists
-	wg, err := localManager.GetFunctionWg(functionName)
-	if err == nil {
-		// Already exists, return it
-		return wg, nil
+// SetFunctionWeight weights the routines of functionName when they wait for a semaphore slot, be it a limit
+// shared WithMaxConcurrency or a WithGlobalSemaphore. Under contention each function holds slots in proportion
+// to its weight, instead of first come first served. Functions default to a weight of 1.
+//
+// Example:
+//
+//	localMgr.SetFunctionWeight("checkout", 3)
+//	localMgr.Go("checkout", checkout, WithMaxConcurrency("db", 8))
+//	localMgr.Go("report", report, WithMaxConcurrency("db", 8))
+func (LM *LocalManagerStruct) SetFunctionWeight(functionName string, weight int) error {
+	localManager, err := types.GetLocalManager(LM.AppName, LM.LocalName)
+	if err != nil {
+		return err
 	}
+	return localManager.SetFunctionWeight(functionName, weight)
+}
 
-	// Create new wait group
-	localManager.AddFunctionWg(functionName)
+// Drain stops the local manager from accepting goroutines: Go and the other spawners fail with ErrDraining
+// while the goroutines already running carry on. Pair it with WaitForAll to let them finish during a rolling deploy.
+//
+// Example:
+//
+//	localMgr.Drain()
+//	localMgr.WaitForAllWithTimeout(30 * time.Second)
+func (LM *LocalManagerStruct) Drain() error {
+	localManager, err := types.GetLocalManager(LM.AppName, LM.LocalName)
+	if err != nil {
+		return err
+	}
+	localManager.SetDraining(true)
+	metrics.RecordManagerOperation("local", "drain", LM.AppName)
+	return nil
+}
 
-	// Record operation
-	metrics.RecordFunctionOperation("wait_group_create", LM.AppName, LM.LocalName, functionName)
+// IsDraining reports whether Drain was called on the local manager, false if it doesn't exist
+func (LM *LocalManagerStruct) IsDraining() bool {
+	localManager, err := types.GetLocalManager(LM.AppName, LM.LocalName)
+	if err != nil {
+		return false
+	}
+	return localManager.IsDraining()
+}
+
+// SetMemoryBudget caps the total memory estimate of the local manager's running routines, 0 means unlimited.
+// Only routines spawned WithMemoryEstimate count against it.
+func (LM *LocalManagerStruct) SetMemoryBudget(bytes int64) error {
+	localManager, err := types.GetLocalManager(LM.AppName, LM.LocalName)
+	if err != nil {
+		return err
+	}
+	return localManager.SetMemoryBudget(bytes)
+}
 
-	// Retrieve and return the newly created wait group
-	return localManager.GetFunctionWg(functionName)
+// GetMemoryInUse returns the memory estimate reserved by the local manager's running routines
+func (LM *LocalManagerStruct) GetMemoryInUse() int64 {
+	localManager, err := types.GetLocalManager(LM.AppName, LM.LocalName)
+	if err != nil {
+		return 0
+	}
+	return localManager.GetMemoryInUse()
+}
+
+// GetFunctionWaitGroups lists the function wait groups of the local manager with their pending routines,
+// for debugging WaitForFunction calls that don't return
+func (LM *LocalManagerStruct) GetFunctionWaitGroups() []types.FunctionWaitGroupInfo {
+	localManager, err := types.GetLocalManager(LM.AppName, LM.LocalName)
+	if err != nil {
+		return nil
+	}
+	return localManager.GetFunctionWaitGroups()
 }
 
 // Multiple go routines can have same function name
@@ -468,3 +1110,51 @@ func (LM *LocalManagerStruct) GetRoutinesByFunctionName(functionName string) ([]
 	}
 	return result, nil	
 }
+
+// GetRoutinesByLabel returns the tracked routines spawned WithLabels carrying the label key with the given value
+func (LM *LocalManagerStruct) GetRoutinesByLabel(key, value string) ([]*types.Routine, error) {
+	result := make([]*types.Routine, 0)
+	routines, err := LM.GetAllGoroutines()
+	if err != nil {
+		return nil, err
+	}
+	for _, routine := range routines {
+		if routine.HasLabel(key, value) {
+			result = append(result, routine)
+		}
+	}
+	return result, nil
+}
+
+// GetFunctionStats returns live, spawned and finished counts plus the average duration for a function.
+// The counters are kept in memory by the local manager, so this works with metrics disabled.
+func (LM *LocalManagerStruct) GetFunctionStats(functionName string) types.FunctionStats {
+	localManager, err := types.GetLocalManager(LM.AppName, LM.LocalName)
+	if err != nil {
+		return types.FunctionStats{FunctionName: functionName}
+	}
+	return localManager.GetFunctionStats(functionName)
+}
+
+// SetErrorRateThreshold calls onBreach when more than ratio of the function's routines finishing
+// within the rolling window failed (returned an error or panicked). It fires once when the rate
+// goes above the threshold and re-arms once it drops back. A nil onBreach removes the threshold.
+// The callback runs on the goroutine of the routine that tipped the rate, keep it short.
+//
+// Example:
+//
+//	localMgr.SetErrorRateThreshold("worker", 0.5, time.Minute, func(functionName string, rate float64) {
+//	    log.Printf("%s is failing %.0f%% of the time", functionName, rate*100)
+//	})
+func (LM *LocalManagerStruct) SetErrorRateThreshold(functionName string, ratio float64, window time.Duration, onBreach func(functionName string, rate float64)) error {
+	localManager, err := types.GetLocalManager(LM.AppName, LM.LocalName)
+	if err != nil {
+		metrics.RecordOperationError("function", "error_rate_threshold", "get_local_manager_failed")
+		return err
+	}
+	if onBreach != nil && (ratio < 0 || ratio >= 1 || window <= 0) {
+		return fmt.Errorf("%w: %s", Errors.ErrInvalidErrorRate, functionName)
+	}
+	localManager.SetErrorRateThreshold(functionName, ratio, window, onBreach)
+	return nil
+}
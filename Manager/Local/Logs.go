@@ -0,0 +1,49 @@
+package Local
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/neerajchowdary889/GoRoutinesManager/Manager/Errors"
+	"github.com/neerajchowdary889/GoRoutinesManager/types"
+)
+
+// Logf appends a line to the log buffer of the worker owning ctx, prefixed with its function name and routine ID.
+// It does nothing if ctx doesn't belong to a worker spawned WithLogBuffer.
+func Logf(ctx context.Context, format string, args ...any) {
+	routine, ok := ctx.Value(routineContextKey{}).(*types.Routine)
+	if !ok {
+		return
+	}
+	buffer := routine.GetLogBuffer()
+	if buffer == nil {
+		return
+	}
+	buffer.Append(fmt.Sprintf("[%s %s] ", routine.GetFunctionName(), routine.GetID()) + fmt.Sprintf(format, args...))
+}
+
+// GetRoutineLogs returns the lines a routine logged with Logf, oldest first.
+// Finished routines are still readable while they are kept in the completed retention.
+// Returns nil lines for a routine spawned without WithLogBuffer.
+func (LM *LocalManagerStruct) GetRoutineLogs(routineID string) ([]string, error) {
+	localManager, err := types.GetLocalManager(LM.AppName, LM.LocalName)
+	if err != nil {
+		return nil, err
+	}
+
+	routine, err := localManager.GetRoutine(routineID)
+	if errors.Is(err, Errors.ErrRoutineCompleted) {
+		routine, _ = localManager.GetCompletedRoutine(routineID)
+		err = nil
+	}
+	if err != nil || routine == nil {
+		return nil, err
+	}
+
+	buffer := routine.GetLogBuffer()
+	if buffer == nil {
+		return nil, nil
+	}
+	return buffer.Lines(), nil
+}
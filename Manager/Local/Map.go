@@ -0,0 +1,96 @@
+package Local
+
+import (
+	"context"
+	"sync"
+
+	"github.com/neerajchowdary889/GoRoutinesManager/Manager/Errors"
+	"github.com/neerajchowdary889/GoRoutinesManager/Manager/Interface"
+	"github.com/neerajchowdary889/GoRoutinesManager/types"
+)
+
+// Map runs fn on every input in its own routine of functionName and collects the results and errors,
+// both index aligned with inputs. It returns once every routine finished, or early when the local context
+// is done, in which case the inputs still running report the context error.
+// Spawns honour the options like Go, combined with WithMaxConcurrency at most n inputs run at once.
+// An input whose worker panics reports Errors.ErrWorkerPanicked, one that couldn't be spawned its spawn error.
+//
+// Example:
+//
+//	results, errs := localMgr.Map("resize", images, func(ctx context.Context, in interface{}) (interface{}, error) {
+//	    return resize(ctx, in.(Image))
+//	}, WithMaxConcurrency("resize", 4))
+func (LM *LocalManagerStruct) Map(functionName string, inputs []interface{}, fn func(ctx context.Context, in interface{}) (interface{}, error), opts ...Interface.GoroutineOption) ([]interface{}, []error) {
+	var mu sync.Mutex
+	results := make([]interface{}, len(inputs))
+	errs := make([]error, len(inputs))
+	finished := make([]bool, len(inputs))
+	finish := func(i int, value interface{}, err error) {
+		mu.Lock()
+		results[i] = value
+		errs[i] = err
+		finished[i] = true
+		mu.Unlock()
+	}
+
+	localManager, err := types.GetLocalManager(LM.AppName, LM.LocalName)
+	if err != nil {
+		err = LM.notCreatedError(err)
+		for i := range errs {
+			errs[i] = err
+		}
+		return results, errs
+	}
+	localCtx, _ := localManager.GetLocalContext()
+	if localCtx == nil {
+		localCtx = context.Background()
+	}
+
+	var wg sync.WaitGroup
+	for i, in := range inputs {
+		wg.Add(1)
+		err := LM.Go(functionName, func(ctx context.Context) (err error) {
+			// Panics are left to the routine's own recovery, only the outcome is recorded here
+			panicked := true
+			var value interface{}
+			defer func() {
+				if panicked {
+					err = Errors.ErrWorkerPanicked
+				}
+				finish(i, value, err)
+				wg.Done()
+			}()
+			value, err = fn(ctx, in)
+			panicked = false
+			return err
+		}, opts...)
+		if err != nil {
+			finish(i, nil, err)
+			wg.Done()
+		}
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-localCtx.Done():
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	outResults := make([]interface{}, len(inputs))
+	outErrs := make([]error, len(inputs))
+	for i := range inputs {
+		if finished[i] {
+			outResults[i] = results[i]
+			outErrs[i] = errs[i]
+		} else {
+			outErrs[i] = localCtx.Err()
+		}
+	}
+	return outResults, outErrs
+}
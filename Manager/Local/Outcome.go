@@ -0,0 +1,74 @@
+package Local
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/neerajchowdary889/GoRoutinesManager/types"
+)
+
+// OutcomeType classifies how a routine finished
+type OutcomeType int
+
+const (
+	OutcomeSuccess   OutcomeType = iota // worker returned nil while its context was still live
+	OutcomeError                        // worker returned an error while its context was still live
+	OutcomeCancelled                    // context was cancelled (shutdown, CancelRoutine, ...) before the worker returned
+	OutcomeTimedOut                     // WithTimeout fired before the worker returned
+	OutcomePanicked                     // worker panicked
+)
+
+func (t OutcomeType) String() string {
+	switch t {
+	case OutcomeSuccess:
+		return "success"
+	case OutcomeError:
+		return "error"
+	case OutcomeCancelled:
+		return "cancelled"
+	case OutcomeTimedOut:
+		return "timed_out"
+	case OutcomePanicked:
+		return "panicked"
+	default:
+		return "unknown"
+	}
+}
+
+// Outcome describes how a routine finished, passed to the WithOnComplete callback
+type Outcome struct {
+	Type      OutcomeType
+	Err       error            // worker error, ctx.Err() if the worker returned nil after cancellation, or the panic wrapped as an error
+	Class     types.ErrorClass // the worker's return value as classified by WithErrorClassifier, failure for panics
+	Duration  time.Duration
+	RoutineID string
+}
+
+// classifyOutcome decides the outcome from the worker result and the routine context.
+// Must run before the routine's context is cancelled by cleanup, otherwise every routine looks cancelled.
+// Precedence: panic, then timeout, then cancellation, then the worker's error.
+func classifyOutcome(ctx context.Context, workerErr error, panicked bool, panicValue any) (OutcomeType, error) {
+	if panicked {
+		if panicValue == nil {
+			return OutcomePanicked, errors.New("panic")
+		}
+		return OutcomePanicked, fmt.Errorf("panic: %v", panicValue)
+	}
+
+	ctxErr := ctx.Err()
+	if workerErr == nil {
+		workerErr = ctxErr
+	}
+	switch {
+	case errors.Is(ctxErr, context.DeadlineExceeded):
+		return OutcomeTimedOut, workerErr
+	case ctxErr != nil:
+		return OutcomeCancelled, workerErr
+	case workerErr != nil:
+		return OutcomeError, workerErr
+	default:
+		return OutcomeSuccess, nil
+	}
+}
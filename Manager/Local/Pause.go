@@ -0,0 +1,85 @@
+package Local
+
+import (
+	"context"
+
+	"github.com/neerajchowdary889/GoRoutinesManager/metrics"
+	"github.com/neerajchowdary889/GoRoutinesManager/types"
+)
+
+// Pause pauses the goroutines of a function without cancelling them, e.g. pollers during maintenance.
+// Go can't preempt a running worker, so pausing is cooperative: it only holds up workers that wait on
+// IsPaused or call WaitIfPaused at their loop boundaries, others carry on. Goroutines of the function
+// spawned while it is paused start paused. Pausing a paused function does nothing.
+func (LM *LocalManagerStruct) Pause(functionName string) error {
+	localManager, err := types.GetLocalManager(LM.AppName, LM.LocalName)
+	if err != nil {
+		return err
+	}
+	if localManager.PauseFunction(functionName) {
+		metrics.RecordFunctionOperation("pause", LM.AppName, LM.LocalName, functionName)
+	}
+	return nil
+}
+
+// Resume releases the goroutines of a function paused with Pause. Resuming a function that isn't paused does nothing.
+func (LM *LocalManagerStruct) Resume(functionName string) error {
+	localManager, err := types.GetLocalManager(LM.AppName, LM.LocalName)
+	if err != nil {
+		return err
+	}
+	if localManager.ResumeFunction(functionName) {
+		metrics.RecordFunctionOperation("resume", LM.AppName, LM.LocalName, functionName)
+	}
+	return nil
+}
+
+// IsFunctionPaused reports whether the function is paused, false if the local manager doesn't exist
+func (LM *LocalManagerStruct) IsFunctionPaused(functionName string) bool {
+	localManager, err := types.GetLocalManager(LM.AppName, LM.LocalName)
+	if err != nil {
+		return false
+	}
+	return localManager.IsFunctionPaused(functionName)
+}
+
+// IsPaused returns a channel that is closed while the function of the worker owning ctx isn't paused,
+// receiving from it blocks while the function is paused. Wait on it together with ctx.Done() so a paused
+// worker still stops on cancellation. Contexts not handed out by Go are never paused.
+//
+// Example:
+//
+//	localMgr.Go("poller", func(ctx context.Context) error {
+//	    for {
+//	        select {
+//	        case <-Local.IsPaused(ctx):
+//	        case <-ctx.Done():
+//	            return ctx.Err()
+//	        }
+//	        poll()
+//	    }
+//	})
+func IsPaused(ctx context.Context) <-chan struct{} {
+	routine, _ := ctx.Value(routineContextKey{}).(*types.Routine)
+	return routine.Resumed()
+}
+
+// WaitIfPaused blocks while the function of the worker owning ctx is paused.
+// Returns ctx.Err() if the context is cancelled, while paused or not, nil otherwise.
+//
+// Example:
+//
+//	for {
+//	    if err := Local.WaitIfPaused(ctx); err != nil {
+//	        return err
+//	    }
+//	    poll()
+//	}
+func WaitIfPaused(ctx context.Context) error {
+	select {
+	case <-IsPaused(ctx):
+		return ctx.Err()
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
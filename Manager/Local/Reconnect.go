@@ -0,0 +1,103 @@
+package Local
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/neerajchowdary889/GoRoutinesManager/Manager/Interface"
+	"github.com/neerajchowdary889/GoRoutinesManager/metrics"
+	"github.com/neerajchowdary889/GoRoutinesManager/types"
+)
+
+// ReconnectConfig configures GoReconnecting, see types.ReconnectConfig
+type ReconnectConfig = types.ReconnectConfig
+
+// Defaults applied to zero fields of ReconnectConfig
+const (
+	DefaultReconnectInitialBackoff = 100 * time.Millisecond
+	DefaultReconnectMaxBackoff     = 30 * time.Second
+	DefaultReconnectMultiplier     = 2.0
+)
+
+// withReconnectDefaults fills the zero fields of the config
+func withReconnectDefaults(cfg ReconnectConfig) ReconnectConfig {
+	if cfg.InitialBackoff <= 0 {
+		cfg.InitialBackoff = DefaultReconnectInitialBackoff
+	}
+	if cfg.MaxBackoff <= 0 {
+		cfg.MaxBackoff = DefaultReconnectMaxBackoff
+	}
+	if cfg.MaxBackoff < cfg.InitialBackoff {
+		cfg.MaxBackoff = cfg.InitialBackoff
+	}
+	if cfg.Multiplier < 1 {
+		cfg.Multiplier = DefaultReconnectMultiplier
+	}
+	return cfg
+}
+
+// GoReconnecting spawns a routine that maintains a connection for as long as its context lives.
+// It calls connect, hands the connection to run until run returns, closes the connection and
+// reconnects. Failed connects back off exponentially, the backoff resets after a successful connect
+// so a connection that drops after a steady run is retried right away.
+// The loop stops and the routine completes with nil once the context is cancelled.
+// Every connect attempt is counted in the reconnect metric with result "success" or "failure".
+//
+// Example:
+//
+//	localMgr.GoReconnecting("feed",
+//	    func(ctx context.Context) (io.Closer, error) { return net.Dial("tcp", addr) },
+//	    func(ctx context.Context, conn io.Closer) error { return consume(ctx, conn.(net.Conn)) },
+//	    ReconnectConfig{InitialBackoff: time.Second, MaxBackoff: time.Minute},
+//	    AddToWaitGroup("feed"))
+func (LM *LocalManagerStruct) GoReconnecting(functionName string, connect func(ctx context.Context) (io.Closer, error), run func(ctx context.Context, conn io.Closer) error, cfg ReconnectConfig, opts ...Interface.GoroutineOption) error {
+	cfg = withReconnectDefaults(cfg)
+
+	return LM.Go(functionName, func(ctx context.Context) error {
+		backoff := cfg.InitialBackoff
+		for {
+			if ctx.Err() != nil {
+				return nil
+			}
+
+			conn, err := connect(ctx)
+			if err != nil {
+				metrics.RecordReconnectAttempt(LM.AppName, LM.LocalName, functionName, "failure")
+				if !sleepContext(ctx, backoff) {
+					return nil
+				}
+				backoff = time.Duration(float64(backoff) * cfg.Multiplier)
+				if backoff > cfg.MaxBackoff {
+					backoff = cfg.MaxBackoff
+				}
+				continue
+			}
+			metrics.RecordReconnectAttempt(LM.AppName, LM.LocalName, functionName, "success")
+			backoff = cfg.InitialBackoff
+
+			// The error only ends this connection, the loop decides whether to reconnect
+			_ = run(ctx, conn)
+			conn.Close()
+
+			if ctx.Err() != nil {
+				return nil
+			}
+			if !sleepContext(ctx, backoff) {
+				return nil
+			}
+		}
+	}, opts...)
+}
+
+// sleepContext waits for d, returning false if the context is cancelled first
+func sleepContext(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
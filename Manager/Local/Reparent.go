@@ -0,0 +1,49 @@
+package Local
+
+import (
+	"context"
+
+	"github.com/neerajchowdary889/GoRoutinesManager/Manager/Errors"
+	"github.com/neerajchowdary889/GoRoutinesManager/metrics"
+	"github.com/neerajchowdary889/GoRoutinesManager/types"
+)
+
+// CancelContext cancels the local manager's context, which cancels every routine following it.
+// Unlike Shutdown it doesn't wait for the routines, force-remove them or touch the wait groups:
+// routines see ctx.Done() and clean up through their own defers, the local manager stays registered.
+// The context stays cancelled, so routines spawned afterwards start cancelled. Routines moved with
+// ReparentRoutines follow their new parent and aren't affected.
+func (LM *LocalManagerStruct) CancelContext() error {
+	localManager, err := types.GetLocalManager(LM.AppName, LM.LocalName)
+	if err != nil {
+		return err
+	}
+	localManager.CancelContext()
+	metrics.RecordManagerOperation("local", "cancel_context", LM.AppName)
+	return nil
+}
+
+// ReparentRoutines moves the cancellation of every live routine of the local manager over to newParent.
+// The routines keep running with the context they were handed, from now on it is cancelled when newParent is done
+// instead of when the local context is. Routines already cancelled are left alone, routines spawned later still
+// follow the local context.
+//
+// Example:
+//
+//	// The subsystem restarted with a fresh context, keep the long-lived workers running under it
+//	localMgr.ReparentRoutines(subsystemCtx)
+func (LM *LocalManagerStruct) ReparentRoutines(newParent context.Context) error {
+	if newParent == nil {
+		return Errors.ErrNilParentContext
+	}
+	routines, err := LM.GetAllGoroutines()
+	if err != nil {
+		return err
+	}
+
+	for _, routine := range routines {
+		routine.Reparent(newParent)
+	}
+	metrics.RecordManagerOperation("local", "reparent", LM.AppName)
+	return nil
+}
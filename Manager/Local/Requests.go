@@ -0,0 +1,50 @@
+package Local
+
+import (
+	"context"
+	"time"
+
+	"github.com/neerajchowdary889/GoRoutinesManager/types"
+)
+
+// requestTracker finds the tracker of the routine owning ctx, nil if it wasn't spawned WithRequestTracker
+func requestTracker(ctx context.Context) *types.RequestTracker {
+	routine, ok := ctx.Value(routineContextKey{}).(*types.Routine)
+	if !ok {
+		return nil
+	}
+	return routine.GetRequestTracker()
+}
+
+// BeginRequest marks the start of a unit of work in a worker spawned WithRequestTracker.
+// Returns false, tracking nothing, if ctx doesn't belong to such a worker.
+func BeginRequest(ctx context.Context) bool {
+	tracker := requestTracker(ctx)
+	if tracker == nil {
+		return false
+	}
+	tracker.Begin()
+	return true
+}
+
+// EndRequest marks the end of a unit of work started with BeginRequest
+func EndRequest(ctx context.Context) {
+	if tracker := requestTracker(ctx); tracker != nil {
+		tracker.End()
+	}
+}
+
+// waitForRequests blocks until the routines have no request in flight or the deadline passes
+func waitForRequests(routines []*types.Routine, deadline time.Time) {
+	for _, routine := range routines {
+		tracker := routine.GetRequestTracker()
+		if tracker == nil {
+			continue
+		}
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return
+		}
+		tracker.WaitIdle(remaining)
+	}
+}
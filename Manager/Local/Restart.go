@@ -0,0 +1,118 @@
+package Local
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"time"
+
+	"github.com/neerajchowdary889/GoRoutinesManager/Manager/Errors"
+	"github.com/neerajchowdary889/GoRoutinesManager/metrics"
+	"github.com/neerajchowdary889/GoRoutinesManager/types"
+)
+
+// runWorker runs the worker once, running it again while it fails as configured WithRetry.
+// The backoff waits on routineCtx so a cancelled routine stops retrying.
+func (LM *LocalManagerStruct) runWorker(workerCtx, routineCtx context.Context, functionName string, workerFunc func(ctx context.Context) error, opts *goroutineOptions) error {
+	err := workerFunc(workerCtx)
+	for attempt := 1; err != nil && attempt < opts.retryAttempts; attempt++ {
+		backoff := opts.retryBackoff
+		if opts.retryMaxDelay > 0 {
+			backoff = ComputeBackoff(attempt-1, opts.retryBackoff, opts.retryMaxDelay, opts.retryJitter)
+		}
+		if Sleep(routineCtx, backoff) != nil {
+			break
+		}
+		metrics.RecordGoroutineOperation("retry", LM.AppName, LM.LocalName, functionName)
+		err = workerFunc(workerCtx)
+	}
+	return err
+}
+
+// superviseWorker runs the worker under a fresh child of routineCtx per run, restarting it as opts.restart allows.
+// It returns the error of the last run. A panic that won't be restarted isn't recovered here,
+// it reaches the routine's own recovery with its original stack.
+func (LM *LocalManagerStruct) superviseWorker(routineCtx context.Context, routine *types.Routine, observe bool, workerFunc func(ctx context.Context) error, opts *goroutineOptions) error {
+	policy := opts.restart
+	functionName := routine.GetFunctionName()
+	restarts := 0
+	for {
+		runCtx, runCancel := context.WithCancel(routineCtx)
+		workerCtx := context.Context(runCtx)
+		if observe {
+			workerCtx = &observedContext{Context: runCtx, routine: routine}
+		}
+		runStart := time.Now()
+
+		// Decides after a run whether another one is allowed, failed covers errors and panics
+		canRestart := func(failed bool) bool {
+			if policy.Mode == types.RestartOnFailure && !failed {
+				return false
+			}
+			if routineCtx.Err() != nil {
+				return false
+			}
+			if policy.ResetWindow > 0 && time.Since(runStart) >= policy.ResetWindow {
+				restarts = 0
+			}
+			return policy.MaxRestarts <= 0 || restarts < policy.MaxRestarts
+		}
+
+		err, restart := LM.runSupervised(workerCtx, routineCtx, functionName, workerFunc, opts, canRestart)
+		// Anything the run left tied to its context goes away with it
+		runCancel()
+		if !restart {
+			return err
+		}
+
+		restarts++
+		routine.IncrementRestarts()
+		if Sleep(routineCtx, policy.Delay) != nil {
+			return err
+		}
+		metrics.RecordGoroutineOperation("restart", LM.AppName, LM.LocalName, functionName)
+	}
+}
+
+// runSupervised runs the worker once for superviseWorker and reports whether it should be restarted.
+// A panic is only recovered when panic recovery is on and canRestart allows another run.
+func (LM *LocalManagerStruct) runSupervised(workerCtx, routineCtx context.Context, functionName string, workerFunc func(ctx context.Context) error, opts *goroutineOptions, canRestart func(failed bool) bool) (err error, restart bool) {
+	returned := false
+	defer func() {
+		if returned || !opts.panicRecovery || !canRestart(true) {
+			return
+		}
+		// nil means the goroutine is exiting through runtime.Goexit, let it go
+		if r := recover(); r != nil {
+			metrics.RecordOperationError("goroutine", "panic", fmt.Sprintf("function: %s, panic: %v", functionName, r))
+			if opts.onPanic != nil {
+				LM.runOnPanic(opts.onPanic, functionName, r, debug.Stack())
+			}
+			err = fmt.Errorf("%w: %v", Errors.ErrWorkerPanicked, r)
+			restart = true
+		}
+	}()
+	err = LM.runWorker(workerCtx, routineCtx, functionName, workerFunc, opts)
+	returned = true
+	return err, canRestart(err != nil)
+}
+
+// GetRoutineRestartCount returns how many times the routine's worker was restarted WithRestart.
+// Finished routines are still readable while they are kept in the completed retention, 0 if the routine is unknown.
+func (LM *LocalManagerStruct) GetRoutineRestartCount(routineID string) int {
+	localManager, err := types.GetLocalManager(LM.AppName, LM.LocalName)
+	if err != nil {
+		return 0
+	}
+
+	routine, err := localManager.GetRoutine(routineID)
+	if errors.Is(err, Errors.ErrRoutineCompleted) {
+		routine, _ = localManager.GetCompletedRoutine(routineID)
+		err = nil
+	}
+	if err != nil || routine == nil {
+		return 0
+	}
+	return routine.GetRestartCount()
+}
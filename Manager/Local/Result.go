@@ -0,0 +1,75 @@
+package Local
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/neerajchowdary889/GoRoutinesManager/Manager/Errors"
+	"github.com/neerajchowdary889/GoRoutinesManager/Manager/Interface"
+	"github.com/neerajchowdary889/GoRoutinesManager/types"
+)
+
+// GoWithResult spawns a goroutine like Go and keeps the value and error its worker returns.
+// Returns the routine ID to read the result with GetRoutineResult once the routine is done.
+// A panicking worker stores Errors.ErrWorkerPanicked as its error.
+// It isn't queued in the spawn queue, over Metadata.MaxRoutines it fails with Errors.ErrMaxRoutinesExceeded.
+//
+// Example:
+//
+//	routineID, _ := localMgr.GoWithResult("sum", func(ctx context.Context) (interface{}, error) {
+//	    return 1 + 2, nil
+//	})
+//	localMgr.WaitForRoutine(routineID, time.Second)
+//	value, err, done := localMgr.GetRoutineResult(routineID)
+func (LM *LocalManagerStruct) GoWithResult(functionName string, workerFunc func(ctx context.Context) (interface{}, error), opts ...Interface.GoroutineOption) (string, error) {
+	options := defaultGoroutineOptions()
+	for _, opt := range opts {
+		if localOpt, ok := opt.(Option); ok {
+			localOpt(options)
+		}
+	}
+
+	// The routine ID has to exist when this returns, a queued spawn wouldn't have one yet
+	options.noQueue = true
+
+	worker := func(ctx context.Context) error {
+		routine, _ := ctx.Value(routineContextKey{}).(*types.Routine)
+		// Store the result before the worker returns, so it is set by the time the done channel closes
+		defer func() {
+			if r := recover(); r != nil {
+				routine.SetResult(nil, fmt.Errorf("%w: %v", Errors.ErrWorkerPanicked, r))
+				panic(r)
+			}
+		}()
+		value, err := workerFunc(ctx)
+		routine.SetResult(value, err)
+		return err
+	}
+	return LM.spawnGoroutine(functionName, worker, options)
+}
+
+// GetRoutineResult returns the value and error stored by a worker spawned with GoWithResult.
+// done is false while the routine is still running, or if it is unknown or no longer retained,
+// in which case err tells which.
+func (LM *LocalManagerStruct) GetRoutineResult(routineID string) (interface{}, error, bool) {
+	localManager, err := types.GetLocalManager(LM.AppName, LM.LocalName)
+	if err != nil {
+		return nil, err, false
+	}
+
+	routine, err := localManager.GetRoutine(routineID)
+	if errors.Is(err, Errors.ErrRoutineCompleted) {
+		routine, _ = localManager.GetCompletedRoutine(routineID)
+		err = nil
+	}
+	if err != nil || routine == nil {
+		return nil, err, false
+	}
+
+	result, ok := routine.GetResult()
+	if !ok {
+		return nil, nil, false
+	}
+	return result.Value, result.Err, true
+}
@@ -2,9 +2,11 @@ package Local
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/neerajchowdary889/GoRoutinesManager/Context"
+	"github.com/neerajchowdary889/GoRoutinesManager/Manager/Errors"
 	"github.com/neerajchowdary889/GoRoutinesManager/metrics"
 	"github.com/neerajchowdary889/GoRoutinesManager/types"
 )
@@ -12,7 +14,7 @@ import (
 // Routine management methods - these operate on individual routines by ID
 
 // CancelRoutine cancels a routine's context by its ID.
-// Returns an error if the routine is not found.
+// Returns an error if the routine is not found, Errors.ErrRoutineCompleted if it already finished.
 func (LM *LocalManagerStruct) CancelRoutine(routineID string) error {
 	localManager, err := types.GetLocalManager(LM.AppName, LM.LocalName)
 	if err != nil {
@@ -22,7 +24,11 @@ func (LM *LocalManagerStruct) CancelRoutine(routineID string) error {
 
 	routine, err := localManager.GetRoutine(routineID)
 	if err != nil {
-		metrics.RecordOperationError("goroutine", "cancel", "routine_not_found")
+		if errors.Is(err, Errors.ErrRoutineCompleted) {
+			metrics.RecordOperationError("goroutine", "cancel", "routine_completed")
+		} else {
+			metrics.RecordOperationError("goroutine", "cancel", "routine_not_found")
+		}
 		return err
 	}
 
@@ -38,7 +44,8 @@ func (LM *LocalManagerStruct) CancelRoutine(routineID string) error {
 }
 
 // WaitForRoutine blocks until the routine's done channel is signaled or the timeout expires.
-// Returns true if the routine completed, false if timeout occurred or routine not found.
+// Returns true if the routine completed, including one already untracked but still retained,
+// false if timeout occurred or routine not found.
 func (LM *LocalManagerStruct) WaitForRoutine(routineID string, timeout time.Duration) bool {
 	localManager, err := types.GetLocalManager(LM.AppName, LM.LocalName)
 	if err != nil {
@@ -47,7 +54,7 @@ func (LM *LocalManagerStruct) WaitForRoutine(routineID string, timeout time.Dura
 
 	routine, err := localManager.GetRoutine(routineID)
 	if err != nil {
-		return false
+		return errors.Is(err, Errors.ErrRoutineCompleted)
 	}
 
 	doneChan := routine.DoneChan()
@@ -64,7 +71,7 @@ func (LM *LocalManagerStruct) WaitForRoutine(routineID string, timeout time.Dura
 }
 
 // IsRoutineDone checks if a routine's done channel has been signaled.
-// Returns false if routine is not found or done channel is nil.
+// Returns true for a completed routine still in retention, false if routine is not found or done channel is nil.
 func (LM *LocalManagerStruct) IsRoutineDone(routineID string) bool {
 	localManager, err := types.GetLocalManager(LM.AppName, LM.LocalName)
 	if err != nil {
@@ -73,7 +80,7 @@ func (LM *LocalManagerStruct) IsRoutineDone(routineID string) bool {
 
 	routine, err := localManager.GetRoutine(routineID)
 	if err != nil {
-		return false
+		return errors.Is(err, Errors.ErrRoutineCompleted)
 	}
 
 	doneChan := routine.DoneChan()
@@ -178,7 +185,7 @@ func (LM *LocalManagerStruct) IsRoutineContextCancelled(routineID string) bool {
 }
 
 // GetRoutine returns a routine by its ID.
-// Returns an error if the routine is not found.
+// Returns an error if the routine is not found, Errors.ErrRoutineCompleted if it already finished.
 func (LM *LocalManagerStruct) GetRoutine(routineID string) (*types.Routine, error) {
 	localManager, err := types.GetLocalManager(LM.AppName, LM.LocalName)
 	if err != nil {
@@ -188,6 +195,9 @@ func (LM *LocalManagerStruct) GetRoutine(routineID string) (*types.Routine, erro
 }
 
 // WaitForFunction waits for all goroutines of a specific function to complete.
+// With types.AutoCleanupFunctionWgs the group of a function that ran before may have been reclaimed,
+// in that case it waits for the routines still tracked under the function name instead.
+// It fails with ErrFunctionWgNotFound for a function that has no group and was never spawned.
 func (LM *LocalManagerStruct) WaitForFunction(functionName string) error {
 	localManager, err := types.GetLocalManager(LM.AppName, LM.LocalName)
 	if err != nil {
@@ -196,6 +206,12 @@ func (LM *LocalManagerStruct) WaitForFunction(functionName string) error {
 
 	wg, err := localManager.GetFunctionWg(functionName)
 	if err != nil {
+		if types.AutoCleanupFunctionWgs && localManager.GetFunctionStats(functionName).Spawned > 0 {
+			// The group was reclaimed after its last routine finished, or its routines never joined one.
+			// Either way, wait for whatever is still tracked under the function name.
+			LM.waitForRoutines(functionName)
+			return nil
+		}
 		return err // No wait group for this function
 	}
 
@@ -203,6 +219,42 @@ func (LM *LocalManagerStruct) WaitForFunction(functionName string) error {
 	return nil
 }
 
+// waitForRoutines waits for the done channels of the routines currently tracked for the function
+func (LM *LocalManagerStruct) waitForRoutines(functionName string) {
+	routines, err := LM.GetRoutinesByFunctionName(functionName)
+	if err != nil {
+		return
+	}
+	for _, routine := range routines {
+		if done := routine.DoneChan(); done != nil {
+			<-done
+		}
+	}
+}
+
+// WaitForFunctionStarted blocks until at least n routines spawned under functionName have begun running their worker.
+// Starts are counted since the local manager was created, so routines that already finished count too.
+// Pair it with a channel the workers block on to assert on running routines without sleeping.
+func (LM *LocalManagerStruct) WaitForFunctionStarted(functionName string, n int) error {
+	localManager, err := types.GetLocalManager(LM.AppName, LM.LocalName)
+	if err != nil {
+		return err
+	}
+	return localManager.WaitFunctionStarted(context.Background(), functionName, n)
+}
+
+// WaitForFunctionStartedWithTimeout is WaitForFunctionStarted giving up after timeout.
+// Returns true if n routines started in time.
+func (LM *LocalManagerStruct) WaitForFunctionStartedWithTimeout(functionName string, n int, timeout time.Duration) bool {
+	localManager, err := types.GetLocalManager(LM.AppName, LM.LocalName)
+	if err != nil {
+		return false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return localManager.WaitFunctionStarted(ctx, functionName, n) == nil
+}
+
 // WaitForFunctionWithTimeout waits for all goroutines of a function with a timeout.
 // Returns true if all completed, false if timeout occurred.
 //
@@ -244,6 +296,41 @@ func (LM *LocalManagerStruct) WaitForFunctionWithTimeout(functionName string, ti
 	}
 }
 
+// WaitForAll waits for every goroutine tracked by the local manager to complete, whatever its function.
+// It waits on the local manager's wait group, so spawn nothing new in the local manager while waiting.
+func (LM *LocalManagerStruct) WaitForAll() error {
+	localManager, err := types.GetLocalManager(LM.AppName, LM.LocalName)
+	if err != nil {
+		return err
+	}
+	if localManager.Wg != nil {
+		localManager.Wg.Wait()
+	}
+	return nil
+}
+
+// WaitForAllWithTimeout is WaitForAll giving up after timeout, it returns true if everything completed in time.
+// Like WaitForFunctionWithTimeout, the waiter goroutine exits on its own once the wait group completes.
+func (LM *LocalManagerStruct) WaitForAllWithTimeout(timeout time.Duration) bool {
+	done := make(chan struct{}, 1) // Buffered so the waiter never blocks after a timeout
+
+	go func() {
+		if err := LM.WaitForAll(); err == nil {
+			select {
+			case done <- struct{}{}:
+			default:
+			}
+		}
+	}()
+
+	select {
+	case <-done:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
+
 // GetFunctionGoroutineCount returns the number of goroutines for a specific function.
 func (LM *LocalManagerStruct) GetFunctionGoroutineCount(functionName string) int {
 	routines, err := LM.GetAllGoroutines()
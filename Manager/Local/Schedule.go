@@ -0,0 +1,68 @@
+package Local
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/neerajchowdary889/GoRoutinesManager/Manager/Errors"
+	"github.com/neerajchowdary889/GoRoutinesManager/Manager/Interface"
+	"github.com/neerajchowdary889/GoRoutinesManager/metrics"
+	"github.com/neerajchowdary889/GoRoutinesManager/types"
+)
+
+// ScheduleConfig configures GoEvery, see types.ScheduleConfig
+type ScheduleConfig = types.ScheduleConfig
+
+// GoEvery spawns a routine that calls fn every cfg.Interval until its context is cancelled.
+// A failing run doesn't stop the schedule, the error is recorded and the next tick runs as usual.
+//
+// With cfg.RunImmediately the first run happens synchronously on the caller's goroutine, with a
+// context derived from the local manager, before the routine is spawned. If it fails its error is
+// returned as is and nothing is scheduled, like a cron "run at start" that must succeed.
+//
+// Example:
+//
+//	localMgr.GoEvery("refresh-cache", ScheduleConfig{Interval: time.Minute, RunImmediately: true},
+//	    func(ctx context.Context) error { return cache.Refresh(ctx) },
+//	    AddToWaitGroup("refresh-cache"))
+func (LM *LocalManagerStruct) GoEvery(functionName string, cfg ScheduleConfig, fn func(ctx context.Context) error, opts ...Interface.GoroutineOption) error {
+	if cfg.Interval <= 0 {
+		metrics.RecordOperationError("goroutine", "schedule", "invalid_interval")
+		return fmt.Errorf("%w: %s", Errors.ErrInvalidInterval, functionName)
+	}
+	// Checked up front as well, the first run must not happen for a routine Go would reject
+	if types.IsReservedFunctionName(functionName) {
+		metrics.RecordOperationError("goroutine", "spawn", "reserved_function_name")
+		return fmt.Errorf("%w: %s", Errors.ErrReservedFunctionName, functionName)
+	}
+
+	if cfg.RunImmediately {
+		localManager, err := types.GetLocalManager(LM.AppName, LM.LocalName)
+		if err != nil {
+			return err
+		}
+		ctx, cancel := localManager.SpawnChild()
+		err = fn(ctx)
+		cancel()
+		if err != nil {
+			metrics.RecordOperationError("goroutine", "scheduled_run", "run_failed")
+			return err
+		}
+	}
+
+	return LM.Go(functionName, func(ctx context.Context) error {
+		ticker := time.NewTicker(cfg.Interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-ticker.C:
+				if err := fn(ctx); err != nil {
+					metrics.RecordOperationError("goroutine", "scheduled_run", "run_failed")
+				}
+			}
+		}
+	}, opts...)
+}
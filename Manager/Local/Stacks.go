@@ -0,0 +1,82 @@
+package Local
+
+import (
+	"bytes"
+	"context"
+	"runtime/pprof"
+	"strconv"
+
+	"github.com/neerajchowdary889/GoRoutinesManager/metrics"
+	"github.com/neerajchowdary889/GoRoutinesManager/types"
+)
+
+// pprof label keys attached to every spawned goroutine.
+// They show up in goroutine/CPU profiles so samples can be attributed to a function group.
+const (
+	Label_AppName      = "app_name"
+	Label_LocalName    = "local_name"
+	Label_FunctionName = "function_name"
+)
+
+// labelRoutine tags the calling goroutine with the manager's pprof labels.
+// Must be called from inside the spawned goroutine, goroutines started by the worker inherit the labels.
+func (LM *LocalManagerStruct) labelRoutine(functionName string) {
+	pprof.SetGoroutineLabels(pprof.WithLabels(context.Background(), pprof.Labels(
+		Label_AppName, LM.AppName,
+		Label_LocalName, LM.LocalName,
+		Label_FunctionName, functionName,
+	)))
+}
+
+// DumpFunctionStacks returns the current stacks of all goroutines spawned for the given function.
+// runtime.Stack doesn't carry pprof labels, so the goroutine profile is used instead and
+// filtered to the records labelled with this manager and function.
+// Identical stacks are grouped by the profile, each record starts with the number of goroutines sharing it.
+// Returns an empty dump if no goroutine of the function is currently running.
+func (LM *LocalManagerStruct) DumpFunctionStacks(functionName string) ([]byte, error) {
+	if _, err := types.GetLocalManager(LM.AppName, LM.LocalName); err != nil {
+		metrics.RecordOperationError("function", "dump_stacks", "get_local_manager_failed")
+		return nil, err
+	}
+
+	var profile bytes.Buffer
+	if err := pprof.Lookup("goroutine").WriteTo(&profile, 1); err != nil {
+		metrics.RecordOperationError("function", "dump_stacks", "profile_failed")
+		return nil, err
+	}
+
+	// Labels are printed as sorted "key":"value" pairs, match each one we care about
+	wanted := [][]byte{
+		[]byte(strconv.Quote(Label_AppName) + ":" + strconv.Quote(LM.AppName)),
+		[]byte(strconv.Quote(Label_LocalName) + ":" + strconv.Quote(LM.LocalName)),
+		[]byte(strconv.Quote(Label_FunctionName) + ":" + strconv.Quote(functionName)),
+	}
+
+	var dump bytes.Buffer
+	// Records are separated by blank lines, the first block is the profile header
+	for _, record := range bytes.Split(profile.Bytes(), []byte("\n\n")) {
+		if matchesLabels(record, wanted) {
+			dump.Write(bytes.TrimSpace(record))
+			dump.WriteString("\n\n")
+		}
+	}
+
+	metrics.RecordFunctionOperation("dump_stacks", LM.AppName, LM.LocalName, functionName)
+	return dump.Bytes(), nil
+}
+
+// matchesLabels reports whether the record's label line contains every wanted pair
+func matchesLabels(record []byte, wanted [][]byte) bool {
+	for _, line := range bytes.Split(record, []byte("\n")) {
+		if !bytes.HasPrefix(line, []byte("# labels: ")) {
+			continue
+		}
+		for _, pair := range wanted {
+			if !bytes.Contains(line, pair) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
@@ -0,0 +1,76 @@
+package Local
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/neerajchowdary889/GoRoutinesManager/types"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Span attribute keys set on the span of every spawned goroutine
+const (
+	Attr_RoutineID = "goroutine.routine_id"
+	Attr_AppName   = "goroutine.app_name"
+	Attr_LocalName = "goroutine.local_name"
+)
+
+// tracerHolder boxes the tracer, atomic.Pointer needs a concrete type
+type tracerHolder struct {
+	tracer trace.Tracer
+}
+
+var tracer atomic.Pointer[tracerHolder]
+
+// SetTracer makes every goroutine spawned afterwards run inside an OpenTelemetry span named after its function,
+// with the routine ID, app and local manager as attributes. The span is a child of the span in the spawn's
+// context, if any, so with GoWithContext it nests under the caller's span, and the worker's own spans nest under it.
+// It ends when the routine finishes, with an error status if the worker failed or panicked.
+// Pass nil to stop tracing, untraced spawns don't pay for it beyond a nil check.
+//
+// Example:
+//
+//	Local.SetTracer(otel.Tracer("jobs"))
+func SetTracer(t trace.Tracer) {
+	if t == nil {
+		tracer.Store(nil)
+		return
+	}
+	tracer.Store(&tracerHolder{tracer: t})
+}
+
+// startRoutineSpan starts the routine's span under ctx, returns a nil span when no tracer is set
+func (LM *LocalManagerStruct) startRoutineSpan(ctx context.Context, routine *types.Routine) (context.Context, trace.Span) {
+	holder := tracer.Load()
+	if holder == nil {
+		return ctx, nil
+	}
+	return holder.tracer.Start(ctx, routine.GetFunctionName(), trace.WithAttributes(
+		attribute.String(Attr_RoutineID, routine.GetID()),
+		attribute.String(Attr_AppName, LM.AppName),
+		attribute.String(Attr_LocalName, LM.LocalName),
+	))
+}
+
+// endRoutineSpan records how the routine finished on its span and ends it, nil spans are ignored
+func endRoutineSpan(span trace.Span, workerErr error, class types.ErrorClass, panicked bool, panicValue interface{}) {
+	if span == nil {
+		return
+	}
+	switch {
+	case panicked:
+		if panicValue != nil {
+			span.RecordError(fmt.Errorf("panic: %v", panicValue))
+		}
+		span.SetStatus(codes.Error, "worker panicked")
+	case workerErr != nil && class == types.ErrorClassFailure:
+		span.RecordError(workerErr)
+		span.SetStatus(codes.Error, workerErr.Error())
+	default:
+		span.SetStatus(codes.Ok, "")
+	}
+	span.End()
+}
@@ -0,0 +1,47 @@
+package Local
+
+import (
+	"context"
+	"errors"
+
+	"github.com/neerajchowdary889/GoRoutinesManager/Manager/Errors"
+	"github.com/neerajchowdary889/GoRoutinesManager/Manager/Interface"
+)
+
+// TryGo spawns a goroutine like Go only if it can start right away, it never blocks and never queues.
+// Every limiter is tried without waiting: a WithRateLimit token, a WithMaxConcurrency slot, a
+// WithGlobalSemaphore slot and a SET_MAX_ROUTINES slot. If any has nothing free, nothing is spawned,
+// whatever the others granted is given back and TryGo returns false with a nil error, so the caller can skip the work. Other failures, such as
+// an uncreated local manager or the memory budget, are returned as errors.
+//
+// Example:
+//
+//	started, err := localMgr.TryGo("refresh-cache", refresh, WithMaxConcurrency("refresh-cache", 1))
+//	if err == nil && !started {
+//	    // A refresh is already running, skip this one
+//	}
+func (LM *LocalManagerStruct) TryGo(functionName string, workerFunc func(ctx context.Context) error, opts ...Interface.GoroutineOption) (bool, error) {
+	options := defaultGoroutineOptions()
+	for _, opt := range opts {
+		if localOpt, ok := opt.(Option); ok {
+			localOpt(options)
+		}
+	}
+	options.try = true
+	options.noQueue = true
+	if _, err := LM.spawnGoroutine(functionName, workerFunc, options); err != nil {
+		if isLimitRefusal(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// isLimitRefusal reports whether a spawn failed only because a limiter had nothing free at the moment
+func isLimitRefusal(err error) bool {
+	return errors.Is(err, Errors.ErrRateLimited) ||
+		errors.Is(err, Errors.ErrMaxConcurrencyExceeded) ||
+		errors.Is(err, Errors.ErrSemaphoreFull) ||
+		errors.Is(err, Errors.ErrMaxRoutinesExceeded)
+}
@@ -1,9 +1,11 @@
 package Local
 
 import (
+	"context"
 	"time"
 
 	"github.com/neerajchowdary889/GoRoutinesManager/Manager/Interface"
+	"github.com/neerajchowdary889/GoRoutinesManager/types"
 )
 
 // Option is a function that configures goroutine options.
@@ -17,19 +19,61 @@ var _ Interface.GoroutineOption = Option(nil)
 // goroutineOptions holds configuration for spawning goroutines
 type goroutineOptions struct {
 	timeout       *time.Duration // nil means no timeout
+	deadline      *time.Time     // nil means no deadline, the earlier of it and the timeout applies
 	panicRecovery bool           // whether to recover from panics
 	waitGroupName string         // function name for wait group (empty means no wait group)
+	leakGrace     *time.Duration // nil means timed out routines are never reported as leaked
+	onComplete    func(Outcome)  // nil means no completion callback
+	trackRequests bool           // whether shutdown waits for in-flight requests before cancelling
+	semaphoreName string         // global semaphore to take a slot from before running (empty means none)
+	softTimeout   *time.Duration // nil means no soft timeout
+	onSoftTimeout func(routineID string)
+	onPanic       func(functionName string, recovered interface{}, stack []byte)
+	memoryBytes   int64 // estimated memory reserved against the budgets while the goroutine runs
+	classifyError func(err error) types.ErrorClass
+	logBufferSize int    // lines kept for Logf, 0 means logging is a no-op
+	limitName     string // per function concurrency limit to take a slot from (empty means none)
+	limitSize     int
+	limitReject   bool   // fail the spawn instead of blocking when the concurrency limit is reached
+	rateName      string // per function spawn rate limit to wait for a token of (empty means none)
+	ratePerSecond float64
+	rateBurst     int
+	retryAttempts int           // total worker runs while it returns an error, 0 or 1 means no retry
+	retryBackoff  time.Duration // fixed delay between runs, or the initial one when retryMaxDelay is set
+	retryMaxDelay time.Duration // cap of the exponential backoff, 0 means the delay is fixed
+	retryJitter   float64
+	restart       types.RestartPolicy
+	parentCtx     context.Context   // set by GoWithContext, nil means the routine only derives from the local context
+	labels        map[string]string // tags for GetRoutinesByLabel and the labeled metric, nil means none
+	priority      int               // spawn queue order once MaxRoutines is reached, higher first
+	noQueue       bool              // fail over MaxRoutines even if a spawn queue is set, for callers needing the ID
+	wgReserved    bool              // the local wait group slot was already added, by GoN for the whole batch
+	try           bool              // take every limiter slot without blocking or fail the spawn, for TryGo
 }
 
 // defaultGoroutineOptions returns the default options
 func defaultGoroutineOptions() *goroutineOptions {
 	return &goroutineOptions{
 		timeout:       nil,
-		panicRecovery: true, // Enabled by default for production safety
+		panicRecovery: defaultPanicRecovery(), // Enabled by default for production safety
 		waitGroupName: "",
+		classifyError: types.DefaultErrorClassifier,
 	}
 }
 
+// defaultPanicRecovery reads the SET_PANIC_RECOVERY metadata flag, true while no metadata is set
+func defaultPanicRecovery() bool {
+	g, err := types.GetGlobalManager()
+	if err != nil {
+		return true
+	}
+	md := g.GetMetadata()
+	if md == nil {
+		return true
+	}
+	return md.GetPanicRecovery()
+}
+
 // WithTimeout sets a timeout for the goroutine.
 // When the timeout expires, the context will be cancelled automatically.
 // The worker function should check ctx.Done() to handle timeout gracefully.
@@ -39,8 +83,24 @@ func WithTimeout(timeout time.Duration) Option {
 	}
 }
 
+// WithDeadline cancels the goroutine's context at the absolute time t, like context.WithDeadline.
+// It suits batch jobs sharing one wall-clock deadline across many goroutines. Combined with WithTimeout,
+// whichever moment comes first applies, and it counts as a timeout for the outcome and WithForceKillOnTimeout.
+//
+// Example:
+//
+//	batchEnd := time.Now().Add(time.Hour)
+//	for _, job := range jobs {
+//	    localMgr.Go("batch", job.Run, WithDeadline(batchEnd))
+//	}
+func WithDeadline(t time.Time) Option {
+	return func(opts *goroutineOptions) {
+		opts.deadline = &t
+	}
+}
+
 // WithPanicRecovery enables or disables panic recovery for the goroutine.
-// Panic recovery is enabled by default for production safety.
+// Panic recovery is enabled by default for production safety, the default follows the SET_PANIC_RECOVERY metadata flag.
 // When enabled, panics in the worker function will be recovered,
 // logged via metrics, and the goroutine will complete normally with cleanup.
 // Set to false only if you want panics to crash the goroutine (not recommended).
@@ -50,6 +110,22 @@ func WithPanicRecovery(enabled bool) Option {
 	}
 }
 
+// WithPanicHandler calls fn when a panic is recovered in the goroutine, with the function name,
+// the recovered value and the stack of the panicking goroutine.
+// It runs before the routine's cleanup, a panicking handler is recovered and doesn't skip it.
+// Has no effect when panic recovery is disabled.
+//
+// Example:
+//
+//	localMgr.Go("worker", work, WithPanicHandler(func(functionName string, recovered interface{}, stack []byte) {
+//	    log.Printf("%s panicked: %v\n%s", functionName, recovered, stack)
+//	}))
+func WithPanicHandler(fn func(functionName string, recovered interface{}, stack []byte)) Option {
+	return func(opts *goroutineOptions) {
+		opts.onPanic = fn
+	}
+}
+
 // AddToWaitGroup adds the goroutine to a function wait group.
 // The functionName parameter specifies which function wait group to use.
 // The wait group will be created if it doesn't exist, and the goroutine
@@ -64,3 +140,277 @@ func AddToWaitGroup(functionName string) Option {
 		opts.waitGroupName = functionName
 	}
 }
+
+// WithForceKillOnTimeout surfaces workers that ignore their context after WithTimeout fires.
+// Go can't kill a goroutine, so if the worker is still running grace after the timeout,
+// the routine is marked as leaked, counted in the leak metric, logged with its spawn site
+// and removed from the active routine map so it no longer shows up as a live routine.
+// It has no effect unless WithTimeout or WithDeadline is also set.
+//
+// Example:
+//
+//	localMgr.Go("worker", func(ctx context.Context) error { ... },
+//	    WithTimeout(5*time.Second),
+//	    WithForceKillOnTimeout(time.Second))
+func WithForceKillOnTimeout(grace time.Duration) Option {
+	return func(opts *goroutineOptions) {
+		opts.leakGrace = &grace
+	}
+}
+
+// WithRequestTracker attaches a request tracker to the goroutine for long-lived loop workers.
+// The worker brackets each unit of work with BeginRequest and EndRequest on its context,
+// and a graceful shutdown waits for the in-flight requests to end before cancelling the context,
+// bounded by the shutdown timeout.
+//
+// Example:
+//
+//	localMgr.Go("server", func(ctx context.Context) error {
+//	    for req := range requests {
+//	        Local.BeginRequest(ctx)
+//	        handle(req)
+//	        Local.EndRequest(ctx)
+//	    }
+//	    return nil
+//	}, WithRequestTracker())
+func WithRequestTracker() Option {
+	return func(opts *goroutineOptions) {
+		opts.trackRequests = true
+	}
+}
+
+// WithGlobalSemaphore makes the goroutine take a slot of the named global semaphore before running the worker,
+// capping concurrency across every app that uses the same name. The semaphore must be registered first with
+// NewGlobalSemaphore on the global manager. The worker doesn't run if its context is done while waiting,
+// the routine then finishes with the context error.
+//
+// Example:
+//
+//	globalMgr.NewGlobalSemaphore("db", 10)
+//	localMgr.Go("query", func(ctx context.Context) error { ... },
+//	    WithGlobalSemaphore("db"))
+func WithGlobalSemaphore(name string) Option {
+	return func(opts *goroutineOptions) {
+		opts.semaphoreName = name
+	}
+}
+
+// WithSoftTimeout calls onExceed once if the worker is still running after d, without cancelling it.
+// It combines with WithTimeout to warn early and cancel later. The callback runs on a timer goroutine,
+// a panic in it is recovered.
+//
+// Example:
+//
+//	localMgr.Go("report", func(ctx context.Context) error { ... },
+//	    WithSoftTimeout(5*time.Second, func(routineID string) {
+//	        log.Printf("report %s is taking longer than 5s", routineID)
+//	    }),
+//	    WithTimeout(30*time.Second))
+func WithSoftTimeout(d time.Duration, onExceed func(routineID string)) Option {
+	return func(opts *goroutineOptions) {
+		opts.softTimeout = &d
+		opts.onSoftTimeout = onExceed
+	}
+}
+
+// WithMemoryEstimate declares how much memory the goroutine is expected to hold while it runs.
+// The estimate is reserved against the local manager's and the global memory budget (see SetMemoryBudget)
+// and Go fails with Errors.ErrMemoryBudgetExceeded if either would go over. It is released when the worker returns.
+// This is coarse admission control for memory heavy workers, nothing is measured.
+//
+// Example:
+//
+//	localMgr.SetMemoryBudget(512 << 20)
+//	localMgr.Go("resize", func(ctx context.Context) error { ... },
+//	    WithMemoryEstimate(64 << 20))
+func WithMemoryEstimate(bytes int64) Option {
+	return func(opts *goroutineOptions) {
+		opts.memoryBytes = bytes
+	}
+}
+
+// WithErrorClassifier decides which worker errors are failures.
+// The class drives the function stats, the error rate watched by SetErrorRateThreshold and Outcome.Class.
+// By default context.Canceled and context.DeadlineExceeded are expected stops, so a worker returning
+// ctx.Err() on shutdown isn't counted as failed. A panicking classifier counts the error as a failure.
+//
+// Example:
+//
+//	localMgr.Go("consumer", consume, WithErrorClassifier(func(err error) types.ErrorClass {
+//	    if errors.Is(err, io.EOF) {
+//	        return types.ErrorClassExpectedStop
+//	    }
+//	    return types.DefaultErrorClassifier(err)
+//	}))
+func WithErrorClassifier(classify func(err error) types.ErrorClass) Option {
+	return func(opts *goroutineOptions) {
+		if classify != nil {
+			opts.classifyError = classify
+		}
+	}
+}
+
+// WithLogBuffer keeps the last size lines the worker logs with Logf, prefixed with the function name and routine ID.
+// The lines stay readable with GetRoutineLogs while the routine runs and after it finishes,
+// for as long as the routine is kept in the completed retention.
+//
+// Example:
+//
+//	localMgr.Go("worker", func(ctx context.Context) error {
+//	    Local.Logf(ctx, "processing batch %d", n)
+//	    return nil
+//	}, WithLogBuffer(100), WithOnComplete(func(outcome Outcome) {
+//	    lines, _ := localMgr.GetRoutineLogs(outcome.RoutineID)
+//	    ...
+//	}))
+func WithLogBuffer(size int) Option {
+	return func(opts *goroutineOptions) {
+		opts.logBufferSize = size
+	}
+}
+
+// WithMaxConcurrency caps how many routines spawned under functionName are tracked at once, independent of MaxRoutines.
+// Go blocks until one of them is untracked, or fails if the local context is done meanwhile.
+// Combine with WithRejectOverConcurrency to fail right away with Errors.ErrMaxConcurrencyExceeded instead.
+// The limit is created with n slots on first use, later spawns under the same name share it whatever n they pass.
+// The slot is released when the routine finishes, even if the worker panics.
+//
+// Example:
+//
+//	for _, url := range urls {
+//	    localMgr.Go("scrape", scrape(url), WithMaxConcurrency("scrape", 3))
+//	}
+func WithMaxConcurrency(functionName string, n int) Option {
+	return func(opts *goroutineOptions) {
+		opts.limitName = functionName
+		opts.limitSize = n
+	}
+}
+
+// WithRejectOverConcurrency makes a spawn over the WithMaxConcurrency limit fail with Errors.ErrMaxConcurrencyExceeded
+// instead of blocking
+func WithRejectOverConcurrency() Option {
+	return func(opts *goroutineOptions) {
+		opts.limitReject = true
+	}
+}
+
+// WithRateLimit caps how fast routines are spawned under functionName to perSecond, with bursts of up to burst.
+// Go waits for a token of the limiter before launching the routine, and fails with the context error if the
+// local context, or the parent of GoWithContext, is done meanwhile. The limiter is created on first use and
+// shared by every later spawn using the same name, whatever rate they pass.
+//
+// Example:
+//
+//	for _, mail := range outbox {
+//	    localMgr.Go("email", send(mail), WithRateLimit("email", 10, 1))
+//	}
+func WithRateLimit(functionName string, perSecond float64, burst int) Option {
+	return func(opts *goroutineOptions) {
+		opts.rateName = functionName
+		opts.ratePerSecond = perSecond
+		opts.rateBurst = burst
+	}
+}
+
+// WithRetry runs the worker again while it returns an error, up to maxAttempts runs in total,
+// waiting backoff between runs. It stops early once the worker returns nil or the context is done,
+// including during the backoff. A panic isn't retried. The routine stays tracked until the last run returns,
+// and the outcome, stats and completion callback only see the last run.
+//
+// Example:
+//
+//	localMgr.Go("sync", syncOnce, WithRetry(3, time.Second))
+func WithRetry(maxAttempts int, backoff time.Duration) Option {
+	return func(opts *goroutineOptions) {
+		opts.retryAttempts = maxAttempts
+		opts.retryBackoff = backoff
+	}
+}
+
+// WithRetryBackoff is WithRetry with an exponential backoff: the delay before retry n (from 0) is
+// min(max, initial * 2^n), moved by a random fraction in [-jitter, +jitter] so retries of many workers spread out.
+// The delays are computed with ComputeBackoff. Like WithRetry, a done context interrupts the wait.
+//
+// Example:
+//
+//	localMgr.Go("fetch", fetch, WithRetryBackoff(5, 100*time.Millisecond, 5*time.Second, 0.2))
+func WithRetryBackoff(maxAttempts int, initial, max time.Duration, jitter float64) Option {
+	return func(opts *goroutineOptions) {
+		opts.retryAttempts = maxAttempts
+		opts.retryBackoff = initial
+		opts.retryMaxDelay = max
+		opts.retryJitter = jitter
+	}
+}
+
+// WithRestart supervises the worker: once it returns, it is run again as the policy's Mode allows,
+// under a fresh child of the routine's context, without the routine being untracked in between.
+// Recovered panics count as failures and go to the panic handler before each restart.
+// Restarts stop at MaxRestarts, or as soon as the routine's context is done, so shutdown and timeouts end them.
+// Each run goes through WithRetry first, the restart count is read with GetRoutineRestartCount.
+//
+// Example:
+//
+//	localMgr.Go("consumer", consume, WithRestart(types.RestartPolicy{
+//	    Mode:        types.RestartOnFailure,
+//	    MaxRestarts: 5,
+//	    ResetWindow: time.Minute,
+//	    Delay:       time.Second,
+//	}))
+func WithRestart(policy types.RestartPolicy) Option {
+	return func(opts *goroutineOptions) {
+		opts.restart = policy
+	}
+}
+
+// WithLabels tags the goroutine with key/value labels, e.g. tenant=acme, to find it later with GetRoutinesByLabel.
+// The label set is also counted in the goroutine_manager_operations_labeled_total metric, with the distinct
+// sets capped at metrics.MaxLabelCombinations: keep values bounded, don't use request or user IDs.
+// Later calls add to the labels of earlier ones.
+//
+// Example:
+//
+//	localMgr.Go("ingest", ingest, WithLabels(map[string]string{"tenant": "acme"}))
+//	routines, _ := localMgr.GetRoutinesByLabel("tenant", "acme")
+func WithLabels(labels map[string]string) Option {
+	return func(opts *goroutineOptions) {
+		if opts.labels == nil {
+			opts.labels = make(map[string]string, len(labels))
+		}
+		for key, value := range labels {
+			opts.labels[key] = value
+		}
+	}
+}
+
+// WithPriority sets where the goroutine goes in the spawn queue when Metadata.MaxRoutines is reached and
+// a queue was set with SetSpawnQueueSize: higher priorities start first, equal ones in the order they were queued.
+// The default priority is 0, negative priorities start after it.
+//
+// Example:
+//
+//	globalMgr.SetSpawnQueueSize(1000)
+//	localMgr.Go("payment", handlePayment, WithPriority(10))
+//	localMgr.Go("report", buildReport, WithPriority(-1))
+func WithPriority(p int) Option {
+	return func(opts *goroutineOptions) {
+		opts.priority = p
+	}
+}
+
+// WithOnComplete registers a callback invoked once the routine finishes, with the classified outcome.
+// It runs on the routine's goroutine before the routine is untracked and its wait groups are released,
+// so anyone waiting on the routine observes the callback as done. A panic in the callback is recovered.
+//
+// Example:
+//
+//	localMgr.Go("worker", func(ctx context.Context) error { ... },
+//	    WithOnComplete(func(outcome Outcome) {
+//	        log.Printf("%s finished: %s after %v (%v)", outcome.RoutineID, outcome.Type, outcome.Duration, outcome.Err)
+//	    }))
+func WithOnComplete(fn func(outcome Outcome)) Option {
+	return func(opts *goroutineOptions) {
+		opts.onComplete = fn
+	}
+}
@@ -0,0 +1,102 @@
+package Contexttests
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/neerajchowdary889/GoRoutinesManager/Context"
+	"github.com/neerajchowdary889/GoRoutinesManager/types"
+)
+
+// expectCancelled fails the test unless ctx is cancelled shortly
+func expectCancelled(t *testing.T, ctx context.Context, what string) {
+	t.Helper()
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatalf("%s wasn't cancelled", what)
+	}
+}
+
+// TestDone_CancelsAppContext verifies that Done cancels the app context and its children,
+// and that the next Get creates a fresh context
+func TestDone_CancelsAppContext(t *testing.T) {
+	fmt.Println("\n=== TestDone_CancelsAppContext ===")
+	appCtx := Context.GetAppContext("done-app")
+	ctx := appCtx.Get()
+	child, cancel := appCtx.NewChildContext()
+	defer cancel()
+
+	appCtx.Done(ctx)
+	expectCancelled(t, ctx, "App context")
+	expectCancelled(t, child, "Child of the app context")
+
+	fresh := appCtx.Get()
+	if fresh == ctx || fresh.Err() != nil {
+		t.Error("Expected a fresh, live app context after Done")
+	}
+	// A stale context doesn't cancel its replacement
+	appCtx.Done(ctx)
+	if fresh.Err() != nil {
+		t.Error("Done with a stale context cancelled its replacement")
+	}
+	appCtx.Shutdown()
+	fmt.Println("✓ Done cancelled the app context and its child")
+}
+
+// TestDone_LocalManagerCancel verifies that the cancel set by SetLocalContext cancels the local's child contexts
+func TestDone_LocalManagerCancel(t *testing.T) {
+	fmt.Println("\n=== TestDone_LocalManagerCancel ===")
+	localManager := (&types.LocalManager{}).SetLocalName("done-local").SetLocalContext()
+	child, cancel := context.WithCancel(localManager.Ctx)
+	defer cancel()
+
+	localManager.Cancel()
+	expectCancelled(t, localManager.Ctx, "Local context")
+	expectCancelled(t, child, "Child of the local context")
+	fmt.Println("✓ Local manager Cancel cancelled its context")
+}
+
+// TestDone_GlobalContext verifies that Done shuts down the current global context only
+func TestDone_GlobalContext(t *testing.T) {
+	fmt.Println("\n=== TestDone_GlobalContext ===")
+	gc := Context.GetGlobalContext()
+	gc.Shutdown()
+	ctx := gc.Init()
+
+	gc.Done(ctx)
+	expectCancelled(t, ctx, "Global context")
+
+	fresh := gc.Init()
+	gc.Done(ctx)
+	if fresh.Err() != nil {
+		t.Error("Done with a stale global context shut down its replacement")
+	}
+	fmt.Println("✓ Done shut down the global context")
+}
+
+// TestDone_ChildAppContext verifies that a child app context follows its parent but not its siblings
+func TestDone_ChildAppContext(t *testing.T) {
+	fmt.Println("\n=== TestDone_ChildAppContext ===")
+	parent := Context.GetAppContext("done-parent")
+	first := Context.GetChildAppContext("done-parent/first", "done-parent")
+	second := Context.GetChildAppContext("done-parent/second", "done-parent")
+	parentCtx, firstCtx, secondCtx := parent.Get(), first.Get(), second.Get()
+
+	first.Done(firstCtx)
+	expectCancelled(t, firstCtx, "Child context")
+	if parentCtx.Err() != nil || secondCtx.Err() != nil {
+		t.Fatal("Cancelling a child cancelled its parent or sibling")
+	}
+	fmt.Println("✓ Child cancelled alone")
+
+	parent.Done(parentCtx)
+	expectCancelled(t, secondCtx, "Sibling of the cancelled child")
+	if fresh := second.Get(); fresh.Err() != nil {
+		t.Error("Expected a fresh child under a fresh parent after the parent was cancelled")
+	}
+	parent.Shutdown()
+	fmt.Println("✓ Parent cancel reached its remaining child")
+}
@@ -0,0 +1,95 @@
+package Contexttests
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/neerajchowdary889/GoRoutinesManager/Context"
+	"github.com/neerajchowdary889/GoRoutinesManager/Logging"
+)
+
+// logEntry is one message received by the capturing logger
+type logEntry struct {
+	level string
+	msg   string
+	kv    []any
+}
+
+// capturingLogger records every message it receives
+type capturingLogger struct {
+	mu      sync.Mutex
+	entries []logEntry
+}
+
+func (l *capturingLogger) record(level, msg string, kv []any) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.entries = append(l.entries, logEntry{level: level, msg: msg, kv: kv})
+}
+
+func (l *capturingLogger) Debug(msg string, kv ...any) { l.record("debug", msg, kv) }
+func (l *capturingLogger) Info(msg string, kv ...any)  { l.record("info", msg, kv) }
+func (l *capturingLogger) Warn(msg string, kv ...any)  { l.record("warn", msg, kv) }
+func (l *capturingLogger) Error(msg string, kv ...any) { l.record("error", msg, kv) }
+
+// captureStdout returns what fn printed to stdout
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("os.Pipe() failed: %v", err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	fn()
+
+	w.Close()
+	out, _ := io.ReadAll(r)
+	return string(out)
+}
+
+// TestSetLogger_AppContextInit verifies that app context init logs at debug level to the injected logger
+// and nothing is printed to stdout
+func TestSetLogger_AppContextInit(t *testing.T) {
+	fmt.Println("\n=== TestSetLogger_AppContextInit ===")
+	logger := &capturingLogger{}
+	Logging.SetLogger(logger)
+	defer Logging.SetLogger(nil)
+
+	out := captureStdout(t, func() {
+		Context.GetAppContext("logger-app").Init()
+		Context.GetAppContext("logger-app").Init()
+		Context.GetAppContext("logger-app").Shutdown()
+	})
+	if out != "" {
+		t.Errorf("Expected nothing on stdout, got %q", out)
+	}
+
+	logger.mu.Lock()
+	defer logger.mu.Unlock()
+	var initialized bool
+	for _, entry := range logger.entries {
+		if entry.msg != "initialized app-level context" {
+			continue
+		}
+		initialized = true
+		if entry.level != "debug" {
+			t.Errorf("Expected the init to log at debug level, got %s", entry.level)
+		}
+		if len(entry.kv) != 2 || entry.kv[0] != "app" || entry.kv[1] != "logger-app" {
+			t.Errorf("Expected app=logger-app, got %v", entry.kv)
+		}
+	}
+	if !initialized {
+		t.Fatalf("Expected an init message, got %+v", logger.entries)
+	}
+	if len(logger.entries) != 3 {
+		t.Errorf("Expected init, already initialized and shutdown messages, got %+v", logger.entries)
+	}
+	fmt.Println("✓ App context init logged at debug level, stdout untouched")
+}
@@ -0,0 +1,53 @@
+package Contexttests
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/neerajchowdary889/GoRoutinesManager/Context"
+)
+
+// TestAcquireRelease verifies that an app context is only cancelled once its last reference is released
+func TestAcquireRelease(t *testing.T) {
+	fmt.Println("\n=== TestAcquireRelease ===")
+	appCtx := Context.GetRefCountedAppContext("refs-app")
+
+	ctx := appCtx.Acquire()
+	if again := appCtx.Acquire(); again != ctx {
+		t.Fatal("Expected both references to share one context")
+	}
+	if refs := appCtx.Refs(); refs != 2 {
+		t.Fatalf("Expected 2 references, got %d", refs)
+	}
+	// A plain GetAppContext handle asserts to the same reference counting
+	if shared, ok := Context.GetAppContext("refs-app").(Context.RefCountedContextInterface); !ok || shared.Refs() != 2 {
+		t.Fatal("Expected GetAppContext to share the references of GetRefCountedAppContext")
+	}
+
+	// Siblings shutting down don't cancel it while references are held
+	appCtx.Shutdown()
+	appCtx.Done(ctx)
+	if ctx.Err() != nil {
+		t.Fatal("App context was cancelled while references are held")
+	}
+
+	if appCtx.Release() {
+		t.Error("Releasing the first reference shouldn't report the last one")
+	}
+	if ctx.Err() != nil {
+		t.Fatal("App context was cancelled with a reference left")
+	}
+	fmt.Println("✓ Context alive after releasing one of two references")
+
+	if !appCtx.Release() {
+		t.Error("Releasing the second reference should report the last one")
+	}
+	expectCancelled(t, ctx, "App context")
+	if refs := appCtx.Refs(); refs != 0 {
+		t.Errorf("Expected no references left, got %d", refs)
+	}
+	if appCtx.Release() {
+		t.Error("Releasing without a reference should be a no-op")
+	}
+	fmt.Println("✓ Context cancelled by the last Release")
+}
@@ -0,0 +1,94 @@
+package Contexttests
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/neerajchowdary889/GoRoutinesManager/Context"
+)
+
+// TestSignalHandling_Default verifies that the global context shuts down on a signal from the injected channel
+func TestSignalHandling_Default(t *testing.T) {
+	fmt.Println("\n=== TestSignalHandling_Default ===")
+	signals := make(chan os.Signal, 1)
+	Context.SetSignalChannel(signals)
+	defer Context.SetSignalChannel(nil)
+
+	// Start from a clean context layer so the handler is set up with the channel
+	Context.GetGlobalContext().Shutdown()
+	ctx := Context.GetGlobalContext().Init()
+
+	signals <- syscall.SIGTERM
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("Global context wasn't cancelled by the signal")
+	}
+	fmt.Println("✓ Global context cancelled on SIGTERM")
+}
+
+// TestSignalHandling_Disabled verifies that the global context ignores signals once signal handling is disabled
+func TestSignalHandling_Disabled(t *testing.T) {
+	fmt.Println("\n=== TestSignalHandling_Disabled ===")
+	signals := make(chan os.Signal, 1)
+	Context.SetSignalChannel(signals)
+	defer Context.SetSignalChannel(nil)
+	Context.DisableSignalHandling()
+	defer Context.EnableSignalHandling()
+
+	Context.GetGlobalContext().Shutdown()
+	ctx := Context.GetGlobalContext().Init()
+
+	// Nobody listens, so the signal stays in the buffer
+	signals <- syscall.SIGTERM
+	select {
+	case <-ctx.Done():
+		t.Fatal("Global context was cancelled although signal handling is disabled")
+	case <-time.After(100 * time.Millisecond):
+	}
+	if len(signals) != 1 {
+		t.Error("Expected the signal to be left unread")
+	}
+
+	// Explicit shutdown still works
+	Context.GetGlobalContext().Shutdown()
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("Global context wasn't cancelled by Shutdown")
+	}
+	fmt.Println("✓ Global context only shut down by an explicit Shutdown")
+}
+
+// TestSetShutdownSignals verifies that only the configured signals shut down the global context
+func TestSetShutdownSignals(t *testing.T) {
+	fmt.Println("\n=== TestSetShutdownSignals ===")
+	signals := make(chan os.Signal, 1)
+	Context.SetSignalChannel(signals)
+	defer Context.SetSignalChannel(nil)
+	Context.SetShutdownSignals(syscall.SIGHUP)
+	defer Context.SetShutdownSignals()
+
+	Context.GetGlobalContext().Shutdown()
+	ctx := Context.GetGlobalContext().Init()
+
+	// SIGTERM isn't configured anymore
+	signals <- syscall.SIGTERM
+	select {
+	case <-ctx.Done():
+		t.Fatal("Global context was cancelled by a signal that isn't configured")
+	case <-time.After(100 * time.Millisecond):
+	}
+	fmt.Println("✓ SIGTERM ignored")
+
+	signals <- syscall.SIGHUP
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("Global context wasn't cancelled by the configured SIGHUP")
+	}
+	fmt.Println("✓ Global context cancelled on SIGHUP")
+}
@@ -0,0 +1,94 @@
+package Contexttests
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/neerajchowdary889/GoRoutinesManager/Context"
+)
+
+// findApp returns the node for the app context with the given name
+func findApp(tree Context.ContextTree, name string) (Context.ContextNode, bool) {
+	for _, node := range tree.Apps {
+		if node.Name == name {
+			return node, true
+		}
+	}
+	return Context.ContextNode{}, false
+}
+
+func TestTree_ReflectsAppContexts(t *testing.T) {
+	fmt.Println("\n=== TestTree_ReflectsAppContexts ===")
+	// Start from a clean context layer
+	Context.GetGlobalContext().Shutdown()
+
+	ctxA := Context.GetAppContext("tree-a").Get()
+	Context.GetAppContext("tree-b").Get()
+
+	tree := Context.Tree()
+	if !tree.Initialized || tree.Global == nil || !tree.Global.Alive {
+		t.Fatalf("Expected an alive global context, got %+v", tree.Global)
+	}
+	for _, name := range []string{"tree-a", "tree-b"} {
+		node, ok := findApp(tree, name)
+		if !ok {
+			t.Fatalf("Expected app context %s in the tree", name)
+		}
+		if !node.Alive || node.Err != "" {
+			t.Errorf("Expected %s to be alive, got %+v", name, node)
+		}
+	}
+	fmt.Println("✓ Created app contexts are listed as alive")
+
+	// Shutting down an app removes it from the registry
+	Context.GetAppContext("tree-a").Shutdown()
+	if ctxA.Err() == nil {
+		t.Fatal("tree-a context should be cancelled")
+	}
+	tree = Context.Tree()
+	if _, ok := findApp(tree, "tree-a"); ok {
+		t.Error("Shut down app context should not be listed")
+	}
+	if node, ok := findApp(tree, "tree-b"); !ok || !node.Alive {
+		t.Error("tree-b should still be alive")
+	}
+	fmt.Println("✓ Shut down app context is gone, the rest stays alive")
+
+	// Global shutdown clears everything
+	Context.GetGlobalContext().Shutdown()
+	tree = Context.Tree()
+	if tree.Initialized || tree.Global != nil || len(tree.Apps) != 0 {
+		t.Errorf("Expected an empty tree after global shutdown, got %+v", tree)
+	}
+	fmt.Println("✓ Global shutdown empties the tree")
+}
+
+func TestTreeHandler_ServesJSON(t *testing.T) {
+	fmt.Println("\n=== TestTreeHandler_ServesJSON ===")
+	Context.GetGlobalContext().Shutdown()
+	defer Context.GetGlobalContext().Shutdown()
+
+	Context.GetAppContext("tree-http").Get()
+
+	rec := httptest.NewRecorder()
+	Context.TreeHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/contexts", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Expected application/json, got %s", ct)
+	}
+
+	var tree Context.ContextTree
+	if err := json.NewDecoder(rec.Body).Decode(&tree); err != nil {
+		t.Fatalf("Failed to decode tree: %v", err)
+	}
+	if node, ok := findApp(tree, "tree-http"); !ok || !node.Alive {
+		t.Errorf("Expected tree-http to be served as alive, got %+v", tree.Apps)
+	}
+	fmt.Println("✓ Handler serves the tree as JSON")
+}
@@ -0,0 +1,44 @@
+package Contexttests
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/neerajchowdary889/GoRoutinesManager/Context"
+)
+
+// TestSetVerbose_Stdout verifies that the context layer prints to stdout only when verbose
+func TestSetVerbose_Stdout(t *testing.T) {
+	fmt.Println("\n=== TestSetVerbose_Stdout ===")
+	defer Context.SetVerbose(false)
+
+	if Context.IsVerbose() {
+		t.Fatal("Verbose should be off by default")
+	}
+	out := captureStdout(t, func() {
+		Context.GetAppContext("quiet-app").Init()
+		Context.GetAppContext("quiet-app").Shutdown()
+	})
+	if out != "" {
+		t.Errorf("Expected nothing on stdout while not verbose, got %q", out)
+	}
+	fmt.Println("✓ Nothing printed while not verbose")
+
+	Context.SetVerbose(true)
+	out = captureStdout(t, func() {
+		Context.GetAppContext("loud-app").Init()
+		Context.GetAppContext("loud-app").Init()
+		Context.GetAppContext("loud-app").Shutdown()
+	})
+	for _, want := range []string{
+		"initialized app-level context app=loud-app",
+		"app context already initialized app=loud-app",
+		"shutting down app-level context app=loud-app",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected %q on stdout while verbose, got %q", want, out)
+		}
+	}
+	fmt.Println("✓ Messages printed while verbose")
+}
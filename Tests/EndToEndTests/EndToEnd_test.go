@@ -280,10 +280,9 @@ func TestComplexSystemWithMetrics(t *testing.T) {
 		fmt.Println("✓ Global Manager shutdown complete")
 	}
 
-	// Give time for goroutines to finish and metrics to update
-	// Keep collector running so metrics reflect the shutdown state
-	fmt.Println("Waiting for metrics to update after shutdown...")
-	time.Sleep(5 * time.Second) // Give collector time to update metrics to 0
+	// Shutdown already flushed the collector, flush once more so the final state is exported
+	metrics.FlushNow()
+	fmt.Println("✓ Metrics flushed after shutdown")
 
 	// Now stop metrics collector (after metrics have been updated)
 	metrics.StopCollector()
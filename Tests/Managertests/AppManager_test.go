@@ -1,12 +1,24 @@
 package Managertests
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/neerajchowdary889/GoRoutinesManager/Manager/App"
+	"github.com/neerajchowdary889/GoRoutinesManager/Manager/Errors"
 	"github.com/neerajchowdary889/GoRoutinesManager/Manager/Global"
+	"github.com/neerajchowdary889/GoRoutinesManager/Manager/Interface"
 	"github.com/neerajchowdary889/GoRoutinesManager/Manager/Local"
+	"github.com/neerajchowdary889/GoRoutinesManager/metrics"
 	"github.com/neerajchowdary889/GoRoutinesManager/types"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 )
 
 func TestAppManager_CreateApp(t *testing.T) {
@@ -287,3 +299,421 @@ func TestAppManager_MultipleApps(t *testing.T) {
 		t.Errorf("Global should see 3 total local managers, got %d", totalCount)
 	}
 }
+
+func TestAppManager_GetRoutinesByFunctionName(t *testing.T) {
+	resetGlobalState()
+
+	appMgr := App.NewAppManager("test-app")
+	_, err := appMgr.CreateApp()
+	if err != nil {
+		t.Fatalf("CreateApp() failed: %v", err)
+	}
+
+	local1, _ := Local.NewLocalManager("test-app", "local1").CreateLocal("local1")
+	local2, _ := Local.NewLocalManager("test-app", "local2").CreateLocal("local2")
+
+	// Same function name spawned in both local managers
+	local1.NewGoRoutine("uploader")
+	local1.NewGoRoutine("uploader")
+	local2.NewGoRoutine("uploader")
+	local2.NewGoRoutine("downloader")
+
+	refs, err := appMgr.GetRoutinesByFunctionName("uploader")
+	if err != nil {
+		t.Fatalf("GetRoutinesByFunctionName() failed: %v", err)
+	}
+
+	if len(refs) != 3 {
+		t.Fatalf("Expected 3 uploader routines, got %d", len(refs))
+	}
+
+	perLocal := make(map[string]int)
+	for _, ref := range refs {
+		if ref.AppName != "test-app" {
+			t.Errorf("Expected app name test-app, got %s", ref.AppName)
+		}
+		if ref.Routine.GetFunctionName() != "uploader" {
+			t.Errorf("Expected function uploader, got %s", ref.Routine.GetFunctionName())
+		}
+		perLocal[ref.LocalName]++
+	}
+
+	if perLocal["local1"] != 2 || perLocal["local2"] != 1 {
+		t.Errorf("Expected 2 routines in local1 and 1 in local2, got %v", perLocal)
+	}
+
+	// Unknown function returns an empty slice
+	refs, err = appMgr.GetRoutinesByFunctionName("missing")
+	if err != nil {
+		t.Fatalf("GetRoutinesByFunctionName() failed: %v", err)
+	}
+	if len(refs) != 0 {
+		t.Errorf("Expected 0 routines for unknown function, got %d", len(refs))
+	}
+}
+
+func TestAppManager_ManageHTTPServer(t *testing.T) {
+	resetGlobalState()
+
+	appMgr := App.NewAppManager("test-app")
+	if _, err := appMgr.CreateApp(); err != nil {
+		t.Fatalf("CreateApp() failed: %v", err)
+	}
+
+	// Reserve a free port for the server
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to reserve a port: %v", err)
+	}
+	addr := listener.Addr().String()
+	listener.Close()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ping", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("pong"))
+	})
+	srv := &http.Server{Addr: addr, Handler: mux}
+
+	if err := appMgr.ManageHTTPServer("http", "api-server", srv, time.Second); err != nil {
+		t.Fatalf("ManageHTTPServer() failed: %v", err)
+	}
+
+	// Wait for the server to come up
+	url := "http://" + addr + "/ping"
+	var up bool
+	for i := 0; i < 50; i++ {
+		if resp, err := http.Get(url); err == nil {
+			resp.Body.Close()
+			up = resp.StatusCode == http.StatusOK
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if !up {
+		t.Fatal("Server did not start")
+	}
+
+	if count := appMgr.GetGoroutineCount(); count != 2 {
+		t.Errorf("Expected 2 tracked routines (serve + drain), got %d", count)
+	}
+
+	if err := appMgr.Shutdown(true); err != nil {
+		t.Fatalf("Shutdown() failed: %v", err)
+	}
+
+	if resp, err := http.Get(url); err == nil {
+		resp.Body.Close()
+		t.Error("Server should not accept requests after manager shutdown")
+	}
+	if count := appMgr.GetGoroutineCount(); count != 0 {
+		t.Errorf("Expected no tracked routines after shutdown, got %d", count)
+	}
+}
+
+func TestAppManager_ManageHTTPServer_BindError(t *testing.T) {
+	resetGlobalState()
+
+	appMgr := App.NewAppManager("test-app")
+	if _, err := appMgr.CreateApp(); err != nil {
+		t.Fatalf("CreateApp() failed: %v", err)
+	}
+
+	// Hold the port so the server can't bind it
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to reserve a port: %v", err)
+	}
+	defer listener.Close()
+
+	srv := &http.Server{Addr: listener.Addr().String(), Handler: http.NewServeMux()}
+	if err := appMgr.ManageHTTPServer("http", "api-server", srv, time.Second); err == nil {
+		t.Fatal("ManageHTTPServer() should fail when the address is in use")
+	}
+	if count := appMgr.GetGoroutineCount(); count != 0 {
+		t.Errorf("Expected no tracked routines after a bind error, got %d", count)
+	}
+}
+
+func TestAppManager_Rename(t *testing.T) {
+	resetGlobalState()
+
+	gm := Global.NewGlobalManager()
+	gm.Init()
+	if _, err := gm.UpdateMetadata(Global.SET_METRICS_URL, []interface{}{true, ""}); err != nil {
+		t.Fatalf("Failed to enable metrics: %v", err)
+	}
+	// Leave metrics uninitialized again for the tests that don't enable them
+	defer metrics.ResetMetrics()
+	defer metrics.StopCollector()
+
+	oldMgr := App.NewAppManager("old-app")
+	if _, err := oldMgr.CreateApp(); err != nil {
+		t.Fatalf("CreateApp() failed: %v", err)
+	}
+	if _, err := App.NewAppManager("taken-app").CreateApp(); err != nil {
+		t.Fatalf("CreateApp() failed: %v", err)
+	}
+	localMgr := Local.NewLocalManager("old-app", "local1")
+	if _, err := localMgr.CreateLocal("local1"); err != nil {
+		t.Fatalf("CreateLocal() failed: %v", err)
+	}
+
+	// Live routine that keeps ticking through the rename
+	var ticks atomic.Int32
+	err := localMgr.Go("ticker", func(ctx context.Context) error {
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(5 * time.Millisecond):
+				ticks.Add(1)
+			}
+		}
+	}, Local.AddToWaitGroup("ticker"))
+	if err != nil {
+		t.Fatalf("Go() failed: %v", err)
+	}
+	spawned := testutil.ToFloat64(metrics.GoroutineOperationsTotal.WithLabelValues("create", "old-app", "local1", "ticker"))
+
+	// Renaming onto an existing app is rejected
+	if err := App.Rename("old-app", "taken-app"); !errors.Is(err, Errors.ErrAppManagerExists) {
+		t.Fatalf("Expected ErrAppManagerExists, got %v", err)
+	}
+
+	if err := App.Rename("old-app", "new-app"); err != nil {
+		t.Fatalf("Rename() failed: %v", err)
+	}
+
+	// Old name no longer resolves
+	if types.IsIntilized().App("old-app") {
+		t.Error("Old app name should no longer be registered")
+	}
+	if _, err := oldMgr.GetAllLocalManagers(); err == nil {
+		t.Error("Lookups under the old name should fail")
+	}
+
+	// New name resolves to the same app with its routines
+	newMgr := App.NewAppManager("new-app")
+	app, err := types.GetAppManager("new-app")
+	if err != nil {
+		t.Fatalf("Lookup under the new name failed: %v", err)
+	}
+	if app.GetAppName() != "new-app" {
+		t.Errorf("Expected app name new-app, got %s", app.GetAppName())
+	}
+	if count := newMgr.GetGoroutineCount(); count != 1 {
+		t.Errorf("Expected 1 routine under the new name, got %d", count)
+	}
+
+	// Routine is still running after the rename
+	before := ticks.Load()
+	time.Sleep(50 * time.Millisecond)
+	if ticks.Load() == before {
+		t.Error("Routine should keep running through the rename")
+	}
+
+	// Counters moved over to the new label
+	migrated := testutil.ToFloat64(metrics.GoroutineOperationsTotal.WithLabelValues("create", "new-app", "local1", "ticker"))
+	if migrated != spawned {
+		t.Errorf("Expected migrated counter %v, got %v", spawned, migrated)
+	}
+
+	// Shutdown through the new name stops the routine
+	if err := newMgr.Shutdown(true); err != nil {
+		t.Fatalf("Shutdown() failed: %v", err)
+	}
+	if count := newMgr.GetGoroutineCount(); count != 0 {
+		t.Errorf("Expected 0 routines after shutdown, got %d", count)
+	}
+}
+
+func TestAppManager_NextLocal(t *testing.T) {
+	resetGlobalState()
+
+	appMgr := App.NewAppManager("test-app")
+	if _, err := appMgr.CreateApp(); err != nil {
+		t.Fatalf("CreateApp() failed: %v", err)
+	}
+
+	// A name taken by hand is skipped
+	if _, err := Local.NewLocalManager("test-app", "shard-2").CreateLocal("shard-2"); err != nil {
+		t.Fatalf("CreateLocal() failed: %v", err)
+	}
+	for _, want := range []string{"shard-1", "shard-3", "shard-4"} {
+		localMgr, err := appMgr.NextLocal("shard")
+		if err != nil {
+			t.Fatalf("NextLocal() failed: %v", err)
+		}
+		if err := localMgr.Go("worker", func(ctx context.Context) error { return nil }); err != nil {
+			t.Errorf("Go() on %s failed: %v", want, err)
+		}
+		if _, err := appMgr.GetLocalManagerByName(want); err != nil {
+			t.Errorf("Expected local %s to exist: %v", want, err)
+		}
+	}
+
+	// Concurrent callers never share a name
+	const workers = 20
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := appMgr.NextLocal("pool"); err != nil {
+				t.Errorf("NextLocal() failed: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	for i := 1; i <= workers; i++ {
+		if _, err := appMgr.GetLocalManagerByName(fmt.Sprintf("pool-%d", i)); err != nil {
+			t.Errorf("Expected local pool-%d to exist: %v", i, err)
+		}
+	}
+	if got := appMgr.GetLocalManagerCount(); got != 4+workers {
+		t.Errorf("Expected %d local managers, got %d", 4+workers, got)
+	}
+}
+
+func TestAppManager_CancelContext(t *testing.T) {
+	resetGlobalState()
+
+	gm := Global.NewGlobalManager()
+	gm.Init()
+	appMgr := App.NewAppManager("cancel-app")
+	if _, err := appMgr.CreateApp(); err != nil {
+		t.Fatalf("CreateApp() failed: %v", err)
+	}
+	locals := map[string]Interface.LocalGoroutineManagerInterface{}
+	for _, name := range []string{"cancel-a", "cancel-b", "cancel-c"} {
+		localMgr := Local.NewLocalManager("cancel-app", name)
+		if _, err := localMgr.CreateLocal(name); err != nil {
+			t.Fatalf("CreateLocal() failed: %v", err)
+		}
+		locals[name] = localMgr
+	}
+
+	var stopped atomic.Int32
+	for _, localMgr := range locals {
+		for i := 0; i < 2; i++ {
+			if err := localMgr.Go("waiter", func(ctx context.Context) error {
+				<-ctx.Done()
+				stopped.Add(1)
+				return ctx.Err()
+			}, Local.AddToWaitGroup("waiter")); err != nil {
+				t.Fatalf("Go() failed: %v", err)
+			}
+		}
+		if err := localMgr.WaitForFunctionStarted("waiter", 2); err != nil {
+			t.Fatalf("WaitForFunctionStarted() failed: %v", err)
+		}
+	}
+
+	// Cancelling one local leaves its siblings running
+	if err := locals["cancel-a"].CancelContext(); err != nil {
+		t.Fatalf("Local CancelContext() failed: %v", err)
+	}
+	if !locals["cancel-a"].WaitForFunctionWithTimeout("waiter", time.Second) {
+		t.Fatal("Routines of the cancelled local did not stop")
+	}
+	if got := stopped.Load(); got != 2 {
+		t.Errorf("Expected only the 2 routines of cancel-a to stop, got %d", got)
+	}
+
+	// Cancelling the app reaches the routines of every local
+	if err := appMgr.CancelContext(); err != nil {
+		t.Fatalf("App CancelContext() failed: %v", err)
+	}
+	for name, localMgr := range locals {
+		if !localMgr.WaitForFunctionWithTimeout("waiter", time.Second) {
+			t.Fatalf("Routines of %s did not stop", name)
+		}
+	}
+	if got := stopped.Load(); got != 6 {
+		t.Errorf("Expected 6 stopped routines, got %d", got)
+	}
+
+	// The topology is left in place
+	if _, err := gm.GetAppManagerByName("cancel-app"); err != nil {
+		t.Errorf("App should still be registered: %v", err)
+	}
+	if got := appMgr.GetLocalManagerCount(); got != 3 {
+		t.Errorf("Expected 3 local managers to remain, got %d", got)
+	}
+	app, err := types.GetAppManager("cancel-app")
+	if err != nil {
+		t.Fatalf("GetAppManager() failed: %v", err)
+	}
+	if appCtx, _ := app.GetAppContext(); appCtx.Err() == nil {
+		t.Error("App context should be cancelled")
+	}
+	for name := range locals {
+		local, err := appMgr.GetLocalManagerByName(name)
+		if err != nil {
+			t.Fatalf("Local %s should still be registered: %v", name, err)
+		}
+		if localCtx, _ := local.GetLocalContext(); localCtx.Err() == nil {
+			t.Errorf("Context of %s should be cancelled", name)
+		}
+	}
+}
+
+func TestAppManager_CancelContextIsolatedAcrossApps(t *testing.T) {
+	resetGlobalState()
+
+	// Both apps have a local named "worker", each runs one routine waiting for its context
+	stopped := make(map[string]chan struct{})
+	locals := make(map[string]Interface.LocalGoroutineManagerInterface)
+	for _, appName := range []string{"cancel-x", "cancel-y"} {
+		if _, err := App.NewAppManager(appName).CreateApp(); err != nil {
+			t.Fatalf("CreateApp() failed: %v", err)
+		}
+		localMgr := Local.NewLocalManager(appName, "worker")
+		if _, err := localMgr.CreateLocal("worker"); err != nil {
+			t.Fatalf("CreateLocal() failed: %v", err)
+		}
+		done := make(chan struct{})
+		if err := localMgr.Go("waiter", func(ctx context.Context) error {
+			<-ctx.Done()
+			close(done)
+			return nil
+		}); err != nil {
+			t.Fatalf("Go() failed: %v", err)
+		}
+		stopped[appName], locals[appName] = done, localMgr
+	}
+	expectRunning := func(appName, after string) {
+		t.Helper()
+		select {
+		case <-stopped[appName]:
+			t.Fatalf("%s cancelled the routine of %s/worker", after, appName)
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+	expectStopped := func(appName, after string) {
+		t.Helper()
+		select {
+		case <-stopped[appName]:
+		case <-time.After(time.Second):
+			t.Fatalf("%s didn't cancel the routine of %s/worker", after, appName)
+		}
+	}
+
+	if err := locals["cancel-x"].CancelContext(); err != nil {
+		t.Fatalf("Local CancelContext() failed: %v", err)
+	}
+	expectStopped("cancel-x", "Local CancelContext() of cancel-x/worker")
+	expectRunning("cancel-y", "Local CancelContext() of cancel-x/worker")
+	fmt.Println("✓ Local CancelContext() left the same-named local of the other app running")
+
+	// The app context reaches its own locals through the context tree, not the other app's
+	if err := App.NewAppManager("cancel-x").CancelContext(); err != nil {
+		t.Fatalf("App CancelContext() failed: %v", err)
+	}
+	expectRunning("cancel-y", "App CancelContext() of cancel-x")
+	if err := App.NewAppManager("cancel-y").CancelContext(); err != nil {
+		t.Fatalf("App CancelContext() failed: %v", err)
+	}
+	expectStopped("cancel-y", "App CancelContext() of cancel-y")
+	fmt.Println("✓ App CancelContext() reached only its own locals")
+}
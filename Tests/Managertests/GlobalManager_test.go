@@ -1,10 +1,23 @@
 package Managertests
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	LocalHelper "github.com/neerajchowdary889/GoRoutinesManager/Helper/Local"
 	"github.com/neerajchowdary889/GoRoutinesManager/Manager/App"
+	"github.com/neerajchowdary889/GoRoutinesManager/Manager/Errors"
 	"github.com/neerajchowdary889/GoRoutinesManager/Manager/Global"
+	"github.com/neerajchowdary889/GoRoutinesManager/Manager/Interface"
 	"github.com/neerajchowdary889/GoRoutinesManager/Manager/Local"
 	"github.com/neerajchowdary889/GoRoutinesManager/types"
 	"github.com/neerajchowdary889/GoRoutinesManager/Tests/Common"
@@ -15,6 +28,16 @@ func resetGlobalState() {
 	Common.ResetGlobalState()
 }
 
+// stopWorkers releases the workers blocked on release, cancels the rest and waits for all of them,
+// so no worker of a test outlives it and runs into the next test resetting the global state
+func stopWorkers(release chan struct{}, localMgrs ...Interface.LocalGoroutineManagerInterface) {
+	close(release)
+	for _, localMgr := range localMgrs {
+		localMgr.CancelContext()
+		localMgr.WaitForAll()
+	}
+}
+
 func TestGlobalManager_Init(t *testing.T) {
 	resetGlobalState()
 
@@ -286,3 +309,940 @@ func TestGlobalManager_BeforeInit(t *testing.T) {
 		t.Error("Expected error when getting app managers before init")
 	}
 }
+
+func TestGlobalManager_StateCounts(t *testing.T) {
+	resetGlobalState()
+
+	gm := Global.NewGlobalManager()
+	gm.Init()
+
+	// Every state is reported, even with no routines
+	counts := gm.StateCounts()
+	for _, state := range types.RoutineStates {
+		if count, ok := counts[state]; !ok || count != 0 {
+			t.Errorf("Expected %s to be reported as 0, got %d (present: %v)", state, count, ok)
+		}
+	}
+
+	// Two apps so the counts are aggregated across the tree
+	for _, appName := range []string{"state-app-1", "state-app-2"} {
+		if _, err := App.NewAppManager(appName).CreateApp(); err != nil {
+			t.Fatalf("CreateApp() failed: %v", err)
+		}
+	}
+	localMgr1 := Local.NewLocalManager("state-app-1", "local1")
+	local1, err := localMgr1.CreateLocal("local1")
+	if err != nil {
+		t.Fatalf("CreateLocal() failed: %v", err)
+	}
+	localMgr2 := Local.NewLocalManager("state-app-2", "local2")
+	if _, err := localMgr2.CreateLocal("local2"); err != nil {
+		t.Fatalf("CreateLocal() failed: %v", err)
+	}
+
+	release := make(chan struct{})
+	defer stopWorkers(release, localMgr1, localMgr2)
+
+	// Tracked but never spawned: starting
+	local1.NewGoRoutine("not-started")
+
+	// Running in both apps
+	running := func(ctx context.Context) error {
+		<-ctx.Done()
+		return nil
+	}
+	localMgr1.Go("running", running)
+	localMgr2.Go("running", running)
+	localMgr2.Go("running", running)
+
+	// Cancelled but ignoring its context: cancelling
+	localMgr2.Go("stubborn", func(ctx context.Context) error {
+		<-release
+		return nil
+	})
+
+	// Held in its completion callback: completed but still tracked
+	localMgr1.Go("finished", func(ctx context.Context) error {
+		return nil
+	}, Local.WithOnComplete(func(Local.Outcome) {
+		<-release
+	}))
+
+	time.Sleep(50 * time.Millisecond)
+	stubborn, err := localMgr2.GetRoutinesByFunctionName("stubborn")
+	if err != nil || len(stubborn) != 1 {
+		t.Fatalf("Expected 1 stubborn routine, got %d (%v)", len(stubborn), err)
+	}
+	if err := localMgr2.CancelRoutine(stubborn[0].GetID()); err != nil {
+		t.Fatalf("CancelRoutine() failed: %v", err)
+	}
+
+	expected := map[types.RoutineState]int{
+		types.RoutineStateStarting:   1,
+		types.RoutineStateRunning:    3,
+		types.RoutineStateCancelling: 1,
+		types.RoutineStateCompleted:  1,
+	}
+	counts = gm.StateCounts()
+	for state, want := range expected {
+		if counts[state] != want {
+			t.Errorf("Expected %d %s routines, got %d", want, state, counts[state])
+		}
+	}
+}
+
+func TestGlobalManager_GetAppManagerByName(t *testing.T) {
+	resetGlobalState()
+
+	gm := Global.NewGlobalManager()
+	gm.Init()
+
+	if _, err := App.NewAppManager("lookup-app").CreateApp(); err != nil {
+		t.Fatalf("CreateApp() failed: %v", err)
+	}
+
+	appMgr, err := gm.GetAppManagerByName("lookup-app")
+	if err != nil {
+		t.Fatalf("GetAppManagerByName() failed: %v", err)
+	}
+
+	localMgr := Local.NewLocalManager("lookup-app", "local1")
+	if _, err := localMgr.CreateLocal("local1"); err != nil {
+		t.Fatalf("CreateLocal() failed: %v", err)
+	}
+	localMgr.Go("lookup-worker", func(ctx context.Context) error {
+		<-ctx.Done()
+		return nil
+	})
+
+	// Operate on the app through the returned interface
+	if count := appMgr.GetLocalManagerCount(); count != 1 {
+		t.Errorf("Expected 1 local manager, got %d", count)
+	}
+	if _, err := appMgr.GetLocalManagerByName("local1"); err != nil {
+		t.Errorf("GetLocalManagerByName() failed: %v", err)
+	}
+	refs, err := appMgr.GetRoutinesByFunctionName("lookup-worker")
+	if err != nil || len(refs) != 1 {
+		t.Errorf("Expected 1 lookup-worker routine, got %d (%v)", len(refs), err)
+	}
+	if err := appMgr.Shutdown(false); err != nil {
+		t.Errorf("Shutdown() through the returned app failed: %v", err)
+	}
+	if count := appMgr.GetGoroutineCount(); count != 0 {
+		t.Errorf("Expected no goroutines after shutdown, got %d", count)
+	}
+
+	// Unknown apps are reported, not created
+	if _, err := gm.GetAppManagerByName("missing-app"); !errors.Is(err, Errors.ErrAppManagerNotFound) {
+		t.Errorf("Expected ErrAppManagerNotFound, got %v", err)
+	}
+	if gm.GetAppManagerCount() != 1 {
+		t.Errorf("Lookup of a missing app should not create it, got %d apps", gm.GetAppManagerCount())
+	}
+}
+
+func TestGlobalManager_GlobalSemaphore(t *testing.T) {
+	resetGlobalState()
+
+	gm := Global.NewGlobalManager()
+	gm.Init()
+
+	if err := gm.NewGlobalSemaphore("db", 2); err != nil {
+		t.Fatalf("NewGlobalSemaphore() failed: %v", err)
+	}
+	if err := gm.NewGlobalSemaphore("db", 2); !errors.Is(err, Errors.ErrSemaphoreExists) {
+		t.Errorf("Expected ErrSemaphoreExists, got %v", err)
+	}
+	if err := gm.NewGlobalSemaphore("empty", 0); !errors.Is(err, Errors.ErrInvalidSemaphoreSize) {
+		t.Errorf("Expected ErrInvalidSemaphoreSize, got %v", err)
+	}
+
+	var running, maxRunning, finished atomic.Int64
+	var wg sync.WaitGroup
+	worker := func(ctx context.Context) error {
+		defer wg.Done()
+		now := running.Add(1)
+		for {
+			peak := maxRunning.Load()
+			if now <= peak || maxRunning.CompareAndSwap(peak, now) {
+				break
+			}
+		}
+		time.Sleep(30 * time.Millisecond)
+		running.Add(-1)
+		finished.Add(1)
+		return nil
+	}
+
+	for _, appName := range []string{"orders-app", "billing-app"} {
+		if _, err := App.NewAppManager(appName).CreateApp(); err != nil {
+			t.Fatalf("CreateApp() failed: %v", err)
+		}
+		localMgr := Local.NewLocalManager(appName, "queries")
+		if _, err := localMgr.CreateLocal("queries"); err != nil {
+			t.Fatalf("CreateLocal() failed: %v", err)
+		}
+		for i := 0; i < 3; i++ {
+			wg.Add(1)
+			if err := localMgr.Go("query", worker, Local.WithGlobalSemaphore("db")); err != nil {
+				t.Fatalf("Go() failed: %v", err)
+			}
+		}
+	}
+	wg.Wait()
+
+	if finished.Load() != 6 {
+		t.Errorf("Expected 6 workers to run, got %d", finished.Load())
+	}
+	if peak := maxRunning.Load(); peak > 2 {
+		t.Errorf("Expected at most 2 workers running at once across apps, got %d", peak)
+	}
+
+	// An unknown semaphore fails the spawn
+	localMgr := Local.NewLocalManager("orders-app", "queries")
+	err := localMgr.Go("query", worker, Local.WithGlobalSemaphore("missing"))
+	if !errors.Is(err, Errors.ErrSemaphoreNotFound) {
+		t.Errorf("Expected ErrSemaphoreNotFound, got %v", err)
+	}
+}
+
+func TestGlobalManager_Done(t *testing.T) {
+	for _, safe := range []bool{false, true} {
+		resetGlobalState()
+
+		gm := Global.NewGlobalManager()
+		if gm.Done() != nil {
+			t.Error("Done() should be nil before Init")
+		}
+		gm.Init()
+
+		if _, err := App.NewAppManager("done-app").CreateApp(); err != nil {
+			t.Fatalf("CreateApp() failed: %v", err)
+		}
+		done := gm.Done()
+		select {
+		case <-done:
+			t.Fatal("Done() closed before Shutdown")
+		default:
+		}
+
+		if err := gm.Shutdown(safe); err != nil {
+			t.Fatalf("Shutdown(%v) failed: %v", safe, err)
+		}
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatalf("Done() not closed after Shutdown(%v)", safe)
+		}
+	}
+}
+
+func TestGlobalManager_Run(t *testing.T) {
+	resetGlobalState()
+
+	gm := Global.NewGlobalManager()
+	if err := gm.Run(context.Background()); !errors.Is(err, Errors.ErrGlobalManagerNotFound) {
+		t.Fatalf("Expected ErrGlobalManagerNotFound before Init, got %v", err)
+	}
+	gm.Init()
+
+	if _, err := App.NewAppManager("run-app").CreateApp(); err != nil {
+		t.Fatalf("CreateApp() failed: %v", err)
+	}
+	localMgr := Local.NewLocalManager("run-app", "run-local")
+	if _, err := localMgr.CreateLocal("run-local"); err != nil {
+		t.Fatalf("CreateLocal() failed: %v", err)
+	}
+	// Takes a moment to wind down, a safe shutdown waits for it
+	var cleanedUp atomic.Bool
+	if err := localMgr.Go("server", func(ctx context.Context) error {
+		<-ctx.Done()
+		time.Sleep(50 * time.Millisecond)
+		cleanedUp.Store(true)
+		return nil
+	}); err != nil {
+		t.Fatalf("Go() failed: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	result := make(chan error, 1)
+	go func() {
+		result <- gm.Run(ctx)
+	}()
+	select {
+	case err := <-result:
+		t.Fatalf("Run() returned before ctx was cancelled: %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	cancel()
+	select {
+	case err := <-result:
+		if err != nil {
+			t.Fatalf("Run() failed: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run() didn't return after ctx was cancelled")
+	}
+	if !cleanedUp.Load() {
+		t.Error("Run() returned before the worker finished winding down")
+	}
+	if count := gm.GetGoroutineCount(); count != 0 {
+		t.Errorf("Expected 0 goroutines after Run(), got %d", count)
+	}
+	select {
+	case <-gm.Done():
+	default:
+		t.Error("Done() not closed after Run()")
+	}
+}
+
+func TestGlobalManager_MemoryBudget(t *testing.T) {
+	resetGlobalState()
+
+	gm := Global.NewGlobalManager()
+	gm.Init()
+	if err := gm.SetMemoryBudget(100); err != nil {
+		t.Fatalf("SetMemoryBudget() failed: %v", err)
+	}
+
+	if _, err := App.NewAppManager("budget-app").CreateApp(); err != nil {
+		t.Fatalf("CreateApp() failed: %v", err)
+	}
+	first := Local.NewLocalManager("budget-app", "first")
+	if _, err := first.CreateLocal("first"); err != nil {
+		t.Fatalf("CreateLocal() failed: %v", err)
+	}
+	second := Local.NewLocalManager("budget-app", "second")
+	if _, err := second.CreateLocal("second"); err != nil {
+		t.Fatalf("CreateLocal() failed: %v", err)
+	}
+
+	release := make(chan struct{})
+	defer stopWorkers(release, first, second)
+	worker := func(ctx context.Context) error {
+		<-release
+		return nil
+	}
+	if err := first.Go("heavy", worker, Local.WithMemoryEstimate(70)); err != nil {
+		t.Fatalf("Go() failed: %v", err)
+	}
+
+	// The second local has no budget of its own but the global one is shared
+	if err := second.Go("heavy", worker, Local.WithMemoryEstimate(70)); !errors.Is(err, Errors.ErrMemoryBudgetExceeded) {
+		t.Errorf("Expected ErrMemoryBudgetExceeded, got %v", err)
+	}
+	if got := second.GetMemoryInUse(); got != 0 {
+		t.Errorf("A rejected global reservation should roll back the local one, got %d", got)
+	}
+	if got := gm.GetMemoryInUse(); got != 70 {
+		t.Errorf("Expected 70 bytes in use globally, got %d", got)
+	}
+	if err := second.Go("heavy", worker, Local.WithMemoryEstimate(30)); err != nil {
+		t.Errorf("Go() within the global budget failed: %v", err)
+	}
+}
+
+func TestManagerStats_MatchGetters(t *testing.T) {
+	resetGlobalState()
+
+	gm := Global.NewGlobalManager()
+	gm.Init()
+	appMgr := App.NewAppManager("stats-app")
+	if _, err := appMgr.CreateApp(); err != nil {
+		t.Fatalf("CreateApp() failed: %v", err)
+	}
+	if _, err := App.NewAppManager("stats-idle-app").CreateApp(); err != nil {
+		t.Fatalf("CreateApp() failed: %v", err)
+	}
+	first := Local.NewLocalManager("stats-app", "first")
+	if _, err := first.CreateLocal("first"); err != nil {
+		t.Fatalf("CreateLocal() failed: %v", err)
+	}
+	second := Local.NewLocalManager("stats-app", "second")
+	if _, err := second.CreateLocal("second"); err != nil {
+		t.Fatalf("CreateLocal() failed: %v", err)
+	}
+
+	release := make(chan struct{})
+	blocked := func(ctx context.Context) error {
+		<-release
+		return nil
+	}
+	for i := 0; i < 3; i++ {
+		if err := first.Go("blocked", blocked, Local.AddToWaitGroup("blocked")); err != nil {
+			t.Fatalf("Go() failed: %v", err)
+		}
+	}
+	if err := second.Go("blocked", blocked, Local.AddToWaitGroup("blocked")); err != nil {
+		t.Fatalf("Go() failed: %v", err)
+	}
+	// Spawned last so the peaks include it next to all the blocked routines
+	if err := first.Go("failing", func(ctx context.Context) error { return errors.New("boom") }); err != nil {
+		t.Fatalf("Go() failed: %v", err)
+	}
+	if !first.WaitForFunctionWithTimeout("failing", time.Second) {
+		t.Fatal("failing routine did not finish")
+	}
+
+	// While the blocked routines run, every count matches its getter
+	local := first.Stats()
+	if local.Goroutines != first.GetGoroutineCount() || local.Goroutines != 3 {
+		t.Errorf("Local Goroutines = %d, getter %d, want 3", local.Goroutines, first.GetGoroutineCount())
+	}
+	if local.TotalSpawned != 4 {
+		t.Errorf("Local TotalSpawned = %d, want 4", local.TotalSpawned)
+	}
+	if len(local.Functions) != 2 || local.Functions[0].FunctionName != "blocked" || local.Functions[1].FunctionName != "failing" {
+		t.Fatalf("Expected stats for blocked and failing, got %+v", local.Functions)
+	}
+	for _, fn := range local.Functions {
+		if fn != first.GetFunctionStats(fn.FunctionName) {
+			t.Errorf("Function stats %+v differ from GetFunctionStats %+v", fn, first.GetFunctionStats(fn.FunctionName))
+		}
+	}
+	if local.Uptime <= 0 {
+		t.Errorf("Expected a positive uptime, got %v", local.Uptime)
+	}
+
+	app := appMgr.Stats()
+	if app.LocalManagers != appMgr.GetLocalManagerCount() || app.LocalManagers != 2 {
+		t.Errorf("App LocalManagers = %d, getter %d, want 2", app.LocalManagers, appMgr.GetLocalManagerCount())
+	}
+	if app.Goroutines != appMgr.GetGoroutineCount() || app.Goroutines != 4 {
+		t.Errorf("App Goroutines = %d, getter %d, want 4", app.Goroutines, appMgr.GetGoroutineCount())
+	}
+	if app.TotalSpawned != 5 || app.Functions != nil {
+		t.Errorf("App TotalSpawned = %d, Functions = %v, want 5 and none", app.TotalSpawned, app.Functions)
+	}
+
+	global := gm.Stats()
+	if global.AppManagers != gm.GetAppManagerCount() || global.AppManagers != 2 {
+		t.Errorf("Global AppManagers = %d, getter %d, want 2", global.AppManagers, gm.GetAppManagerCount())
+	}
+	if global.LocalManagers != gm.GetLocalManagerCount() {
+		t.Errorf("Global LocalManagers = %d, getter %d", global.LocalManagers, gm.GetLocalManagerCount())
+	}
+	if global.Goroutines != gm.GetGoroutineCount() || global.Goroutines != 4 {
+		t.Errorf("Global Goroutines = %d, getter %d, want 4", global.Goroutines, gm.GetGoroutineCount())
+	}
+	if global.TotalSpawned != 5 {
+		t.Errorf("Global TotalSpawned = %d, want 5", global.TotalSpawned)
+	}
+	if global.Uptime < app.Uptime {
+		t.Errorf("Global uptime %v should not be shorter than the app's %v", global.Uptime, app.Uptime)
+	}
+
+	// Peaks stay after the routines finish
+	close(release)
+	if !first.WaitForFunctionWithTimeout("blocked", time.Second) || !second.WaitForFunctionWithTimeout("blocked", time.Second) {
+		t.Fatal("blocked routines did not finish")
+	}
+	local, app, global = first.Stats(), appMgr.Stats(), gm.Stats()
+	if local.Goroutines != 0 || local.PeakGoroutines != 4 {
+		t.Errorf("Local Goroutines = %d, Peak = %d, want 0 and 4", local.Goroutines, local.PeakGoroutines)
+	}
+	if app.Goroutines != 0 || app.PeakGoroutines != 5 {
+		t.Errorf("App Goroutines = %d, Peak = %d, want 0 and 5", app.Goroutines, app.PeakGoroutines)
+	}
+	if global.Goroutines != 0 || global.PeakGoroutines != 5 {
+		t.Errorf("Global Goroutines = %d, Peak = %d, want 0 and 5", global.Goroutines, global.PeakGoroutines)
+	}
+}
+
+// buildRoutineTree tracks apps*locals*perLocal routines without running goroutines for them
+// Run with -race: the tree is mutated while it is snapshotted
+func TestGlobalManager_ConsistentSnapshot(t *testing.T) {
+	resetGlobalState()
+
+	gm := Global.NewGlobalManager()
+	if _, err := gm.Init(); err != nil {
+		t.Fatalf("Init() failed: %v", err)
+	}
+	if got := gm.ConsistentSnapshot(); got.AppManagers != 0 || got.Goroutines != 0 {
+		t.Errorf("Expected an empty snapshot, got %+v", got)
+	}
+
+	for _, appName := range []string{"snap-a", "snap-b"} {
+		if _, err := App.NewAppManager(appName).CreateApp(); err != nil {
+			t.Fatalf("CreateApp() failed: %v", err)
+		}
+	}
+
+	var mutators sync.WaitGroup
+	for _, appName := range []string{"snap-a", "snap-b"} {
+		mutators.Add(1)
+		go func(appName string) {
+			defer mutators.Done()
+			for i := 0; i < 200; i++ {
+				// Add local managers and churn short lived routines in them
+				localName := fmt.Sprintf("local-%d", i%5)
+				localMgr := Local.NewLocalManager(appName, localName)
+				if _, err := localMgr.CreateLocal(localName); err != nil && !errors.Is(err, Errors.WrngLocalManagerAlreadyExists) {
+					t.Errorf("CreateLocal() failed: %v", err)
+					return
+				}
+				_ = localMgr.Go("churn", func(ctx context.Context) error { return nil })
+			}
+		}(appName)
+	}
+
+	mutated := make(chan struct{})
+	go func() {
+		mutators.Wait()
+		close(mutated)
+	}()
+	for done := false; !done; {
+		select {
+		case <-mutated:
+			done = true
+		default:
+		}
+		snapshot := gm.ConsistentSnapshot()
+		if snapshot.AppManagers != len(snapshot.Apps) {
+			t.Fatalf("AppManagers %d doesn't match %d apps", snapshot.AppManagers, len(snapshot.Apps))
+		}
+		locals, goroutines := 0, 0
+		for appName, app := range snapshot.Apps {
+			appGoroutines := 0
+			for _, count := range app.Locals {
+				appGoroutines += count
+			}
+			if app.LocalManagers != len(app.Locals) || app.Goroutines != appGoroutines {
+				t.Fatalf("App %s counts don't add up: %+v", appName, app)
+			}
+			locals += app.LocalManagers
+			goroutines += app.Goroutines
+		}
+		if snapshot.LocalManagers != locals || snapshot.Goroutines != goroutines {
+			t.Fatalf("Tree counts don't add up: %d locals and %d goroutines, apps sum to %d and %d",
+				snapshot.LocalManagers, snapshot.Goroutines, locals, goroutines)
+		}
+	}
+
+	// Once quiet, the snapshot agrees with the per manager getters
+	for _, appName := range []string{"snap-a", "snap-b"} {
+		for i := 0; i < 5; i++ {
+			_ = Local.NewLocalManager(appName, fmt.Sprintf("local-%d", i)).WaitForFunction("churn")
+		}
+	}
+	snapshot := gm.ConsistentSnapshot()
+	if snapshot.AppManagers != 2 || snapshot.LocalManagers != 10 || snapshot.Goroutines != gm.GetGoroutineCount() {
+		t.Errorf("Unexpected quiet snapshot: %+v, GetGoroutineCount() = %d", snapshot, gm.GetGoroutineCount())
+	}
+}
+
+func buildRoutineTree(tb testing.TB, apps, locals, perLocal int) Interface.GlobalGoroutineManagerInterface {
+	tb.Helper()
+	resetGlobalState()
+
+	gm := Global.NewGlobalManager()
+	gm.Init()
+	for a := 0; a < apps; a++ {
+		appName := fmt.Sprintf("bench-app-%d", a)
+		if _, err := App.NewAppManager(appName).CreateApp(); err != nil {
+			tb.Fatalf("CreateApp() failed: %v", err)
+		}
+		for l := 0; l < locals; l++ {
+			localName := fmt.Sprintf("bench-local-%d", l)
+			local, err := Local.NewLocalManager(appName, localName).CreateLocal(localName)
+			if err != nil {
+				tb.Fatalf("CreateLocal() failed: %v", err)
+			}
+			for r := 0; r < perLocal; r++ {
+				local.NewGoRoutine("worker")
+			}
+		}
+	}
+	return gm
+}
+
+func BenchmarkGlobalManager_GetAllGoroutines(b *testing.B) {
+	gm := buildRoutineTree(b, 10, 10, 100)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		routines, err := gm.GetAllGoroutines()
+		if err != nil || len(routines) != 10000 {
+			b.Fatalf("Expected 10000 routines, got %d (%v)", len(routines), err)
+		}
+	}
+}
+
+// BenchmarkGlobalManager_GetAllGoroutines_Unsized is the previous approach, appending map copies of every
+// local manager to a nil slice, kept as the baseline for BenchmarkGlobalManager_GetAllGoroutines
+func BenchmarkGlobalManager_GetAllGoroutines_Unsized(b *testing.B) {
+	gm := buildRoutineTree(b, 10, 10, 100)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		appManagers, err := gm.GetAllAppManagers()
+		if err != nil {
+			b.Fatalf("GetAllAppManagers() failed: %v", err)
+		}
+		var routines []*types.Routine
+		for _, appManager := range appManagers {
+			for _, localManager := range appManager.GetLocalManagers() {
+				routines = append(routines, LocalHelper.NewLocalHelper().RoutinesMapToSlice(localManager.GetRoutines())...)
+			}
+		}
+		if len(routines) != 10000 {
+			b.Fatalf("Expected 10000 routines, got %d", len(routines))
+		}
+	}
+}
+
+// untrackedReportWorker is started with a plain go statement, outside the manager
+func untrackedReportWorker(release <-chan struct{}) {
+	<-release
+}
+
+func TestGlobalManager_GoroutineReport(t *testing.T) {
+	resetGlobalState()
+
+	gm := Global.NewGlobalManager()
+	gm.Init()
+	if _, err := App.NewAppManager("report-app").CreateApp(); err != nil {
+		t.Fatalf("CreateApp() failed: %v", err)
+	}
+	localMgr := Local.NewLocalManager("report-app", "report-local")
+	if _, err := localMgr.CreateLocal("report-local"); err != nil {
+		t.Fatalf("CreateLocal() failed: %v", err)
+	}
+
+	release := make(chan struct{})
+	defer stopWorkers(release, localMgr)
+	spawn := func(functionName string, n int) {
+		for i := 0; i < n; i++ {
+			if err := localMgr.Go(functionName, func(ctx context.Context) error {
+				<-release
+				return nil
+			}); err != nil {
+				t.Fatalf("Go() failed: %v", err)
+			}
+		}
+	}
+	spawn("fetcher", 3)
+	spawn("indexer", 2)
+	go untrackedReportWorker(release)
+
+	// Wait until the spawned goroutines have labelled themselves
+	var report string
+	deadline := time.Now().Add(time.Second)
+	for {
+		report = gm.GoroutineReport()
+		if strings.Contains(report, "fetcher: 3 running, 3 tracked") && strings.Contains(report, "indexer: 2 running, 2 tracked") {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected fetcher and indexer with their counts in the report:\n%s", report)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if !strings.Contains(report, "\nreport-app/report-local\n") {
+		t.Errorf("Expected the report-app/report-local group in the report:\n%s", report)
+	}
+	_, untracked, found := strings.Cut(report, "\nUntracked: ")
+	if !found {
+		t.Fatalf("Expected an untracked section in the report:\n%s", report)
+	}
+	if !strings.Contains(untracked, "Managertests.untrackedReportWorker") {
+		t.Errorf("Expected the goroutine started outside the manager to be listed as untracked:\n%s", report)
+	}
+	if strings.Contains(untracked, "fetcher") || strings.Contains(untracked, "indexer") {
+		t.Errorf("Tracked workers listed as untracked:\n%s", report)
+	}
+}
+
+func TestGlobalManager_SnapshotJSON(t *testing.T) {
+	resetGlobalState()
+
+	gm := Global.NewGlobalManager()
+	gm.Init()
+	release := make(chan struct{})
+	spawn := func(appName, localName, functionName string, n int) Interface.LocalGoroutineManagerInterface {
+		localMgr := Local.NewLocalManager(appName, localName)
+		if _, err := localMgr.CreateLocal(localName); err != nil {
+			t.Fatalf("CreateLocal() failed: %v", err)
+		}
+		for i := 0; i < n; i++ {
+			if err := localMgr.Go(functionName, func(ctx context.Context) error {
+				select {
+				case <-release:
+				case <-ctx.Done():
+				}
+				return nil
+			}, Local.AddToWaitGroup(functionName)); err != nil {
+				t.Fatalf("Go() failed: %v", err)
+			}
+		}
+		return localMgr
+	}
+	for _, appName := range []string{"billing", "api"} {
+		if _, err := App.NewAppManager(appName).CreateApp(); err != nil {
+			t.Fatalf("CreateApp() failed: %v", err)
+		}
+	}
+	apiHTTP := spawn("api", "http", "handler", 2)
+	apiGRPC := spawn("api", "grpc", "stream", 1)
+	billing := spawn("billing", "invoices", "render", 1)
+	routines, _ := billing.GetRoutinesByFunctionName("render")
+	billing.CancelRoutine(routines[0].GetID())
+	time.Sleep(20 * time.Millisecond)
+
+	encoded, err := json.Marshal(gm.Snapshot())
+	if err != nil {
+		t.Fatalf("Marshal() failed: %v", err)
+	}
+	var snapshot struct {
+		TakenAt  time.Time `json:"taken_at"`
+		Routines int       `json:"routines"`
+		Apps     []struct {
+			Name   string `json:"name"`
+			Locals []struct {
+				Name               string `json:"name"`
+				Draining           bool   `json:"draining"`
+				FunctionWaitGroups []struct {
+					FunctionName string `json:"function_name"`
+					Pending      int64  `json:"pending"`
+				} `json:"function_wait_groups"`
+				Routines []map[string]interface{} `json:"routines"`
+			} `json:"locals"`
+		} `json:"apps"`
+	}
+	if err := json.Unmarshal(encoded, &snapshot); err != nil {
+		t.Fatalf("Unmarshal() failed: %v\n%s", err, encoded)
+	}
+
+	if snapshot.TakenAt.IsZero() {
+		t.Error("Expected taken_at to be set")
+	}
+	if snapshot.Routines != 3 {
+		t.Errorf("Expected 3 routines, got %d\n%s", snapshot.Routines, encoded)
+	}
+	if len(snapshot.Apps) != 2 || snapshot.Apps[0].Name != "api" || snapshot.Apps[1].Name != "billing" {
+		t.Fatalf("Expected apps api and billing sorted by name, got %s", encoded)
+	}
+	api := snapshot.Apps[0]
+	if len(api.Locals) != 2 || api.Locals[0].Name != "grpc" || api.Locals[1].Name != "http" {
+		t.Fatalf("Expected api locals grpc and http sorted by name, got %s", encoded)
+	}
+	httpLocal := api.Locals[1]
+	if len(httpLocal.Routines) != 2 {
+		t.Fatalf("Expected 2 routines in api/http, got %d", len(httpLocal.Routines))
+	}
+	for _, key := range []string{"id", "function_name", "state", "started_at", "uptime", "uptime_ms", "context_cancelled"} {
+		if _, ok := httpLocal.Routines[0][key]; !ok {
+			t.Errorf("Routine is missing %q: %v", key, httpLocal.Routines[0])
+		}
+	}
+	if httpLocal.Routines[0]["function_name"] != "handler" || httpLocal.Routines[0]["context_cancelled"] != false {
+		t.Errorf("Unexpected routine %v", httpLocal.Routines[0])
+	}
+	if len(httpLocal.FunctionWaitGroups) != 1 || httpLocal.FunctionWaitGroups[0].FunctionName != "handler" || httpLocal.FunctionWaitGroups[0].Pending != 2 {
+		t.Errorf("Expected the handler wait group with 2 pending, got %+v", httpLocal.FunctionWaitGroups)
+	}
+
+	// The cancelled routine has finished, an empty local still encodes its lists
+	invoices := snapshot.Apps[1].Locals[0]
+	if invoices.Routines == nil || len(invoices.Routines) != 0 {
+		t.Errorf("Expected an empty routines list for billing/invoices, got %s", encoded)
+	}
+
+	// Before Init the snapshot is empty but still valid JSON
+	stopWorkers(release, apiHTTP, apiGRPC, billing)
+	resetGlobalState()
+	encoded, err = json.Marshal(Global.NewGlobalManager().Snapshot())
+	if err != nil {
+		t.Fatalf("Marshal() failed: %v", err)
+	}
+	if !strings.Contains(string(encoded), `"apps":[]`) {
+		t.Errorf("Expected an empty apps list before Init, got %s", encoded)
+	}
+}
+
+func TestGlobalManager_AdminHandler(t *testing.T) {
+	resetGlobalState()
+
+	gm := Global.NewGlobalManager()
+	gm.Init()
+	if _, err := App.NewAppManager("admin-app").CreateApp(); err != nil {
+		t.Fatalf("CreateApp() failed: %v", err)
+	}
+	localMgr := Local.NewLocalManager("admin-app", "admin-local")
+	if _, err := localMgr.CreateLocal("admin-local"); err != nil {
+		t.Fatalf("CreateLocal() failed: %v", err)
+	}
+	stopped := make(chan error, 1)
+	if err := localMgr.Go("cancellable", func(ctx context.Context) error {
+		<-ctx.Done()
+		stopped <- ctx.Err()
+		return ctx.Err()
+	}); err != nil {
+		t.Fatalf("Go() failed: %v", err)
+	}
+
+	server := httptest.NewServer(Global.GetAdminHandler())
+	defer server.Close()
+
+	// List
+	resp, err := http.Get(server.URL + "/routines")
+	if err != nil {
+		t.Fatalf("GET /routines failed: %v", err)
+	}
+	var routines []map[string]interface{}
+	err = json.NewDecoder(resp.Body).Decode(&routines)
+	resp.Body.Close()
+	if err != nil {
+		t.Fatalf("Decode() failed: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK || len(routines) != 1 {
+		t.Fatalf("Expected 200 with 1 routine, got %d with %v", resp.StatusCode, routines)
+	}
+	routine := routines[0]
+	if routine["app_name"] != "admin-app" || routine["local_name"] != "admin-local" || routine["function_name"] != "cancellable" {
+		t.Errorf("Unexpected routine %v", routine)
+	}
+	routineID, _ := routine["id"].(string)
+
+	// Cancel
+	resp, err = http.Post(server.URL+"/routines/"+routineID+"/cancel", "", nil)
+	if err != nil {
+		t.Fatalf("POST cancel failed: %v", err)
+	}
+	var cancelled map[string]string
+	json.NewDecoder(resp.Body).Decode(&cancelled)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || cancelled["cancelled"] != routineID {
+		t.Errorf("Expected 200 with the cancelled ID, got %d with %v", resp.StatusCode, cancelled)
+	}
+	select {
+	case err := <-stopped:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected context.Canceled, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Routine wasn't cancelled")
+	}
+
+	// Not found, for an unknown ID and for the routine once it's untracked
+	deadline := time.Now().Add(time.Second)
+	for localMgr.GetGoroutineCount() != 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	for _, id := range []string{"no-such-routine", routineID} {
+		resp, err = http.Post(server.URL+"/routines/"+id+"/cancel", "", nil)
+		if err != nil {
+			t.Fatalf("POST cancel failed: %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusNotFound {
+			t.Errorf("Expected 404 for %s, got %d", id, resp.StatusCode)
+		}
+	}
+
+	// Only the documented methods are served
+	resp, err = http.Post(server.URL+"/routines", "", nil)
+	if err != nil {
+		t.Fatalf("POST /routines failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("Expected 405 for POST /routines, got %d", resp.StatusCode)
+	}
+}
+
+// TestGlobalManager_Subscribe verifies the lifecycle events of a create-spawn-complete-shutdown flow arrive in order
+func TestGlobalManager_Subscribe(t *testing.T) {
+	fmt.Println("\n=== TestGlobalManager_Subscribe ===")
+	resetGlobalState()
+
+	gm := Global.NewGlobalManager()
+	gm.Init()
+
+	events := make(chan types.Event, 16)
+	unsubscribe := gm.Subscribe(func(e types.Event) {
+		// Leftovers of other tests may still publish
+		if e.App == "events-app" {
+			events <- e
+		}
+	})
+	defer unsubscribe()
+
+	if _, err := App.NewAppManager("events-app").CreateApp(); err != nil {
+		t.Fatalf("CreateApp() failed: %v", err)
+	}
+	localMgr := Local.NewLocalManager("events-app", "events-local")
+	if _, err := localMgr.CreateLocal("events-local"); err != nil {
+		t.Fatalf("CreateLocal() failed: %v", err)
+	}
+	errAudit := errors.New("audit me")
+	if err := localMgr.Go("audited", func(ctx context.Context) error {
+		return errAudit
+	}, Local.AddToWaitGroup("audited")); err != nil {
+		t.Fatalf("Go() failed: %v", err)
+	}
+	if err := localMgr.WaitForFunction("audited"); err != nil {
+		t.Fatalf("WaitForFunction() failed: %v", err)
+	}
+	if err := localMgr.Shutdown(true); err != nil {
+		t.Fatalf("Shutdown() failed: %v", err)
+	}
+
+	expected := []types.EventType{
+		types.EventAppCreated,
+		types.EventLocalCreated,
+		types.EventRoutineStarted,
+		types.EventRoutineCompleted,
+		types.EventShutdownStarted,
+	}
+	var routineID string
+	for i, want := range expected {
+		select {
+		case e := <-events:
+			if e.Type != want {
+				t.Fatalf("Event %d: expected %s, got %s", i, want, e.Type)
+			}
+			if e.Time.IsZero() {
+				t.Errorf("Event %d (%s) has no time", i, e.Type)
+			}
+			switch e.Type {
+			case types.EventLocalCreated, types.EventShutdownStarted:
+				if e.Local != "events-local" {
+					t.Errorf("%s: expected local events-local, got %q", e.Type, e.Local)
+				}
+			case types.EventRoutineStarted:
+				if e.Function != "audited" || e.RoutineID == "" {
+					t.Errorf("%s: expected function audited with a routine ID, got %q/%q", e.Type, e.Function, e.RoutineID)
+				}
+				routineID = e.RoutineID
+			case types.EventRoutineCompleted:
+				if e.RoutineID != routineID {
+					t.Errorf("%s: expected routine %s, got %s", e.Type, routineID, e.RoutineID)
+				}
+				if !errors.Is(e.Err, errAudit) {
+					t.Errorf("%s: expected the worker error, got %v", e.Type, e.Err)
+				}
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("Timed out waiting for event %d (%s)", i, want)
+		}
+	}
+	fmt.Println("✓ Events arrived in order: app, local, started, completed, shutdown")
+
+	// Nothing is delivered after unsubscribing
+	unsubscribe()
+	if _, err := Local.NewLocalManager("events-app", "events-local-2").CreateLocal("events-local-2"); err != nil {
+		t.Fatalf("CreateLocal() failed: %v", err)
+	}
+	select {
+	case e := <-events:
+		t.Errorf("Unexpected event after unsubscribing: %s", e.Type)
+	case <-time.After(50 * time.Millisecond):
+	}
+	fmt.Println("✓ No events after unsubscribing")
+}
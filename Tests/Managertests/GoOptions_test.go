@@ -2,14 +2,28 @@ package Managertests
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/neerajchowdary889/GoRoutinesManager/Manager/App"
+	"github.com/neerajchowdary889/GoRoutinesManager/Manager/Errors"
+	"github.com/neerajchowdary889/GoRoutinesManager/Manager/Global"
+	"github.com/neerajchowdary889/GoRoutinesManager/Manager/Interface"
 	"github.com/neerajchowdary889/GoRoutinesManager/Manager/Local"
 	"github.com/neerajchowdary889/GoRoutinesManager/Tests/Common"
+	"github.com/neerajchowdary889/GoRoutinesManager/metrics"
+	"github.com/neerajchowdary889/GoRoutinesManager/types"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 )
 
 // TestGo_Basic tests Go() without any options
@@ -119,6 +133,91 @@ func TestGo_WithTimeout(t *testing.T) {
 	fmt.Println("✓ WithTimeout test passed")
 }
 
+// TestGo_WithDeadline tests that the earlier of a deadline and a timeout cancels the worker
+func TestGo_WithDeadline(t *testing.T) {
+	fmt.Println("\n=== TestGo_WithDeadline ===")
+	Common.ResetGlobalState()
+
+	appMgr := App.NewAppManager("test-app")
+	if _, err := appMgr.CreateApp(); err != nil {
+		t.Fatalf("CreateApp() failed: %v", err)
+	}
+	localMgr := Local.NewLocalManager("test-app", "test-local")
+	if _, err := localMgr.CreateLocal("test-local"); err != nil {
+		t.Fatalf("CreateLocal() failed: %v", err)
+	}
+
+	// waitForCancel spawns a worker that blocks on its context and reports when and why it was cancelled
+	type cancellation struct {
+		at  time.Time
+		err error
+	}
+	waitForCancel := func(name string, opts ...Interface.GoroutineOption) (time.Time, chan cancellation) {
+		cancelled := make(chan cancellation, 1)
+		start := time.Now()
+		err := localMgr.Go(name, func(ctx context.Context) error {
+			<-ctx.Done()
+			cancelled <- cancellation{time.Now(), ctx.Err()}
+			return ctx.Err()
+		}, opts...)
+		if err != nil {
+			t.Fatalf("Go() failed: %v", err)
+		}
+		return start, cancelled
+	}
+
+	// The deadline comes first, the longer timeout doesn't matter
+	outcomes := make(chan Local.Outcome, 1)
+	start, cancelled := waitForCancel("batch",
+		Local.WithDeadline(time.Now().Add(50*time.Millisecond)),
+		Local.WithTimeout(5*time.Second),
+		Local.WithOnComplete(func(o Local.Outcome) { outcomes <- o }))
+	select {
+	case c := <-cancelled:
+		if elapsed := c.at.Sub(start); elapsed < 40*time.Millisecond || elapsed > time.Second {
+			t.Errorf("Expected the worker to be cancelled around 50ms, got %v", elapsed)
+		}
+		if !errors.Is(c.err, context.DeadlineExceeded) {
+			t.Errorf("Expected context.DeadlineExceeded, got %v", c.err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Worker not cancelled at its deadline")
+	}
+	if o := <-outcomes; o.Type != Local.OutcomeTimedOut {
+		t.Errorf("Expected a timed out outcome, got %v", o.Type)
+	}
+	fmt.Println("✓ Deadline won over a longer timeout")
+
+	// The timeout comes first, the later deadline doesn't matter
+	start, cancelled = waitForCancel("short-timeout",
+		Local.WithDeadline(time.Now().Add(5*time.Second)),
+		Local.WithTimeout(50*time.Millisecond))
+	select {
+	case c := <-cancelled:
+		if elapsed := c.at.Sub(start); elapsed > time.Second {
+			t.Errorf("Expected the timeout to win, cancelled after %v", elapsed)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Worker not cancelled at its timeout")
+	}
+	fmt.Println("✓ Timeout won over a later deadline")
+
+	// Shutdown still cancels a routine whose deadline is far away
+	_, cancelled = waitForCancel("far-deadline", Local.WithDeadline(time.Now().Add(time.Hour)))
+	if err := localMgr.Shutdown(false); err != nil {
+		t.Fatalf("Shutdown() failed: %v", err)
+	}
+	select {
+	case c := <-cancelled:
+		if !errors.Is(c.err, context.Canceled) {
+			t.Errorf("Expected context.Canceled from the shutdown, got %v", c.err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Shutdown didn't cancel the routine")
+	}
+	fmt.Println("✓ Local shutdown cancelled a routine with a far deadline")
+}
+
 // TestGo_WithPanicRecovery tests Go() with WithPanicRecovery option
 func TestGo_WithPanicRecovery(t *testing.T) {
 	fmt.Println("\n=== TestGo_WithPanicRecovery ===")
@@ -170,6 +269,163 @@ func TestGo_WithPanicRecovery(t *testing.T) {
 	fmt.Println("✓ WithPanicRecovery test passed")
 }
 
+// TestGo_WithPanicHandler tests that the panic handler sees the recovered value and stack before cleanup
+func TestGo_WithPanicHandler(t *testing.T) {
+	fmt.Println("\n=== TestGo_WithPanicHandler ===")
+	Common.ResetGlobalState()
+
+	appMgr := App.NewAppManager("test-app")
+	if _, err := appMgr.CreateApp(); err != nil {
+		t.Fatalf("CreateApp() failed: %v", err)
+	}
+	localMgr := Local.NewLocalManager("test-app", "test-local")
+	if _, err := localMgr.CreateLocal("test-local"); err != nil {
+		t.Fatalf("CreateLocal() failed: %v", err)
+	}
+
+	type panicReport struct {
+		functionName string
+		recovered    interface{}
+		stack        []byte
+		tracked      int
+	}
+	reports := make(chan panicReport, 1)
+	err := localMgr.Go("panic-worker", func(ctx context.Context) error {
+		panic("test panic")
+	}, Local.WithPanicHandler(func(functionName string, recovered interface{}, stack []byte) {
+		// Cleanup hasn't run yet, the routine is still tracked
+		reports <- panicReport{functionName, recovered, stack, localMgr.GetGoroutineCount()}
+	}))
+	if err != nil {
+		t.Fatalf("Go() failed: %v", err)
+	}
+
+	select {
+	case report := <-reports:
+		if report.functionName != "panic-worker" {
+			t.Errorf("Expected function name panic-worker, got %q", report.functionName)
+		}
+		if report.recovered != "test panic" {
+			t.Errorf("Expected recovered value %q, got %v", "test panic", report.recovered)
+		}
+		if len(report.stack) == 0 || !strings.Contains(string(report.stack), "panic") {
+			t.Errorf("Expected the panicking stack, got %q", report.stack)
+		}
+		if report.tracked != 1 {
+			t.Errorf("Expected the handler to run before cleanup with 1 tracked routine, got %d", report.tracked)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Panic handler was not called")
+	}
+	fmt.Println("✓ Handler received the function name, panic value and stack")
+
+	// A panicking handler must not skip the cleanup
+	err = localMgr.Go("bad-handler", func(ctx context.Context) error {
+		panic("worker panic")
+	}, Local.AddToWaitGroup("bad-handler"), Local.WithPanicHandler(func(string, interface{}, []byte) {
+		panic("handler panic")
+	}))
+	if err != nil {
+		t.Fatalf("Go() failed: %v", err)
+	}
+	if !localMgr.WaitForFunctionWithTimeout("bad-handler", time.Second) {
+		t.Fatal("Wait group not released after the panic handler panicked")
+	}
+	deadline := time.Now().Add(time.Second)
+	for localMgr.GetGoroutineCount() != 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if count := localMgr.GetGoroutineCount(); count != 0 {
+		t.Errorf("Expected 0 routines after a panicking handler, got %d", count)
+	}
+	fmt.Println("✓ Cleanup ran despite the panicking handler")
+}
+
+// TestGo_PanicRecoveryDefault tests that SET_PANIC_RECOVERY flips the default while WithPanicRecovery still overrides it
+func TestGo_PanicRecoveryDefault(t *testing.T) {
+	if os.Getenv("GRM_PANIC_RECOVERY_CHILD") == "1" {
+		// Runs in a child process, the unrecovered panic is expected to crash it
+		Common.ResetGlobalState()
+		App.NewAppManager("test-app").CreateApp()
+		localMgr := Local.NewLocalManager("test-app", "test-local")
+		localMgr.CreateLocal("test-local")
+		Global.NewGlobalManager().UpdateMetadata(Global.SET_PANIC_RECOVERY, false)
+		localMgr.Go("panic-worker", func(ctx context.Context) error {
+			panic("unrecovered panic")
+		})
+		time.Sleep(time.Second)
+		os.Exit(0)
+	}
+
+	fmt.Println("\n=== TestGo_PanicRecoveryDefault ===")
+	Common.ResetGlobalState()
+
+	appMgr := App.NewAppManager("test-app")
+	if _, err := appMgr.CreateApp(); err != nil {
+		t.Fatalf("CreateApp() failed: %v", err)
+	}
+	localMgr := Local.NewLocalManager("test-app", "test-local")
+	if _, err := localMgr.CreateLocal("test-local"); err != nil {
+		t.Fatalf("CreateLocal() failed: %v", err)
+	}
+	gm := Global.NewGlobalManager()
+	if _, err := gm.UpdateMetadata(Global.SET_PANIC_RECOVERY, "false"); err == nil {
+		t.Error("Expected an error for a non bool value")
+	}
+
+	recovered := func(name string, opts ...Interface.GoroutineOption) bool {
+		handled := make(chan struct{}, 1)
+		opts = append(opts, Local.WithPanicHandler(func(string, interface{}, []byte) {
+			handled <- struct{}{}
+		}))
+		if err := localMgr.Go(name, func(ctx context.Context) error {
+			panic("test panic")
+		}, opts...); err != nil {
+			t.Fatalf("Go() failed: %v", err)
+		}
+		select {
+		case <-handled:
+			return true
+		case <-time.After(time.Second):
+			return false
+		}
+	}
+
+	if !recovered("default-worker") {
+		t.Fatal("Expected panics to be recovered by default")
+	}
+	fmt.Println("✓ Panics are recovered by default")
+
+	if _, err := gm.UpdateMetadata(Global.SET_PANIC_RECOVERY, false); err != nil {
+		t.Fatalf("UpdateMetadata() failed: %v", err)
+	}
+	if !recovered("override-worker", Local.WithPanicRecovery(true)) {
+		t.Error("Expected WithPanicRecovery(true) to override the disabled default")
+	}
+	fmt.Println("✓ WithPanicRecovery(true) overrides the disabled default")
+
+	// With the default off an unrecovered panic crashes the process, so spawn it in a child
+	cmd := exec.Command(os.Args[0], "-test.run=^TestGo_PanicRecoveryDefault$")
+	cmd.Env = append(os.Environ(), "GRM_PANIC_RECOVERY_CHILD=1")
+	output, err := cmd.CombinedOutput()
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		t.Fatalf("Expected the child to crash on the unrecovered panic, got err %v", err)
+	}
+	if !strings.Contains(string(output), "unrecovered panic") {
+		t.Errorf("Expected the child to die from the worker panic, output:\n%s", output)
+	}
+	fmt.Println("✓ Spawns without the option follow the disabled default")
+
+	if _, err := gm.UpdateMetadata(Global.SET_PANIC_RECOVERY, true); err != nil {
+		t.Fatalf("UpdateMetadata() failed: %v", err)
+	}
+	if !recovered("restored-worker") {
+		t.Error("Expected panics to be recovered after re-enabling the default")
+	}
+	fmt.Println("✓ Re-enabling the default restores recovery")
+}
+
 // TestGo_AddToWaitGroup tests Go() with AddToWaitGroup option
 func TestGo_AddToWaitGroup(t *testing.T) {
 	fmt.Println("\n=== TestGo_AddToWaitGroup ===")
@@ -564,3 +820,1354 @@ func TestGo_EmptyOptions(t *testing.T) {
 
 	fmt.Println("✓ Empty options test passed")
 }
+
+// TestGo_WithForceKillOnTimeout tests that a worker ignoring its context after a timeout is surfaced as leaked
+func TestGo_WithForceKillOnTimeout(t *testing.T) {
+	fmt.Println("\n=== TestGo_WithForceKillOnTimeout ===")
+	Common.ResetGlobalState()
+
+	gm := Global.NewGlobalManager()
+	if _, err := gm.Init(); err != nil {
+		t.Fatalf("Init() failed: %v", err)
+	}
+	if _, err := gm.UpdateMetadata(Global.SET_METRICS_URL, []interface{}{true, ""}); err != nil {
+		t.Fatalf("Failed to enable metrics: %v", err)
+	}
+	// Leave metrics uninitialized again for the tests that don't enable them
+	defer metrics.ResetMetrics()
+	defer metrics.StopCollector()
+
+	appMgr := App.NewAppManager("leak-app")
+	if _, err := appMgr.CreateApp(); err != nil {
+		t.Fatalf("CreateApp() failed: %v", err)
+	}
+	localMgr := Local.NewLocalManager("leak-app", "leak-local")
+	if _, err := localMgr.CreateLocal("leak-local"); err != nil {
+		t.Fatalf("CreateLocal() failed: %v", err)
+	}
+
+	// Uncooperative worker never looks at ctx
+	release := make(chan struct{})
+	var finished atomic.Bool
+	err := localMgr.Go("stubborn-worker", func(ctx context.Context) error {
+		<-release
+		finished.Store(true)
+		return nil
+	}, Local.WithTimeout(50*time.Millisecond), Local.WithForceKillOnTimeout(50*time.Millisecond))
+	if err != nil {
+		t.Fatalf("Go() failed: %v", err)
+	}
+
+	routines, _ := localMgr.GetRoutinesByFunctionName("stubborn-worker")
+	if len(routines) != 1 {
+		t.Fatalf("Expected 1 tracked routine, got %d", len(routines))
+	}
+	routine := routines[0]
+	if routine.GetSpawnSite() == "" {
+		t.Error("Expected spawn site to be captured")
+	}
+
+	// Timeout (50ms) + grace (50ms) + margin
+	time.Sleep(200 * time.Millisecond)
+
+	if !routine.IsLeaked() {
+		t.Error("Routine should be marked as leaked")
+	}
+	if count := localMgr.GetGoroutineCount(); count != 0 {
+		t.Errorf("Leaked routine should be removed from the active map, got count %d", count)
+	}
+	leaked := testutil.ToFloat64(metrics.GoroutinesLeakedTotal.WithLabelValues("leak-app", "leak-local", "stubborn-worker"))
+	if leaked != 1 {
+		t.Errorf("Expected leak metric to be 1, got %v", leaked)
+	}
+	if finished.Load() {
+		t.Error("Worker should still be running")
+	}
+
+	// A cooperative worker that honours the timeout is never flagged
+	err = localMgr.Go("polite-worker", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}, Local.WithTimeout(50*time.Millisecond), Local.WithForceKillOnTimeout(50*time.Millisecond))
+	if err != nil {
+		t.Fatalf("Go() failed: %v", err)
+	}
+	time.Sleep(200 * time.Millisecond)
+	polite := testutil.ToFloat64(metrics.GoroutinesLeakedTotal.WithLabelValues("leak-app", "leak-local", "polite-worker"))
+	if polite != 0 {
+		t.Errorf("Cooperative worker should not be reported as leaked, got %v", polite)
+	}
+
+	// The leaked routine is untracked already, wait on its done channel for it to finish
+	close(release)
+	<-routine.DoneChan()
+	localMgr.WaitForAll()
+	fmt.Println("✓ Uncooperative worker surfaced as leaked")
+}
+
+// TestGo_SpawnSiteThroughWrappers tests that the spawn site points at the caller for every spawning entry point
+func TestGo_SpawnSiteThroughWrappers(t *testing.T) {
+	fmt.Println("\n=== TestGo_SpawnSiteThroughWrappers ===")
+	resetGlobalState()
+
+	appMgr := App.NewAppManager("test-app")
+	if _, err := appMgr.CreateApp(); err != nil {
+		t.Fatalf("CreateApp() failed: %v", err)
+	}
+	localMgr := Local.NewLocalManager("test-app", "test-local")
+	if _, err := localMgr.CreateLocal("test-local"); err != nil {
+		t.Fatalf("CreateLocal() failed: %v", err)
+	}
+
+	release := make(chan struct{})
+	defer stopWorkers(release, localMgr)
+	worker := func(ctx context.Context) error {
+		<-release
+		return nil
+	}
+	// The site is only captured with a leak grace, long enough to never fire here
+	opts := []Interface.GoroutineOption{Local.WithTimeout(time.Minute), Local.WithForceKillOnTimeout(time.Minute)}
+
+	if err := localMgr.Go("via-go", worker, opts...); err != nil {
+		t.Fatalf("Go() failed: %v", err)
+	}
+	if err := localMgr.GoWithContext(context.Background(), "via-go-with-context", worker, opts...); err != nil {
+		t.Fatalf("GoWithContext() failed: %v", err)
+	}
+	if ok, err := localMgr.TryGo("via-try-go", worker, opts...); !ok || err != nil {
+		t.Fatalf("TryGo() failed: %v, %v", ok, err)
+	}
+	if _, err := localMgr.GoN(2, "via-go-n", func(ctx context.Context, index int) error {
+		return worker(ctx)
+	}, opts...); err != nil {
+		t.Fatalf("GoN() failed: %v", err)
+	}
+
+	for _, functionName := range []string{"via-go", "via-go-with-context", "via-try-go", "via-go-n"} {
+		routines, _ := localMgr.GetRoutinesByFunctionName(functionName)
+		if len(routines) == 0 {
+			t.Fatalf("Expected routines for %s", functionName)
+		}
+		for _, routine := range routines {
+			if site := routine.GetSpawnSite(); !strings.Contains(site, "GoOptions_test.go:") {
+				t.Errorf("Expected the spawn site of %s in this test, got %q", functionName, site)
+			}
+		}
+	}
+	fmt.Println("✓ Spawn site points at the caller for Go, GoWithContext, TryGo and GoN")
+}
+
+// TestGo_WithOnComplete tests that the completion callback classifies every outcome type
+func TestGo_WithOnComplete(t *testing.T) {
+	fmt.Println("\n=== TestGo_WithOnComplete ===")
+	Common.ResetGlobalState()
+
+	appMgr := App.NewAppManager("test-app")
+	if _, err := appMgr.CreateApp(); err != nil {
+		t.Fatalf("CreateApp() failed: %v", err)
+	}
+	localMgr := Local.NewLocalManager("test-app", "test-local")
+	if _, err := localMgr.CreateLocal("test-local"); err != nil {
+		t.Fatalf("CreateLocal() failed: %v", err)
+	}
+
+	workerErr := errors.New("worker failed")
+	waitForCtx := func(ctx context.Context) error {
+		<-ctx.Done()
+		return nil
+	}
+
+	tests := []struct {
+		name    string
+		worker  func(ctx context.Context) error
+		opts    []Interface.GoroutineOption
+		cancel  bool
+		want    Local.OutcomeType
+		wantErr error
+	}{
+		{name: "success", worker: func(ctx context.Context) error { return nil }, want: Local.OutcomeSuccess},
+		{name: "error", worker: func(ctx context.Context) error { return workerErr }, want: Local.OutcomeError, wantErr: workerErr},
+		{name: "cancelled", worker: waitForCtx, cancel: true, want: Local.OutcomeCancelled, wantErr: context.Canceled},
+		{name: "timed-out", worker: waitForCtx, opts: []Interface.GoroutineOption{Local.WithTimeout(20 * time.Millisecond)},
+			want: Local.OutcomeTimedOut, wantErr: context.DeadlineExceeded},
+		{name: "panicked", worker: func(ctx context.Context) error { panic("boom") }, want: Local.OutcomePanicked},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outcomes := make(chan Local.Outcome, 1)
+			opts := append(tt.opts, Local.WithOnComplete(func(outcome Local.Outcome) {
+				outcomes <- outcome
+			}))
+			if err := localMgr.Go(tt.name, tt.worker, opts...); err != nil {
+				t.Fatalf("Go() failed: %v", err)
+			}
+
+			routines, _ := localMgr.GetRoutinesByFunctionName(tt.name)
+			var routineID string
+			if len(routines) == 1 {
+				routineID = routines[0].GetID()
+			}
+			if tt.cancel {
+				if err := localMgr.CancelRoutine(routineID); err != nil {
+					t.Fatalf("CancelRoutine() failed: %v", err)
+				}
+			}
+
+			var outcome Local.Outcome
+			select {
+			case outcome = <-outcomes:
+			case <-time.After(time.Second):
+				t.Fatal("OnComplete was not called")
+			}
+
+			if outcome.Type != tt.want {
+				t.Errorf("Expected outcome %s, got %s", tt.want, outcome.Type)
+			}
+			if tt.wantErr != nil && !errors.Is(outcome.Err, tt.wantErr) {
+				t.Errorf("Expected error %v, got %v", tt.wantErr, outcome.Err)
+			}
+			if tt.want == Local.OutcomeSuccess && outcome.Err != nil {
+				t.Errorf("Success should carry no error, got %v", outcome.Err)
+			}
+			if tt.want == Local.OutcomePanicked && (outcome.Err == nil || !strings.Contains(outcome.Err.Error(), "boom")) {
+				t.Errorf("Expected panic value in error, got %v", outcome.Err)
+			}
+			if routineID != "" && outcome.RoutineID != routineID {
+				t.Errorf("Expected routine ID %s, got %s", routineID, outcome.RoutineID)
+			}
+			if outcome.Duration <= 0 {
+				t.Errorf("Expected a positive duration, got %v", outcome.Duration)
+			}
+			fmt.Printf("✓ %s classified as %s\n", tt.name, outcome.Type)
+		})
+	}
+}
+
+// TestGo_CheckStop verifies the cancellation checkpoints before and after the routine is cancelled
+func TestGo_CheckStop(t *testing.T) {
+	fmt.Println("\n=== TestGo_CheckStop ===")
+	Common.ResetGlobalState()
+
+	appMgr := App.NewAppManager("test-app")
+	if _, err := appMgr.CreateApp(); err != nil {
+		t.Fatalf("CreateApp() failed: %v", err)
+	}
+	localMgr := Local.NewLocalManager("test-app", "test-local")
+	if _, err := localMgr.CreateLocal("test-local"); err != nil {
+		t.Fatalf("CreateLocal() failed: %v", err)
+	}
+
+	beforeErr := make(chan error, 1)
+	afterErr := make(chan error, 1)
+	iterations := atomic.Int64{}
+	err := localMgr.Go("checkpoint", func(ctx context.Context) error {
+		beforeErr <- Local.CheckStop(ctx)
+		for !Local.ShouldStop(ctx) {
+			iterations.Add(1)
+			time.Sleep(time.Millisecond)
+		}
+		afterErr <- Local.CheckStop(ctx)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Go() failed: %v", err)
+	}
+
+	if err := <-beforeErr; err != nil {
+		t.Fatalf("CheckStop() should be nil before cancellation, got %v", err)
+	}
+
+	routines, _ := localMgr.GetRoutinesByFunctionName("checkpoint")
+	if len(routines) != 1 {
+		t.Fatalf("Expected 1 routine, got %d", len(routines))
+	}
+	if err := localMgr.CancelRoutine(routines[0].GetID()); err != nil {
+		t.Fatalf("CancelRoutine() failed: %v", err)
+	}
+
+	select {
+	case err := <-afterErr:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected context.Canceled after cancellation, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Worker did not observe the cancellation")
+	}
+	fmt.Printf("✓ Worker stopped after %d iterations\n", iterations.Load())
+}
+
+// TestGo_Sleep verifies Sleep returns right away on cancellation instead of sleeping the full duration
+func TestGo_Sleep(t *testing.T) {
+	fmt.Println("\n=== TestGo_Sleep ===")
+	Common.ResetGlobalState()
+
+	appMgr := App.NewAppManager("test-app")
+	if _, err := appMgr.CreateApp(); err != nil {
+		t.Fatalf("CreateApp() failed: %v", err)
+	}
+	localMgr := Local.NewLocalManager("test-app", "test-local")
+	if _, err := localMgr.CreateLocal("test-local"); err != nil {
+		t.Fatalf("CreateLocal() failed: %v", err)
+	}
+
+	if err := Local.Sleep(context.Background(), time.Millisecond); err != nil {
+		t.Errorf("Sleep() should be nil after a full sleep, got %v", err)
+	}
+
+	sleepErr := make(chan error, 1)
+	if err := localMgr.Go("sleeper", func(ctx context.Context) error {
+		err := Local.Sleep(ctx, time.Hour)
+		sleepErr <- err
+		return err
+	}, Local.AddToWaitGroup("sleeper")); err != nil {
+		t.Fatalf("Go() failed: %v", err)
+	}
+	if err := localMgr.WaitForFunctionStarted("sleeper", 1); err != nil {
+		t.Fatalf("WaitForFunctionStarted() failed: %v", err)
+	}
+
+	start := time.Now()
+	if err := localMgr.ShutdownFunction("sleeper", 5*time.Second); err != nil {
+		t.Fatalf("ShutdownFunction() failed: %v", err)
+	}
+	select {
+	case err := <-sleepErr:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected context.Canceled, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Sleep() did not return on cancellation")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Shutdown took %v, the sleeping worker held it up", elapsed)
+	}
+	fmt.Printf("✓ Sleeping worker stopped in %v\n", time.Since(start))
+}
+
+// TestGo_PauseResume tests that workers checking IsPaused only make progress while their function isn't paused
+func TestGo_PauseResume(t *testing.T) {
+	fmt.Println("\n=== TestGo_PauseResume ===")
+	Common.ResetGlobalState()
+
+	appMgr := App.NewAppManager("test-app")
+	if _, err := appMgr.CreateApp(); err != nil {
+		t.Fatalf("CreateApp() failed: %v", err)
+	}
+	localMgr := Local.NewLocalManager("test-app", "test-local")
+	if _, err := localMgr.CreateLocal("test-local"); err != nil {
+		t.Fatalf("CreateLocal() failed: %v", err)
+	}
+
+	var polls, others atomic.Int64
+	stopped := make(chan error, 1)
+	if err := localMgr.Go("poller", func(ctx context.Context) error {
+		for {
+			select {
+			case <-Local.IsPaused(ctx):
+			case <-ctx.Done():
+				stopped <- ctx.Err()
+				return ctx.Err()
+			}
+			polls.Add(1)
+			time.Sleep(time.Millisecond)
+		}
+	}); err != nil {
+		t.Fatalf("Go() failed: %v", err)
+	}
+	// Another function isn't affected by pausing the poller
+	if err := localMgr.Go("other", func(ctx context.Context) error {
+		for Local.WaitIfPaused(ctx) == nil {
+			others.Add(1)
+			time.Sleep(time.Millisecond)
+		}
+		return nil
+	}); err != nil {
+		t.Fatalf("Go() failed: %v", err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	if polls.Load() == 0 {
+		t.Fatal("Poller made no progress before Pause()")
+	}
+
+	if err := localMgr.Pause("poller"); err != nil {
+		t.Fatalf("Pause() failed: %v", err)
+	}
+	if !localMgr.IsFunctionPaused("poller") || localMgr.IsFunctionPaused("other") {
+		t.Error("Expected only the poller to be paused")
+	}
+	// Let an iteration that already passed the gate finish
+	time.Sleep(20 * time.Millisecond)
+	pausedAt, othersAt := polls.Load(), others.Load()
+	time.Sleep(100 * time.Millisecond)
+	if got := polls.Load(); got != pausedAt {
+		t.Errorf("Poller kept polling while paused: %d -> %d", pausedAt, got)
+	}
+	if others.Load() == othersAt {
+		t.Error("Pausing the poller held up another function")
+	}
+	if count := localMgr.GetGoroutineCount(); count != 2 {
+		t.Errorf("Expected the paused worker to stay tracked, got %d goroutines", count)
+	}
+	fmt.Printf("✓ Poller held at %d polls while paused\n", pausedAt)
+
+	if err := localMgr.Resume("poller"); err != nil {
+		t.Fatalf("Resume() failed: %v", err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	if polls.Load() == pausedAt {
+		t.Error("Poller made no progress after Resume()")
+	}
+	fmt.Println("✓ Poller resumed polling")
+
+	// A paused worker still stops on cancellation
+	localMgr.Pause("poller")
+	time.Sleep(20 * time.Millisecond)
+	routines, _ := localMgr.GetRoutinesByFunctionName("poller")
+	if len(routines) != 1 {
+		t.Fatalf("Expected 1 poller routine, got %d", len(routines))
+	}
+	if err := localMgr.CancelRoutine(routines[0].GetID()); err != nil {
+		t.Fatalf("CancelRoutine() failed: %v", err)
+	}
+	select {
+	case err := <-stopped:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected context.Canceled, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Paused worker did not stop on cancellation")
+	}
+	fmt.Println("✓ Paused worker stopped on cancellation")
+
+	// Contexts not handed out by Go are never paused
+	select {
+	case <-Local.IsPaused(context.Background()):
+	default:
+		t.Error("IsPaused() blocked for a context not handed out by Go")
+	}
+	localMgr.Shutdown(false)
+}
+
+// TestGo_WithSoftTimeout verifies the soft timeout callback fires once while the worker keeps running
+func TestGo_WithSoftTimeout(t *testing.T) {
+	fmt.Println("\n=== TestGo_WithSoftTimeout ===")
+	Common.ResetGlobalState()
+
+	appMgr := App.NewAppManager("test-app")
+	if _, err := appMgr.CreateApp(); err != nil {
+		t.Fatalf("CreateApp() failed: %v", err)
+	}
+	localMgr := Local.NewLocalManager("test-app", "test-local")
+	if _, err := localMgr.CreateLocal("test-local"); err != nil {
+		t.Fatalf("CreateLocal() failed: %v", err)
+	}
+
+	var fired atomic.Int32
+	var workerFinished atomic.Bool
+	var finishedAtCallback atomic.Bool
+	exceeded := make(chan string, 1)
+	workerErr := make(chan error, 1)
+
+	err := localMgr.Go("slow-report", func(ctx context.Context) error {
+		select {
+		case <-time.After(150 * time.Millisecond):
+		case <-ctx.Done():
+		}
+		workerFinished.Store(true)
+		workerErr <- ctx.Err()
+		return nil
+	},
+		Local.WithSoftTimeout(30*time.Millisecond, func(routineID string) {
+			finishedAtCallback.Store(workerFinished.Load())
+			fired.Add(1)
+			exceeded <- routineID
+		}),
+		Local.WithTimeout(time.Second))
+	if err != nil {
+		t.Fatalf("Go() failed: %v", err)
+	}
+	routines, _ := localMgr.GetRoutinesByFunctionName("slow-report")
+	if len(routines) != 1 {
+		t.Fatalf("Expected 1 routine, got %d", len(routines))
+	}
+
+	select {
+	case routineID := <-exceeded:
+		if routineID != routines[0].GetID() {
+			t.Errorf("Expected routine ID %s, got %s", routines[0].GetID(), routineID)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Soft timeout callback did not fire")
+	}
+	if finishedAtCallback.Load() {
+		t.Error("Soft timeout should fire while the worker is still running")
+	}
+
+	if err := <-workerErr; err != nil {
+		t.Errorf("Soft timeout must not cancel the worker, context error: %v", err)
+	}
+	if fired.Load() != 1 {
+		t.Errorf("Expected the callback to fire once, fired %d times", fired.Load())
+	}
+	fmt.Println("✓ Soft timeout fired once and the worker kept running")
+
+	// A worker finishing in time never triggers the callback
+	var fastFired atomic.Bool
+	if err := localMgr.Go("fast-report", func(ctx context.Context) error {
+		return nil
+	}, Local.WithSoftTimeout(30*time.Millisecond, func(string) {
+		fastFired.Store(true)
+	}), Local.AddToWaitGroup("fast-report")); err != nil {
+		t.Fatalf("Go() failed: %v", err)
+	}
+	localMgr.WaitForFunction("fast-report")
+	time.Sleep(60 * time.Millisecond)
+	if fastFired.Load() {
+		t.Error("Soft timeout should not fire for a worker that finished in time")
+	}
+	fmt.Println("✓ Fast worker did not trigger the soft timeout")
+}
+
+// TestGo_WithErrorClassifier tests that expected stops aren't counted as failures
+func TestGo_WithErrorClassifier(t *testing.T) {
+	fmt.Println("\n=== TestGo_WithErrorClassifier ===")
+	Common.ResetGlobalState()
+
+	appMgr := App.NewAppManager("test-app")
+	if _, err := appMgr.CreateApp(); err != nil {
+		t.Fatalf("CreateApp() failed: %v", err)
+	}
+	localMgr := Local.NewLocalManager("test-app", "test-local")
+	if _, err := localMgr.CreateLocal("test-local"); err != nil {
+		t.Fatalf("CreateLocal() failed: %v", err)
+	}
+
+	// A worker returning ctx.Err() on shutdown is an expected stop by default
+	outcomes := make(chan Local.Outcome, 1)
+	if err := localMgr.Go("until-shutdown", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}, Local.AddToWaitGroup("until-shutdown"), Local.WithOnComplete(func(o Local.Outcome) {
+		outcomes <- o
+	})); err != nil {
+		t.Fatalf("Go() failed: %v", err)
+	}
+	if err := localMgr.WaitForFunctionStarted("until-shutdown", 1); err != nil {
+		t.Fatalf("WaitForFunctionStarted() failed: %v", err)
+	}
+	if err := localMgr.ShutdownFunction("until-shutdown", time.Second); err != nil {
+		t.Fatalf("ShutdownFunction() failed: %v", err)
+	}
+	stats := localMgr.GetFunctionStats("until-shutdown")
+	if stats.Failed != 0 || stats.Stopped != 1 {
+		t.Errorf("Expected 0 failed and 1 stopped, got %+v", stats)
+	}
+	if o := <-outcomes; o.Class != types.ErrorClassExpectedStop {
+		t.Errorf("Expected Outcome.Class expected_stop, got %v", o.Class)
+	}
+	fmt.Println("✓ Shutdown cancellation counted as an expected stop")
+
+	// A custom classifier turns a sentinel into an expected stop, other errors still fail
+	errDrained := errors.New("queue drained")
+	classify := func(err error) types.ErrorClass {
+		if errors.Is(err, errDrained) {
+			return types.ErrorClassExpectedStop
+		}
+		return types.DefaultErrorClassifier(err)
+	}
+	for _, err := range []error{errDrained, errors.New("boom"), nil} {
+		workerErr := err
+		if err := localMgr.Go("consumer", func(ctx context.Context) error {
+			return workerErr
+		}, Local.AddToWaitGroup("consumer"), Local.WithErrorClassifier(classify)); err != nil {
+			t.Fatalf("Go() failed: %v", err)
+		}
+	}
+	if err := localMgr.WaitForFunction("consumer"); err != nil {
+		t.Fatalf("WaitForFunction() failed: %v", err)
+	}
+	stats = localMgr.GetFunctionStats("consumer")
+	if stats.Stopped != 1 || stats.Failed != 1 || stats.Completed != 1 || stats.Live != 0 {
+		t.Errorf("Expected 1 stopped, 1 failed, 1 completed, got %+v", stats)
+	}
+
+	// A panicking classifier fails safe
+	if err := localMgr.Go("odd", func(ctx context.Context) error {
+		return errDrained
+	}, Local.AddToWaitGroup("odd"), Local.WithErrorClassifier(func(error) types.ErrorClass {
+		panic("classifier bug")
+	})); err != nil {
+		t.Fatalf("Go() failed: %v", err)
+	}
+	if err := localMgr.WaitForFunction("odd"); err != nil {
+		t.Fatalf("WaitForFunction() failed: %v", err)
+	}
+	if stats := localMgr.GetFunctionStats("odd"); stats.Failed != 1 {
+		t.Errorf("Expected a panicking classifier to count a failure, got %+v", stats)
+	}
+	fmt.Println("✓ Custom classifier applied")
+}
+
+func TestGo_WithLogBuffer(t *testing.T) {
+	fmt.Println("\n=== TestGo_WithLogBuffer ===")
+	Common.ResetGlobalState()
+
+	appMgr := App.NewAppManager("test-app")
+	if _, err := appMgr.CreateApp(); err != nil {
+		t.Fatalf("CreateApp() failed: %v", err)
+	}
+	localMgr := Local.NewLocalManager("test-app", "test-local")
+	if _, err := localMgr.CreateLocal("test-local"); err != nil {
+		t.Fatalf("CreateLocal() failed: %v", err)
+	}
+
+	routineIDs := make(chan string, 2)
+	recordID := Local.WithOnComplete(func(o Local.Outcome) {
+		routineIDs <- o.RoutineID
+	})
+
+	// Only the last two lines fit the buffer
+	if err := localMgr.Go("logger", func(ctx context.Context) error {
+		for i := 1; i <= 3; i++ {
+			Local.Logf(ctx, "step %d", i)
+		}
+		return nil
+	}, Local.AddToWaitGroup("logger"), Local.WithLogBuffer(2), recordID); err != nil {
+		t.Fatalf("Go() failed: %v", err)
+	}
+	if err := localMgr.Go("silent", func(ctx context.Context) error {
+		Local.Logf(ctx, "dropped")
+		return nil
+	}, Local.AddToWaitGroup("silent"), recordID); err != nil {
+		t.Fatalf("Go() failed: %v", err)
+	}
+	if err := localMgr.WaitForFunction("logger"); err != nil {
+		t.Fatalf("WaitForFunction() failed: %v", err)
+	}
+	if err := localMgr.WaitForFunction("silent"); err != nil {
+		t.Fatalf("WaitForFunction() failed: %v", err)
+	}
+
+	buffered := 0
+	for i := 0; i < 2; i++ {
+		routineID := <-routineIDs
+		lines, err := localMgr.GetRoutineLogs(routineID)
+		if err != nil {
+			t.Fatalf("GetRoutineLogs() failed: %v", err)
+		}
+		routine, _ := localMgr.GetRoutine(routineID)
+		if routine != nil {
+			t.Fatalf("Expected routine %s to be completed", routineID)
+		}
+		if len(lines) == 0 {
+			continue // the silent routine had no buffer
+		}
+		buffered++
+		want := []string{
+			fmt.Sprintf("[logger %s] step 2", routineID),
+			fmt.Sprintf("[logger %s] step 3", routineID),
+		}
+		if strings.Join(lines, "\n") != strings.Join(want, "\n") {
+			t.Errorf("Expected %q, got %q", want, lines)
+		}
+	}
+	if buffered != 1 {
+		t.Errorf("Expected only the buffered routine to have lines, got %d", buffered)
+	}
+	fmt.Println("✓ Buffered lines read back after completion")
+
+	if _, err := localMgr.GetRoutineLogs("missing"); !errors.Is(err, Errors.ErrRoutineNotFound) {
+		t.Errorf("Expected ErrRoutineNotFound, got %v", err)
+	}
+	fmt.Println("✓ Unknown routine reported")
+}
+
+func TestGo_WithMaxConcurrency(t *testing.T) {
+	fmt.Println("\n=== TestGo_WithMaxConcurrency ===")
+	Common.ResetGlobalState()
+
+	appMgr := App.NewAppManager("test-app")
+	if _, err := appMgr.CreateApp(); err != nil {
+		t.Fatalf("CreateApp() failed: %v", err)
+	}
+	localMgr := Local.NewLocalManager("test-app", "test-local")
+	if _, err := localMgr.CreateLocal("test-local"); err != nil {
+		t.Fatalf("CreateLocal() failed: %v", err)
+	}
+
+	// 10 workers with a limit of 3, each one holds its slot until released
+	started := make(chan struct{})
+	proceed := make(chan struct{})
+	spawnErr := make(chan error, 1)
+	go func() {
+		for i := 0; i < 10; i++ {
+			if err := localMgr.Go("scrape", func(ctx context.Context) error {
+				started <- struct{}{}
+				<-proceed
+				return nil
+			}, Local.AddToWaitGroup("scrape"), Local.WithMaxConcurrency("scrape", 3)); err != nil {
+				spawnErr <- err
+				return
+			}
+		}
+		spawnErr <- nil
+	}()
+
+	maxSeen := 0
+	for i := 0; i < 10; i++ {
+		<-started
+		if count := localMgr.GetFunctionGoroutineCount("scrape"); count > maxSeen {
+			maxSeen = count
+		}
+		if i < 2 {
+			continue // let the first 3 fill the limit before releasing any
+		}
+		proceed <- struct{}{}
+	}
+	// The first two are still holding their slots
+	proceed <- struct{}{}
+	proceed <- struct{}{}
+	if err := <-spawnErr; err != nil {
+		t.Fatalf("Go() failed: %v", err)
+	}
+	if err := localMgr.WaitForFunction("scrape"); err != nil {
+		t.Fatalf("WaitForFunction() failed: %v", err)
+	}
+	if maxSeen != 3 {
+		t.Errorf("Expected at most 3 scrape routines at once, saw %d", maxSeen)
+	}
+	fmt.Printf("✓ At most %d routines ran at once\n", maxSeen)
+
+	// With a limit of 1, a panicking worker must still free its slot
+	if err := localMgr.Go("fragile", func(ctx context.Context) error {
+		panic("boom")
+	}, Local.AddToWaitGroup("fragile"), Local.WithMaxConcurrency("fragile", 1)); err != nil {
+		t.Fatalf("Go() failed: %v", err)
+	}
+	if err := localMgr.WaitForFunction("fragile"); err != nil {
+		t.Fatalf("WaitForFunction() failed: %v", err)
+	}
+	release := make(chan struct{})
+	defer stopWorkers(release, localMgr)
+	blocker := func(ctx context.Context) error {
+		<-release
+		return nil
+	}
+	if err := localMgr.Go("fragile", blocker, Local.WithMaxConcurrency("fragile", 1), Local.WithRejectOverConcurrency()); err != nil {
+		t.Fatalf("Go() after a panic failed, the slot leaked: %v", err)
+	}
+	err := localMgr.Go("fragile", blocker, Local.WithMaxConcurrency("fragile", 1), Local.WithRejectOverConcurrency())
+	if !errors.Is(err, Errors.ErrMaxConcurrencyExceeded) {
+		t.Errorf("Expected ErrMaxConcurrencyExceeded, got %v", err)
+	}
+	fmt.Printf("✓ Slot released after a panic, over the limit rejected: %v\n", err)
+}
+
+func TestGo_WeightedConcurrency(t *testing.T) {
+	fmt.Println("\n=== TestGo_WeightedConcurrency ===")
+	Common.ResetGlobalState()
+
+	appMgr := App.NewAppManager("test-app")
+	if _, err := appMgr.CreateApp(); err != nil {
+		t.Fatalf("CreateApp() failed: %v", err)
+	}
+	localMgr := Local.NewLocalManager("test-app", "test-local")
+	if _, err := localMgr.CreateLocal("test-local"); err != nil {
+		t.Fatalf("CreateLocal() failed: %v", err)
+	}
+
+	if err := localMgr.SetFunctionWeight("heavy", 0); !errors.Is(err, Errors.ErrInvalidWeight) {
+		t.Errorf("Expected ErrInvalidWeight, got %v", err)
+	}
+	if err := localMgr.SetFunctionWeight("heavy", 2); err != nil {
+		t.Fatalf("SetFunctionWeight() failed: %v", err)
+	}
+
+	// heavy and light share a limit of 6, both keep it saturated
+	const perFunction = 40
+	var running, pending [2]atomic.Int32
+	var spawners sync.WaitGroup
+	for fn, name := range []string{"heavy", "light"} {
+		pending[fn].Store(perFunction)
+		for i := 0; i < perFunction; i++ {
+			spawners.Add(1)
+			go func() {
+				defer spawners.Done()
+				err := localMgr.Go(name, func(ctx context.Context) error {
+					pending[fn].Add(-1)
+					running[fn].Add(1)
+					time.Sleep(10 * time.Millisecond)
+					running[fn].Add(-1)
+					return nil
+				}, Local.AddToWaitGroup(name), Local.WithMaxConcurrency("db", 6))
+				if err != nil {
+					t.Errorf("Go() failed: %v", err)
+				}
+			}()
+		}
+	}
+
+	// Sample the slots held while both functions still have spawns waiting
+	var heavySlots, lightSlots int32
+	for pending[0].Load() > 0 && pending[1].Load() > 0 {
+		heavySlots += running[0].Load()
+		lightSlots += running[1].Load()
+		time.Sleep(time.Millisecond)
+	}
+	spawners.Wait()
+	localMgr.WaitForFunction("heavy")
+	localMgr.WaitForFunction("light")
+
+	share := float64(heavySlots) / float64(heavySlots+lightSlots)
+	if share < 0.55 || share > 0.8 {
+		t.Errorf("Expected heavy to hold about 2/3 of the slots, got %.2f", share)
+	}
+	fmt.Printf("✓ heavy (weight 2) held %.2f of the contended slots\n", share)
+}
+
+func TestGo_WithRetry(t *testing.T) {
+	fmt.Println("\n=== TestGo_WithRetry ===")
+	Common.ResetGlobalState()
+
+	gm := Global.NewGlobalManager()
+	if _, err := gm.Init(); err != nil {
+		t.Fatalf("Init() failed: %v", err)
+	}
+	if _, err := gm.UpdateMetadata(Global.SET_METRICS_URL, []interface{}{true, ""}); err != nil {
+		t.Fatalf("Failed to enable metrics: %v", err)
+	}
+	// Leave metrics uninitialized again for the tests that don't enable them
+	defer metrics.ResetMetrics()
+	defer metrics.StopCollector()
+
+	appMgr := App.NewAppManager("retry-app")
+	if _, err := appMgr.CreateApp(); err != nil {
+		t.Fatalf("CreateApp() failed: %v", err)
+	}
+	localMgr := Local.NewLocalManager("retry-app", "retry-local")
+	if _, err := localMgr.CreateLocal("retry-local"); err != nil {
+		t.Fatalf("CreateLocal() failed: %v", err)
+	}
+
+	retryCounter := metrics.GoroutineOperationsTotal.WithLabelValues("retry", "retry-app", "retry-local", "flaky")
+	retriesBefore := testutil.ToFloat64(retryCounter)
+
+	// Fails twice, then succeeds
+	var attempts atomic.Int32
+	outcomes := make(chan Local.Outcome, 1)
+	if err := localMgr.Go("flaky", func(ctx context.Context) error {
+		if attempts.Add(1) <= 2 {
+			return errors.New("transient")
+		}
+		return nil
+	}, Local.AddToWaitGroup("flaky"), Local.WithRetry(5, time.Millisecond), Local.WithOnComplete(func(o Local.Outcome) {
+		outcomes <- o
+	})); err != nil {
+		t.Fatalf("Go() failed: %v", err)
+	}
+	if err := localMgr.WaitForFunction("flaky"); err != nil {
+		t.Fatalf("WaitForFunction() failed: %v", err)
+	}
+	if got := attempts.Load(); got != 3 {
+		t.Errorf("Expected 3 attempts, got %d", got)
+	}
+	if retries := testutil.ToFloat64(retryCounter) - retriesBefore; retries != 2 {
+		t.Errorf("Expected 2 retries recorded, got %v", retries)
+	}
+	if o := <-outcomes; o.Type != Local.OutcomeSuccess {
+		t.Errorf("Expected the final outcome to be a success, got %v (%v)", o.Type, o.Err)
+	}
+	if stats := localMgr.GetFunctionStats("flaky"); stats.Completed != 1 || stats.Failed != 0 {
+		t.Errorf("Expected 1 completed and no failure, got %+v", stats)
+	}
+	fmt.Println("✓ Retried twice, then succeeded")
+
+	// Gives up after maxAttempts runs
+	var runs atomic.Int32
+	if err := localMgr.Go("broken", func(ctx context.Context) error {
+		runs.Add(1)
+		return errors.New("permanent")
+	}, Local.AddToWaitGroup("broken"), Local.WithRetry(3, time.Millisecond)); err != nil {
+		t.Fatalf("Go() failed: %v", err)
+	}
+	if err := localMgr.WaitForFunction("broken"); err != nil {
+		t.Fatalf("WaitForFunction() failed: %v", err)
+	}
+	if got := runs.Load(); got != 3 {
+		t.Errorf("Expected 3 runs, got %d", got)
+	}
+	if stats := localMgr.GetFunctionStats("broken"); stats.Failed != 1 {
+		t.Errorf("Expected 1 failure, got %+v", stats)
+	}
+	fmt.Println("✓ Gave up after the last attempt")
+}
+
+func TestComputeBackoff(t *testing.T) {
+	fmt.Println("\n=== TestComputeBackoff ===")
+
+	// Without jitter the sequence doubles until the cap
+	want := []time.Duration{100, 200, 400, 800, 1000, 1000}
+	for attempt, expected := range want {
+		if got := Local.ComputeBackoff(attempt, 100*time.Millisecond, time.Second, 0); got != expected*time.Millisecond {
+			t.Errorf("Attempt %d: expected %v, got %v", attempt, expected*time.Millisecond, got)
+		}
+	}
+	// Large attempts don't overflow past the cap
+	if got := Local.ComputeBackoff(1000, time.Millisecond, time.Minute, 0); got != time.Minute {
+		t.Errorf("Expected the cap for a large attempt, got %v", got)
+	}
+	fmt.Println("✓ Exponential sequence capped")
+
+	// Jitter stays within ±20% and is reproducible after reseeding
+	sequence := func() []time.Duration {
+		Local.SeedBackoff(42)
+		delays := make([]time.Duration, 8)
+		for attempt := range delays {
+			delays[attempt] = Local.ComputeBackoff(attempt, 100*time.Millisecond, time.Second, 0.2)
+		}
+		return delays
+	}
+	first, second := sequence(), sequence()
+	for attempt, delay := range first {
+		base := Local.ComputeBackoff(attempt, 100*time.Millisecond, time.Second, 0)
+		if delay < base*8/10 || delay > base*12/10 {
+			t.Errorf("Attempt %d: %v is outside ±20%% of %v", attempt, delay, base)
+		}
+		if second[attempt] != delay {
+			t.Errorf("Attempt %d: expected the same delay after reseeding, got %v and %v", attempt, delay, second[attempt])
+		}
+	}
+	fmt.Printf("✓ Jittered sequence: %v\n", first)
+}
+
+func TestGo_WithRetryBackoff(t *testing.T) {
+	fmt.Println("\n=== TestGo_WithRetryBackoff ===")
+	Common.ResetGlobalState()
+
+	appMgr := App.NewAppManager("test-app")
+	if _, err := appMgr.CreateApp(); err != nil {
+		t.Fatalf("CreateApp() failed: %v", err)
+	}
+	localMgr := Local.NewLocalManager("test-app", "test-local")
+	if _, err := localMgr.CreateLocal("test-local"); err != nil {
+		t.Fatalf("CreateLocal() failed: %v", err)
+	}
+
+	// Fails twice, then succeeds after backing off 1ms and 2ms
+	var attempts atomic.Int32
+	if err := localMgr.Go("flaky", func(ctx context.Context) error {
+		if attempts.Add(1) <= 2 {
+			return errors.New("transient")
+		}
+		return nil
+	}, Local.AddToWaitGroup("flaky"), Local.WithRetryBackoff(5, time.Millisecond, 10*time.Millisecond, 0.5)); err != nil {
+		t.Fatalf("Go() failed: %v", err)
+	}
+	if err := localMgr.WaitForFunction("flaky"); err != nil {
+		t.Fatalf("WaitForFunction() failed: %v", err)
+	}
+	if got := attempts.Load(); got != 3 {
+		t.Errorf("Expected 3 attempts, got %d", got)
+	}
+
+	// A shutdown interrupts a long backoff instead of waiting it out
+	var runs atomic.Int32
+	if err := localMgr.Go("stuck", func(ctx context.Context) error {
+		runs.Add(1)
+		return errors.New("down")
+	}, Local.AddToWaitGroup("stuck"), Local.WithRetryBackoff(5, time.Hour, time.Hour, 0)); err != nil {
+		t.Fatalf("Go() failed: %v", err)
+	}
+	if err := localMgr.WaitForFunctionStarted("stuck", 1); err != nil {
+		t.Fatalf("WaitForFunctionStarted() failed: %v", err)
+	}
+	start := time.Now()
+	if err := localMgr.ShutdownFunction("stuck", 5*time.Second); err != nil {
+		t.Fatalf("ShutdownFunction() failed: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Shutdown waited %v for the backoff", elapsed)
+	}
+	if got := runs.Load(); got != 1 {
+		t.Errorf("Expected no retry after the shutdown, got %d runs", got)
+	}
+	fmt.Println("✓ Retried with backoff, shutdown interrupted the wait")
+}
+
+func TestGo_WithRestart(t *testing.T) {
+	fmt.Println("\n=== TestGo_WithRestart ===")
+	Common.ResetGlobalState()
+
+	appMgr := App.NewAppManager("restart-app")
+	if _, err := appMgr.CreateApp(); err != nil {
+		t.Fatalf("CreateApp() failed: %v", err)
+	}
+	localMgr := Local.NewLocalManager("restart-app", "restart-local")
+	if _, err := localMgr.CreateLocal("restart-local"); err != nil {
+		t.Fatalf("CreateLocal() failed: %v", err)
+	}
+
+	// Always fails, restarts stop at MaxRestarts
+	var runs atomic.Int32
+	outcomes := make(chan Local.Outcome, 1)
+	if err := localMgr.Go("crashing", func(ctx context.Context) error {
+		runs.Add(1)
+		return errors.New("crashed")
+	}, Local.AddToWaitGroup("crashing"), Local.WithRestart(types.RestartPolicy{
+		Mode:        types.RestartOnFailure,
+		MaxRestarts: 2,
+	}), Local.WithOnComplete(func(o Local.Outcome) {
+		outcomes <- o
+	})); err != nil {
+		t.Fatalf("Go() failed: %v", err)
+	}
+	if err := localMgr.WaitForFunction("crashing"); err != nil {
+		t.Fatalf("WaitForFunction() failed: %v", err)
+	}
+	o := <-outcomes
+	if got := runs.Load(); got != 3 {
+		t.Errorf("Expected 3 runs, got %d", got)
+	}
+	if got := localMgr.GetRoutineRestartCount(o.RoutineID); got != 2 {
+		t.Errorf("Expected 2 restarts, got %d", got)
+	}
+	if o.Type != Local.OutcomeError {
+		t.Errorf("Expected the final outcome to be an error, got %v", o.Type)
+	}
+	fmt.Println("✓ Stopped after 2 restarts")
+
+	// A recovered panic is restarted too, a successful run ends the routine
+	var panicRuns atomic.Int32
+	if err := localMgr.Go("panicky", func(ctx context.Context) error {
+		if panicRuns.Add(1) == 1 {
+			panic("first run")
+		}
+		return nil
+	}, Local.AddToWaitGroup("panicky"), Local.WithRestart(types.RestartPolicy{
+		Mode: types.RestartOnFailure,
+	}), Local.WithOnComplete(func(o Local.Outcome) {
+		outcomes <- o
+	})); err != nil {
+		t.Fatalf("Go() failed: %v", err)
+	}
+	if err := localMgr.WaitForFunction("panicky"); err != nil {
+		t.Fatalf("WaitForFunction() failed: %v", err)
+	}
+	o = <-outcomes
+	if got := panicRuns.Load(); got != 2 {
+		t.Errorf("Expected 2 runs, got %d", got)
+	}
+	if got := localMgr.GetRoutineRestartCount(o.RoutineID); got != 1 {
+		t.Errorf("Expected 1 restart, got %d", got)
+	}
+	if o.Type != Local.OutcomeSuccess {
+		t.Errorf("Expected the final outcome to be a success, got %v (%v)", o.Type, o.Err)
+	}
+	fmt.Println("✓ Panic restarted, success ended the routine")
+
+	// Cancellation stops an unlimited RestartAlways supervisor, each run gets a fresh context
+	var alwaysRuns atomic.Int32
+	runCtxs := make(chan context.Context, 1)
+	if err := localMgr.Go("always", func(ctx context.Context) error {
+		alwaysRuns.Add(1)
+		select {
+		case runCtxs <- ctx:
+		default:
+		}
+		time.Sleep(time.Millisecond)
+		return nil
+	}, Local.AddToWaitGroup("always"), Local.WithTimeout(100*time.Millisecond), Local.WithRestart(types.RestartPolicy{
+		Mode: types.RestartAlways,
+	})); err != nil {
+		t.Fatalf("Go() failed: %v", err)
+	}
+	if !localMgr.WaitForFunctionWithTimeout("always", time.Second) {
+		t.Fatal("Restarts didn't stop once the routine's context was done")
+	}
+	if got := alwaysRuns.Load(); got < 2 {
+		t.Errorf("Expected the worker to be restarted, got %d runs", got)
+	}
+	if firstCtx := <-runCtxs; firstCtx.Err() == nil {
+		t.Error("Expected the context of a finished run to be cancelled")
+	}
+	fmt.Printf("✓ RestartAlways ran %d times until the timeout\n", alwaysRuns.Load())
+}
+
+// TestGo_WithRestart_PanicAsFailure tests that with panic recovery on, a recovered panic counts as a
+// failure for RestartOnFailure, waiting the restart delay between runs
+func TestGo_WithRestart_PanicAsFailure(t *testing.T) {
+	fmt.Println("\n=== TestGo_WithRestart_PanicAsFailure ===")
+	Common.ResetGlobalState()
+
+	if _, err := App.NewAppManager("panic-restart-app").CreateApp(); err != nil {
+		t.Fatalf("CreateApp() failed: %v", err)
+	}
+	localMgr := Local.NewLocalManager("panic-restart-app", "panic-restart-local")
+	if _, err := localMgr.CreateLocal("panic-restart-local"); err != nil {
+		t.Fatalf("CreateLocal() failed: %v", err)
+	}
+
+	// Panics on the first two runs, succeeds on the third
+	start := time.Now()
+	var runs atomic.Int32
+	outcomes := make(chan Local.Outcome, 1)
+	if err := localMgr.Go("flaky", func(ctx context.Context) error {
+		if run := runs.Add(1); run <= 2 {
+			panic(fmt.Sprintf("run %d", run))
+		}
+		return nil
+	}, Local.AddToWaitGroup("flaky"), Local.WithPanicRecovery(true), Local.WithRestart(types.RestartPolicy{
+		Mode:        types.RestartOnFailure,
+		MaxRestarts: 5,
+		Delay:       10 * time.Millisecond,
+	}), Local.WithOnComplete(func(o Local.Outcome) {
+		outcomes <- o
+	})); err != nil {
+		t.Fatalf("Go() failed: %v", err)
+	}
+	if !localMgr.WaitForFunctionWithTimeout("flaky", time.Second) {
+		t.Fatal("Restarted worker didn't finish")
+	}
+	o := <-outcomes
+	if got := runs.Load(); got != 3 {
+		t.Errorf("Expected 3 runs, got %d", got)
+	}
+	if got := localMgr.GetRoutineRestartCount(o.RoutineID); got != 2 {
+		t.Errorf("Expected 2 restarts, got %d", got)
+	}
+	if o.Type != Local.OutcomeSuccess {
+		t.Errorf("Expected the final outcome to be a success, got %v (%v)", o.Type, o.Err)
+	}
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+		t.Errorf("Expected the 2 restarts to wait their delay, took %v", elapsed)
+	}
+	fmt.Println("✓ Two recovered panics restarted, the third run succeeded")
+}
+
+// TestGo_WithLabels tests tagging routines WithLabels and finding them with GetRoutinesByLabel
+func TestGo_WithLabels(t *testing.T) {
+	fmt.Println("\n=== TestGo_WithLabels ===")
+	Common.ResetGlobalState()
+
+	gm := Global.NewGlobalManager()
+	if _, err := gm.Init(); err != nil {
+		t.Fatalf("Init() failed: %v", err)
+	}
+	if _, err := gm.UpdateMetadata(Global.SET_METRICS_URL, []interface{}{true, ""}); err != nil {
+		t.Fatalf("Failed to enable metrics: %v", err)
+	}
+	// Leave metrics uninitialized again for the tests that don't enable them
+	defer metrics.ResetMetrics()
+	defer metrics.StopCollector()
+
+	if _, err := App.NewAppManager("labels-app").CreateApp(); err != nil {
+		t.Fatalf("CreateApp() failed: %v", err)
+	}
+	localMgr := Local.NewLocalManager("labels-app", "labels-local")
+	if _, err := localMgr.CreateLocal("labels-local"); err != nil {
+		t.Fatalf("CreateLocal() failed: %v", err)
+	}
+
+	labeled := metrics.LabeledOperationsTotal.WithLabelValues("create", "labels-app", "labels-local", "tenant-worker", "region=eu,tenant=acme")
+	labeledBefore := testutil.ToFloat64(labeled)
+
+	release := make(chan struct{})
+	block := func(ctx context.Context) error {
+		<-release
+		return nil
+	}
+	acme := map[string]string{"tenant": "acme", "region": "eu"}
+	for i := 0; i < 2; i++ {
+		if err := localMgr.Go("tenant-worker", block, Local.AddToWaitGroup("tenant-worker"), Local.WithLabels(acme)); err != nil {
+			t.Fatalf("Go() failed: %v", err)
+		}
+	}
+	if err := localMgr.Go("tenant-worker", block, Local.AddToWaitGroup("tenant-worker"),
+		Local.WithLabels(map[string]string{"tenant": "globex"})); err != nil {
+		t.Fatalf("Go() failed: %v", err)
+	}
+	if err := localMgr.Go("tenant-worker", block, Local.AddToWaitGroup("tenant-worker")); err != nil {
+		t.Fatalf("Go() failed: %v", err)
+	}
+	// The labels were copied, changing the caller's map afterwards doesn't retag the routines
+	acme["tenant"] = "changed"
+
+	routines, err := localMgr.GetRoutinesByLabel("tenant", "acme")
+	if err != nil {
+		t.Fatalf("GetRoutinesByLabel() failed: %v", err)
+	}
+	if len(routines) != 2 {
+		t.Fatalf("Expected 2 routines tagged tenant=acme, got %d", len(routines))
+	}
+	if got := routines[0].GetLabels(); got["region"] != "eu" || got["tenant"] != "acme" {
+		t.Errorf("Unexpected labels %v", got)
+	}
+	if routines, _ := localMgr.GetRoutinesByLabel("tenant", "globex"); len(routines) != 1 {
+		t.Errorf("Expected 1 routine tagged tenant=globex, got %d", len(routines))
+	}
+	if routines, _ := localMgr.GetRoutinesByLabel("region", "eu"); len(routines) != 2 {
+		t.Errorf("Expected 2 routines tagged region=eu, got %d", len(routines))
+	}
+	if routines, _ := localMgr.GetRoutinesByLabel("tenant", "changed"); len(routines) != 0 {
+		t.Errorf("Expected no routines for tenant=changed, got %d", len(routines))
+	}
+	fmt.Println("✓ Filtered routines by label")
+
+	if got := testutil.ToFloat64(labeled) - labeledBefore; got != 2 {
+		t.Errorf("Expected 2 labeled creations for region=eu,tenant=acme, got %v", got)
+	}
+	fmt.Println("✓ Labeled operations counted per label set")
+
+	close(release)
+	if err := localMgr.WaitForFunction("tenant-worker"); err != nil {
+		t.Fatalf("WaitForFunction() failed: %v", err)
+	}
+	if routines, _ := localMgr.GetRoutinesByLabel("tenant", "acme"); len(routines) != 0 {
+		t.Errorf("Expected finished routines to be gone, got %d", len(routines))
+	}
+}
+
+// TestGo_WithRateLimit tests that spawns sharing a rate limit are spaced out and that waiting is cancellable
+func TestGo_WithRateLimit(t *testing.T) {
+	fmt.Println("\n=== TestGo_WithRateLimit ===")
+	Common.ResetGlobalState()
+
+	if _, err := App.NewAppManager("rate-app").CreateApp(); err != nil {
+		t.Fatalf("CreateApp() failed: %v", err)
+	}
+	localMgr := Local.NewLocalManager("rate-app", "rate-local")
+	if _, err := localMgr.CreateLocal("rate-local"); err != nil {
+		t.Fatalf("CreateLocal() failed: %v", err)
+	}
+
+	// A burst of 10 goes right away, the next 10 take a second at 10/s
+	var spawned atomic.Int32
+	start := time.Now()
+	for i := 0; i < 20; i++ {
+		if err := localMgr.Go("email", func(ctx context.Context) error {
+			spawned.Add(1)
+			return nil
+		}, Local.AddToWaitGroup("email"), Local.WithRateLimit("email", 10, 10)); err != nil {
+			t.Fatalf("Go() failed: %v", err)
+		}
+	}
+	elapsed := time.Since(start)
+	if elapsed < 900*time.Millisecond {
+		t.Errorf("Expected 20 spawns at 10/s with a burst of 10 to take about 1s, took %v", elapsed)
+	}
+	if err := localMgr.WaitForFunction("email"); err != nil {
+		t.Fatalf("WaitForFunction() failed: %v", err)
+	}
+	if got := spawned.Load(); got != 20 {
+		t.Errorf("Expected 20 workers to run, got %d", got)
+	}
+	fmt.Printf("✓ 20 spawns at 10/s took %v\n", elapsed)
+
+	// The limiter is shared by name, a later call passing a higher rate still waits on the first one
+	if err := localMgr.Go("email", func(ctx context.Context) error { return nil },
+		Local.WithRateLimit("email", 1000, 1000)); err != nil {
+		t.Fatalf("Go() failed: %v", err)
+	}
+	if waited := time.Since(start) - elapsed; waited < 50*time.Millisecond {
+		t.Errorf("Expected the shared limiter to make the spawn wait, waited %v", waited)
+	}
+	fmt.Println("✓ Limiter shared across calls with the same name")
+
+	// Waiting for a token ends with the caller's context
+	limit := Local.WithRateLimit("slow", 0.1, 1)
+	if err := localMgr.Go("slow", func(ctx context.Context) error { return nil }, limit); err != nil {
+		t.Fatalf("Go() failed: %v", err)
+	}
+	parent, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err := localMgr.GoWithContext(parent, "slow", func(ctx context.Context) error {
+		t.Error("Worker ran without a token")
+		return nil
+	}, limit)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the parent's context.DeadlineExceeded, got %v", err)
+	}
+	fmt.Printf("✓ Cancelled wait for a token: %v\n", err)
+}
+
+// TestGo_Tracing tests that a configured tracer gets one span per goroutine with its status
+func TestGo_Tracing(t *testing.T) {
+	fmt.Println("\n=== TestGo_Tracing ===")
+	Common.ResetGlobalState()
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	Local.SetTracer(provider.Tracer("goroutines"))
+	defer Local.SetTracer(nil)
+
+	if _, err := App.NewAppManager("trace-app").CreateApp(); err != nil {
+		t.Fatalf("CreateApp() failed: %v", err)
+	}
+	localMgr := Local.NewLocalManager("trace-app", "trace-local")
+	if _, err := localMgr.CreateLocal("trace-local"); err != nil {
+		t.Fatalf("CreateLocal() failed: %v", err)
+	}
+
+	// The worker's spans nest under the routine's span
+	okID, err := localMgr.GoWithResult("traced-ok", func(ctx context.Context) (interface{}, error) {
+		_, child := provider.Tracer("worker").Start(ctx, "child")
+		child.End()
+		return nil, nil
+	})
+	if err != nil {
+		t.Fatalf("GoWithResult() failed: %v", err)
+	}
+	errFailed := errors.New("traced failure")
+	if err := localMgr.Go("traced-fail", func(ctx context.Context) error { return errFailed }, Local.AddToWaitGroup("traced-fail")); err != nil {
+		t.Fatalf("Go() failed: %v", err)
+	}
+	if err := localMgr.Go("traced-panic", func(ctx context.Context) error { panic("boom") }, Local.AddToWaitGroup("traced-panic")); err != nil {
+		t.Fatalf("Go() failed: %v", err)
+	}
+	localMgr.WaitForRoutine(okID, time.Second)
+	localMgr.WaitForFunction("traced-fail")
+	localMgr.WaitForFunction("traced-panic")
+
+	spans := make(map[string]sdktrace.ReadOnlySpan)
+	for _, span := range recorder.Ended() {
+		spans[span.Name()] = span
+	}
+	okSpan, ok := spans["traced-ok"]
+	if !ok {
+		t.Fatalf("Expected a span named traced-ok, got %v", spans)
+	}
+	attrs := make(map[string]string)
+	for _, kv := range okSpan.Attributes() {
+		attrs[string(kv.Key)] = kv.Value.AsString()
+	}
+	if attrs[Local.Attr_RoutineID] != okID || attrs[Local.Attr_AppName] != "trace-app" || attrs[Local.Attr_LocalName] != "trace-local" {
+		t.Errorf("Unexpected span attributes %v", attrs)
+	}
+	if okSpan.Status().Code != codes.Ok {
+		t.Errorf("Expected an Ok status, got %v", okSpan.Status())
+	}
+	if child, ok := spans["child"]; !ok || child.Parent().SpanID() != okSpan.SpanContext().SpanID() {
+		t.Error("Expected the worker's span to be a child of the routine's span")
+	}
+	fmt.Println("✓ Span named after the function with the routine ID")
+
+	if span := spans["traced-fail"]; span == nil || span.Status().Code != codes.Error || span.Status().Description != errFailed.Error() {
+		t.Errorf("Expected an error status for the failed worker, got %v", span)
+	}
+	if span := spans["traced-panic"]; span == nil || span.Status().Code != codes.Error || len(span.Events()) == 0 {
+		t.Errorf("Expected an error status and a recorded panic for the panicking worker, got %v", span)
+	}
+	fmt.Println("✓ Error and panic recorded on the span status")
+
+	// Without a tracer nothing is recorded
+	Local.SetTracer(nil)
+	before := len(recorder.Ended())
+	if err := localMgr.Go("untraced", func(ctx context.Context) error { return nil }, Local.AddToWaitGroup("untraced")); err != nil {
+		t.Fatalf("Go() failed: %v", err)
+	}
+	localMgr.WaitForFunction("untraced")
+	if after := len(recorder.Ended()); after != before {
+		t.Errorf("Expected no span without a tracer, got %d new", after-before)
+	}
+}
@@ -2,14 +2,22 @@ package Managertests
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"runtime"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/neerajchowdary889/GoRoutinesManager/Context"
 	"github.com/neerajchowdary889/GoRoutinesManager/Manager/App"
+	"github.com/neerajchowdary889/GoRoutinesManager/Manager/Errors"
+	"github.com/neerajchowdary889/GoRoutinesManager/Manager/Global"
+	"github.com/neerajchowdary889/GoRoutinesManager/Manager/Interface"
 	"github.com/neerajchowdary889/GoRoutinesManager/Manager/Local"
+	"github.com/neerajchowdary889/GoRoutinesManager/types"
 )
 
 func TestLocalManager_CreateLocal(t *testing.T) {
@@ -669,3 +677,1545 @@ func TestLocalManager_ComplexOperationsWithArguments(t *testing.T) {
 
 	fmt.Println("\n✓ All complex operations completed successfully!")
 }
+
+func TestLocalManager_CreateLocalWithContext(t *testing.T) {
+	resetGlobalState()
+
+	appMgr := App.NewAppManager("test-app")
+	_, err := appMgr.CreateApp()
+	if err != nil {
+		t.Fatalf("CreateApp() failed: %v", err)
+	}
+
+	localMgr := Local.NewLocalManager("test-app", "ctx-local")
+	local, ctx, err := localMgr.CreateLocalWithContext("ctx-local")
+	if err != nil {
+		t.Fatalf("CreateLocalWithContext() failed: %v", err)
+	}
+	if local == nil || ctx == nil {
+		t.Fatal("CreateLocalWithContext() returned nil manager or context")
+	}
+
+	// Context is live and is the local manager's own context
+	if ctx.Err() != nil {
+		t.Fatalf("Expected live context, got %v", ctx.Err())
+	}
+	localCtx, _ := local.GetLocalContext()
+	if ctx != localCtx {
+		t.Error("Returned context should be the local manager's context")
+	}
+
+	// Calling it again returns the existing manager and the same context
+	local2, ctx2, err := localMgr.CreateLocalWithContext("ctx-local")
+	if err != nil {
+		t.Fatalf("Second CreateLocalWithContext() failed: %v", err)
+	}
+	if local2 != local || ctx2 != ctx {
+		t.Error("CreateLocalWithContext() should be idempotent")
+	}
+
+	// Shutting down the context tree cancels the returned context
+	Context.GetGlobalContext().Shutdown()
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("Expected context to be cancelled on shutdown")
+	}
+}
+
+func TestLocalManager_Go_ReservedFunctionName(t *testing.T) {
+	resetGlobalState()
+
+	appMgr := App.NewAppManager("test-app")
+	_, err := appMgr.CreateApp()
+	if err != nil {
+		t.Fatalf("CreateApp() failed: %v", err)
+	}
+
+	localMgr := Local.NewLocalManager("test-app", "test-local")
+	_, err = localMgr.CreateLocal("test-local")
+	if err != nil {
+		t.Fatalf("CreateLocal() failed: %v", err)
+	}
+
+	var executed atomic.Bool
+	err = localMgr.Go("__internal", func(ctx context.Context) error {
+		executed.Store(true)
+		return nil
+	})
+	if !errors.Is(err, Errors.ErrReservedFunctionName) {
+		t.Fatalf("Expected ErrReservedFunctionName, got %v", err)
+	}
+	if localMgr.GetGoroutineCount() != 0 {
+		t.Error("Reserved function name should not be tracked")
+	}
+
+	// Normal names (including ones containing underscores) still work
+	done := make(chan struct{})
+	err = localMgr.Go("normal_worker", func(ctx context.Context) error {
+		close(done)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Go() with a normal name failed: %v", err)
+	}
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Normal worker did not run")
+	}
+
+	if executed.Load() {
+		t.Error("Reserved worker should never have executed")
+	}
+}
+
+// stuckStackWorker is a named worker so its frame can be found in stack dumps
+func stuckStackWorker(ctx context.Context) error {
+	<-ctx.Done()
+	return nil
+}
+
+func TestLocalManager_DumpFunctionStacks(t *testing.T) {
+	resetGlobalState()
+
+	appMgr := App.NewAppManager("test-app")
+	_, err := appMgr.CreateApp()
+	if err != nil {
+		t.Fatalf("CreateApp() failed: %v", err)
+	}
+
+	localMgr := Local.NewLocalManager("test-app", "test-local")
+	_, err = localMgr.CreateLocal("test-local")
+	if err != nil {
+		t.Fatalf("CreateLocal() failed: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := localMgr.Go("stuck", stuckStackWorker); err != nil {
+			t.Fatalf("Go() failed: %v", err)
+		}
+	}
+	// A different function in the same local manager must not show up in the dump
+	if err := localMgr.Go("other", func(ctx context.Context) error {
+		<-ctx.Done()
+		return nil
+	}); err != nil {
+		t.Fatalf("Go() failed: %v", err)
+	}
+	defer localMgr.Shutdown(false)
+
+	// Give the goroutines time to start and apply their labels
+	time.Sleep(50 * time.Millisecond)
+
+	dump, err := localMgr.DumpFunctionStacks("stuck")
+	if err != nil {
+		t.Fatalf("DumpFunctionStacks() failed: %v", err)
+	}
+	if !strings.Contains(string(dump), "stuckStackWorker") {
+		t.Errorf("Expected dump to contain the worker frame, got:\n%s", dump)
+	}
+	if !strings.Contains(string(dump), `"function_name":"stuck"`) {
+		t.Errorf("Expected dump to carry the function label, got:\n%s", dump)
+	}
+	if strings.Contains(string(dump), `"function_name":"other"`) {
+		t.Error("Dump should only contain stacks of the requested function")
+	}
+
+	// Unknown function yields an empty dump
+	dump, err = localMgr.DumpFunctionStacks("missing")
+	if err != nil {
+		t.Fatalf("DumpFunctionStacks() failed: %v", err)
+	}
+	if len(dump) != 0 {
+		t.Errorf("Expected empty dump for unknown function, got:\n%s", dump)
+	}
+}
+
+func TestLocalManager_GetFunctionStats(t *testing.T) {
+	resetGlobalState()
+
+	appMgr := App.NewAppManager("test-app")
+	_, err := appMgr.CreateApp()
+	if err != nil {
+		t.Fatalf("CreateApp() failed: %v", err)
+	}
+
+	localMgr := Local.NewLocalManager("test-app", "test-local")
+	_, err = localMgr.CreateLocal("test-local")
+	if err != nil {
+		t.Fatalf("CreateLocal() failed: %v", err)
+	}
+
+	// 3 succeed, 2 fail, 1 panics - all in the wait group so we can wait for them
+	for i := 0; i < 3; i++ {
+		localMgr.Go("stats-worker", func(ctx context.Context) error {
+			time.Sleep(20 * time.Millisecond)
+			return nil
+		}, Local.AddToWaitGroup("stats-worker"))
+	}
+	for i := 0; i < 2; i++ {
+		localMgr.Go("stats-worker", func(ctx context.Context) error {
+			time.Sleep(20 * time.Millisecond)
+			return errors.New("boom")
+		}, Local.AddToWaitGroup("stats-worker"))
+	}
+	localMgr.Go("stats-worker", func(ctx context.Context) error {
+		time.Sleep(20 * time.Millisecond)
+		panic("worker panic")
+	}, Local.AddToWaitGroup("stats-worker"), Local.WithPanicRecovery(true))
+
+	// One more stays running and must show up as live
+	release := make(chan struct{})
+	localMgr.Go("stats-worker", func(ctx context.Context) error {
+		<-release
+		return nil
+	})
+	defer stopWorkers(release, localMgr)
+
+	if !localMgr.WaitForFunctionWithTimeout("stats-worker", 2*time.Second) {
+		t.Fatal("Workers did not finish in time")
+	}
+
+	stats := localMgr.GetFunctionStats("stats-worker")
+	if stats.FunctionName != "stats-worker" {
+		t.Errorf("Expected function name stats-worker, got %s", stats.FunctionName)
+	}
+	if stats.Spawned != 7 {
+		t.Errorf("Expected 7 spawned, got %d", stats.Spawned)
+	}
+	if stats.Completed != 3 {
+		t.Errorf("Expected 3 completed, got %d", stats.Completed)
+	}
+	if stats.Failed != 2 {
+		t.Errorf("Expected 2 failed, got %d", stats.Failed)
+	}
+	if stats.Panicked != 1 {
+		t.Errorf("Expected 1 panicked, got %d", stats.Panicked)
+	}
+	if stats.Live != 1 {
+		t.Errorf("Expected 1 live, got %d", stats.Live)
+	}
+	if stats.AverageDuration < 20*time.Millisecond {
+		t.Errorf("Expected average duration of at least 20ms, got %v", stats.AverageDuration)
+	}
+
+	// Unknown function returns zero stats
+	empty := localMgr.GetFunctionStats("missing")
+	if empty.Spawned != 0 || empty.Live != 0 || empty.AverageDuration != 0 {
+		t.Errorf("Expected zero stats for unknown function, got %+v", empty)
+	}
+}
+
+func TestLocalManager_SetErrorRateThreshold(t *testing.T) {
+	resetGlobalState()
+
+	appMgr := App.NewAppManager("test-app")
+	if _, err := appMgr.CreateApp(); err != nil {
+		t.Fatalf("CreateApp() failed: %v", err)
+	}
+	localMgr := Local.NewLocalManager("test-app", "test-local")
+	if _, err := localMgr.CreateLocal("test-local"); err != nil {
+		t.Fatalf("CreateLocal() failed: %v", err)
+	}
+
+	var breaches []float64
+	var mu sync.Mutex
+	err := localMgr.SetErrorRateThreshold("flaky-worker", 0.5, time.Minute, func(functionName string, rate float64) {
+		mu.Lock()
+		defer mu.Unlock()
+		if functionName != "flaky-worker" {
+			t.Errorf("Expected callback for flaky-worker, got %s", functionName)
+		}
+		breaches = append(breaches, rate)
+	})
+	if err != nil {
+		t.Fatalf("SetErrorRateThreshold() failed: %v", err)
+	}
+
+	// One at a time so the rate after each routine is known: 2 successes then 4 failures
+	run := func(fail bool) {
+		localMgr.Go("flaky-worker", func(ctx context.Context) error {
+			if fail {
+				return errors.New("boom")
+			}
+			return nil
+		}, Local.AddToWaitGroup("flaky-worker"))
+		if !localMgr.WaitForFunctionWithTimeout("flaky-worker", time.Second) {
+			t.Fatal("Worker did not finish in time")
+		}
+	}
+	run(false)
+	run(false)
+	run(true) // 1/3
+	run(true) // 2/4, at the threshold but not above
+	mu.Lock()
+	if len(breaches) != 0 {
+		t.Errorf("Callback should not fire at or below the threshold, got %v", breaches)
+	}
+	mu.Unlock()
+
+	run(true) // 3/5 breaches
+	run(true) // 4/6 still breached, not reported again
+	mu.Lock()
+	if len(breaches) != 1 {
+		t.Fatalf("Expected the callback to fire once, got %v", breaches)
+	}
+	if breaches[0] != 0.6 {
+		t.Errorf("Expected a breach at rate 0.6, got %v", breaches[0])
+	}
+	mu.Unlock()
+
+	// Invalid thresholds are rejected
+	err = localMgr.SetErrorRateThreshold("flaky-worker", 1.5, time.Minute, func(string, float64) {})
+	if !errors.Is(err, Errors.ErrInvalidErrorRate) {
+		t.Errorf("Expected ErrInvalidErrorRate for a ratio above 1, got %v", err)
+	}
+	err = localMgr.SetErrorRateThreshold("flaky-worker", 0.5, 0, func(string, float64) {})
+	if !errors.Is(err, Errors.ErrInvalidErrorRate) {
+		t.Errorf("Expected ErrInvalidErrorRate for an empty window, got %v", err)
+	}
+}
+
+func TestLocalManager_GoWait(t *testing.T) {
+	resetGlobalState()
+
+	appMgr := App.NewAppManager("test-app")
+	if _, err := appMgr.CreateApp(); err != nil {
+		t.Fatalf("CreateApp() failed: %v", err)
+	}
+	localMgr := Local.NewLocalManager("test-app", "test-local")
+	if _, err := localMgr.CreateLocal("test-local"); err != nil {
+		t.Fatalf("CreateLocal() failed: %v", err)
+	}
+
+	// Finish in reverse order so the result order can't come from completion order
+	errSecond := errors.New("second failed")
+	workers := []func(ctx context.Context) error{
+		func(ctx context.Context) error {
+			time.Sleep(60 * time.Millisecond)
+			return nil
+		},
+		func(ctx context.Context) error {
+			time.Sleep(30 * time.Millisecond)
+			return errSecond
+		},
+		func(ctx context.Context) error {
+			return nil
+		},
+	}
+	errs := localMgr.GoWait("batch", workers, time.Second)
+	if len(errs) != 3 {
+		t.Fatalf("Expected 3 results, got %d", len(errs))
+	}
+	if errs[0] != nil || !errors.Is(errs[1], errSecond) || errs[2] != nil {
+		t.Errorf("Expected [nil, %v, nil], got %v", errSecond, errs)
+	}
+
+	// Workers past the timeout report a deadline, panics are reported too
+	release := make(chan struct{})
+	defer stopWorkers(release, localMgr)
+	errs = localMgr.GoWait("batch", []func(ctx context.Context) error{
+		func(ctx context.Context) error {
+			<-release
+			return nil
+		},
+		func(ctx context.Context) error {
+			panic("batch panic")
+		},
+	}, 50*time.Millisecond)
+	if !errors.Is(errs[0], context.DeadlineExceeded) {
+		t.Errorf("Expected the stuck worker to report context.DeadlineExceeded, got %v", errs[0])
+	}
+	if !errors.Is(errs[1], Errors.ErrWorkerPanicked) {
+		t.Errorf("Expected the panicking worker to report ErrWorkerPanicked, got %v", errs[1])
+	}
+}
+
+func TestLocalManager_GoN(t *testing.T) {
+	fmt.Println("\n=== TestLocalManager_GoN ===")
+	resetGlobalState()
+
+	appMgr := App.NewAppManager("test-app")
+	if _, err := appMgr.CreateApp(); err != nil {
+		t.Fatalf("CreateApp() failed: %v", err)
+	}
+	localMgr := Local.NewLocalManager("test-app", "test-local")
+	if _, err := localMgr.CreateLocal("test-local"); err != nil {
+		t.Fatalf("CreateLocal() failed: %v", err)
+	}
+
+	var mu sync.Mutex
+	seen := make(map[int]int)
+	ids, err := localMgr.GoN(50, "indexed", func(ctx context.Context, index int) error {
+		mu.Lock()
+		seen[index]++
+		mu.Unlock()
+		return nil
+	}, Local.AddToWaitGroup("indexed"))
+	if err != nil {
+		t.Fatalf("GoN() failed: %v", err)
+	}
+	if len(ids) != 50 {
+		t.Fatalf("Expected 50 routine IDs, got %d", len(ids))
+	}
+	if err := localMgr.WaitForFunction("indexed"); err != nil {
+		t.Fatalf("WaitForFunction() failed: %v", err)
+	}
+	mu.Lock()
+	for i := 0; i < 50; i++ {
+		if seen[i] != 1 {
+			t.Errorf("Expected index %d to be observed once, got %d", i, seen[i])
+		}
+	}
+	mu.Unlock()
+	fmt.Println("✓ Observed every index 0..49 once")
+
+	// Over the routine limit part way, the spawned routines are cancelled and the local wait group still balances
+	if _, err := Global.NewGlobalManager().UpdateMetadata(Global.SET_MAX_ROUTINES, 3); err != nil {
+		t.Fatalf("UpdateMetadata() failed: %v", err)
+	}
+	var cancelled atomic.Int32
+	ids, err = localMgr.GoN(5, "batch", func(ctx context.Context, index int) error {
+		<-ctx.Done()
+		cancelled.Add(1)
+		return ctx.Err()
+	})
+	if !errors.Is(err, Errors.ErrMaxRoutinesExceeded) || ids != nil {
+		t.Fatalf("Expected ErrMaxRoutinesExceeded and no IDs, got %v, %v", ids, err)
+	}
+	if !strings.Contains(err.Error(), "spawn 4 of 5") {
+		t.Errorf("Expected the error to name the failed spawn, got %v", err)
+	}
+	if !localMgr.WaitForAllWithTimeout(time.Second) {
+		t.Fatal("Local wait group didn't drain after the failed batch")
+	}
+	if got := cancelled.Load(); got != 3 {
+		t.Errorf("Expected the 3 spawned routines to be cancelled, got %d", got)
+	}
+	fmt.Printf("✓ Failed batch cancelled its routines: %v\n", err)
+}
+
+func TestLocalManager_Rename(t *testing.T) {
+	resetGlobalState()
+
+	appMgr := App.NewAppManager("test-app")
+	if _, err := appMgr.CreateApp(); err != nil {
+		t.Fatalf("CreateApp() failed: %v", err)
+	}
+	oldMgr := Local.NewLocalManager("test-app", "old-local")
+	if _, err := oldMgr.CreateLocal("old-local"); err != nil {
+		t.Fatalf("CreateLocal() failed: %v", err)
+	}
+	if _, err := Local.NewLocalManager("test-app", "taken-local").CreateLocal("taken-local"); err != nil {
+		t.Fatalf("CreateLocal() failed: %v", err)
+	}
+
+	stopped := make(chan struct{})
+	err := oldMgr.Go("worker", func(ctx context.Context) error {
+		<-ctx.Done()
+		close(stopped)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Go() failed: %v", err)
+	}
+
+	if err := Local.Rename("test-app", "old-local", "taken-local"); !errors.Is(err, Errors.ErrLocalManagerExists) {
+		t.Fatalf("Expected ErrLocalManagerExists, got %v", err)
+	}
+	if err := Local.Rename("test-app", "missing", "other"); !errors.Is(err, Errors.ErrLocalManagerNotFound) {
+		t.Fatalf("Expected ErrLocalManagerNotFound, got %v", err)
+	}
+
+	if err := Local.Rename("test-app", "old-local", "new-local"); err != nil {
+		t.Fatalf("Rename() failed: %v", err)
+	}
+
+	if _, err := appMgr.GetLocalManagerByName("old-local"); err == nil {
+		t.Error("Lookup under the old name should fail")
+	}
+	local, err := appMgr.GetLocalManagerByName("new-local")
+	if err != nil {
+		t.Fatalf("Lookup under the new name failed: %v", err)
+	}
+	if local.GetLocalName() != "new-local" {
+		t.Errorf("Expected local name new-local, got %s", local.GetLocalName())
+	}
+
+	// The routine survived the rename and is reachable through the new name
+	newMgr := Local.NewLocalManager("test-app", "new-local")
+	if count := newMgr.GetGoroutineCount(); count != 1 {
+		t.Fatalf("Expected 1 routine under the new name, got %d", count)
+	}
+	select {
+	case <-stopped:
+		t.Fatal("Routine should still be running after the rename")
+	default:
+	}
+
+	if err := newMgr.Shutdown(false); err != nil {
+		t.Fatalf("Shutdown() failed: %v", err)
+	}
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("Routine should stop on shutdown through the new name")
+	}
+}
+
+func TestLocalManager_CompletedRoutineError(t *testing.T) {
+	fmt.Println("\n=== TestLocalManager_CompletedRoutineError ===")
+	resetGlobalState()
+
+	appMgr := App.NewAppManager("test-app")
+	if _, err := appMgr.CreateApp(); err != nil {
+		t.Fatalf("CreateApp() failed: %v", err)
+	}
+	localMgr := Local.NewLocalManager("test-app", "test-local")
+	if _, err := localMgr.CreateLocal("test-local"); err != nil {
+		t.Fatalf("CreateLocal() failed: %v", err)
+	}
+
+	if err := localMgr.Go("short-lived", func(ctx context.Context) error {
+		return nil
+	}); err != nil {
+		t.Fatalf("Go() failed: %v", err)
+	}
+	routines, err := localMgr.GetRoutinesByFunctionName("short-lived")
+	if err != nil || len(routines) != 1 {
+		t.Fatalf("Expected 1 routine, got %d (%v)", len(routines), err)
+	}
+	routineID := routines[0].GetID()
+
+	// The routine is untracked before its done channel closes
+	if !localMgr.WaitForRoutine(routineID, time.Second) {
+		t.Fatal("Routine did not complete")
+	}
+
+	_, err = localMgr.GetRoutine(routineID)
+	if !errors.Is(err, Errors.ErrRoutineCompleted) {
+		t.Fatalf("Expected ErrRoutineCompleted, got %v", err)
+	}
+	if !errors.Is(err, Errors.ErrRoutineNotFound) {
+		t.Errorf("ErrRoutineCompleted should wrap ErrRoutineNotFound, got %v", err)
+	}
+	if err := localMgr.CancelRoutine(routineID); !errors.Is(err, Errors.ErrRoutineCompleted) {
+		t.Errorf("Expected ErrRoutineCompleted from CancelRoutine, got %v", err)
+	}
+	if !localMgr.IsRoutineDone(routineID) {
+		t.Error("A retained completed routine should report done")
+	}
+	fmt.Println("✓ Completed routine reported as completed")
+
+	_, err = localMgr.GetRoutine("never-existed")
+	if !errors.Is(err, Errors.ErrRoutineNotFound) || errors.Is(err, Errors.ErrRoutineCompleted) {
+		t.Errorf("Expected plain ErrRoutineNotFound for an unknown routine, got %v", err)
+	}
+	fmt.Println("✓ Unknown routine reported as not found")
+}
+
+func TestLocalManager_Go_NotCreated(t *testing.T) {
+	fmt.Println("\n=== TestLocalManager_Go_NotCreated ===")
+	resetGlobalState()
+
+	worker := func(ctx context.Context) error { return nil }
+
+	// App never created
+	err := Local.NewLocalManager("missing-app", "missing-local").Go("worker", worker)
+	if !errors.Is(err, Errors.ErrAppManagerNotCreated) {
+		t.Errorf("Expected ErrAppManagerNotCreated, got %v", err)
+	}
+	if !errors.Is(err, Errors.ErrAppManagerNotFound) {
+		t.Errorf("ErrAppManagerNotCreated should wrap ErrAppManagerNotFound, got %v", err)
+	}
+	if err != nil && !strings.Contains(err.Error(), "CreateApp") {
+		t.Errorf("Expected the error to point at CreateApp, got %q", err)
+	}
+	fmt.Printf("✓ Missing app: %v\n", err)
+
+	// App created, local only named
+	if _, err := App.NewAppManager("test-app").CreateApp(); err != nil {
+		t.Fatalf("CreateApp() failed: %v", err)
+	}
+	err = Local.NewLocalManager("test-app", "named-only").Go("worker", worker)
+	if !errors.Is(err, Errors.ErrLocalManagerNotCreated) {
+		t.Errorf("Expected ErrLocalManagerNotCreated, got %v", err)
+	}
+	if errors.Is(err, Errors.ErrAppManagerNotCreated) {
+		t.Errorf("A missing local should not be reported as a missing app, got %v", err)
+	}
+	if err != nil && !strings.Contains(err.Error(), "CreateLocal") {
+		t.Errorf("Expected the error to point at CreateLocal, got %q", err)
+	}
+	fmt.Printf("✓ Missing local: %v\n", err)
+}
+
+func TestLocalManager_MemoryBudget(t *testing.T) {
+	fmt.Println("\n=== TestLocalManager_MemoryBudget ===")
+	resetGlobalState()
+
+	appMgr := App.NewAppManager("test-app")
+	if _, err := appMgr.CreateApp(); err != nil {
+		t.Fatalf("CreateApp() failed: %v", err)
+	}
+	localMgr := Local.NewLocalManager("test-app", "test-local")
+	if _, err := localMgr.CreateLocal("test-local"); err != nil {
+		t.Fatalf("CreateLocal() failed: %v", err)
+	}
+
+	if err := localMgr.SetMemoryBudget(-1); !errors.Is(err, Errors.ErrInvalidMemoryBudget) {
+		t.Errorf("Expected ErrInvalidMemoryBudget, got %v", err)
+	}
+	if err := localMgr.SetMemoryBudget(100); err != nil {
+		t.Fatalf("SetMemoryBudget() failed: %v", err)
+	}
+
+	release := make(chan struct{})
+	worker := func(ctx context.Context) error {
+		<-release
+		return nil
+	}
+	if err := localMgr.Go("heavy", worker, Local.WithMemoryEstimate(60)); err != nil {
+		t.Fatalf("Go() failed: %v", err)
+	}
+	if got := localMgr.GetMemoryInUse(); got != 60 {
+		t.Errorf("Expected 60 bytes in use, got %d", got)
+	}
+
+	// 60 + 60 goes over the budget of 100
+	err := localMgr.Go("heavy", worker, Local.WithMemoryEstimate(60))
+	if !errors.Is(err, Errors.ErrMemoryBudgetExceeded) {
+		t.Errorf("Expected ErrMemoryBudgetExceeded, got %v", err)
+	}
+	if got := localMgr.GetMemoryInUse(); got != 60 {
+		t.Errorf("A rejected spawn should not reserve memory, got %d", got)
+	}
+	fmt.Printf("✓ Rejected over budget: %v\n", err)
+
+	// Routines without an estimate are not counted
+	if err := localMgr.Go("light", func(ctx context.Context) error { return nil }); err != nil {
+		t.Errorf("Go() without an estimate failed: %v", err)
+	}
+
+	close(release)
+	deadline := time.Now().Add(time.Second)
+	for localMgr.GetMemoryInUse() != 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if got := localMgr.GetMemoryInUse(); got != 0 {
+		t.Fatalf("Expected the estimate to be released on completion, got %d", got)
+	}
+	if err := localMgr.Go("heavy", func(ctx context.Context) error { return nil }, Local.WithMemoryEstimate(60)); err != nil {
+		t.Errorf("Go() after release failed: %v", err)
+	}
+	localMgr.WaitForAll()
+	fmt.Println("✓ Estimate released on completion")
+}
+
+func TestLocalManager_MaxRoutines(t *testing.T) {
+	fmt.Println("\n=== TestLocalManager_MaxRoutines ===")
+	resetGlobalState()
+
+	appMgr := App.NewAppManager("test-app")
+	if _, err := appMgr.CreateApp(); err != nil {
+		t.Fatalf("CreateApp() failed: %v", err)
+	}
+	localMgr := Local.NewLocalManager("test-app", "test-local")
+	if _, err := localMgr.CreateLocal("test-local"); err != nil {
+		t.Fatalf("CreateLocal() failed: %v", err)
+	}
+	if _, err := Global.NewGlobalManager().UpdateMetadata(Global.SET_MAX_ROUTINES, 2); err != nil {
+		t.Fatalf("UpdateMetadata() failed: %v", err)
+	}
+
+	releaseFirst := make(chan struct{})
+	releaseRest := make(chan struct{})
+	defer stopWorkers(releaseRest, localMgr)
+	if err := localMgr.Go("first", func(ctx context.Context) error {
+		<-releaseFirst
+		return nil
+	}, Local.AddToWaitGroup("first")); err != nil {
+		t.Fatalf("Go() failed: %v", err)
+	}
+	if err := localMgr.Go("rest", func(ctx context.Context) error {
+		<-releaseRest
+		return nil
+	}); err != nil {
+		t.Fatalf("Go() failed: %v", err)
+	}
+
+	err := localMgr.Go("rest", func(ctx context.Context) error { return nil })
+	if !errors.Is(err, Errors.ErrMaxRoutinesExceeded) {
+		t.Fatalf("Expected ErrMaxRoutinesExceeded, got %v", err)
+	}
+	fmt.Printf("✓ Rejected over the limit: %v\n", err)
+
+	// Completing a routine frees its slot
+	close(releaseFirst)
+	if err := localMgr.WaitForFunction("first"); err != nil {
+		t.Fatalf("WaitForFunction() failed: %v", err)
+	}
+	if err := localMgr.Go("rest", func(ctx context.Context) error {
+		<-releaseRest
+		return nil
+	}); err != nil {
+		t.Errorf("Go() after a routine completed failed: %v", err)
+	}
+	fmt.Println("✓ Slot released on completion")
+}
+
+func TestLocalManager_Map(t *testing.T) {
+	fmt.Println("\n=== TestLocalManager_Map ===")
+	resetGlobalState()
+
+	appMgr := App.NewAppManager("test-app")
+	if _, err := appMgr.CreateApp(); err != nil {
+		t.Fatalf("CreateApp() failed: %v", err)
+	}
+	localMgr := Local.NewLocalManager("test-app", "test-local")
+	if _, err := localMgr.CreateLocal("test-local"); err != nil {
+		t.Fatalf("CreateLocal() failed: %v", err)
+	}
+
+	inputs := make([]interface{}, 10)
+	for i := range inputs {
+		inputs[i] = i
+	}
+	errSeven := errors.New("seven is not welcome")
+	var running, peak atomic.Int32
+	results, errs := localMgr.Map("square", inputs, func(ctx context.Context, in interface{}) (interface{}, error) {
+		n := in.(int)
+		current := running.Add(1)
+		defer running.Add(-1)
+		for {
+			old := peak.Load()
+			if current <= old || peak.CompareAndSwap(old, current) {
+				break
+			}
+		}
+		// Later inputs finish first, the order can't come from completion order
+		time.Sleep(time.Duration(10-n) * time.Millisecond)
+		if n == 7 {
+			return nil, errSeven
+		}
+		return n * n, nil
+	}, Local.WithMaxConcurrency("square", 3))
+
+	if len(results) != 10 || len(errs) != 10 {
+		t.Fatalf("Expected 10 results and errors, got %d and %d", len(results), len(errs))
+	}
+	for i := range inputs {
+		if i == 7 {
+			if !errors.Is(errs[i], errSeven) || results[i] != nil {
+				t.Errorf("Expected input 7 to fail with its error, got %v, %v", results[i], errs[i])
+			}
+			continue
+		}
+		if errs[i] != nil || results[i] != i*i {
+			t.Errorf("Expected %d for input %d, got %v, %v", i*i, i, results[i], errs[i])
+		}
+	}
+	if got := peak.Load(); got > 3 {
+		t.Errorf("Expected at most 3 inputs at once with WithMaxConcurrency, got %d", got)
+	}
+	fmt.Printf("✓ Squares in input order, error in slot 7, peak concurrency %d\n", peak.Load())
+}
+
+func TestLocalManager_SpawnQueue(t *testing.T) {
+	fmt.Println("\n=== TestLocalManager_SpawnQueue ===")
+	resetGlobalState()
+
+	gm := Global.NewGlobalManager()
+	appMgr := App.NewAppManager("test-app")
+	if _, err := appMgr.CreateApp(); err != nil {
+		t.Fatalf("CreateApp() failed: %v", err)
+	}
+	localMgr := Local.NewLocalManager("test-app", "test-local")
+	if _, err := localMgr.CreateLocal("test-local"); err != nil {
+		t.Fatalf("CreateLocal() failed: %v", err)
+	}
+	if _, err := gm.UpdateMetadata(Global.SET_MAX_ROUTINES, 1); err != nil {
+		t.Fatalf("UpdateMetadata() failed: %v", err)
+	}
+	if err := gm.SetSpawnQueueSize(3); err != nil {
+		t.Fatalf("SetSpawnQueueSize() failed: %v", err)
+	}
+
+	// Take the only slot so everything below is queued
+	release := make(chan struct{})
+	if err := localMgr.Go("blocker", func(ctx context.Context) error {
+		<-release
+		return nil
+	}); err != nil {
+		t.Fatalf("Go() failed: %v", err)
+	}
+
+	var mu sync.Mutex
+	var order []string
+	started := make(chan struct{}, 3)
+	record := func(name string) func(ctx context.Context) error {
+		return func(ctx context.Context) error {
+			mu.Lock()
+			order = append(order, name)
+			mu.Unlock()
+			started <- struct{}{}
+			return nil
+		}
+	}
+	for _, spawn := range []struct {
+		name     string
+		priority int
+	}{{"low", 0}, {"high", 10}, {"mid", 5}} {
+		if err := localMgr.Go(spawn.name, record(spawn.name), Local.WithPriority(spawn.priority)); err != nil {
+			t.Fatalf("Go(%s) failed: %v", spawn.name, err)
+		}
+	}
+	if got := gm.GetSpawnQueueLength(); got != 3 {
+		t.Fatalf("Expected 3 queued spawns, got %d", got)
+	}
+	err := localMgr.Go("overflow", record("overflow"))
+	if !errors.Is(err, Errors.ErrSpawnQueueFull) || !errors.Is(err, Errors.ErrMaxRoutinesExceeded) {
+		t.Fatalf("Expected ErrSpawnQueueFull, got %v", err)
+	}
+	fmt.Printf("✓ Queued 3 spawns, rejected the 4th: %v\n", err)
+
+	// Slots free up one at a time, the queue hands them out by priority
+	close(release)
+	for i := 0; i < 3; i++ {
+		select {
+		case <-started:
+		case <-time.After(time.Second):
+			t.Fatalf("Only %d queued spawns started", i)
+		}
+	}
+	mu.Lock()
+	got := strings.Join(order, ",")
+	mu.Unlock()
+	if got != "high,mid,low" {
+		t.Errorf("Expected queued spawns to start as high,mid,low, got %s", got)
+	}
+	fmt.Printf("✓ Started by priority: %s\n", got)
+
+	// Shutdown drops what is still queued and stops the dispatcher
+	if err := localMgr.Go("holder", func(ctx context.Context) error {
+		<-ctx.Done()
+		return nil
+	}); err != nil {
+		t.Fatalf("Go() failed: %v", err)
+	}
+	dropped := atomic.Bool{}
+	if err := localMgr.Go("dropped", func(ctx context.Context) error {
+		dropped.Store(true)
+		return nil
+	}); err != nil {
+		t.Fatalf("Go() failed: %v", err)
+	}
+	if err := gm.Shutdown(false); err != nil {
+		t.Fatalf("Shutdown() failed: %v", err)
+	}
+	if got := gm.GetSpawnQueueLength(); got != 0 {
+		t.Errorf("Expected an empty queue after shutdown, got %d", got)
+	}
+	if err := gm.SetSpawnQueueSize(3); !errors.Is(err, Errors.ErrSpawnQueueClosed) {
+		t.Errorf("Expected ErrSpawnQueueClosed after shutdown, got %v", err)
+	}
+	time.Sleep(20 * time.Millisecond)
+	if dropped.Load() {
+		t.Error("Expected the queued spawn to be dropped by shutdown")
+	}
+	fmt.Println("✓ Shutdown dropped the queued spawn")
+}
+
+func TestLocalManager_GoWithResult(t *testing.T) {
+	fmt.Println("\n=== TestLocalManager_GoWithResult ===")
+	resetGlobalState()
+
+	appMgr := App.NewAppManager("test-app")
+	if _, err := appMgr.CreateApp(); err != nil {
+		t.Fatalf("CreateApp() failed: %v", err)
+	}
+	localMgr := Local.NewLocalManager("test-app", "test-local")
+	if _, err := localMgr.CreateLocal("test-local"); err != nil {
+		t.Fatalf("CreateLocal() failed: %v", err)
+	}
+
+	release := make(chan struct{})
+	routineID, err := localMgr.GoWithResult("sum", func(ctx context.Context) (interface{}, error) {
+		<-release
+		sum := 0
+		for i := 1; i <= 10; i++ {
+			sum += i
+		}
+		return sum, nil
+	})
+	if err != nil {
+		t.Fatalf("GoWithResult() failed: %v", err)
+	}
+	if _, _, done := localMgr.GetRoutineResult(routineID); done {
+		t.Error("Expected no result while the worker is running")
+	}
+
+	close(release)
+	if !localMgr.WaitForRoutine(routineID, time.Second) {
+		t.Fatal("WaitForRoutine() timed out")
+	}
+	value, err, done := localMgr.GetRoutineResult(routineID)
+	if !done || err != nil || value != 55 {
+		t.Errorf("Expected (55, nil, true), got (%v, %v, %v)", value, err, done)
+	}
+	fmt.Printf("✓ Result read back: %v\n", value)
+
+	// The worker's error is kept as well
+	errBoom := errors.New("boom")
+	routineID, err = localMgr.GoWithResult("fails", func(ctx context.Context) (interface{}, error) {
+		return nil, errBoom
+	})
+	if err != nil {
+		t.Fatalf("GoWithResult() failed: %v", err)
+	}
+	if !localMgr.WaitForRoutine(routineID, time.Second) {
+		t.Fatal("WaitForRoutine() timed out")
+	}
+	if _, err, done := localMgr.GetRoutineResult(routineID); !done || !errors.Is(err, errBoom) {
+		t.Errorf("Expected the worker error, got (%v, %v)", err, done)
+	}
+
+	// A recovered panic is reported as ErrWorkerPanicked
+	routineID, err = localMgr.GoWithResult("panics", func(ctx context.Context) (interface{}, error) {
+		panic("bad input")
+	})
+	if err != nil {
+		t.Fatalf("GoWithResult() failed: %v", err)
+	}
+	if !localMgr.WaitForRoutine(routineID, time.Second) {
+		t.Fatal("WaitForRoutine() timed out")
+	}
+	if _, err, done := localMgr.GetRoutineResult(routineID); !done || !errors.Is(err, Errors.ErrWorkerPanicked) {
+		t.Errorf("Expected ErrWorkerPanicked, got (%v, %v)", err, done)
+	}
+	fmt.Println("✓ Errors and panics kept")
+
+	if _, err, done := localMgr.GetRoutineResult("missing"); done || !errors.Is(err, Errors.ErrRoutineNotFound) {
+		t.Errorf("Expected ErrRoutineNotFound, got (%v, %v)", err, done)
+	}
+}
+
+func TestLocalManager_ContextIgnoringRoutines(t *testing.T) {
+	fmt.Println("\n=== TestLocalManager_ContextIgnoringRoutines ===")
+	resetGlobalState()
+	types.DetectIgnoredContexts = true
+	defer func() { types.DetectIgnoredContexts = false }()
+
+	appMgr := App.NewAppManager("test-app")
+	if _, err := appMgr.CreateApp(); err != nil {
+		t.Fatalf("CreateApp() failed: %v", err)
+	}
+	localMgr := Local.NewLocalManager("test-app", "test-local")
+	if _, err := localMgr.CreateLocal("test-local"); err != nil {
+		t.Fatalf("CreateLocal() failed: %v", err)
+	}
+
+	release := make(chan struct{})
+	defer stopWorkers(release, localMgr)
+	started := make(chan struct{}, 2)
+	// Blocks without ever looking at ctx
+	if err := localMgr.Go("ignorer", func(ctx context.Context) error {
+		started <- struct{}{}
+		<-release
+		return nil
+	}); err != nil {
+		t.Fatalf("Go() failed: %v", err)
+	}
+	// Selects on ctx.Done()
+	if err := localMgr.Go("listener", func(ctx context.Context) error {
+		started <- struct{}{}
+		select {
+		case <-ctx.Done():
+		case <-release:
+		}
+		return nil
+	}); err != nil {
+		t.Fatalf("Go() failed: %v", err)
+	}
+	<-started
+	<-started
+
+	if err := localMgr.CancelContext(); err != nil {
+		t.Fatalf("CancelContext() failed: %v", err)
+	}
+
+	// The check runs asynchronously once the contexts are cancelled
+	var flagged []*types.Routine
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		routines, err := localMgr.GetContextIgnoringRoutines()
+		if err != nil {
+			t.Fatalf("GetContextIgnoringRoutines() failed: %v", err)
+		}
+		if flagged = routines; len(flagged) > 0 {
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if len(flagged) != 1 || flagged[0].GetFunctionName() != "ignorer" {
+		t.Fatalf("Expected only the ignorer to be flagged, got %d routines", len(flagged))
+	}
+	fmt.Printf("✓ Flagged %s as ignoring its context\n", flagged[0].GetID())
+}
+
+func TestLocalManager_ReparentRoutines(t *testing.T) {
+	fmt.Println("\n=== TestLocalManager_ReparentRoutines ===")
+	resetGlobalState()
+
+	if _, err := App.NewAppManager("test-app").CreateApp(); err != nil {
+		t.Fatalf("CreateApp() failed: %v", err)
+	}
+	localMgr := Local.NewLocalManager("test-app", "test-local")
+	_, localCtx, err := localMgr.CreateLocalWithContext("test-local")
+	if err != nil {
+		t.Fatalf("CreateLocalWithContext() failed: %v", err)
+	}
+
+	if err := localMgr.ReparentRoutines(nil); !errors.Is(err, Errors.ErrNilParentContext) {
+		t.Errorf("Expected ErrNilParentContext, got %v", err)
+	}
+
+	const workers = 3
+	var cancelled atomic.Int32
+	for i := 0; i < workers; i++ {
+		if err := localMgr.Go("long-lived", func(ctx context.Context) error {
+			<-ctx.Done()
+			cancelled.Add(1)
+			return nil
+		}, Local.AddToWaitGroup("long-lived")); err != nil {
+			t.Fatalf("Go() failed: %v", err)
+		}
+	}
+	if err := localMgr.WaitForFunctionStarted("long-lived", workers); err != nil {
+		t.Fatalf("WaitForFunctionStarted() failed: %v", err)
+	}
+
+	newParent, cancelNew := context.WithCancel(context.Background())
+	defer cancelNew()
+	if err := localMgr.ReparentRoutines(newParent); err != nil {
+		t.Fatalf("ReparentRoutines() failed: %v", err)
+	}
+
+	// Cancelling the old context tree no longer reaches the routines
+	Context.GetGlobalContext().Shutdown()
+	select {
+	case <-localCtx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("Expected the local context to be cancelled")
+	}
+	if localMgr.WaitForFunctionWithTimeout("long-lived", 50*time.Millisecond) {
+		t.Fatalf("Routines should keep running after the old parent is cancelled, %d stopped", cancelled.Load())
+	}
+	fmt.Println("✓ Routines survive cancellation of the old parent")
+
+	// The new parent cancels them
+	cancelNew()
+	if !localMgr.WaitForFunctionWithTimeout("long-lived", time.Second) {
+		t.Fatal("Routines should stop once the new parent is cancelled")
+	}
+	if got := cancelled.Load(); got != workers {
+		t.Errorf("Expected %d cancelled routines, got %d", workers, got)
+	}
+	fmt.Println("✓ Cancellation follows the new parent")
+}
+
+func TestLocalManager_GoWithContext(t *testing.T) {
+	fmt.Println("\n=== TestLocalManager_GoWithContext ===")
+	resetGlobalState()
+
+	if _, err := App.NewAppManager("test-app").CreateApp(); err != nil {
+		t.Fatalf("CreateApp() failed: %v", err)
+	}
+	localMgr := Local.NewLocalManager("test-app", "test-local")
+	if _, err := localMgr.CreateLocal("test-local"); err != nil {
+		t.Fatalf("CreateLocal() failed: %v", err)
+	}
+
+	if err := localMgr.GoWithContext(nil, "worker", func(ctx context.Context) error { return nil }); !errors.Is(err, Errors.ErrNilParentContext) {
+		t.Errorf("Expected ErrNilParentContext, got %v", err)
+	}
+
+	type requestIDKey struct{}
+	parent := context.WithValue(context.Background(), requestIDKey{}, "req-42")
+
+	// The worker reads the caller's values and is still stopped by the local shutdown
+	seen := make(chan interface{}, 1)
+	if err := localMgr.GoWithContext(parent, "handler", func(ctx context.Context) error {
+		seen <- ctx.Value(requestIDKey{})
+		<-ctx.Done()
+		return nil
+	}, Local.AddToWaitGroup("handler")); err != nil {
+		t.Fatalf("GoWithContext() failed: %v", err)
+	}
+	if got := <-seen; got != "req-42" {
+		t.Errorf("Expected the worker to read req-42, got %v", got)
+	}
+	if count := localMgr.GetGoroutineCount(); count != 1 {
+		t.Errorf("Expected 1 tracked goroutine, got %d", count)
+	}
+	fmt.Println("✓ Worker read the parent's value")
+
+	// Cancelling the parent cancels the routine
+	cancellable, cancelParent := context.WithCancel(parent)
+	if err := localMgr.GoWithContext(cancellable, "request-scoped", func(ctx context.Context) error {
+		<-ctx.Done()
+		return nil
+	}, Local.AddToWaitGroup("request-scoped")); err != nil {
+		t.Fatalf("GoWithContext() failed: %v", err)
+	}
+	cancelParent()
+	if !localMgr.WaitForFunctionWithTimeout("request-scoped", time.Second) {
+		t.Fatal("Routine not cancelled with its parent")
+	}
+	fmt.Println("✓ Parent cancellation stopped the routine")
+
+	if err := localMgr.Shutdown(true); err != nil {
+		t.Fatalf("Shutdown() failed: %v", err)
+	}
+	if !localMgr.WaitForFunctionWithTimeout("handler", time.Second) {
+		t.Fatal("Shutdown didn't stop the routine")
+	}
+	if count := localMgr.GetGoroutineCount(); count != 0 {
+		t.Errorf("Expected 0 goroutines after shutdown, got %d", count)
+	}
+	fmt.Println("✓ Local shutdown stopped the routine")
+}
+
+func TestLocalManager_ConcurrentCreateAndGet(t *testing.T) {
+	fmt.Println("\n=== TestLocalManager_ConcurrentCreateAndGet ===")
+	resetGlobalState()
+
+	if _, err := App.NewAppManager("test-app").CreateApp(); err != nil {
+		t.Fatalf("CreateApp() failed: %v", err)
+	}
+
+	const creators, readers = 8, 8
+	start := make(chan struct{})
+	created := make(chan *types.LocalManager, creators)
+	var wg sync.WaitGroup
+	var halfBuilt atomic.Int32
+	for i := 0; i < creators; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			localManager, err := Local.NewLocalManager("test-app", "shared").CreateLocal("shared")
+			if err != nil {
+				t.Errorf("CreateLocal() failed: %v", err)
+				return
+			}
+			created <- localManager
+		}()
+	}
+	for i := 0; i < readers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			// Keep reading until the local shows up, then check it is complete
+			for {
+				localManager, err := types.GetLocalManager("test-app", "shared")
+				if err != nil {
+					runtime.Gosched()
+					continue
+				}
+				ctx, _ := localManager.GetLocalContext()
+				if ctx == nil || localManager.Wg == nil {
+					halfBuilt.Add(1)
+				}
+				return
+			}
+		}()
+	}
+	close(start)
+	wg.Wait()
+	close(created)
+
+	if n := halfBuilt.Load(); n != 0 {
+		t.Fatalf("Readers saw %d half built local managers", n)
+	}
+	var first *types.LocalManager
+	for localManager := range created {
+		if first == nil {
+			first = localManager
+		} else if localManager != first {
+			t.Fatal("Concurrent CreateLocal calls returned different local managers")
+		}
+	}
+	if published, _ := types.GetLocalManager("test-app", "shared"); published != first {
+		t.Error("CreateLocal returned a local manager that isn't the published one")
+	}
+	fmt.Println("✓ Readers only saw a fully built local manager, creators all got the published one")
+
+	// The published manager is usable right away
+	localMgr := Local.NewLocalManager("test-app", "shared")
+	if err := localMgr.Go("worker", func(ctx context.Context) error { return nil }, Local.AddToWaitGroup("worker")); err != nil {
+		t.Fatalf("Go() failed: %v", err)
+	}
+	if err := localMgr.WaitForFunction("worker"); err != nil {
+		t.Fatalf("WaitForFunction() failed: %v", err)
+	}
+}
+
+func TestLocalManager_Drain(t *testing.T) {
+	fmt.Println("\n=== TestLocalManager_Drain ===")
+	resetGlobalState()
+
+	gm := Global.NewGlobalManager()
+	if _, err := gm.Init(); err != nil {
+		t.Fatalf("Init() failed: %v", err)
+	}
+	appMgr := App.NewAppManager("test-app")
+	if _, err := appMgr.CreateApp(); err != nil {
+		t.Fatalf("CreateApp() failed: %v", err)
+	}
+	newLocal := func(appName, localName string) Interface.LocalGoroutineManagerInterface {
+		localMgr := Local.NewLocalManager(appName, localName)
+		if _, err := localMgr.CreateLocal(localName); err != nil {
+			t.Fatalf("CreateLocal() failed: %v", err)
+		}
+		return localMgr
+	}
+	localMgr := newLocal("test-app", "test-local")
+
+	// A slow worker already in flight when the drain starts
+	var completed atomic.Bool
+	if err := localMgr.Go("slow", func(ctx context.Context) error {
+		time.Sleep(100 * time.Millisecond)
+		completed.Store(true)
+		return nil
+	}); err != nil {
+		t.Fatalf("Go() failed: %v", err)
+	}
+
+	if localMgr.IsDraining() {
+		t.Error("IsDraining() true before Drain()")
+	}
+	if err := localMgr.Drain(); err != nil {
+		t.Fatalf("Drain() failed: %v", err)
+	}
+	if !localMgr.IsDraining() {
+		t.Error("IsDraining() false after Drain()")
+	}
+	err := localMgr.Go("late", func(ctx context.Context) error { return nil })
+	if !errors.Is(err, Errors.ErrDraining) {
+		t.Errorf("Expected ErrDraining for a spawn after Drain(), got %v", err)
+	}
+	fmt.Println("✓ Spawns rejected after Drain()")
+
+	if !localMgr.WaitForAllWithTimeout(time.Second) {
+		t.Fatal("In-flight worker didn't finish after Drain()")
+	}
+	if !completed.Load() {
+		t.Error("In-flight worker was cut short by Drain()")
+	}
+	fmt.Println("✓ In-flight worker completed while draining")
+
+	// The app and global levels drain every descendant
+	otherLocal := newLocal("test-app", "other-local")
+	if _, err := App.NewAppManager("other-app").CreateApp(); err != nil {
+		t.Fatalf("CreateApp() failed: %v", err)
+	}
+	otherAppLocal := newLocal("other-app", "other-app-local")
+
+	if err := appMgr.Drain(); err != nil {
+		t.Fatalf("App Drain() failed: %v", err)
+	}
+	if !otherLocal.IsDraining() {
+		t.Error("App Drain() didn't drain its local managers")
+	}
+	if otherAppLocal.IsDraining() {
+		t.Error("App Drain() drained a local manager of another app")
+	}
+	if err := gm.Drain(); err != nil {
+		t.Fatalf("Global Drain() failed: %v", err)
+	}
+	if err := otherAppLocal.Go("late", func(ctx context.Context) error { return nil }); !errors.Is(err, Errors.ErrDraining) {
+		t.Errorf("Expected ErrDraining after the global Drain(), got %v", err)
+	}
+	fmt.Println("✓ App and global Drain() reach every descendant")
+
+	if err := App.NewAppManager("missing-app").Drain(); !errors.Is(err, Errors.ErrAppManagerNotFound) {
+		t.Errorf("Expected ErrAppManagerNotFound, got %v", err)
+	}
+}
+
+func TestLocalManager_TryGo(t *testing.T) {
+	fmt.Println("\n=== TestLocalManager_TryGo ===")
+	resetGlobalState()
+
+	if _, err := App.NewAppManager("test-app").CreateApp(); err != nil {
+		t.Fatalf("CreateApp() failed: %v", err)
+	}
+	localMgr := Local.NewLocalManager("test-app", "test-local")
+	if _, err := localMgr.CreateLocal("test-local"); err != nil {
+		t.Fatalf("CreateLocal() failed: %v", err)
+	}
+	gm := Global.NewGlobalManager()
+
+	// A saturated concurrency limit refuses without blocking or spawning
+	limit := Local.WithMaxConcurrency("refresh", 1)
+	release := make(chan struct{})
+	started, err := localMgr.TryGo("refresh", func(ctx context.Context) error {
+		<-release
+		return nil
+	}, limit, Local.AddToWaitGroup("refresh"))
+	if err != nil || !started {
+		t.Fatalf("Expected the first TryGo() to start, got %v, %v", started, err)
+	}
+	start := time.Now()
+	started, err = localMgr.TryGo("refresh", func(ctx context.Context) error {
+		t.Error("Worker ran over the concurrency limit")
+		return nil
+	}, limit)
+	if err != nil || started {
+		t.Fatalf("Expected TryGo() to refuse while saturated, got %v, %v", started, err)
+	}
+	if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+		t.Errorf("Expected TryGo() to return right away, took %v", elapsed)
+	}
+	if got := localMgr.GetFunctionGoroutineCount("refresh"); got != 1 {
+		t.Errorf("Expected the refused TryGo() not to spawn, got %d routines", got)
+	}
+	fmt.Println("✓ Refused while the concurrency limit is saturated")
+
+	// Once the slot frees up TryGo starts again
+	close(release)
+	if err := localMgr.WaitForFunction("refresh"); err != nil {
+		t.Fatalf("WaitForFunction() failed: %v", err)
+	}
+	ran := make(chan struct{})
+	started, err = localMgr.TryGo("refresh", func(ctx context.Context) error {
+		close(ran)
+		return nil
+	}, limit)
+	if err != nil || !started {
+		t.Fatalf("Expected TryGo() to start after the slot freed up, got %v, %v", started, err)
+	}
+	select {
+	case <-ran:
+	case <-time.After(time.Second):
+		t.Fatal("Worker started by TryGo() didn't run")
+	}
+	fmt.Println("✓ Started again once the slot freed up")
+
+	// A rate limit without a token refuses instead of waiting for one
+	rate := Local.WithRateLimit("email", 0.1, 1)
+	noop := func(ctx context.Context) error { return nil }
+	if started, err := localMgr.TryGo("email", noop, rate); err != nil || !started {
+		t.Fatalf("Expected the burst token to start the spawn, got %v, %v", started, err)
+	}
+	if started, err := localMgr.TryGo("email", noop, rate); err != nil || started {
+		t.Errorf("Expected TryGo() to refuse without a token, got %v, %v", started, err)
+	}
+	fmt.Println("✓ Refused without a rate limit token")
+
+	// A refusal by a later limiter gives the rate limit token back
+	rate = Local.WithRateLimit("sync", 0.1, 1)
+	syncLimit := Local.WithMaxConcurrency("sync", 1)
+	releaseSync := make(chan struct{})
+	if err := localMgr.Go("sync", func(ctx context.Context) error {
+		<-releaseSync
+		return nil
+	}, syncLimit, Local.AddToWaitGroup("sync")); err != nil {
+		t.Fatalf("Go() failed: %v", err)
+	}
+	if started, err := localMgr.TryGo("sync", noop, rate, syncLimit); err != nil || started {
+		t.Fatalf("Expected TryGo() to refuse while the concurrency limit is saturated, got %v, %v", started, err)
+	}
+	close(releaseSync)
+	if err := localMgr.WaitForFunction("sync"); err != nil {
+		t.Fatalf("WaitForFunction() failed: %v", err)
+	}
+	if started, err := localMgr.TryGo("sync", noop, rate, syncLimit); err != nil || !started {
+		t.Errorf("Expected the token of the refused TryGo() to be given back, got %v, %v", started, err)
+	}
+	fmt.Println("✓ Rate limit token given back when a later limiter refused")
+
+	// A full global semaphore refuses and gives back the concurrency slot it took
+	if err := gm.NewGlobalSemaphore("db", 1); err != nil {
+		t.Fatalf("NewGlobalSemaphore() failed: %v", err)
+	}
+	releaseQuery := make(chan struct{})
+	if started, err := localMgr.TryGo("query", func(ctx context.Context) error {
+		<-releaseQuery
+		return nil
+	}, Local.WithGlobalSemaphore("db"), Local.AddToWaitGroup("query")); err != nil || !started {
+		t.Fatalf("Expected the first query to start, got %v, %v", started, err)
+	}
+	if started, err := localMgr.TryGo("report", noop, Local.WithGlobalSemaphore("db"), Local.WithMaxConcurrency("report", 1)); err != nil || started {
+		t.Errorf("Expected TryGo() to refuse with the semaphore full, got %v, %v", started, err)
+	}
+	close(releaseQuery)
+	if err := localMgr.WaitForFunction("query"); err != nil {
+		t.Fatalf("WaitForFunction() failed: %v", err)
+	}
+	if started, err := localMgr.TryGo("report", noop, Local.WithGlobalSemaphore("db"), Local.WithMaxConcurrency("report", 1)); err != nil || !started {
+		t.Errorf("Expected TryGo() to start once the semaphore freed up, got %v, %v", started, err)
+	}
+	fmt.Println("✓ Refused while the global semaphore is full")
+
+	// SET_MAX_ROUTINES refuses instead of queueing
+	if err := gm.SetSpawnQueueSize(10); err != nil {
+		t.Fatalf("SetSpawnQueueSize() failed: %v", err)
+	}
+	deadline := time.Now().Add(time.Second)
+	for localMgr.GetGoroutineCount() != 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if _, err := gm.UpdateMetadata(Global.SET_MAX_ROUTINES, 1); err != nil {
+		t.Fatalf("UpdateMetadata() failed: %v", err)
+	}
+	releaseHeld := make(chan struct{})
+	defer stopWorkers(releaseHeld, localMgr)
+	if started, err := localMgr.TryGo("held", func(ctx context.Context) error {
+		<-releaseHeld
+		return nil
+	}); err != nil || !started {
+		t.Fatalf("Expected the first routine to start, got %v, %v", started, err)
+	}
+	if started, err := localMgr.TryGo("held", noop); err != nil || started {
+		t.Errorf("Expected TryGo() to refuse at SET_MAX_ROUTINES, got %v, %v", started, err)
+	}
+	fmt.Println("✓ Refused at SET_MAX_ROUTINES instead of queueing")
+
+	// Other failures are still returned as errors
+	missing := Local.NewLocalManager("test-app", "missing-local")
+	if started, err := missing.TryGo("work", noop); err == nil || started {
+		t.Errorf("Expected an error for an uncreated local manager, got %v, %v", started, err)
+	}
+}
+
+func TestLocalManager_ShutdownIsolatedAcrossApps(t *testing.T) {
+	fmt.Println("\n=== TestLocalManager_ShutdownIsolatedAcrossApps ===")
+	resetGlobalState()
+
+	// Both apps have a local named "worker"
+	locals := make(map[string]Interface.LocalGoroutineManagerInterface)
+	for _, appName := range []string{"isolated-a", "isolated-b"} {
+		if _, err := App.NewAppManager(appName).CreateApp(); err != nil {
+			t.Fatalf("CreateApp() failed: %v", err)
+		}
+		localMgr := Local.NewLocalManager(appName, "worker")
+		if _, err := localMgr.CreateLocal("worker"); err != nil {
+			t.Fatalf("CreateLocal() failed: %v", err)
+		}
+		locals[appName] = localMgr
+	}
+
+	stopped := make(chan struct{})
+	if err := locals["isolated-b"].Go("loop", func(ctx context.Context) error {
+		<-ctx.Done()
+		close(stopped)
+		return nil
+	}); err != nil {
+		t.Fatalf("Go() failed: %v", err)
+	}
+	if err := locals["isolated-a"].Go("loop", func(ctx context.Context) error {
+		<-ctx.Done()
+		return nil
+	}); err != nil {
+		t.Fatalf("Go() failed: %v", err)
+	}
+
+	if err := locals["isolated-a"].Shutdown(false); err != nil {
+		t.Fatalf("Shutdown() failed: %v", err)
+	}
+	select {
+	case <-stopped:
+		t.Fatal("Shutting down isolated-a/worker cancelled the routine of isolated-b/worker")
+	case <-time.After(100 * time.Millisecond):
+	}
+	if got := locals["isolated-b"].GetGoroutineCount(); got != 1 {
+		t.Errorf("Expected isolated-b/worker to keep its routine, got %d", got)
+	}
+	fmt.Println("✓ Same-named local of another app kept running")
+
+	if err := locals["isolated-b"].Shutdown(false); err != nil {
+		t.Fatalf("Shutdown() failed: %v", err)
+	}
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("Routine of isolated-b/worker wasn't cancelled by its own shutdown")
+	}
+}
+
+func TestLocalManager_AppContextReferences(t *testing.T) {
+	fmt.Println("\n=== TestLocalManager_AppContextReferences ===")
+	resetGlobalState()
+
+	appMgr := App.NewAppManager("refs-app")
+	if _, err := appMgr.CreateApp(); err != nil {
+		t.Fatalf("CreateApp() failed: %v", err)
+	}
+	appCtx := Context.GetRefCountedAppContext(types.Prefix_AppManager + "refs-app")
+
+	// Both locals derive their contexts from the app's and hold a reference on it
+	locals := make(map[string]Interface.LocalGoroutineManagerInterface)
+	for _, name := range []string{"refs-first", "refs-second"} {
+		localMgr := Local.NewLocalManager("refs-app", name)
+		if _, err := localMgr.CreateLocal(name); err != nil {
+			t.Fatalf("CreateLocal() failed: %v", err)
+		}
+		// Creating an existing local doesn't take another reference
+		if _, err := localMgr.CreateLocal(name); err != nil {
+			t.Fatalf("CreateLocal() failed: %v", err)
+		}
+		locals[name] = localMgr
+	}
+	if got := appCtx.Refs(); got != 3 {
+		t.Fatalf("Expected references of the app and its 2 locals, got %d", got)
+	}
+
+	stopped := make(chan struct{})
+	if err := locals["refs-second"].Go("loop", func(ctx context.Context) error {
+		<-ctx.Done()
+		close(stopped)
+		return nil
+	}); err != nil {
+		t.Fatalf("Go() failed: %v", err)
+	}
+	expectRunning := func(after string) {
+		t.Helper()
+		select {
+		case <-stopped:
+			t.Fatalf("%s cancelled the routine of the sibling local", after)
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+
+	if err := locals["refs-first"].Shutdown(false); err != nil {
+		t.Fatalf("Shutdown() failed: %v", err)
+	}
+	if got := appCtx.Refs(); got != 2 {
+		t.Errorf("Expected the shut down local to release its reference, got %d", got)
+	}
+	// A plain shutdown of the shared context is refused while the references are held
+	appCtx.Shutdown()
+	expectRunning("Shutting down a sibling")
+	if got := locals["refs-second"].GetGoroutineCount(); got != 1 {
+		t.Errorf("Expected the sibling to keep its routine, got %d", got)
+	}
+	fmt.Println("✓ Sibling kept running after one local shut down")
+
+	// The app ends its context regardless of the locals' references
+	if err := appMgr.Shutdown(false); err != nil {
+		t.Fatalf("App Shutdown() failed: %v", err)
+	}
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("App Shutdown() didn't cancel the routine")
+	}
+	if got := appCtx.Refs(); got != 0 {
+		t.Errorf("Expected no references after the app shut down, got %d", got)
+	}
+	fmt.Println("✓ App shutdown ended the shared context")
+}
@@ -1,10 +1,15 @@
 package Managertests
 
 import (
+	"errors"
 	"testing"
 	"time"
 
+	"github.com/neerajchowdary889/GoRoutinesManager/Context"
+	"github.com/neerajchowdary889/GoRoutinesManager/Manager/App"
+	"github.com/neerajchowdary889/GoRoutinesManager/Manager/Errors"
 	"github.com/neerajchowdary889/GoRoutinesManager/Manager/Global"
+	"github.com/neerajchowdary889/GoRoutinesManager/Manager/Local"
 	"github.com/neerajchowdary889/GoRoutinesManager/Tests/Common"
 	"github.com/neerajchowdary889/GoRoutinesManager/metrics"
 	"github.com/neerajchowdary889/GoRoutinesManager/types"
@@ -415,6 +420,56 @@ func TestGlobalManager_UpdateMetadata_InvalidTypes(t *testing.T) {
 	}
 }
 
+// TestGlobalManager_UpdateMetadata_InvalidValues tests that out of range values are rejected and leave the metadata unchanged
+func TestGlobalManager_UpdateMetadata_InvalidValues(t *testing.T) {
+	Common.ResetGlobalState()
+
+	gm := Global.NewGlobalManager()
+	gm.Init()
+	before, _ := gm.GetMetadata()
+	shutdownTimeout := before.GetShutdownTimeout()
+	updateInterval := before.GetUpdateInterval()
+	maxRoutines := before.GetMaxRoutines()
+
+	negative := -5 * time.Second
+	tests := []struct {
+		name  string
+		flag  string
+		value interface{}
+	}{
+		{"zero shutdown timeout", Global.SET_SHUTDOWN_TIMEOUT, time.Duration(0)},
+		{"negative shutdown timeout", Global.SET_SHUTDOWN_TIMEOUT, &negative},
+		{"negative max routines", Global.SET_MAX_ROUTINES, -1},
+		{"negative max apps", Global.SET_MAX_APPS, int64(-2)},
+		{"negative max locals per app", Global.SET_MAX_LOCALS_PER_APP, int32(-3)},
+		{"zero update interval", Global.SET_UPDATE_INTERVAL, time.Duration(0)},
+		{"sub-millisecond update interval", Global.SET_UPDATE_INTERVAL, 100 * time.Microsecond},
+		{"sub-millisecond metrics interval", Global.SET_METRICS_URL, []interface{}{true, "", time.Microsecond}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := gm.UpdateMetadata(tt.flag, tt.value); !errors.Is(err, Errors.ErrInvalidMetadata) {
+				t.Errorf("Expected ErrInvalidMetadata, got %v", err)
+			}
+		})
+	}
+
+	after, _ := gm.GetMetadata()
+	if after.GetShutdownTimeout() != shutdownTimeout || after.GetUpdateInterval() != updateInterval || after.GetMaxRoutines() != maxRoutines {
+		t.Errorf("Rejected updates should not change the metadata, got timeout %v, interval %v, max routines %d",
+			after.GetShutdownTimeout(), after.GetUpdateInterval(), after.GetMaxRoutines())
+	}
+
+	// 0 still means unlimited for the limits
+	if _, err := gm.UpdateMetadata(Global.SET_MAX_ROUTINES, 0); err != nil {
+		t.Errorf("Expected 0 max routines to be accepted, got %v", err)
+	}
+	if _, err := gm.UpdateMetadata(Global.SET_UPDATE_INTERVAL, Global.MinUpdateInterval); err != nil {
+		t.Errorf("Expected the minimum update interval to be accepted, got %v", err)
+	}
+	gm.UpdateMetadata(Global.SET_UPDATE_INTERVAL, updateInterval)
+}
+
 // TestGlobalManager_GetMetadata tests getting metadata via GlobalManager
 func TestGlobalManager_GetMetadata(t *testing.T) {
 	Common.ResetGlobalState()
@@ -556,3 +611,110 @@ func TestGlobalManager_MetricsServer_Integration(t *testing.T) {
 		t.Error("Metrics server should be stopped after disabling")
 	}
 }
+
+// TestGlobalManager_UpdateMetadata_SetMaxApps tests that CreateApp respects the app cap
+func TestGlobalManager_UpdateMetadata_SetMaxApps(t *testing.T) {
+	Common.ResetGlobalState()
+
+	gm := Global.NewGlobalManager()
+	gm.Init()
+
+	if _, err := gm.UpdateMetadata(Global.SET_MAX_APPS, 2); err != nil {
+		t.Fatalf("UpdateMetadata(SET_MAX_APPS) failed: %v", err)
+	}
+
+	for _, name := range []string{"app1", "app2"} {
+		if _, err := App.NewAppManager(name).CreateApp(); err != nil {
+			t.Fatalf("CreateApp(%s) failed: %v", name, err)
+		}
+	}
+
+	// Third app exceeds the cap
+	_, err := App.NewAppManager("app3").CreateApp()
+	if !errors.Is(err, Errors.ErrMaxAppsExceeded) {
+		t.Fatalf("Expected ErrMaxAppsExceeded, got %v", err)
+	}
+	if types.IsIntilized().App("app3") {
+		t.Error("Rejected app should not be registered")
+	}
+	_, err = App.NewAppManager("capped-app").CreateApp()
+	if !errors.Is(err, Errors.ErrMaxAppsExceeded) {
+		t.Fatalf("Expected ErrMaxAppsExceeded, got %v", err)
+	}
+	if isContextAlive(types.Prefix_AppManager + "capped-app") {
+		t.Error("Rejected app should not leave a live context behind")
+	}
+
+	// Re-creating an existing app is still allowed
+	if _, err := App.NewAppManager("app1").CreateApp(); err != nil {
+		t.Errorf("CreateApp() for existing app should succeed, got %v", err)
+	}
+
+	// Lifting the cap allows new apps again
+	if _, err := gm.UpdateMetadata(Global.SET_MAX_APPS, 0); err != nil {
+		t.Fatalf("UpdateMetadata(SET_MAX_APPS) failed: %v", err)
+	}
+	if _, err := App.NewAppManager("app3").CreateApp(); err != nil {
+		t.Errorf("CreateApp() should succeed once the cap is lifted, got %v", err)
+	}
+
+	if _, err := gm.UpdateMetadata(Global.SET_MAX_APPS, "two"); err == nil {
+		t.Error("Expected error for non-integer max apps")
+	}
+}
+
+// TestGlobalManager_UpdateMetadata_SetMaxLocalsPerApp tests that CreateLocal respects the per-app local cap
+func TestGlobalManager_UpdateMetadata_SetMaxLocalsPerApp(t *testing.T) {
+	Common.ResetGlobalState()
+
+	gm := Global.NewGlobalManager()
+	gm.Init()
+
+	if _, err := gm.UpdateMetadata(Global.SET_MAX_LOCALS_PER_APP, 1); err != nil {
+		t.Fatalf("UpdateMetadata(SET_MAX_LOCALS_PER_APP) failed: %v", err)
+	}
+
+	appMgr := App.NewAppManager("app1")
+	if _, err := appMgr.CreateApp(); err != nil {
+		t.Fatalf("CreateApp() failed: %v", err)
+	}
+	if _, err := Local.NewLocalManager("app1", "local1").CreateLocal("local1"); err != nil {
+		t.Fatalf("CreateLocal(local1) failed: %v", err)
+	}
+
+	// Second local in the same app exceeds the cap
+	_, err := Local.NewLocalManager("app1", "local2").CreateLocal("local2")
+	if !errors.Is(err, Errors.ErrMaxLocalsExceeded) {
+		t.Fatalf("Expected ErrMaxLocalsExceeded, got %v", err)
+	}
+	if appMgr.GetLocalManagerCount() != 1 {
+		t.Errorf("Expected 1 local manager, got %d", appMgr.GetLocalManagerCount())
+	}
+	if isContextAlive(types.LocalContextKey("app1", "local2")) {
+		t.Error("Rejected local should not leave a live context behind")
+	}
+
+	// Existing local can still be fetched through CreateLocal
+	if _, err := Local.NewLocalManager("app1", "local1").CreateLocal("local1"); err != nil {
+		t.Errorf("CreateLocal() for existing local should succeed, got %v", err)
+	}
+
+	// The cap is per app, another app gets its own budget
+	appMgr2 := App.NewAppManager("app2")
+	if _, err := appMgr2.CreateApp(); err != nil {
+		t.Fatalf("CreateApp() failed: %v", err)
+	}
+	if _, err := Local.NewLocalManager("app2", "local1").CreateLocal("local1"); err != nil {
+		t.Errorf("CreateLocal() in a second app should succeed, got %v", err)
+	}
+}
+
+// isContextAlive reports whether a live context is registered under name
+func isContextAlive(name string) bool {
+	for _, node := range Context.Tree().Apps {
+		if node.Name == name && node.Alive {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,120 @@
+package Managertests
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/neerajchowdary889/GoRoutinesManager/Manager/App"
+	"github.com/neerajchowdary889/GoRoutinesManager/Manager/Global"
+	"github.com/neerajchowdary889/GoRoutinesManager/Manager/Local"
+	"github.com/neerajchowdary889/GoRoutinesManager/Tests/Common"
+	"github.com/neerajchowdary889/GoRoutinesManager/metrics"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// fakeConn records whether it was closed
+type fakeConn struct {
+	closed atomic.Bool
+}
+
+func (c *fakeConn) Close() error {
+	c.closed.Store(true)
+	return nil
+}
+
+// TestLocalManager_GoReconnecting tests that a failing connect is retried with backoff until it succeeds
+func TestLocalManager_GoReconnecting(t *testing.T) {
+	fmt.Println("\n=== TestLocalManager_GoReconnecting ===")
+	Common.ResetGlobalState()
+
+	gm := Global.NewGlobalManager()
+	if _, err := gm.Init(); err != nil {
+		t.Fatalf("Init() failed: %v", err)
+	}
+	if _, err := gm.UpdateMetadata(Global.SET_METRICS_URL, []interface{}{true, ""}); err != nil {
+		t.Fatalf("Failed to enable metrics: %v", err)
+	}
+	// Leave metrics uninitialized again for the tests that don't enable them
+	defer metrics.ResetMetrics()
+	defer metrics.StopCollector()
+
+	appMgr := App.NewAppManager("reconnect-app")
+	if _, err := appMgr.CreateApp(); err != nil {
+		t.Fatalf("CreateApp() failed: %v", err)
+	}
+	localMgr := Local.NewLocalManager("reconnect-app", "reconnect-local")
+	if _, err := localMgr.CreateLocal("reconnect-local"); err != nil {
+		t.Fatalf("CreateLocal() failed: %v", err)
+	}
+
+	// Counters are process wide, only look at what this run adds
+	failureCounter := metrics.GoroutineReconnectAttemptsTotal.WithLabelValues("reconnect-app", "reconnect-local", "feed", "failure")
+	successCounter := metrics.GoroutineReconnectAttemptsTotal.WithLabelValues("reconnect-app", "reconnect-local", "feed", "success")
+	failuresBefore := testutil.ToFloat64(failureCounter)
+	successesBefore := testutil.ToFloat64(successCounter)
+
+	// Fails three times, then hands out a connection that stays up until shutdown
+	var attempts atomic.Int32
+	var runs atomic.Int32
+	conn := &fakeConn{}
+	connect := func(ctx context.Context) (io.Closer, error) {
+		if attempts.Add(1) <= 3 {
+			return nil, errors.New("connection refused")
+		}
+		return conn, nil
+	}
+	run := func(ctx context.Context, c io.Closer) error {
+		runs.Add(1)
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	cfg := Local.ReconnectConfig{InitialBackoff: 10 * time.Millisecond, MaxBackoff: 40 * time.Millisecond}
+	if err := localMgr.GoReconnecting("feed", connect, run, cfg, Local.AddToWaitGroup("feed")); err != nil {
+		t.Fatalf("GoReconnecting() failed: %v", err)
+	}
+
+	// Backoff 10ms + 20ms + 40ms before the fourth attempt
+	deadline := time.Now().Add(2 * time.Second)
+	for runs.Load() == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if runs.Load() != 1 {
+		t.Fatalf("Expected run to start once connected, got %d runs", runs.Load())
+	}
+
+	// Steady run: no further connect attempts while the connection is up
+	time.Sleep(100 * time.Millisecond)
+	if got := attempts.Load(); got != 4 {
+		t.Errorf("Expected 4 connect attempts, got %d", got)
+	}
+	if runs.Load() != 1 {
+		t.Errorf("Expected a single steady run, got %d", runs.Load())
+	}
+	if conn.closed.Load() {
+		t.Error("Connection should stay open during a steady run")
+	}
+
+	failures := testutil.ToFloat64(failureCounter) - failuresBefore
+	successes := testutil.ToFloat64(successCounter) - successesBefore
+	if failures != 3 || successes != 1 {
+		t.Errorf("Expected 3 failed and 1 successful attempt in metrics, got %v and %v", failures, successes)
+	}
+
+	if err := localMgr.ShutdownFunction("feed", time.Second); err != nil {
+		t.Fatalf("ShutdownFunction() failed: %v", err)
+	}
+	if !conn.closed.Load() {
+		t.Error("Connection should be closed on shutdown")
+	}
+	if got := attempts.Load(); got != 4 {
+		t.Errorf("No reconnect expected after shutdown, got %d attempts", got)
+	}
+
+	fmt.Println("✓ Reconnected after 3 failures and ran steadily until shutdown")
+}
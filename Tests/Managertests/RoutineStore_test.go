@@ -0,0 +1,142 @@
+package Managertests
+
+import (
+	"context"
+	"hash/fnv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/neerajchowdary889/GoRoutinesManager/Manager/App"
+	"github.com/neerajchowdary889/GoRoutinesManager/Manager/Local"
+	"github.com/neerajchowdary889/GoRoutinesManager/types"
+)
+
+// shardedRoutineStore is an alternate RoutineStore spreading routines over several maps.
+// It counts Add calls so tests can prove it is the store actually in use.
+type shardedRoutineStore struct {
+	shards [4]map[string]*types.Routine
+	adds   *atomic.Int64
+}
+
+func newShardedRoutineStore(adds *atomic.Int64) *shardedRoutineStore {
+	store := &shardedRoutineStore{adds: adds}
+	for i := range store.shards {
+		store.shards[i] = make(map[string]*types.Routine)
+	}
+	return store
+}
+
+func (s *shardedRoutineStore) shard(routineID string) map[string]*types.Routine {
+	h := fnv.New32a()
+	h.Write([]byte(routineID))
+	return s.shards[h.Sum32()%uint32(len(s.shards))]
+}
+
+func (s *shardedRoutineStore) Add(routine *types.Routine) {
+	s.adds.Add(1)
+	s.shard(routine.ID)[routine.ID] = routine
+}
+
+func (s *shardedRoutineStore) Remove(routineID string) bool {
+	shard := s.shard(routineID)
+	if _, ok := shard[routineID]; !ok {
+		return false
+	}
+	delete(shard, routineID)
+	return true
+}
+
+func (s *shardedRoutineStore) Get(routineID string) (*types.Routine, bool) {
+	routine, ok := s.shard(routineID)[routineID]
+	return routine, ok
+}
+
+func (s *shardedRoutineStore) Range(fn func(routine *types.Routine) bool) {
+	for _, shard := range s.shards {
+		for _, routine := range shard {
+			if !fn(routine) {
+				return
+			}
+		}
+	}
+}
+
+func (s *shardedRoutineStore) Count() int {
+	count := 0
+	for _, shard := range s.shards {
+		count += len(shard)
+	}
+	return count
+}
+
+// TestRoutineStore_AlternateImplementation re-runs the routine tracking tests with every
+// local manager backed by the sharded store instead of the default map
+func TestRoutineStore_AlternateImplementation(t *testing.T) {
+	var adds atomic.Int64
+	types.SetRoutineStoreFactory(func() types.RoutineStore {
+		return newShardedRoutineStore(&adds)
+	})
+	defer types.SetRoutineStoreFactory(nil)
+
+	suite := map[string]func(*testing.T){
+		"Go_Basic":                             TestGo_Basic,
+		"Go_AddToWaitGroup":                    TestGo_AddToWaitGroup,
+		"Go_ContextCancellation":               TestGo_ContextCancellation,
+		"AppManager_GetGoroutineCount":         TestAppManager_GetGoroutineCount,
+		"AppManager_GetAllGoroutines":          TestAppManager_GetAllGoroutines,
+		"AppManager_GetRoutinesByFunctionName": TestAppManager_GetRoutinesByFunctionName,
+		"LocalManager_GetFunctionStats":        TestLocalManager_GetFunctionStats,
+		"LocalManager_Rename":                  TestLocalManager_Rename,
+	}
+	for name, test := range suite {
+		t.Run(name, test)
+	}
+
+	if adds.Load() == 0 {
+		t.Fatal("Sharded store was never used")
+	}
+}
+
+// TestRoutineStore_SetRoutineStore tests swapping the store of a live local manager
+func TestRoutineStore_SetRoutineStore(t *testing.T) {
+	resetGlobalState()
+
+	appMgr := App.NewAppManager("test-app")
+	if _, err := appMgr.CreateApp(); err != nil {
+		t.Fatalf("CreateApp() failed: %v", err)
+	}
+	localMgr := Local.NewLocalManager("test-app", "test-local")
+	local, err := localMgr.CreateLocal("test-local")
+	if err != nil {
+		t.Fatalf("CreateLocal() failed: %v", err)
+	}
+
+	release := make(chan struct{})
+	for i := 0; i < 3; i++ {
+		localMgr.Go("worker", func(ctx context.Context) error {
+			<-release
+			return nil
+		})
+	}
+
+	// Existing routines move over to the new store
+	var adds atomic.Int64
+	local.SetRoutineStore(newShardedRoutineStore(&adds))
+	if adds.Load() != 3 {
+		t.Errorf("Expected 3 routines migrated, got %d", adds.Load())
+	}
+	if count := localMgr.GetGoroutineCount(); count != 3 {
+		t.Errorf("Expected 3 routines after swap, got %d", count)
+	}
+
+	// Completion removes them from the new store
+	close(release)
+	deadline := time.Now().Add(time.Second)
+	for localMgr.GetGoroutineCount() != 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if count := local.Routines.Count(); count != 0 {
+		t.Errorf("Expected the new store to be empty after completion, got %d", count)
+	}
+}
@@ -0,0 +1,91 @@
+package Managertests
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/neerajchowdary889/GoRoutinesManager/Manager/App"
+	"github.com/neerajchowdary889/GoRoutinesManager/Manager/Errors"
+	"github.com/neerajchowdary889/GoRoutinesManager/Manager/Local"
+	"github.com/neerajchowdary889/GoRoutinesManager/Tests/Common"
+)
+
+// TestLocalManager_GoEvery_RunImmediately tests that the first run happens synchronously, before the first tick
+func TestLocalManager_GoEvery_RunImmediately(t *testing.T) {
+	fmt.Println("\n=== TestLocalManager_GoEvery_RunImmediately ===")
+	Common.ResetGlobalState()
+
+	appMgr := App.NewAppManager("schedule-app")
+	if _, err := appMgr.CreateApp(); err != nil {
+		t.Fatalf("CreateApp() failed: %v", err)
+	}
+	localMgr := Local.NewLocalManager("schedule-app", "schedule-local")
+	if _, err := localMgr.CreateLocal("schedule-local"); err != nil {
+		t.Fatalf("CreateLocal() failed: %v", err)
+	}
+
+	interval := 200 * time.Millisecond
+
+	// Without RunImmediately nothing runs until the first tick
+	var delayedRuns atomic.Int32
+	err := localMgr.GoEvery("delayed", Local.ScheduleConfig{Interval: interval}, func(ctx context.Context) error {
+		delayedRuns.Add(1)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("GoEvery() failed: %v", err)
+	}
+	if delayedRuns.Load() != 0 {
+		t.Errorf("Expected no run before the first tick, got %d", delayedRuns.Load())
+	}
+
+	// With RunImmediately the first run is done by the time GoEvery returns
+	var immediateRuns atomic.Int32
+	start := time.Now()
+	err = localMgr.GoEvery("immediate", Local.ScheduleConfig{Interval: interval, RunImmediately: true}, func(ctx context.Context) error {
+		immediateRuns.Add(1)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("GoEvery() failed: %v", err)
+	}
+	if immediateRuns.Load() != 1 {
+		t.Errorf("Expected the first run to complete before GoEvery returns, got %d runs", immediateRuns.Load())
+	}
+	if elapsed := time.Since(start); elapsed >= interval {
+		t.Errorf("First run should not wait for the tick delay, took %v", elapsed)
+	}
+
+	// The schedule keeps going afterwards
+	time.Sleep(interval + interval/2)
+	if immediateRuns.Load() != 2 {
+		t.Errorf("Expected the immediate run plus one tick, got %d runs", immediateRuns.Load())
+	}
+	if delayedRuns.Load() != 1 {
+		t.Errorf("Expected one tick, got %d runs", delayedRuns.Load())
+	}
+
+	// A failing first run is returned directly and nothing is scheduled
+	errFirstRun := errors.New("warmup failed")
+	err = localMgr.GoEvery("failing", Local.ScheduleConfig{Interval: interval, RunImmediately: true}, func(ctx context.Context) error {
+		return errFirstRun
+	})
+	if !errors.Is(err, errFirstRun) {
+		t.Errorf("Expected the first run's error, got %v", err)
+	}
+	if routines, _ := localMgr.GetRoutinesByFunctionName("failing"); len(routines) != 0 {
+		t.Errorf("Expected no routine after a failed first run, got %d", len(routines))
+	}
+
+	// A non positive interval is rejected
+	err = localMgr.GoEvery("no-interval", Local.ScheduleConfig{}, func(ctx context.Context) error { return nil })
+	if !errors.Is(err, Errors.ErrInvalidInterval) {
+		t.Errorf("Expected ErrInvalidInterval, got %v", err)
+	}
+
+	fmt.Println("✓ First run executed synchronously before the ticker loop")
+}
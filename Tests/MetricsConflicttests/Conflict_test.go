@@ -0,0 +1,80 @@
+package MetricsConflicttests
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/neerajchowdary889/GoRoutinesManager/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// This package runs in its own test binary so InitMetrics hasn't run yet when the host metrics are registered
+
+// TestInitMetrics_ConflictingHostMetrics verifies that metric names already taken by the host
+// are skipped instead of panicking, and the remaining built-in metrics are still registered
+func TestInitMetrics_ConflictingHostMetrics(t *testing.T) {
+	fmt.Println("\n=== TestInitMetrics_ConflictingHostMetrics ===")
+
+	// Same name, different label names
+	hostInitialized := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "goroutine_manager_global_initialized",
+		Help: "Host metric that happens to share the name",
+	}, []string{"host"})
+	// Same descriptor as the built-in metric
+	hostGoroutines := prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "goroutine_manager",
+		Subsystem: "global",
+		Name:      "goroutines_total",
+		Help:      "Total number of tracked goroutines",
+	})
+	prometheus.MustRegister(hostInitialized, hostGoroutines)
+	hostInitialized.WithLabelValues("a").Set(42)
+
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				t.Fatalf("InitMetrics() panicked: %v", r)
+			}
+		}()
+		metrics.InitMetrics()
+	}()
+
+	errs := metrics.RegistrationErrors()
+	if len(errs) != 2 {
+		t.Fatalf("Expected 2 registration errors, got %d: %v", len(errs), errs)
+	}
+	var already prometheus.AlreadyRegisteredError
+	if !errors.As(errs[1], &already) {
+		t.Errorf("Expected an AlreadyRegisteredError for the identical descriptor, got %v", errs[1])
+	}
+	fmt.Printf("✓ InitMetrics skipped %d conflicting metrics\n", len(errs))
+
+	// The skipped metrics are still usable, they just aren't exported
+	metrics.GlobalInitialized.Set(1)
+	metrics.GoroutinesTotal.Set(5)
+	metrics.AppManagersTotal.Set(3)
+
+	families, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatalf("Gather() failed: %v", err)
+	}
+	values := map[string]float64{}
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			if metric.GetGauge() != nil {
+				values[family.GetName()] = metric.GetGauge().GetValue()
+			}
+		}
+	}
+	if got := values["goroutine_manager_global_initialized"]; got != 42 {
+		t.Errorf("Host metric should be untouched, got %v", got)
+	}
+	if got := values["goroutine_manager_global_goroutines_total"]; got != 0 {
+		t.Errorf("Host gauge should not receive library values, got %v", got)
+	}
+	if got, ok := values["goroutine_manager_global_app_managers_total"]; !ok || got != 3 {
+		t.Errorf("Non-conflicting built-in metric should be exported, got %v (present: %v)", got, ok)
+	}
+	fmt.Println("✓ Host metrics untouched, other built-in metrics exported")
+}
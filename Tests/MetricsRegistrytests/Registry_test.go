@@ -0,0 +1,76 @@
+package MetricsRegistrytests
+
+import (
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/neerajchowdary889/GoRoutinesManager/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// This package runs in its own test binary so the metrics aren't initialized into the default registry yet
+
+// TestInitMetricsWithRegistry verifies that the built-in metrics go into the given registry,
+// stay out of the default one, and are served from it by GetMetricsHandler
+func TestInitMetricsWithRegistry(t *testing.T) {
+	fmt.Println("\n=== TestInitMetricsWithRegistry ===")
+
+	// Start from a clean slate whatever ran before
+	metrics.ResetMetrics()
+
+	reg := prometheus.NewRegistry()
+	metrics.InitMetricsWithRegistry(reg)
+	metrics.AppManagersTotal.Set(3)
+
+	if metrics.GetRegistry() != reg {
+		t.Error("GetRegistry() should return the registry passed to InitMetricsWithRegistry")
+	}
+
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("Gather() failed: %v", err)
+	}
+	found := 0
+	for _, family := range families {
+		if strings.HasPrefix(family.GetName(), "goroutine_manager_") {
+			found++
+		}
+	}
+	if found == 0 {
+		t.Fatal("Expected goroutine_manager_* families in the custom registry")
+	}
+	fmt.Printf("✓ %d goroutine_manager_* families in the custom registry\n", found)
+
+	defaults, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatalf("Gather() on the default registry failed: %v", err)
+	}
+	for _, family := range defaults {
+		if strings.HasPrefix(family.GetName(), "goroutine_manager_") {
+			t.Errorf("Family %s leaked into the default registry", family.GetName())
+		}
+	}
+	fmt.Println("✓ Default registry untouched")
+
+	// Later initializations keep the custom registry
+	metrics.InitMetrics()
+	if metrics.GetRegistry() != reg {
+		t.Error("InitMetrics() after InitMetricsWithRegistry should keep the custom registry")
+	}
+
+	server := httptest.NewServer(metrics.GetMetricsHandler())
+	defer server.Close()
+	resp, err := server.Client().Get(server.URL)
+	if err != nil {
+		t.Fatalf("GET /metrics failed: %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if !strings.Contains(string(body), "goroutine_manager_global_app_managers_total 3") {
+		t.Errorf("Handler should serve the custom registry, got:\n%s", body)
+	}
+	fmt.Println("✓ GetMetricsHandler serves the custom registry")
+}
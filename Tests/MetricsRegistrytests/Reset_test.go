@@ -0,0 +1,81 @@
+package MetricsRegistrytests
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/neerajchowdary889/GoRoutinesManager/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// appGoroutineSeries returns how many goroutine_manager_app_goroutines series the registry holds
+func appGoroutineSeries(t *testing.T, reg *prometheus.Registry) int {
+	t.Helper()
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("Gather() failed: %v", err)
+	}
+	for _, family := range families {
+		if family.GetName() == "goroutine_manager_app_goroutines" {
+			return len(family.GetMetric())
+		}
+	}
+	return 0
+}
+
+// TestResetMetrics_Reinitialize verifies that metrics can be initialized again after a reset
+// without a duplicate registration, and that label series don't survive the reset
+func TestResetMetrics_Reinitialize(t *testing.T) {
+	fmt.Println("\n=== TestResetMetrics_Reinitialize ===")
+
+	// Start from a clean slate whatever ran before
+	metrics.ResetMetrics()
+
+	first := prometheus.NewRegistry()
+	metrics.InitMetricsWithRegistry(first)
+	metrics.AppGoroutines.WithLabelValues("stale-app").Set(4)
+	if got := appGoroutineSeries(t, first); got != 1 {
+		t.Fatalf("Expected 1 app goroutines series, got %d", got)
+	}
+
+	metrics.ResetMetrics()
+	if metrics.IsInitialized() {
+		t.Fatal("IsInitialized() should be false after ResetMetrics()")
+	}
+	if got := appGoroutineSeries(t, first); got != 0 {
+		t.Errorf("Expected the reset to unregister the metrics, %d series left", got)
+	}
+	fmt.Println("✓ Reset unregistered the built-in metrics")
+
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				t.Fatalf("Re-initializing panicked: %v", r)
+			}
+		}()
+		metrics.InitMetricsWithRegistry(first)
+	}()
+	if !metrics.IsInitialized() {
+		t.Fatal("IsInitialized() should be true after re-initializing")
+	}
+	if errs := metrics.RegistrationErrors(); len(errs) != 0 {
+		t.Errorf("Expected no registration errors on re-initialization, got %v", errs)
+	}
+	if got := appGoroutineSeries(t, first); got != 0 {
+		t.Errorf("Expected no stale label series after re-initializing, got %d", got)
+	}
+	fmt.Println("✓ Re-initialized without duplicates or stale series")
+
+	// The default registry works again too
+	metrics.ResetMetrics()
+	metrics.InitMetrics()
+	if errs := metrics.RegistrationErrors(); len(errs) != 0 {
+		t.Errorf("Expected no registration errors in the default registry, got %v", errs)
+	}
+	metrics.ResetMetrics()
+	metrics.InitMetrics()
+	if errs := metrics.RegistrationErrors(); len(errs) != 0 {
+		t.Errorf("Expected no registration errors after resetting the default registry, got %v", errs)
+	}
+	fmt.Println("✓ Default registry can be reset and re-initialized")
+}
@@ -0,0 +1,85 @@
+package Metricstests
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/neerajchowdary889/GoRoutinesManager/Manager/App"
+	"github.com/neerajchowdary889/GoRoutinesManager/Manager/Local"
+	"github.com/neerajchowdary889/GoRoutinesManager/metrics"
+)
+
+// seriesForApp counts the exported series labelled with the app
+func seriesForApp(t *testing.T, appName string) int {
+	t.Helper()
+	families, err := metrics.GetRegistry().Gather()
+	if err != nil {
+		t.Fatalf("Gather() failed: %v", err)
+	}
+	count := 0
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == "app_name" && label.GetValue() == appName {
+					count++
+				}
+			}
+		}
+	}
+	return count
+}
+
+// TestAppMetrics_Disabled verifies that an app opted out of metrics exports no series while others still do
+func TestAppMetrics_Disabled(t *testing.T) {
+	fmt.Println("\n=== TestAppMetrics_Disabled ===")
+	enableMetrics(t)
+	defer metrics.StopCollector()
+
+	for _, appName := range []string{"payments-app", "low-value-app"} {
+		appMgr := App.NewAppManager(appName)
+		if _, err := appMgr.CreateApp(); err != nil {
+			t.Fatalf("CreateApp() failed: %v", err)
+		}
+	}
+	if err := App.NewAppManager("low-value-app").SetMetricsEnabled(false); err != nil {
+		t.Fatalf("SetMetricsEnabled() failed: %v", err)
+	}
+	// Creating the apps was recorded before the opt-out, those series are kept
+	paymentsBefore := seriesForApp(t, "payments-app")
+	lowValueBefore := seriesForApp(t, "low-value-app")
+
+	release := make(chan struct{})
+	defer func() {
+		close(release)
+		for _, appName := range []string{"payments-app", "low-value-app"} {
+			App.NewAppManager(appName).WaitForAll()
+		}
+	}()
+	for _, appName := range []string{"payments-app", "low-value-app"} {
+		localMgr := Local.NewLocalManager(appName, "worker-local")
+		if _, err := localMgr.CreateLocal("worker-local"); err != nil {
+			t.Fatalf("CreateLocal() failed: %v", err)
+		}
+		// One finishes, one keeps running so both event and collected series are exercised
+		localMgr.GoWait("job", []func(ctx context.Context) error{
+			func(ctx context.Context) error { return nil },
+		}, 0)
+		if err := localMgr.Go("worker", func(ctx context.Context) error {
+			<-release
+			return nil
+		}); err != nil {
+			t.Fatalf("Go() failed: %v", err)
+		}
+	}
+	metrics.FlushNow()
+
+	if count := seriesForApp(t, "payments-app"); count <= paymentsBefore {
+		t.Error("Expected new series for the enabled app")
+	}
+	if count := seriesForApp(t, "low-value-app"); count != lowValueBefore {
+		t.Errorf("Expected no new series for the disabled app, got %d", count-lowValueBefore)
+	}
+
+	fmt.Println("✓ Disabled app produced no series")
+}
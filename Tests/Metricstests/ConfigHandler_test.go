@@ -0,0 +1,71 @@
+package Metricstests
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/neerajchowdary889/GoRoutinesManager/Manager/Global"
+	"github.com/neerajchowdary889/GoRoutinesManager/Tests/Common"
+	"github.com/neerajchowdary889/GoRoutinesManager/metrics"
+)
+
+// fetchConfig serves the config handler once and decodes the JSON body
+func fetchConfig(t *testing.T) (int, map[string]interface{}) {
+	t.Helper()
+	recorder := httptest.NewRecorder()
+	metrics.ConfigHandler().ServeHTTP(recorder, httptest.NewRequest("GET", "/config", nil))
+	if recorder.Code != http.StatusOK {
+		return recorder.Code, nil
+	}
+	var config map[string]interface{}
+	if err := json.NewDecoder(recorder.Body).Decode(&config); err != nil {
+		t.Fatalf("Failed to decode config: %v", err)
+	}
+	return recorder.Code, config
+}
+
+// TestConfigHandler_ReflectsMetadata verifies the config endpoint serves the live metadata
+func TestConfigHandler_ReflectsMetadata(t *testing.T) {
+	fmt.Println("\n=== TestConfigHandler_ReflectsMetadata ===")
+	Common.ResetGlobalState()
+
+	// Not initialized yet
+	if code, _ := fetchConfig(t); code != http.StatusInternalServerError {
+		t.Errorf("Expected 500 before init, got %d", code)
+	}
+
+	gm := Global.NewGlobalManager()
+	if _, err := gm.Init(); err != nil {
+		t.Fatalf("Init() failed: %v", err)
+	}
+
+	_, config := fetchConfig(t)
+	if config["metrics_enabled"] != false {
+		t.Errorf("Expected metrics disabled, got %v", config["metrics_enabled"])
+	}
+	if config["max_routines"] != float64(0) {
+		t.Errorf("Expected max_routines 0, got %v", config["max_routines"])
+	}
+
+	// ShutdownTimeout is process wide, restore the default for the other tests
+	defer gm.UpdateMetadata(Global.SET_SHUTDOWN_TIMEOUT, 10*time.Second)
+	gm.UpdateMetadata(Global.SET_SHUTDOWN_TIMEOUT, 3*time.Second)
+	gm.UpdateMetadata(Global.SET_MAX_ROUTINES, 250)
+	gm.UpdateMetadata(Global.SET_MAX_APPS, 4)
+
+	_, config = fetchConfig(t)
+	if config["shutdown_timeout"] != "3s" {
+		t.Errorf("Expected shutdown_timeout 3s, got %v", config["shutdown_timeout"])
+	}
+	if config["max_routines"] != float64(250) {
+		t.Errorf("Expected max_routines 250, got %v", config["max_routines"])
+	}
+	if config["max_apps"] != float64(4) {
+		t.Errorf("Expected max_apps 4, got %v", config["max_apps"])
+	}
+	fmt.Println("✓ Config reflects updated metadata")
+}
@@ -0,0 +1,74 @@
+package Metricstests
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/neerajchowdary889/GoRoutinesManager/Manager/App"
+	"github.com/neerajchowdary889/GoRoutinesManager/Manager/Local"
+	"github.com/neerajchowdary889/GoRoutinesManager/metrics"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// TestDraining_GaugesDuringSlowShutdown verifies the draining gauges rise while a shutdown waits and drop back after
+func TestDraining_GaugesDuringSlowShutdown(t *testing.T) {
+	fmt.Println("\n=== TestDraining_GaugesDuringSlowShutdown ===")
+	enableMetrics(t)
+	defer metrics.StopCollector()
+
+	appMgr := App.NewAppManager("drain-app")
+	if _, err := appMgr.CreateApp(); err != nil {
+		t.Fatalf("CreateApp() failed: %v", err)
+	}
+	for _, name := range []string{"drain-local-1", "drain-local-2"} {
+		localMgr := Local.NewLocalManager("drain-app", name)
+		if _, err := localMgr.CreateLocal(name); err != nil {
+			t.Fatalf("CreateLocal() failed: %v", err)
+		}
+		// Keeps the graceful shutdown waiting for a while
+		err := localMgr.Go("slow", func(ctx context.Context) error {
+			time.Sleep(300 * time.Millisecond)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("Go() failed: %v", err)
+		}
+	}
+
+	if got := testutil.ToFloat64(metrics.AppsDraining); got != 0 {
+		t.Fatalf("Expected no draining apps before shutdown, got %v", got)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- appMgr.Shutdown(true)
+	}()
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if testutil.ToFloat64(metrics.AppsDraining) == 1 && testutil.ToFloat64(metrics.LocalsDraining) == 2 {
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if got := testutil.ToFloat64(metrics.AppsDraining); got != 1 {
+		t.Errorf("Expected 1 draining app during shutdown, got %v", got)
+	}
+	if got := testutil.ToFloat64(metrics.LocalsDraining); got != 2 {
+		t.Errorf("Expected 2 draining locals during shutdown, got %v", got)
+	}
+	fmt.Println("✓ Gauges rose during the shutdown")
+
+	if err := <-done; err != nil {
+		t.Fatalf("Shutdown() failed: %v", err)
+	}
+	if got := testutil.ToFloat64(metrics.AppsDraining); got != 0 {
+		t.Errorf("Expected 0 draining apps after shutdown, got %v", got)
+	}
+	if got := testutil.ToFloat64(metrics.LocalsDraining); got != 0 {
+		t.Errorf("Expected 0 draining locals after shutdown, got %v", got)
+	}
+	fmt.Println("✓ Gauges back to zero after the shutdown")
+}
@@ -0,0 +1,50 @@
+package Metricstests
+
+import (
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/neerajchowdary889/GoRoutinesManager/Manager/App"
+	"github.com/neerajchowdary889/GoRoutinesManager/Manager/Local"
+	"github.com/neerajchowdary889/GoRoutinesManager/metrics"
+)
+
+// TestGetFilteredHandler_ExcludesOtherApps verifies that a filtered endpoint only serves the matching tenant's series
+func TestGetFilteredHandler_ExcludesOtherApps(t *testing.T) {
+	fmt.Println("\n=== TestGetFilteredHandler_ExcludesOtherApps ===")
+	enableMetrics(t)
+	defer metrics.StopCollector()
+
+	for _, appName := range []string{"tenant-a", "tenant-b"} {
+		if _, err := App.NewAppManager(appName).CreateApp(); err != nil {
+			t.Fatalf("CreateApp(%s) failed: %v", appName, err)
+		}
+		if _, err := Local.NewLocalManager(appName, "api").CreateLocal("api"); err != nil {
+			t.Fatalf("CreateLocal(%s/api) failed: %v", appName, err)
+		}
+	}
+	metrics.FlushNow()
+
+	handler := metrics.GetFilteredHandler(func(appName, localName string) bool {
+		return appName == "tenant-a"
+	})
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+	body, _ := io.ReadAll(recorder.Result().Body)
+	output := string(body)
+
+	if !strings.Contains(output, `app_name="tenant-a"`) {
+		t.Error("Filtered output should contain tenant-a series")
+	}
+	if strings.Contains(output, `app_name="tenant-b"`) {
+		t.Error("Filtered output should not contain tenant-b series")
+	}
+	if !strings.Contains(output, "goroutine_manager_global_app_managers_total") {
+		t.Error("Filtered output should still contain unscoped global series")
+	}
+	fmt.Println("✓ Filtered handler only exposes the matching app")
+}
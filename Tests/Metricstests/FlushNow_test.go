@@ -0,0 +1,79 @@
+package Metricstests
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/neerajchowdary889/GoRoutinesManager/Manager/App"
+	"github.com/neerajchowdary889/GoRoutinesManager/Manager/Global"
+	"github.com/neerajchowdary889/GoRoutinesManager/Manager/Interface"
+	"github.com/neerajchowdary889/GoRoutinesManager/Manager/Local"
+	"github.com/neerajchowdary889/GoRoutinesManager/Tests/Common"
+	"github.com/neerajchowdary889/GoRoutinesManager/metrics"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// enableMetrics resets the global state and initializes a global manager with metrics enabled
+// Callers should defer metrics.StopCollector()
+func enableMetrics(t *testing.T) Interface.GlobalGoroutineManagerInterface {
+	Common.ResetGlobalState()
+
+	gm := Global.NewGlobalManager()
+	if _, err := gm.Init(); err != nil {
+		t.Fatalf("Init() failed: %v", err)
+	}
+	if _, err := gm.UpdateMetadata(Global.SET_METRICS_URL, []interface{}{true, ""}); err != nil {
+		t.Fatalf("Failed to enable metrics: %v", err)
+	}
+	return gm
+}
+
+// TestFlushNow_AfterShutdown verifies that metrics read zero right after Shutdown without waiting for a collector tick
+func TestFlushNow_AfterShutdown(t *testing.T) {
+	fmt.Println("\n=== TestFlushNow_AfterShutdown ===")
+	gm := enableMetrics(t)
+	defer metrics.StopCollector()
+
+	appMgr := App.NewAppManager("flush-app")
+	if _, err := appMgr.CreateApp(); err != nil {
+		t.Fatalf("CreateApp() failed: %v", err)
+	}
+	localMgr := Local.NewLocalManager("flush-app", "flush-local")
+	if _, err := localMgr.CreateLocal("flush-local"); err != nil {
+		t.Fatalf("CreateLocal() failed: %v", err)
+	}
+
+	for i := 0; i < 5; i++ {
+		err := localMgr.Go("worker", func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}, Local.AddToWaitGroup("worker"))
+		if err != nil {
+			t.Fatalf("Go() failed: %v", err)
+		}
+	}
+
+	// A flush makes the live state visible immediately
+	metrics.FlushNow()
+	if got := testutil.ToFloat64(metrics.GoroutinesTotal); got != 5 {
+		t.Fatalf("Expected 5 goroutines before shutdown, got %v", got)
+	}
+	if got := testutil.ToFloat64(metrics.LocalGoroutines.WithLabelValues("flush-app", "flush-local")); got != 5 {
+		t.Fatalf("Expected 5 local goroutines before shutdown, got %v", got)
+	}
+
+	// Shutdown flushes internally, so counts must already be zero
+	if err := gm.Shutdown(true); err != nil {
+		t.Fatalf("Shutdown() failed: %v", err)
+	}
+	if got := testutil.ToFloat64(metrics.GoroutinesTotal); got != 0 {
+		t.Errorf("Expected 0 goroutines right after shutdown, got %v", got)
+	}
+
+	metrics.FlushNow()
+	if got := testutil.ToFloat64(metrics.LocalGoroutines.WithLabelValues("flush-app", "flush-local")); got != 0 {
+		t.Errorf("Expected 0 local goroutines after FlushNow, got %v", got)
+	}
+	fmt.Println("✓ Metrics reflect shutdown state immediately")
+}
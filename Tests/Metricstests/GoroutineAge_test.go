@@ -0,0 +1,74 @@
+package Metricstests
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/neerajchowdary889/GoRoutinesManager/Manager/App"
+	"github.com/neerajchowdary889/GoRoutinesManager/Manager/Local"
+	"github.com/neerajchowdary889/GoRoutinesManager/metrics"
+)
+
+// ageSeriesForLocal counts the goroutine age series of the local manager
+func ageSeriesForLocal(t *testing.T, localName string) int {
+	t.Helper()
+	families, err := metrics.GetRegistry().Gather()
+	if err != nil {
+		t.Fatalf("Gather() failed: %v", err)
+	}
+	count := 0
+	for _, family := range families {
+		if family.GetName() != "goroutine_manager_goroutine_age_seconds" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == "local_name" && label.GetValue() == localName {
+					count++
+				}
+			}
+		}
+	}
+	return count
+}
+
+// TestGoroutineAge_RemovedOnCompletion verifies that finished routines don't leave age series behind
+func TestGoroutineAge_RemovedOnCompletion(t *testing.T) {
+	fmt.Println("\n=== TestGoroutineAge_RemovedOnCompletion ===")
+	enableMetrics(t)
+	defer metrics.StopCollector()
+
+	if _, err := App.NewAppManager("age-app").CreateApp(); err != nil {
+		t.Fatalf("CreateApp() failed: %v", err)
+	}
+	localMgr := Local.NewLocalManager("age-app", "age-local")
+	if _, err := localMgr.CreateLocal("age-local"); err != nil {
+		t.Fatalf("CreateLocal() failed: %v", err)
+	}
+
+	release := make(chan struct{})
+	for i := 0; i < 100; i++ {
+		if err := localMgr.Go("short", func(ctx context.Context) error {
+			<-release
+			return nil
+		}, Local.AddToWaitGroup("short")); err != nil {
+			t.Fatalf("Go() failed: %v", err)
+		}
+	}
+
+	// Collect while they run so each routine has an age series
+	metrics.FlushNow()
+	if got := ageSeriesForLocal(t, "age-local"); got != 100 {
+		t.Fatalf("Expected 100 age series while running, got %d", got)
+	}
+
+	close(release)
+	if err := localMgr.WaitForFunction("short"); err != nil {
+		t.Fatalf("WaitForFunction() failed: %v", err)
+	}
+	if got := ageSeriesForLocal(t, "age-local"); got != 0 {
+		t.Errorf("Expected no age series after completion, got %d", got)
+	}
+	fmt.Println("✓ Age series removed on completion")
+}
@@ -0,0 +1,53 @@
+package Metricstests
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/neerajchowdary889/GoRoutinesManager/Manager/App"
+	"github.com/neerajchowdary889/GoRoutinesManager/Manager/Local"
+	"github.com/neerajchowdary889/GoRoutinesManager/metrics"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// TestLabeledTotal_CapsLabelCombinations verifies that label sets past the cap are counted as overflow
+func TestLabeledTotal_CapsLabelCombinations(t *testing.T) {
+	fmt.Println("\n=== TestLabeledTotal_CapsLabelCombinations ===")
+	enableMetrics(t)
+	defer metrics.StopCollector()
+
+	appMgr := App.NewAppManager("labels-cap-app")
+	if _, err := appMgr.CreateApp(); err != nil {
+		t.Fatalf("CreateApp() failed: %v", err)
+	}
+	localMgr := Local.NewLocalManager("labels-cap-app", "labels-cap-local")
+	if _, err := localMgr.CreateLocal("labels-cap-local"); err != nil {
+		t.Fatalf("CreateLocal() failed: %v", err)
+	}
+
+	// No room for new label sets, every request ID below is new
+	defer func(max int) { metrics.MaxLabelCombinations = max }(metrics.MaxLabelCombinations)
+	metrics.MaxLabelCombinations = 0
+
+	overflow := metrics.LabeledOperationsTotal.WithLabelValues("create", "labels-cap-app", "labels-cap-local", "request", metrics.OverflowLabels)
+	overflowBefore := testutil.ToFloat64(overflow)
+	for i := 0; i < 5; i++ {
+		labels := map[string]string{"request_id": fmt.Sprintf("labels-cap-%d", i)}
+		if err := localMgr.Go("request", func(ctx context.Context) error { return nil },
+			Local.AddToWaitGroup("request"), Local.WithLabels(labels)); err != nil {
+			t.Fatalf("Go() failed: %v", err)
+		}
+	}
+	if err := localMgr.WaitForFunction("request"); err != nil {
+		t.Fatalf("WaitForFunction() failed: %v", err)
+	}
+
+	if got := testutil.ToFloat64(overflow) - overflowBefore; got != 5 {
+		t.Errorf("Expected 5 creations counted as overflow, got %v", got)
+	}
+	if got := testutil.ToFloat64(metrics.LabeledOperationsTotal.WithLabelValues("create", "labels-cap-app", "labels-cap-local", "request", "request_id=labels-cap-0")); got != 0 {
+		t.Errorf("Expected no series for a label set past the cap, got %v", got)
+	}
+	fmt.Println("✓ Label sets past the cap counted as overflow")
+}
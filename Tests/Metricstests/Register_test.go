@@ -0,0 +1,55 @@
+package Metricstests
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/neerajchowdary889/GoRoutinesManager/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// customQueueDepth is shared across runs, registering it again must be a no-op
+var customQueueDepth = prometheus.NewGauge(prometheus.GaugeOpts{
+	Name: "custom_test_queue_depth",
+	Help: "Custom gauge registered next to the built-in metrics",
+})
+
+// TestRegister_CustomCollector verifies that a custom gauge is gathered from the shared registry
+func TestRegister_CustomCollector(t *testing.T) {
+	fmt.Println("\n=== TestRegister_CustomCollector ===")
+
+	if err := metrics.Register(customQueueDepth); err != nil {
+		t.Fatalf("Register() failed: %v", err)
+	}
+	if err := metrics.Register(customQueueDepth); err != nil {
+		t.Fatalf("Registering the same collector again should be a no-op, got %v", err)
+	}
+	customQueueDepth.Set(7)
+
+	families, err := metrics.GetRegistry().Gather()
+	if err != nil {
+		t.Fatalf("Gather() failed: %v", err)
+	}
+	found := false
+	for _, family := range families {
+		if family.GetName() == "custom_test_queue_depth" {
+			found = true
+			if value := family.GetMetric()[0].GetGauge().GetValue(); value != 7 {
+				t.Errorf("Expected custom gauge value 7, got %v", value)
+			}
+		}
+	}
+	if !found {
+		t.Fatal("Custom gauge missing from the gathered metrics")
+	}
+	fmt.Println("✓ Custom gauge served from the shared registry")
+
+	clash := prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "custom_test_queue_depth",
+		Help: "Custom gauge registered next to the built-in metrics",
+	})
+	if err := metrics.Register(clash); err == nil {
+		t.Error("Expected an error for a different collector with the same descriptor")
+	}
+	fmt.Println("✓ Conflicting collector rejected")
+}
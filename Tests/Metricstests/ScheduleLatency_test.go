@@ -0,0 +1,67 @@
+package Metricstests
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/neerajchowdary889/GoRoutinesManager/Manager/App"
+	"github.com/neerajchowdary889/GoRoutinesManager/Manager/Local"
+	"github.com/neerajchowdary889/GoRoutinesManager/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// TestScheduleLatency_UnderLoad verifies that a worker kept off the CPU records its scheduling delay
+func TestScheduleLatency_UnderLoad(t *testing.T) {
+	fmt.Println("\n=== TestScheduleLatency_UnderLoad ===")
+	enableMetrics(t)
+	defer metrics.StopCollector()
+
+	appMgr := App.NewAppManager("latency-app")
+	if _, err := appMgr.CreateApp(); err != nil {
+		t.Fatalf("CreateApp() failed: %v", err)
+	}
+	localMgr := Local.NewLocalManager("latency-app", "latency-local")
+	if _, err := localMgr.CreateLocal("latency-local"); err != nil {
+		t.Fatalf("CreateLocal() failed: %v", err)
+	}
+
+	// Histograms are process wide, only look at what this run adds
+	histogram := metrics.GoroutineScheduleLatency.WithLabelValues("latency-app", "latency-local", "delayed-worker")
+	read := func() (uint64, float64) {
+		var m dto.Metric
+		if err := histogram.(prometheus.Metric).Write(&m); err != nil {
+			t.Fatalf("Failed to read schedule latency histogram: %v", err)
+		}
+		return m.GetHistogram().GetSampleCount(), m.GetHistogram().GetSampleSum()
+	}
+	countBefore, sumBefore := read()
+
+	// A single P kept busy by the spawning goroutine: the worker only starts once it is preempted
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(1))
+	started := make(chan struct{})
+	if err := localMgr.Go("delayed-worker", func(ctx context.Context) error {
+		close(started)
+		return nil
+	}); err != nil {
+		t.Fatalf("Go() failed: %v", err)
+	}
+	for busyUntil := time.Now().Add(30 * time.Millisecond); time.Now().Before(busyUntil); {
+	}
+	<-started
+	localMgr.WaitForAll()
+
+	count, sum := read()
+	if count-countBefore != 1 {
+		t.Fatalf("Expected 1 schedule latency sample, got %d", count-countBefore)
+	}
+	latency := time.Duration((sum - sumBefore) * float64(time.Second))
+	if latency < time.Millisecond {
+		t.Errorf("Expected a non trivial scheduling latency under load, got %v", latency)
+	}
+
+	fmt.Printf("✓ Recorded scheduling latency of %v\n", latency)
+}
@@ -0,0 +1,69 @@
+package Metricstests
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/neerajchowdary889/GoRoutinesManager/metrics"
+)
+
+// TestStartMetricsServerReady_BindFailure verifies that a port already in use is reported immediately
+func TestStartMetricsServerReady_BindFailure(t *testing.T) {
+	fmt.Println("\n=== TestStartMetricsServerReady_BindFailure ===")
+
+	occupied, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to occupy a port: %v", err)
+	}
+	defer occupied.Close()
+
+	start := time.Now()
+	err = metrics.StartMetricsServerReady(occupied.Addr().String(), 5*time.Second)
+	elapsed := time.Since(start)
+	if err == nil {
+		metrics.StopMetricsServer(context.Background())
+		t.Fatal("Expected a bind error for a port already in use")
+	}
+	if elapsed > time.Second {
+		t.Errorf("Expected the bind error immediately, took %v", elapsed)
+	}
+	fmt.Printf("✓ Bind failure returned in %v: %v\n", elapsed, err)
+}
+
+// TestStartMetricsServerReady_AcceptsOnReturn verifies the server answers as soon as the call returns
+func TestStartMetricsServerReady_AcceptsOnReturn(t *testing.T) {
+	fmt.Println("\n=== TestStartMetricsServerReady_AcceptsOnReturn ===")
+	enableMetrics(t)
+
+	// Grab a free port and release it for the server
+	probe, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to find a free port: %v", err)
+	}
+	addr := probe.Addr().String()
+	probe.Close()
+
+	if err := metrics.StartMetricsServerReady(addr, 5*time.Second); err != nil {
+		t.Fatalf("Failed to start metrics server: %v", err)
+	}
+	defer metrics.StopMetricsServer(context.Background())
+
+	// No sleep, the server must already be accepting connections
+	resp, err := http.Get("http://" + addr + "/health")
+	if err != nil {
+		t.Fatalf("Expected the server to accept connections on return: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected 200 from /health, got %d", resp.StatusCode)
+	}
+
+	if err := metrics.StartMetricsServerReady(addr, time.Second); err == nil {
+		t.Error("Expected an error when the server is already running")
+	}
+	fmt.Println("✓ Server accepted connections on return")
+}
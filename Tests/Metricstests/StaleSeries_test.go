@@ -0,0 +1,95 @@
+package Metricstests
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/neerajchowdary889/GoRoutinesManager/Manager/App"
+	"github.com/neerajchowdary889/GoRoutinesManager/Manager/Local"
+	"github.com/neerajchowdary889/GoRoutinesManager/metrics"
+	"github.com/neerajchowdary889/GoRoutinesManager/types"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// TestCollector_DeletesStaleSeries verifies that the collector deletes the series of an app
+// that went away and of a function whose routines all finished
+func TestCollector_DeletesStaleSeries(t *testing.T) {
+	fmt.Println("\n=== TestCollector_DeletesStaleSeries ===")
+	enableMetrics(t)
+	defer metrics.StopCollector()
+
+	// Drop whatever earlier tests exported
+	metrics.FlushNow()
+
+	appMgr := App.NewAppManager("stale-app")
+	if _, err := appMgr.CreateApp(); err != nil {
+		t.Fatalf("CreateApp() failed: %v", err)
+	}
+	localMgr := Local.NewLocalManager("stale-app", "stale-local")
+	if _, err := localMgr.CreateLocal("stale-local"); err != nil {
+		t.Fatalf("CreateLocal() failed: %v", err)
+	}
+	release := make(chan struct{})
+	if err := localMgr.Go("short-lived", func(ctx context.Context) error {
+		<-release
+		return nil
+	}, Local.AddToWaitGroup("short-lived")); err != nil {
+		t.Fatalf("Go() failed: %v", err)
+	}
+	if err := localMgr.Go("long-lived", func(ctx context.Context) error {
+		<-ctx.Done()
+		return nil
+	}); err != nil {
+		t.Fatalf("Go() failed: %v", err)
+	}
+	longLived, _ := localMgr.GetRoutinesByFunctionName("long-lived")
+
+	metrics.FlushNow()
+	if got := testutil.CollectAndCount(metrics.AppGoroutines); got != 1 {
+		t.Fatalf("Expected 1 app goroutines series, got %d", got)
+	}
+	if got := testutil.CollectAndCount(metrics.GoroutinesByFunction); got != 2 {
+		t.Fatalf("Expected 2 function series, got %d", got)
+	}
+
+	// A function whose routines all finished loses its series
+	close(release)
+	if err := localMgr.WaitForFunction("short-lived"); err != nil {
+		t.Fatalf("WaitForFunction() failed: %v", err)
+	}
+	metrics.FlushNow()
+	if got := testutil.CollectAndCount(metrics.GoroutinesByFunction); got != 1 {
+		t.Errorf("Expected 1 function series after short-lived finished, got %d", got)
+	}
+	fmt.Println("✓ Finished function series deleted")
+
+	// An app that was shut down and removed loses its app and local series
+	if err := appMgr.Shutdown(false); err != nil {
+		t.Fatalf("Shutdown() failed: %v", err)
+	}
+	// The unsafe shutdown untracked the routine, wait on its done channel for it to finish
+	for _, routine := range longLived {
+		<-routine.DoneChan()
+	}
+	globalMgr, err := types.GetGlobalManager()
+	if err != nil {
+		t.Fatalf("GetGlobalManager() failed: %v", err)
+	}
+	globalMgr.RemoveAppManager("stale-app")
+	metrics.FlushNow()
+
+	for name, count := range map[string]int{
+		"app_goroutines":            testutil.CollectAndCount(metrics.AppGoroutines),
+		"app_local_managers":        testutil.CollectAndCount(metrics.AppLocalManagers),
+		"app_initialized":           testutil.CollectAndCount(metrics.AppInitialized),
+		"local_goroutines":          testutil.CollectAndCount(metrics.LocalGoroutines),
+		"local_function_waitgroups": testutil.CollectAndCount(metrics.LocalFunctionWaitgroups),
+		"goroutines_by_function":    testutil.CollectAndCount(metrics.GoroutinesByFunction),
+	} {
+		if count != 0 {
+			t.Errorf("Expected no %s series after the app was removed, got %d", name, count)
+		}
+	}
+	fmt.Println("✓ Removed app series deleted")
+}
@@ -0,0 +1,52 @@
+package Metricstests
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/neerajchowdary889/GoRoutinesManager/Manager/App"
+	"github.com/neerajchowdary889/GoRoutinesManager/Manager/Local"
+	"github.com/neerajchowdary889/GoRoutinesManager/metrics"
+)
+
+// TestWriteTo_TextFormat verifies that the metrics are dumped in text exposition format without a server
+func TestWriteTo_TextFormat(t *testing.T) {
+	fmt.Println("\n=== TestWriteTo_TextFormat ===")
+	enableMetrics(t)
+	defer metrics.StopCollector()
+
+	appMgr := App.NewAppManager("dump-app")
+	if _, err := appMgr.CreateApp(); err != nil {
+		t.Fatalf("CreateApp() failed: %v", err)
+	}
+	localMgr := Local.NewLocalManager("dump-app", "dump-local")
+	if _, err := localMgr.CreateLocal("dump-local"); err != nil {
+		t.Fatalf("CreateLocal() failed: %v", err)
+	}
+	if err := localMgr.Go("dump-worker", func(ctx context.Context) error {
+		return nil
+	}, Local.AddToWaitGroup("dump-worker")); err != nil {
+		t.Fatalf("Go() failed: %v", err)
+	}
+	localMgr.WaitForFunction("dump-worker")
+
+	var out bytes.Buffer
+	if err := metrics.WriteTo(&out); err != nil {
+		t.Fatalf("WriteTo() failed: %v", err)
+	}
+	text := out.String()
+
+	for _, want := range []string{
+		"# TYPE goroutine_manager_operations_goroutine_operations_total counter",
+		`function_name="dump-worker"`,
+		"goroutine_manager_global_initialized",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected %q in the dumped metrics", want)
+		}
+	}
+	fmt.Printf("✓ Dumped %d bytes of metrics\n", out.Len())
+}
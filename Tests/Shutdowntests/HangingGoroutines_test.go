@@ -2,14 +2,19 @@ package Shutdowntests
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/neerajchowdary889/GoRoutinesManager/Manager/App"
+	"github.com/neerajchowdary889/GoRoutinesManager/Manager/Errors"
+	"github.com/neerajchowdary889/GoRoutinesManager/Manager/Global"
 	"github.com/neerajchowdary889/GoRoutinesManager/Manager/Local"
 	"github.com/neerajchowdary889/GoRoutinesManager/Tests/Common"
+	"github.com/neerajchowdary889/GoRoutinesManager/types"
 )
 
 func TestLocalManager_SafeShutdown_WithHangingGoroutines(t *testing.T) {
@@ -161,3 +166,160 @@ func TestLocalManager_SafeShutdown_AllHanging(t *testing.T) {
 		fmt.Printf("✓ Shutdown completed quickly (%v) - goroutines respected cancellation\n", elapsed)
 	}
 }
+
+// TestLocalManager_ForcedShutdown_WaitGroupDoneOnce races a forced shutdown against routines completing
+// on their own. Each wait group slot must be released exactly once: a second Done would panic
+// with a negative counter, a missing one would leave the wait group blocked forever.
+func TestLocalManager_ForcedShutdown_WaitGroupDoneOnce(t *testing.T) {
+	fmt.Println("\n=== TestLocalManager_ForcedShutdown_WaitGroupDoneOnce ===")
+	Common.ResetGlobalState()
+
+	gm := Global.NewGlobalManager()
+	if _, err := gm.Init(); err != nil {
+		t.Fatalf("Init() failed: %v", err)
+	}
+	// Tiny timeout so the force path runs while routines are still finishing
+	if _, err := gm.UpdateMetadata(Global.SET_SHUTDOWN_TIMEOUT, time.Millisecond); err != nil {
+		t.Fatalf("UpdateMetadata() failed: %v", err)
+	}
+	defer gm.UpdateMetadata(Global.SET_SHUTDOWN_TIMEOUT, 10*time.Second)
+
+	appMgr := App.NewAppManager("test-app")
+	if _, err := appMgr.CreateApp(); err != nil {
+		t.Fatalf("CreateApp() failed: %v", err)
+	}
+
+	for iteration := 0; iteration < 20; iteration++ {
+		localName := fmt.Sprintf("stress-%d", iteration)
+		localMgr := Local.NewLocalManager("test-app", localName)
+		local, err := localMgr.CreateLocal(localName)
+		if err != nil {
+			t.Fatalf("CreateLocal() failed: %v", err)
+		}
+
+		for i := 0; i < 50; i++ {
+			// Staggered finish times, the workers ignore ctx so some finish after being force-removed
+			delay := time.Duration(i%5) * time.Millisecond
+			localMgr.Go("worker", func(ctx context.Context) error {
+				time.Sleep(delay)
+				return nil
+			}, Local.AddToWaitGroup("worker"))
+		}
+
+		// Workers still running at the timeout are reported, anything else is a failure
+		var shutdownErr *types.ShutdownError
+		if err := localMgr.Shutdown(true); err != nil && !errors.As(err, &shutdownErr) {
+			t.Fatalf("Shutdown() failed: %v", err)
+		}
+
+		// Every slot released exactly once: Wait returns and nothing panicked
+		waited := make(chan struct{})
+		go func() {
+			local.GetLocalWaitGroup().Wait()
+			close(waited)
+		}()
+		select {
+		case <-waited:
+		case <-time.After(2 * time.Second):
+			t.Fatalf("Iteration %d: local wait group never reached zero", iteration)
+		}
+	}
+
+	// Let the stragglers run their own cleanup after being force-removed
+	time.Sleep(50 * time.Millisecond)
+	fmt.Println("✓ Forced shutdowns racing natural completion never double-released a wait group")
+}
+
+func TestSafeShutdown_ReportsStuckFunctions(t *testing.T) {
+	fmt.Println("\n=== TestSafeShutdown_ReportsStuckFunctions ===")
+	Common.ResetGlobalState()
+
+	gm := Global.NewGlobalManager()
+	if _, err := gm.Init(); err != nil {
+		t.Fatalf("Init() failed: %v", err)
+	}
+	if _, err := gm.UpdateMetadata(Global.SET_SHUTDOWN_TIMEOUT, 100*time.Millisecond); err != nil {
+		t.Fatalf("UpdateMetadata() failed: %v", err)
+	}
+	defer gm.UpdateMetadata(Global.SET_SHUTDOWN_TIMEOUT, 10*time.Second)
+
+	appMgr := App.NewAppManager("test-app")
+	if _, err := appMgr.CreateApp(); err != nil {
+		t.Fatalf("CreateApp() failed: %v", err)
+	}
+	// Workers that ignore their context, only closing release stops them
+	release := make(chan struct{})
+	// Stuck routines are force removed, wait on their done channels for them to finish
+	var spawned []*types.Routine
+	defer func() {
+		close(release)
+		for _, routine := range spawned {
+			<-routine.DoneChan()
+		}
+	}()
+	spawn := func(localName, functionName string, n int, ignoreCtx bool) {
+		localMgr := Local.NewLocalManager("test-app", localName)
+		if _, err := localMgr.CreateLocal(localName); err != nil {
+			t.Fatalf("CreateLocal() failed: %v", err)
+		}
+		for i := 0; i < n; i++ {
+			if err := localMgr.Go(functionName, func(ctx context.Context) error {
+				if ignoreCtx {
+					<-release
+					return nil
+				}
+				<-ctx.Done()
+				return ctx.Err()
+			}); err != nil {
+				t.Fatalf("Go() failed: %v", err)
+			}
+		}
+		routines, _ := localMgr.GetRoutinesByFunctionName(functionName)
+		spawned = append(spawned, routines...)
+	}
+	spawn("local-a", "stuck-a", 2, true)
+	spawn("local-a", "polite", 3, false)
+	spawn("local-b", "stuck-b", 1, true)
+
+	// A single local manager reports only its own stuck function
+	err := Local.NewLocalManager("test-app", "local-b").Shutdown(true)
+	var shutdownErr *types.ShutdownError
+	if !errors.As(err, &shutdownErr) {
+		t.Fatalf("Expected a ShutdownError from the local shutdown, got %v", err)
+	}
+	expectedLocal := []types.StuckFunction{{AppName: "test-app", LocalName: "local-b", FunctionName: "stuck-b", Remaining: 1}}
+	if len(shutdownErr.Functions) != 1 || shutdownErr.Functions[0] != expectedLocal[0] {
+		t.Errorf("Expected %+v, got %+v", expectedLocal, shutdownErr.Functions)
+	}
+	fmt.Printf("✓ Local shutdown error: %v\n", err)
+
+	// The app shutdown joins its local managers, the polite function stopped in time
+	spawn("local-c", "stuck-c", 1, true)
+	err = appMgr.Shutdown(true)
+	if !errors.As(err, &shutdownErr) {
+		t.Fatalf("Expected a ShutdownError from the app shutdown, got %v", err)
+	}
+	if !errors.Is(err, Errors.ErrShutdownTimedOut) {
+		t.Errorf("Expected the error to wrap ErrShutdownTimedOut, got %v", err)
+	}
+	stuck := make(map[string]int)
+	for _, function := range shutdownErr.Functions {
+		stuck[function.LocalName+"/"+function.FunctionName] = function.Remaining
+	}
+	expected := map[string]int{"local-a/stuck-a": 2, "local-c/stuck-c": 1}
+	if len(stuck) != len(expected) {
+		t.Errorf("Expected stuck functions %v, got %v", expected, stuck)
+	}
+	for name, remaining := range expected {
+		if stuck[name] != remaining {
+			t.Errorf("Expected %s with %d remaining, got %d", name, remaining, stuck[name])
+		}
+	}
+	if shutdownErr.Remaining() != 3 {
+		t.Errorf("Expected 3 remaining goroutines, got %d", shutdownErr.Remaining())
+	}
+	if strings.Contains(err.Error(), "polite") {
+		t.Errorf("Function that stopped in time listed in %v", err)
+	}
+	fmt.Printf("✓ App shutdown error: %v\n", err)
+}
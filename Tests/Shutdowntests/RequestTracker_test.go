@@ -0,0 +1,91 @@
+package Shutdowntests
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/neerajchowdary889/GoRoutinesManager/Manager/App"
+	"github.com/neerajchowdary889/GoRoutinesManager/Manager/Local"
+	"github.com/neerajchowdary889/GoRoutinesManager/Tests/Common"
+)
+
+// TestShutdownFunction_WaitsForInFlightRequest verifies that shutdown only cancels the worker after EndRequest
+func TestShutdownFunction_WaitsForInFlightRequest(t *testing.T) {
+	fmt.Println("\n=== TestShutdownFunction_WaitsForInFlightRequest ===")
+	Common.ResetGlobalState()
+
+	appMgr := App.NewAppManager("request-app")
+	if _, err := appMgr.CreateApp(); err != nil {
+		t.Fatalf("CreateApp() failed: %v", err)
+	}
+	localMgr := Local.NewLocalManager("request-app", "request-local")
+	if _, err := localMgr.CreateLocal("request-local"); err != nil {
+		t.Fatalf("CreateLocal() failed: %v", err)
+	}
+
+	requestStarted := make(chan struct{})
+	finishRequest := make(chan struct{})
+	var cancelledDuringRequest atomic.Bool
+	var requestEnded atomic.Bool
+
+	err := localMgr.Go("server", func(ctx context.Context) error {
+		if !Local.BeginRequest(ctx) {
+			t.Error("BeginRequest() should track the request of a worker spawned WithRequestTracker")
+		}
+		close(requestStarted)
+		<-finishRequest
+		cancelledDuringRequest.Store(ctx.Err() != nil)
+		requestEnded.Store(true)
+		Local.EndRequest(ctx)
+
+		<-ctx.Done()
+		return nil
+	}, Local.WithRequestTracker(), Local.AddToWaitGroup("server"))
+	if err != nil {
+		t.Fatalf("Go() failed: %v", err)
+	}
+	<-requestStarted
+
+	shutdownDone := make(chan error, 1)
+	go func() {
+		shutdownDone <- localMgr.ShutdownFunction("server", 2*time.Second)
+	}()
+
+	select {
+	case err := <-shutdownDone:
+		t.Fatalf("Shutdown returned while a request was in flight: %v", err)
+	case <-time.After(200 * time.Millisecond):
+	}
+	fmt.Println("✓ Shutdown is waiting on the open request")
+
+	close(finishRequest)
+	select {
+	case err := <-shutdownDone:
+		if err != nil {
+			t.Fatalf("ShutdownFunction() failed: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Shutdown did not finish after the request ended")
+	}
+
+	if !requestEnded.Load() {
+		t.Error("Request should have ended before shutdown finished")
+	}
+	if cancelledDuringRequest.Load() {
+		t.Error("Worker context was cancelled while the request was still in flight")
+	}
+	fmt.Println("✓ Worker cancelled only after EndRequest")
+}
+
+// TestBeginRequest_WithoutTracker verifies that requests aren't tracked for workers spawned without a tracker
+func TestBeginRequest_WithoutTracker(t *testing.T) {
+	fmt.Println("\n=== TestBeginRequest_WithoutTracker ===")
+	if Local.BeginRequest(context.Background()) {
+		t.Error("BeginRequest() should report false for a context without a tracker")
+	}
+	Local.EndRequest(context.Background())
+	fmt.Println("✓ Untracked context ignored")
+}
@@ -0,0 +1,177 @@
+package Shutdowntests
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/neerajchowdary889/GoRoutinesManager/Manager/App"
+	"github.com/neerajchowdary889/GoRoutinesManager/Manager/Global"
+	"github.com/neerajchowdary889/GoRoutinesManager/Manager/Local"
+	"github.com/neerajchowdary889/GoRoutinesManager/Tests/Common"
+	"github.com/neerajchowdary889/GoRoutinesManager/types"
+)
+
+// stageEvent is one recorded call of the shutdown stage hook
+type stageEvent struct {
+	stage    types.ShutdownStage
+	scope    string
+	function string
+}
+
+// stageRecorder collects shutdown stage events in the order they fire
+type stageRecorder struct {
+	mu     sync.Mutex
+	events []stageEvent
+}
+
+func (r *stageRecorder) record(stage types.ShutdownStage, app, local, function string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, stageEvent{stage: stage, scope: app + "/" + local, function: function})
+}
+
+// stagesOf returns the stages of one scope and the positions they fired at
+func (r *stageRecorder) stagesOf(scope, function string) ([]types.ShutdownStage, []int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var stages []types.ShutdownStage
+	var positions []int
+	for i, event := range r.events {
+		if event.scope == scope && event.function == function {
+			stages = append(stages, event.stage)
+			positions = append(positions, i)
+		}
+	}
+	return stages, positions
+}
+
+func TestShutdownStages_MixedWorkload(t *testing.T) {
+	fmt.Println("\n=== TestShutdownStages_MixedWorkload ===")
+	Common.ResetGlobalState()
+
+	gm := Global.NewGlobalManager()
+	if _, err := gm.Init(); err != nil {
+		t.Fatalf("Init() failed: %v", err)
+	}
+	if _, err := gm.UpdateMetadata(Global.SET_SHUTDOWN_TIMEOUT, 200*time.Millisecond); err != nil {
+		t.Fatalf("UpdateMetadata() failed: %v", err)
+	}
+	// ShutdownTimeout is process wide, restore the default for the other tests
+	defer gm.UpdateMetadata(Global.SET_SHUTDOWN_TIMEOUT, 10*time.Second)
+
+	appMgr := App.NewAppManager("stage-app")
+	if _, err := appMgr.CreateApp(); err != nil {
+		t.Fatalf("CreateApp() failed: %v", err)
+	}
+	localMgr := Local.NewLocalManager("stage-app", "stage-local")
+	if _, err := localMgr.CreateLocal("stage-local"); err != nil {
+		t.Fatalf("CreateLocal() failed: %v", err)
+	}
+
+	// One function drains in time, the other ignores its context
+	release := make(chan struct{})
+	localMgr.Go("cooperative", func(ctx context.Context) error {
+		<-ctx.Done()
+		return nil
+	}, Local.AddToWaitGroup("cooperative"))
+	localMgr.Go("stubborn", func(ctx context.Context) error {
+		<-release
+		return nil
+	}, Local.AddToWaitGroup("stubborn"))
+	stubbornRoutines, _ := localMgr.GetRoutinesByFunctionName("stubborn")
+	time.Sleep(50 * time.Millisecond)
+
+	recorder := &stageRecorder{}
+	types.OnShutdownStage(recorder.record)
+	defer types.OnShutdownStage(nil)
+
+	// The stubborn function didn't stop in time and is reported
+	var shutdownErr *types.ShutdownError
+	if err := appMgr.Shutdown(true); !errors.As(err, &shutdownErr) {
+		t.Fatalf("Expected a ShutdownError for the stubborn function, got %v", err)
+	}
+	if len(shutdownErr.Functions) != 1 || shutdownErr.Functions[0].FunctionName != "stubborn" {
+		t.Errorf("Expected only the stubborn function to be reported, got %+v", shutdownErr.Functions)
+	}
+
+	// The app scope only brackets its children, the local drain completes once the stubborn routine is force removed
+	expected := map[stageEvent][]types.ShutdownStage{
+		{scope: "stage-app/"}:            {types.ShutdownStageBeginDrain, types.ShutdownStageDone},
+		{scope: "stage-app/stage-local"}: {types.ShutdownStageBeginDrain, types.ShutdownStageGracefulComplete, types.ShutdownStageDone},
+		{scope: "stage-app/stage-local", function: "cooperative"}: {
+			types.ShutdownStageBeginDrain, types.ShutdownStageCancel, types.ShutdownStageGracefulComplete, types.ShutdownStageDone,
+		},
+		{scope: "stage-app/stage-local", function: "stubborn"}: {
+			types.ShutdownStageBeginDrain, types.ShutdownStageCancel, types.ShutdownStageForceRemove, types.ShutdownStageDone,
+		},
+	}
+
+	for scope, want := range expected {
+		got, _ := recorder.stagesOf(scope.scope, scope.function)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Scope %s %q: expected stages %v, got %v", scope.scope, scope.function, want, got)
+		}
+	}
+
+	// Scopes nest: app brackets local, local brackets its functions
+	_, app := recorder.stagesOf("stage-app/", "")
+	_, local := recorder.stagesOf("stage-app/stage-local", "")
+	_, cooperative := recorder.stagesOf("stage-app/stage-local", "cooperative")
+	_, stubborn := recorder.stagesOf("stage-app/stage-local", "stubborn")
+	if len(app) == 2 && len(local) == 3 {
+		if app[0] > local[0] || app[1] < local[2] {
+			t.Errorf("App stages %v should bracket local stages %v", app, local)
+		}
+		for _, function := range [][]int{cooperative, stubborn} {
+			if len(function) > 0 && (function[0] < local[0] || function[len(function)-1] > local[1]) {
+				t.Errorf("Function stages %v should fire between local begin-drain and graceful-complete %v", function, local)
+			}
+		}
+	}
+
+	// The force removed routine is untracked, wait on its done channel before the state is reset under it
+	close(release)
+	for _, routine := range stubbornRoutines {
+		<-routine.DoneChan()
+	}
+
+	// Unsafe shutdown skips the drain and goes straight to cancel and force-remove
+	Common.ResetGlobalState()
+	gm = Global.NewGlobalManager()
+	if _, err := gm.Init(); err != nil {
+		t.Fatalf("Init() failed: %v", err)
+	}
+	if _, err := App.NewAppManager("stage-app").CreateApp(); err != nil {
+		t.Fatalf("CreateApp() failed: %v", err)
+	}
+	localMgr = Local.NewLocalManager("stage-app", "stage-local")
+	if _, err := localMgr.CreateLocal("stage-local"); err != nil {
+		t.Fatalf("CreateLocal() failed: %v", err)
+	}
+	localMgr.Go("cooperative", func(ctx context.Context) error {
+		<-ctx.Done()
+		return nil
+	})
+	unsafeRoutines, _ := localMgr.GetRoutinesByFunctionName("cooperative")
+
+	recorder = &stageRecorder{}
+	types.OnShutdownStage(recorder.record)
+	if err := localMgr.Shutdown(false); err != nil {
+		t.Fatalf("Shutdown() failed: %v", err)
+	}
+	for _, routine := range unsafeRoutines {
+		<-routine.DoneChan()
+	}
+	got, _ := recorder.stagesOf("stage-app/stage-local", "")
+	want := []types.ShutdownStage{types.ShutdownStageBeginDrain, types.ShutdownStageCancel, types.ShutdownStageForceRemove, types.ShutdownStageDone}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Unsafe shutdown: expected stages %v, got %v", want, got)
+	}
+
+	fmt.Println("✓ Shutdown stages fired in the documented order")
+}
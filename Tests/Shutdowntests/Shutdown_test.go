@@ -3,6 +3,7 @@ package Shutdowntests
 import (
 	"context"
 	"fmt"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -11,6 +12,7 @@ import (
 	"github.com/neerajchowdary889/GoRoutinesManager/Manager/Global"
 	"github.com/neerajchowdary889/GoRoutinesManager/Manager/Local"
 	"github.com/neerajchowdary889/GoRoutinesManager/Tests/Common"
+	"github.com/neerajchowdary889/GoRoutinesManager/types"
 )
 
 func TestLocalManager_SafeShutdown(t *testing.T) {
@@ -289,3 +291,203 @@ func TestGlobalManager_UnsafeShutdown(t *testing.T) {
 		t.Error("Unsafe shutdown should be immediate")
 	}
 }
+
+func TestLocalManager_SafeShutdown_AnnouncesDeadline(t *testing.T) {
+	fmt.Println("\n=== TestLocalManager_SafeShutdown_AnnouncesDeadline ===")
+	Common.ResetGlobalState()
+
+	gm := Global.NewGlobalManager()
+	if _, err := gm.Init(); err != nil {
+		t.Fatalf("Init() failed: %v", err)
+	}
+	if _, err := gm.UpdateMetadata(Global.SET_SHUTDOWN_TIMEOUT, 2*time.Second); err != nil {
+		t.Fatalf("UpdateMetadata() failed: %v", err)
+	}
+	// ShutdownTimeout is process wide, restore the default for the other tests
+	defer gm.UpdateMetadata(Global.SET_SHUTDOWN_TIMEOUT, 10*time.Second)
+
+	appMgr := App.NewAppManager("test-app")
+	if _, err := appMgr.CreateApp(); err != nil {
+		t.Fatalf("CreateApp() failed: %v", err)
+	}
+	localMgr := Local.NewLocalManager("test-app", "test-local")
+	if _, err := localMgr.CreateLocal("test-local"); err != nil {
+		t.Fatalf("CreateLocal() failed: %v", err)
+	}
+
+	type observed struct {
+		ctxDeadline    time.Time
+		ctxOk          bool
+		helperDeadline time.Time
+		helperOk       bool
+		beforeOk       bool
+	}
+	result := make(chan observed, 1)
+	localMgr.Go("flusher", func(ctx context.Context) error {
+		var o observed
+		_, o.beforeOk = Local.ShutdownDeadline(ctx)
+		<-ctx.Done()
+		o.ctxDeadline, o.ctxOk = ctx.Deadline()
+		o.helperDeadline, o.helperOk = Local.ShutdownDeadline(ctx)
+		result <- o
+		return nil
+	}, Local.AddToWaitGroup("flusher"))
+
+	// Let the worker record that no deadline is set while running normally
+	time.Sleep(50 * time.Millisecond)
+
+	startTime := time.Now()
+	if err := localMgr.Shutdown(true); err != nil {
+		t.Fatalf("Shutdown() failed: %v", err)
+	}
+
+	o := <-result
+	if o.beforeOk {
+		t.Error("No shutdown deadline should be set before shutdown")
+	}
+	if !o.ctxOk || !o.helperOk {
+		t.Fatalf("Worker should observe the shutdown deadline (ctx: %v, helper: %v)", o.ctxOk, o.helperOk)
+	}
+	if !o.ctxDeadline.Equal(o.helperDeadline) {
+		t.Errorf("ctx.Deadline() %v and ShutdownDeadline() %v should agree", o.ctxDeadline, o.helperDeadline)
+	}
+	budget := o.ctxDeadline.Sub(startTime)
+	if budget < 1900*time.Millisecond || budget > 2100*time.Millisecond {
+		t.Errorf("Expected a ~2s shutdown budget, got %v", budget)
+	}
+	fmt.Printf("✓ Worker observed a shutdown budget of %v\n", budget)
+
+	// Contexts that weren't handed out by Go carry no shutdown deadline
+	if _, ok := Local.ShutdownDeadline(context.Background()); ok {
+		t.Error("Background context should not carry a shutdown deadline")
+	}
+}
+
+func TestGlobalManager_ShutdownWithProgress(t *testing.T) {
+	fmt.Println("\n=== TestGlobalManager_ShutdownWithProgress ===")
+	Common.ResetGlobalState()
+
+	// 2 apps with 2 local managers each, every local running 2 workers
+	for appNum := 1; appNum <= 2; appNum++ {
+		appName := fmt.Sprintf("app%d", appNum)
+		if _, err := App.NewAppManager(appName).CreateApp(); err != nil {
+			t.Fatalf("CreateApp() failed: %v", err)
+		}
+		for localNum := 1; localNum <= 2; localNum++ {
+			localName := fmt.Sprintf("local%d", localNum)
+			localMgr := Local.NewLocalManager(appName, localName)
+			if _, err := localMgr.CreateLocal(localName); err != nil {
+				t.Fatalf("CreateLocal() failed: %v", err)
+			}
+			for i := 0; i < 2; i++ {
+				if err := localMgr.Go("worker", func(ctx context.Context) error {
+					<-ctx.Done()
+					return ctx.Err()
+				}); err != nil {
+					t.Fatalf("Go() failed: %v", err)
+				}
+			}
+		}
+	}
+
+	globalMgr := Global.NewGlobalManager()
+	progress, err := globalMgr.ShutdownWithProgress(false)
+	if err != nil {
+		t.Fatalf("ShutdownWithProgress() failed: %v", err)
+	}
+
+	var events []types.ShutdownProgress
+	timeout := time.After(5 * time.Second)
+	for done := false; !done; {
+		select {
+		case p, ok := <-progress:
+			if !ok {
+				done = true
+				break
+			}
+			events = append(events, p)
+		case <-timeout:
+			t.Fatal("Timed out waiting for the progress channel to close")
+		}
+	}
+
+	first, last := events[0], events[len(events)-1]
+	if first.AppsTotal != 2 || first.LocalsTotal != 4 || first.RoutinesTotal != 8 || first.AppsRemaining != 2 {
+		t.Errorf("Unexpected initial snapshot: %+v", first)
+	}
+	for i := 1; i < len(events); i++ {
+		prev, cur := events[i-1], events[i]
+		if cur.AppsRemaining > prev.AppsRemaining || cur.LocalsDrained < prev.LocalsDrained || cur.RoutinesCancelled < prev.RoutinesCancelled {
+			t.Errorf("Progress moved backwards: %+v -> %+v", prev, cur)
+		}
+	}
+	if !last.Done || last.Err != nil || last.AppsRemaining != 0 || last.LocalsDrained != 4 || last.RoutinesCancelled != 8 {
+		t.Errorf("Unexpected final snapshot: %+v", last)
+	}
+	fmt.Printf("✓ %d progress snapshots, final: %+v\n", len(events), last)
+}
+
+func TestGlobalManager_ShutdownOrdered(t *testing.T) {
+	fmt.Println("\n=== TestGlobalManager_ShutdownOrdered ===")
+	Common.ResetGlobalState()
+
+	globalMgr := Global.NewGlobalManager()
+	if _, err := globalMgr.Init(); err != nil {
+		t.Fatalf("Init() failed: %v", err)
+	}
+
+	// Each app's worker notes when it was cancelled and when it finished winding down
+	var mu sync.Mutex
+	cancelledAt := make(map[string]time.Time)
+	finishedAt := make(map[string]time.Time)
+	for _, appName := range []string{"api", "ingest", "workers"} {
+		if _, err := App.NewAppManager(appName).CreateApp(); err != nil {
+			t.Fatalf("CreateApp() failed: %v", err)
+		}
+		localMgr := Local.NewLocalManager(appName, "main")
+		if _, err := localMgr.CreateLocal("main"); err != nil {
+			t.Fatalf("CreateLocal() failed: %v", err)
+		}
+		appName := appName
+		if err := localMgr.Go("worker", func(ctx context.Context) error {
+			<-ctx.Done()
+			mu.Lock()
+			cancelledAt[appName] = time.Now()
+			mu.Unlock()
+			time.Sleep(30 * time.Millisecond)
+			mu.Lock()
+			finishedAt[appName] = time.Now()
+			mu.Unlock()
+			return nil
+		}, Local.AddToWaitGroup("worker")); err != nil {
+			t.Fatalf("Go() failed: %v", err)
+		}
+	}
+
+	// "workers" isn't named, it goes down after the ordered apps; unknown names are skipped
+	if err := globalMgr.ShutdownOrdered(true, []string{"api", "missing-app", "ingest"}); err != nil {
+		t.Fatalf("ShutdownOrdered() failed: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	sequence := []string{"api", "ingest", "workers"}
+	for _, appName := range sequence {
+		if finishedAt[appName].IsZero() {
+			t.Fatalf("App %s's worker never finished", appName)
+		}
+	}
+	for i := 1; i < len(sequence); i++ {
+		previous, next := sequence[i-1], sequence[i]
+		if !finishedAt[previous].Before(cancelledAt[next]) {
+			t.Errorf("Expected %s to finish shutting down (%v) before %s was cancelled (%v)",
+				previous, finishedAt[previous].Format(time.StampMicro), next, cancelledAt[next].Format(time.StampMicro))
+		}
+	}
+	select {
+	case <-globalMgr.Done():
+	default:
+		t.Error("Done() not closed after ShutdownOrdered()")
+	}
+	fmt.Println("✓ Apps shut down in the given order, unknown names skipped")
+}
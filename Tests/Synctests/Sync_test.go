@@ -0,0 +1,123 @@
+package Synctests
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/neerajchowdary889/GoRoutinesManager/Helper/Sync"
+	"github.com/neerajchowdary889/GoRoutinesManager/Manager/App"
+	"github.com/neerajchowdary889/GoRoutinesManager/Manager/Interface"
+	"github.com/neerajchowdary889/GoRoutinesManager/Manager/Local"
+	"github.com/neerajchowdary889/GoRoutinesManager/Tests/Common"
+)
+
+func setup(t *testing.T) Interface.LocalGoroutineManagerInterface {
+	t.Helper()
+	Common.ResetGlobalState()
+
+	if _, err := App.NewAppManager("test-app").CreateApp(); err != nil {
+		t.Fatalf("CreateApp() failed: %v", err)
+	}
+	localMgr := Local.NewLocalManager("test-app", "test-local")
+	if _, err := localMgr.CreateLocal("test-local"); err != nil {
+		t.Fatalf("CreateLocal() failed: %v", err)
+	}
+	return localMgr
+}
+
+// Assert on running routines without sleeping: hold them on a gate, wait for them to start, then release
+func TestSync_GateAndWaitStarted(t *testing.T) {
+	fmt.Println("\n=== TestSync_GateAndWaitStarted ===")
+	localMgr := setup(t)
+	h := Sync.New(t, localMgr)
+	gate := Sync.NewGate()
+
+	const workers = 3
+	var finished atomic.Int32
+	for i := 0; i < workers; i++ {
+		h.Go("worker", func(ctx context.Context) error {
+			defer finished.Add(1)
+			return gate.Wait(ctx)
+		})
+	}
+
+	h.WaitStarted("worker", workers)
+	if got := localMgr.GetFunctionGoroutineCount("worker"); got != workers {
+		t.Errorf("Expected %d running routines, got %d", workers, got)
+	}
+	if got := finished.Load(); got != 0 {
+		t.Errorf("No routine should finish before the gate opens, %d did", got)
+	}
+	fmt.Printf("✓ %d routines running, held on the gate\n", workers)
+
+	gate.Open()
+	h.WaitDone("worker")
+	if got := finished.Load(); got != workers {
+		t.Errorf("Expected %d finished routines, got %d", workers, got)
+	}
+	if got := localMgr.GetFunctionGoroutineCount("worker"); got != 0 {
+		t.Errorf("Expected no tracked routines after WaitDone, got %d", got)
+	}
+	fmt.Println("✓ All routines finished after the gate opened")
+}
+
+// Cancellation is observable deterministically too: the worker reports ctx.Err() through the gate
+func TestSync_CancelWhileHeld(t *testing.T) {
+	fmt.Println("\n=== TestSync_CancelWhileHeld ===")
+	localMgr := setup(t)
+	h := Sync.New(t, localMgr)
+	gate := Sync.NewGate()
+
+	var workerErr atomic.Value
+	h.Go("held", func(ctx context.Context) error {
+		err := gate.Wait(ctx)
+		workerErr.Store(fmt.Sprint(err))
+		return err
+	}, Local.WithTimeout(50*time.Millisecond))
+
+	h.WaitStarted("held", 1)
+	h.WaitDone("held")
+	if got := workerErr.Load(); got != context.DeadlineExceeded.Error() {
+		t.Errorf("Expected the worker to see %v, got %v", context.DeadlineExceeded, got)
+	}
+	fmt.Println("✓ Timed out worker observed without sleeping")
+}
+
+func TestLocalManager_WaitForFunctionStarted(t *testing.T) {
+	fmt.Println("\n=== TestLocalManager_WaitForFunctionStarted ===")
+	localMgr := setup(t)
+
+	if localMgr.WaitForFunctionStartedWithTimeout("never", 1, 20*time.Millisecond) {
+		t.Error("Expected a timeout for a function that never started")
+	}
+
+	// Routines outside any wait group are still waited for by WaitForFunction
+	release := make(chan struct{})
+	var done atomic.Bool
+	if err := localMgr.Go("ungrouped", func(ctx context.Context) error {
+		<-release
+		done.Store(true)
+		return nil
+	}); err != nil {
+		t.Fatalf("Go() failed: %v", err)
+	}
+	if err := localMgr.WaitForFunctionStarted("ungrouped", 1); err != nil {
+		t.Fatalf("WaitForFunctionStarted() failed: %v", err)
+	}
+	close(release)
+	if err := localMgr.WaitForFunction("ungrouped"); err != nil {
+		t.Fatalf("WaitForFunction() failed: %v", err)
+	}
+	if !done.Load() {
+		t.Error("WaitForFunction returned before the ungrouped routine finished")
+	}
+
+	// Starts are cumulative, finished routines still count
+	if !localMgr.WaitForFunctionStartedWithTimeout("ungrouped", 1, 20*time.Millisecond) {
+		t.Error("A finished routine should still count as started")
+	}
+	fmt.Println("✓ Start and completion observed without sleeping")
+}
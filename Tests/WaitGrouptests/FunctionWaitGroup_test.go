@@ -2,14 +2,19 @@ package WaitGrouptests
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/neerajchowdary889/GoRoutinesManager/Manager/App"
+	"github.com/neerajchowdary889/GoRoutinesManager/Manager/Errors"
+	"github.com/neerajchowdary889/GoRoutinesManager/Manager/Interface"
 	"github.com/neerajchowdary889/GoRoutinesManager/Manager/Local"
 	"github.com/neerajchowdary889/GoRoutinesManager/Tests/Common"
+	"github.com/neerajchowdary889/GoRoutinesManager/types"
 )
 
 
@@ -393,3 +398,439 @@ func TestFunctionWaitGroup_Timeout(t *testing.T) {
 		fmt.Println("✓ Completed within timeout")
 	}
 }
+
+func TestFunctionWaitGroup_List(t *testing.T) {
+	fmt.Println("\n=== TestFunctionWaitGroup_List ===")
+	Common.ResetGlobalState()
+
+	appMgr := App.NewAppManager("test-app")
+	if _, err := appMgr.CreateApp(); err != nil {
+		t.Fatalf("CreateApp() failed: %v", err)
+	}
+	localMgr := Local.NewLocalManager("test-app", "test-local")
+	if _, err := localMgr.CreateLocal("test-local"); err != nil {
+		t.Fatalf("CreateLocal() failed: %v", err)
+	}
+
+	blockOnCtx := func(ctx context.Context) error {
+		<-ctx.Done()
+		return nil
+	}
+	releaseWriter := make(chan struct{})
+	spawns := []struct {
+		name   string
+		count  int
+		worker func(ctx context.Context) error
+	}{
+		{name: "reader", count: 2, worker: blockOnCtx},
+		// Ignores cancellation so its shutdown stays in progress
+		{name: "writer", count: 1, worker: func(ctx context.Context) error {
+			<-releaseWriter
+			return nil
+		}},
+	}
+	for _, spawn := range spawns {
+		for i := 0; i < spawn.count; i++ {
+			if err := localMgr.Go(spawn.name, spawn.worker, Local.AddToWaitGroup(spawn.name)); err != nil {
+				t.Fatalf("Go() failed: %v", err)
+			}
+		}
+	}
+
+	infos := localMgr.GetFunctionWaitGroups()
+	want := map[string]int64{"reader": 2, "writer": 1}
+	if len(infos) != len(want) {
+		t.Fatalf("Expected %d function wait groups, got %+v", len(want), infos)
+	}
+	for i, info := range infos {
+		if i > 0 && infos[i-1].FunctionName > info.FunctionName {
+			t.Errorf("Expected groups sorted by function name, got %+v", infos)
+		}
+		if pending, ok := want[info.FunctionName]; !ok || info.Pending != pending {
+			t.Errorf("Expected %s to have %d pending, got %+v", info.FunctionName, pending, info)
+		}
+		if info.Draining {
+			t.Errorf("No group should be draining yet, got %+v", info)
+		}
+	}
+	fmt.Println("✓ Pending counts match the spawned routines")
+
+	shutdownDone := make(chan error, 1)
+	go func() {
+		shutdownDone <- localMgr.ShutdownFunction("writer", 2*time.Second)
+	}()
+
+	deadline := time.Now().Add(time.Second)
+	draining := false
+	for !draining && time.Now().Before(deadline) {
+		for _, info := range localMgr.GetFunctionWaitGroups() {
+			if info.FunctionName == "writer" && info.Draining {
+				draining = true
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if !draining {
+		t.Error("Expected the writer group to be draining during its shutdown")
+	}
+	fmt.Println("✓ Writer group reported as draining")
+
+	close(releaseWriter)
+	if err := <-shutdownDone; err != nil {
+		t.Fatalf("ShutdownFunction() failed: %v", err)
+	}
+	for _, info := range localMgr.GetFunctionWaitGroups() {
+		if info.FunctionName == "writer" {
+			t.Errorf("Writer group should be gone after its shutdown, got %+v", info)
+		}
+	}
+	localMgr.Shutdown(false)
+}
+
+func TestFunctionWaitGroup_AutoCleanup(t *testing.T) {
+	fmt.Println("\n=== TestFunctionWaitGroup_AutoCleanup ===")
+	Common.ResetGlobalState()
+
+	appMgr := App.NewAppManager("test-app")
+	if _, err := appMgr.CreateApp(); err != nil {
+		t.Fatalf("CreateApp() failed: %v", err)
+	}
+	localMgr := Local.NewLocalManager("test-app", "test-local")
+	if _, err := localMgr.CreateLocal("test-local"); err != nil {
+		t.Fatalf("CreateLocal() failed: %v", err)
+	}
+
+	// A long-lived group must survive while its routine runs
+	if err := localMgr.Go("long-lived", func(ctx context.Context) error {
+		<-ctx.Done()
+		return nil
+	}, Local.AddToWaitGroup("long-lived")); err != nil {
+		t.Fatalf("Go() failed: %v", err)
+	}
+
+	// An explicitly created group is kept once its routines finish
+	if _, err := localMgr.NewFunctionWaitGroup(context.Background(), "explicit"); err != nil {
+		t.Fatalf("NewFunctionWaitGroup() failed: %v", err)
+	}
+	if err := localMgr.Go("explicit", func(ctx context.Context) error {
+		return nil
+	}, Local.AddToWaitGroup("explicit")); err != nil {
+		t.Fatalf("Go() failed: %v", err)
+	}
+	if err := localMgr.WaitForFunction("explicit"); err != nil {
+		t.Fatalf("WaitForFunction() failed: %v", err)
+	}
+
+	const uniqueNames = 200
+	for i := 0; i < uniqueNames; i++ {
+		name := fmt.Sprintf("job-%d", i)
+		if err := localMgr.Go(name, func(ctx context.Context) error {
+			return nil
+		}, Local.AddToWaitGroup(name)); err != nil {
+			t.Fatalf("Go() failed: %v", err)
+		}
+	}
+	for i := 0; i < uniqueNames; i++ {
+		if err := localMgr.WaitForFunction(fmt.Sprintf("job-%d", i)); err != nil {
+			t.Fatalf("WaitForFunction() failed: %v", err)
+		}
+	}
+
+	// The group is reclaimed right after the last routine signals it, give the cleanup a moment
+	deadline := time.Now().Add(time.Second)
+	var infos []types.FunctionWaitGroupInfo
+	for time.Now().Before(deadline) {
+		infos = localMgr.GetFunctionWaitGroups()
+		if len(infos) == 2 {
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if len(infos) != 2 || infos[0].FunctionName != "explicit" || infos[0].Pending != 0 ||
+		infos[1].FunctionName != "long-lived" || infos[1].Pending != 1 {
+		t.Fatalf("Expected only the explicit and long-lived groups to remain, got %d groups: %+v", len(infos), infos)
+	}
+	localManager, err := types.GetLocalManager("test-app", "test-local")
+	if err != nil {
+		t.Fatalf("GetLocalManager() failed: %v", err)
+	}
+	if _, err := localManager.GetFunctionWg("explicit"); err != nil {
+		t.Errorf("Explicit group should be kept, got %v", err)
+	}
+	fmt.Printf("✓ %d empty wait groups reclaimed, the explicit one kept\n", uniqueNames)
+
+	// A reclaimed group still waits fine, a function that never ran has no group to wait on
+	if err := localMgr.WaitForFunction("job-1"); err != nil {
+		t.Errorf("WaitForFunction() on a reclaimed group failed: %v", err)
+	}
+	if err := localMgr.WaitForFunction("never-spawned"); !errors.Is(err, Errors.ErrFunctionWgNotFound) {
+		t.Errorf("Expected ErrFunctionWgNotFound for a function that never ran, got %v", err)
+	}
+	fmt.Println("✓ WaitForFunction tells reclaimed groups from missing ones")
+
+	// A reclaimed group is recreated on the next spawn
+	done := make(chan struct{})
+	if err := localMgr.Go("job-0", func(ctx context.Context) error {
+		<-done
+		return nil
+	}, Local.AddToWaitGroup("job-0")); err != nil {
+		t.Fatalf("Go() failed: %v", err)
+	}
+	if localMgr.WaitForFunctionWithTimeout("job-0", 50*time.Millisecond) {
+		t.Error("Recreated group should wait for its running routine")
+	}
+	close(done)
+	if !localMgr.WaitForFunctionWithTimeout("job-0", time.Second) {
+		t.Error("Recreated group should complete once its routine returns")
+	}
+	fmt.Println("✓ Reclaimed group recreated on demand")
+	localMgr.Shutdown(false)
+}
+
+func TestFunctionWaitGroup_ConcurrentCreate(t *testing.T) {
+	fmt.Println("\n=== TestFunctionWaitGroup_ConcurrentCreate ===")
+	Common.ResetGlobalState()
+
+	appMgr := App.NewAppManager("test-app")
+	if _, err := appMgr.CreateApp(); err != nil {
+		t.Fatalf("CreateApp() failed: %v", err)
+	}
+	localMgr := Local.NewLocalManager("test-app", "test-local")
+	if _, err := localMgr.CreateLocal("test-local"); err != nil {
+		t.Fatalf("CreateLocal() failed: %v", err)
+	}
+
+	const callers = 50
+	start := make(chan struct{})
+	var ready sync.WaitGroup
+
+	// Concurrent callers asking for a brand new group must all get the same one
+	groups := make([]*sync.WaitGroup, callers)
+	var createErrors atomic.Int32
+	for i := 0; i < callers; i++ {
+		ready.Add(1)
+		go func(i int) {
+			defer ready.Done()
+			<-start
+			wg, err := localMgr.NewFunctionWaitGroup(context.Background(), "fresh")
+			if err != nil {
+				createErrors.Add(1)
+				return
+			}
+			groups[i] = wg
+		}(i)
+	}
+
+	// Concurrent spawns into a new function must never fail on the wait group
+	release := make(chan struct{})
+	var spawnErrors, started atomic.Int32
+	for i := 0; i < callers; i++ {
+		ready.Add(1)
+		go func() {
+			defer ready.Done()
+			<-start
+			err := localMgr.Go("burst", func(ctx context.Context) error {
+				started.Add(1)
+				<-release
+				return nil
+			}, Local.AddToWaitGroup("burst"))
+			if err != nil {
+				spawnErrors.Add(1)
+			}
+		}()
+	}
+	close(start)
+	ready.Wait()
+
+	if n := createErrors.Load(); n != 0 {
+		t.Fatalf("Expected no NewFunctionWaitGroup() failures, got %d", n)
+	}
+	for i, wg := range groups {
+		if wg != groups[0] {
+			t.Fatalf("Caller %d got a different wait group instance", i)
+		}
+	}
+	fmt.Printf("✓ %d concurrent callers shared one wait group\n", callers)
+
+	if n := spawnErrors.Load(); n != 0 {
+		t.Fatalf("Expected no spawn failures, got %d", n)
+	}
+	if localMgr.WaitForFunctionWithTimeout("burst", 50*time.Millisecond) {
+		t.Error("Group should wait for every spawned routine")
+	}
+	close(release)
+	if !localMgr.WaitForFunctionWithTimeout("burst", time.Second) {
+		t.Fatal("Group should complete once all routines return")
+	}
+	if n := started.Load(); n != callers {
+		t.Errorf("Expected %d routines to run, got %d", callers, n)
+	}
+	fmt.Printf("✓ %d concurrent spawns joined one wait group without failures\n", callers)
+
+	// A missing local manager is the only failure left, and it says so
+	missing := Local.NewLocalManager("test-app", "missing-local")
+	if _, err := missing.NewFunctionWaitGroup(context.Background(), "fresh"); !errors.Is(err, Errors.ErrLocalManagerNotFound) {
+		t.Errorf("Expected ErrLocalManagerNotFound, got %v", err)
+	}
+	fmt.Println("✓ Missing local manager reported")
+	localMgr.Shutdown(false)
+}
+
+func TestWaitForAll_MixedWorkers(t *testing.T) {
+	fmt.Println("\n=== TestWaitForAll_MixedWorkers ===")
+	Common.ResetGlobalState()
+
+	appMgr := App.NewAppManager("test-app")
+	if _, err := appMgr.CreateApp(); err != nil {
+		t.Fatalf("CreateApp() failed: %v", err)
+	}
+	localMgr := Local.NewLocalManager("test-app", "test-local")
+	if _, err := localMgr.CreateLocal("test-local"); err != nil {
+		t.Fatalf("CreateLocal() failed: %v", err)
+	}
+
+	// Fast and slow workers under different functions, some without a function wait group
+	var finished atomic.Int32
+	spawn := func(name string, d time.Duration, opts ...Interface.GoroutineOption) {
+		if err := localMgr.Go(name, func(ctx context.Context) error {
+			time.Sleep(d)
+			finished.Add(1)
+			return nil
+		}, opts...); err != nil {
+			t.Fatalf("Go() failed: %v", err)
+		}
+	}
+	for i := 0; i < 5; i++ {
+		spawn("fast", 5*time.Millisecond, Local.AddToWaitGroup("fast"))
+		spawn("untracked", 10*time.Millisecond)
+	}
+	release := make(chan struct{})
+	if err := localMgr.Go("slow", func(ctx context.Context) error {
+		<-release
+		finished.Add(1)
+		return nil
+	}, Local.AddToWaitGroup("slow")); err != nil {
+		t.Fatalf("Go() failed: %v", err)
+	}
+
+	// The slow worker holds everything up
+	if localMgr.WaitForAllWithTimeout(100 * time.Millisecond) {
+		t.Fatal("WaitForAllWithTimeout() returned true while the slow worker runs")
+	}
+	if got := finished.Load(); got != 10 {
+		t.Errorf("Expected the 10 fast workers to be done by now, got %d", got)
+	}
+	fmt.Println("✓ Timed out while the slow worker runs")
+
+	close(release)
+	if !localMgr.WaitForAllWithTimeout(time.Second) {
+		t.Fatal("WaitForAllWithTimeout() timed out after the slow worker was released")
+	}
+	if err := localMgr.WaitForAll(); err != nil {
+		t.Fatalf("WaitForAll() failed: %v", err)
+	}
+	if got := finished.Load(); got != 11 {
+		t.Errorf("Expected all 11 workers to be done, got %d", got)
+	}
+	if count := localMgr.GetGoroutineCount(); count != 0 {
+		t.Errorf("Expected 0 tracked goroutines, got %d", count)
+	}
+	fmt.Println("✓ WaitForAll returned once every worker finished")
+
+	missing := Local.NewLocalManager("test-app", "missing-local")
+	if err := missing.WaitForAll(); !errors.Is(err, Errors.ErrLocalManagerNotFound) {
+		t.Errorf("Expected ErrLocalManagerNotFound, got %v", err)
+	}
+	if missing.WaitForAllWithTimeout(50 * time.Millisecond) {
+		t.Error("WaitForAllWithTimeout() should fail for a missing local manager")
+	}
+}
+
+func TestAppWaitForAll_MultipleLocals(t *testing.T) {
+	fmt.Println("\n=== TestAppWaitForAll_MultipleLocals ===")
+	Common.ResetGlobalState()
+
+	appMgr := App.NewAppManager("test-app")
+	if _, err := appMgr.CreateApp(); err != nil {
+		t.Fatalf("CreateApp() failed: %v", err)
+	}
+	fastLocal := Local.NewLocalManager("test-app", "fast-local")
+	if _, err := fastLocal.CreateLocal("fast-local"); err != nil {
+		t.Fatalf("CreateLocal() failed: %v", err)
+	}
+	slowLocal := Local.NewLocalManager("test-app", "slow-local")
+	if _, err := slowLocal.CreateLocal("slow-local"); err != nil {
+		t.Fatalf("CreateLocal() failed: %v", err)
+	}
+
+	var finished atomic.Int32
+	for i := 0; i < 5; i++ {
+		if err := fastLocal.Go("fast", func(ctx context.Context) error {
+			time.Sleep(10 * time.Millisecond)
+			finished.Add(1)
+			return nil
+		}); err != nil {
+			t.Fatalf("Go() failed: %v", err)
+		}
+	}
+	release := make(chan struct{})
+	if err := slowLocal.Go("slow", func(ctx context.Context) error {
+		<-release
+		finished.Add(1)
+		return nil
+	}); err != nil {
+		t.Fatalf("Go() failed: %v", err)
+	}
+
+	// Local managers created while waiting must not break the wait
+	stopCreating := make(chan struct{})
+	var creators sync.WaitGroup
+	creators.Add(1)
+	go func() {
+		defer creators.Done()
+		for i := 0; ; i++ {
+			select {
+			case <-stopCreating:
+				return
+			default:
+			}
+			name := fmt.Sprintf("late-local-%d", i)
+			Local.NewLocalManager("test-app", name).CreateLocal(name)
+			time.Sleep(time.Millisecond)
+		}
+	}()
+
+	// The slow local manager holds the app up
+	if appMgr.WaitForAllWithTimeout(100 * time.Millisecond) {
+		t.Fatal("WaitForAllWithTimeout() returned true while the slow worker runs")
+	}
+	if got := finished.Load(); got != 5 {
+		t.Errorf("Expected the 5 fast workers to be done by now, got %d", got)
+	}
+	fmt.Println("✓ Timed out while the slow local manager has a running worker")
+
+	close(release)
+	if !appMgr.WaitForAllWithTimeout(time.Second) {
+		t.Fatal("WaitForAllWithTimeout() timed out after the slow worker was released")
+	}
+	if err := appMgr.WaitForAll(); err != nil {
+		t.Fatalf("WaitForAll() failed: %v", err)
+	}
+	close(stopCreating)
+	creators.Wait()
+	if got := finished.Load(); got != 6 {
+		t.Errorf("Expected all 6 workers to be done, got %d", got)
+	}
+	if count := appMgr.GetGoroutineCount(); count != 0 {
+		t.Errorf("Expected 0 tracked goroutines in the app, got %d", count)
+	}
+	fmt.Println("✓ WaitForAll returned once both local managers finished, with local managers created meanwhile")
+
+	missing := App.NewAppManager("missing-app")
+	if err := missing.WaitForAll(); !errors.Is(err, Errors.ErrAppManagerNotFound) {
+		t.Errorf("Expected ErrAppManagerNotFound, got %v", err)
+	}
+	if missing.WaitForAllWithTimeout(50 * time.Millisecond) {
+		t.Error("WaitForAllWithTimeout() should fail for a missing app manager")
+	}
+}
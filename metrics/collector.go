@@ -2,6 +2,7 @@ package metrics
 
 import (
 	"runtime"
+	"sync"
 	"time"
 
 	"github.com/neerajchowdary889/GoRoutinesManager/types"
@@ -12,6 +13,9 @@ type Collector struct {
 	// stopCh is used to signal the collector to stop
 	stopCh chan struct{}
 
+	// doneCh is closed once the collection loop has returned
+	doneCh chan struct{}
+
 	// intervalCh is used to signal interval changes
 	intervalCh chan time.Duration
 
@@ -20,15 +24,57 @@ type Collector struct {
 
 	// currentInterval stores the current interval for comparison
 	currentInterval time.Duration
+
+	// seen holds the label sets exported by the previous collection, to delete the ones that disappear
+	seen *seriesTracker
+}
+
+// seriesTracker remembers which per-app, per-local and per-function series the collector exported
+// It is shared by every collector since the series live in package level metrics,
+// so a temporary collector used by FlushNow still cleans up after the running one
+type seriesTracker struct {
+	mu        sync.Mutex
+	apps      map[string]struct{}
+	locals    map[localSeries]struct{}
+	functions map[functionSeries]struct{}
+	routines  map[routineSeries]struct{}
+}
+
+// localSeries is the label set of a per-local series
+type localSeries struct {
+	appName, localName string
+}
+
+// functionSeries is the label set of a per-function series
+type functionSeries struct {
+	appName, localName, functionName string
+}
+
+// routineSeries is the label set of a per-routine series
+type routineSeries struct {
+	functionSeries
+	routineID string
+}
+
+// collectedSeries is the series tracker shared by all collectors
+var collectedSeries seriesTracker
+
+// reset forgets every tracked series, for when the metrics themselves were recreated
+func (t *seriesTracker) reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.apps, t.locals, t.functions, t.routines = nil, nil, nil, nil
 }
 
 // NewCollector creates a new metrics collector
 func NewCollector() *Collector {
 	return &Collector{
 		stopCh:          make(chan struct{}),
+		doneCh:          make(chan struct{}),
 		intervalCh:      make(chan time.Duration, 1), // Buffered to avoid blocking
 		running:         false,
 		currentInterval: types.UpdateInterval,
+		seen:            &collectedSeries,
 	}
 }
 
@@ -42,13 +88,14 @@ func (c *Collector) Start() {
 	go c.collectLoop()
 }
 
-// Stop stops the metrics collector
+// Stop stops the metrics collector, it returns once a collection in progress has finished
 func (c *Collector) Stop() {
 	if !c.running {
 		return
 	}
 
 	close(c.stopCh)
+	<-c.doneCh
 	c.running = false
 }
 
@@ -69,6 +116,7 @@ func (c *Collector) UpdateInterval(newInterval time.Duration) {
 // collectLoop runs the collection loop with dynamic interval support
 // It observes changes to types.UpdateInterval and updates the ticker accordingly
 func (c *Collector) collectLoop() {
+    defer close(c.doneCh)
     globalMgr, _ := types.GetGlobalManager()
     metadata := globalMgr.GetMetadata()
     
@@ -110,6 +158,10 @@ func (c *Collector) Collect() {
 		return
 	}
 
+	// Collections compare against the previous one, so they must not interleave
+	c.seen.mu.Lock()
+	defer c.seen.mu.Unlock()
+
 	c.collectGlobalMetrics()
 	c.collectAppMetrics()
 	c.collectLocalMetrics()
@@ -152,6 +204,11 @@ func (c *Collector) collectGlobalMetrics() {
 		}
 		GoroutinesTotal.Set(float64(goroutineCount))
 
+		// Breakdown by lifecycle state, every state is reported so a drop to 0 is visible
+		for state, count := range globalMgr.GetStateCounts() {
+			GoroutinesByState.WithLabelValues(state.String()).Set(float64(count))
+		}
+
 		// Get shutdown timeout
 		metadata := globalMgr.GetMetadata()
 		if metadata != nil {
@@ -162,11 +219,16 @@ func (c *Collector) collectGlobalMetrics() {
 		AppManagersTotal.Set(0)
 		LocalManagersTotal.Set(0)
 		GoroutinesTotal.Set(0)
+		GoroutinesByState.Reset()
 	}
 }
 
 // collectAppMetrics collects metrics for each app manager
 func (c *Collector) collectAppMetrics() {
+	// Track which apps we've seen to clean up old metrics
+	seenApps := make(map[string]struct{})
+	defer c.pruneApps(seenApps)
+
 	if !types.IsIntilized().Global() {
 		return
 	}
@@ -178,11 +240,12 @@ func (c *Collector) collectAppMetrics() {
 
 	appManagers := globalMgr.GetAppManagers()
 
-	// Track which apps we've seen to clean up old metrics
-	seenApps := make(map[string]bool)
-
 	for appName, appMgr := range appManagers {
-		seenApps[appName] = true
+		// Apps that opted out of metrics export no series
+		if !appMgr.GetMetricsEnabled() {
+			continue
+		}
+		seenApps[appName] = struct{}{}
 
 		// App is initialized
 		AppInitialized.WithLabelValues(appName).Set(1)
@@ -201,8 +264,23 @@ func (c *Collector) collectAppMetrics() {
 	}
 }
 
+// pruneApps deletes the series of apps exported last time but not seen now, e.g. shut down or opted out
+func (c *Collector) pruneApps(seenApps map[string]struct{}) {
+	for appName := range c.seen.apps {
+		if _, ok := seenApps[appName]; !ok {
+			AppInitialized.DeleteLabelValues(appName)
+			AppLocalManagers.DeleteLabelValues(appName)
+			AppGoroutines.DeleteLabelValues(appName)
+		}
+	}
+	c.seen.apps = seenApps
+}
+
 // collectLocalMetrics collects metrics for each local manager
 func (c *Collector) collectLocalMetrics() {
+	seenLocals := make(map[localSeries]struct{})
+	defer c.pruneLocals(seenLocals)
+
 	if !types.IsIntilized().Global() {
 		return
 	}
@@ -215,9 +293,14 @@ func (c *Collector) collectLocalMetrics() {
 	appManagers := globalMgr.GetAppManagers()
 
 	for appName, appMgr := range appManagers {
+		if !appMgr.GetMetricsEnabled() {
+			continue
+		}
 		localManagers := appMgr.GetLocalManagers()
 
 		for localName, localMgr := range localManagers {
+			seenLocals[localSeries{appName, localName}] = struct{}{}
+
 			// Count goroutines
 			goroutineCount := localMgr.GetRoutineCount()
 			LocalGoroutines.WithLabelValues(appName, localName).Set(float64(goroutineCount))
@@ -229,8 +312,24 @@ func (c *Collector) collectLocalMetrics() {
 	}
 }
 
+// pruneLocals deletes the series of local managers exported last time but not seen now
+func (c *Collector) pruneLocals(seenLocals map[localSeries]struct{}) {
+	for local := range c.seen.locals {
+		if _, ok := seenLocals[local]; !ok {
+			LocalGoroutines.DeleteLabelValues(local.appName, local.localName)
+			LocalFunctionWaitgroups.DeleteLabelValues(local.appName, local.localName)
+		}
+	}
+	c.seen.locals = seenLocals
+}
+
 // collectGoroutineMetrics collects detailed goroutine metrics
 func (c *Collector) collectGoroutineMetrics() {
+	seenFunctions := make(map[functionSeries]struct{})
+	seenRoutines := make(map[routineSeries]struct{})
+	defer c.pruneFunctions(seenFunctions)
+	defer c.pruneRoutines(seenRoutines)
+
 	if !types.IsIntilized().Global() {
 		return
 	}
@@ -246,6 +345,9 @@ func (c *Collector) collectGoroutineMetrics() {
 	functionCounts := make(map[string]map[string]map[string]int) // app -> local -> function -> count
 
 	for appName, appMgr := range appManagers {
+		if !appMgr.GetMetricsEnabled() {
+			continue
+		}
 		localManagers := appMgr.GetLocalManagers()
 
 		for localName, localMgr := range localManagers {
@@ -266,6 +368,7 @@ func (c *Collector) collectGoroutineMetrics() {
 				functionCounts[appName][localName][functionName]++
 
 				// Update goroutine age
+				seenRoutines[routineSeries{functionSeries{appName, localName, functionName}, routine.ID}] = struct{}{}
 				UpdateGoroutineAge(appName, localName, functionName, routine.ID, routine.StartedAt)
 			}
 		}
@@ -275,12 +378,34 @@ func (c *Collector) collectGoroutineMetrics() {
 	for appName, localMap := range functionCounts {
 		for localName, functionMap := range localMap {
 			for functionName, count := range functionMap {
+				seenFunctions[functionSeries{appName, localName, functionName}] = struct{}{}
 				GoroutinesByFunction.WithLabelValues(appName, localName, functionName).Set(float64(count))
 			}
 		}
 	}
 }
 
+// pruneFunctions deletes the series of functions exported last time that have no routines left
+func (c *Collector) pruneFunctions(seenFunctions map[functionSeries]struct{}) {
+	for function := range c.seen.functions {
+		if _, ok := seenFunctions[function]; !ok {
+			GoroutinesByFunction.DeleteLabelValues(function.appName, function.localName, function.functionName)
+		}
+	}
+	c.seen.functions = seenFunctions
+}
+
+// pruneRoutines deletes the age series of routines that finished since the last collection.
+// Routines remove their own series on completion, this catches the ones a collection in flight recreated.
+func (c *Collector) pruneRoutines(seenRoutines map[routineSeries]struct{}) {
+	for routine := range c.seen.routines {
+		if _, ok := seenRoutines[routine]; !ok {
+			GoroutineAge.DeleteLabelValues(routine.appName, routine.localName, routine.functionName, routine.routineID)
+		}
+	}
+	c.seen.routines = seenRoutines
+}
+
 // collectMetadataMetrics collects metrics from metadata
 func (c *Collector) collectMetadataMetrics() {
     if !types.IsIntilized().Global() {
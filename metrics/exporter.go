@@ -2,13 +2,20 @@ package metrics
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
-	"log"
+	"io"
+	"net"
 	"net/http"
 	"sync"
+	"time"
 
+	"github.com/neerajchowdary889/GoRoutinesManager/Logging"
 	"github.com/neerajchowdary889/GoRoutinesManager/types"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
 )
 
 var (
@@ -47,9 +54,81 @@ func StartMetricsServer(addr string) error {
 	}
 	collectorLock.Unlock()
 
+	metricsServer = newMetricsServer(addr)
+
+	// Start server in a goroutine
+	go func(server *http.Server) {
+		Logging.Info("starting metrics server", "addr", addr)
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			Logging.Error("metrics server error", "error", err)
+		}
+	}(metricsServer)
+
+	return nil
+}
+
+// StartMetricsServerReady is StartMetricsServer for callers that need the server up when it returns.
+// The listener is created synchronously, so bind errors such as a port already in use are returned
+// right away, and it only returns once the server answers on /health, or fails after timeout.
+func StartMetricsServerReady(addr string, timeout time.Duration) error {
+	serverLock.Lock()
+	defer serverLock.Unlock()
+
+	if metricsServer != nil {
+		return fmt.Errorf("metrics server is already running")
+	}
+
+	// Bind before anything else so a failed start leaves nothing running
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("metrics server: %w", err)
+	}
+
+	// StartCollector initializes the metrics and is a no-op if the collector already runs
+	StartCollector()
+
+	server := newMetricsServer(addr)
+	go func() {
+		Logging.Info("starting metrics server", "addr", listener.Addr().String())
+		if err := server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			Logging.Error("metrics server error", "error", err)
+		}
+	}()
+
+	if err := waitForHealth(listener.Addr().String(), timeout); err != nil {
+		server.Close()
+		return err
+	}
+
+	metricsServer = server
+	return nil
+}
+
+// waitForHealth polls /health on addr until it answers 200 or the timeout passes
+func waitForHealth(addr string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	client := &http.Client{Timeout: timeout}
+	for {
+		resp, err := client.Get("http://" + addr + "/health")
+		if err == nil {
+			resp.Body.Close()
+			if resp.StatusCode == http.StatusOK {
+				return nil
+			}
+			err = fmt.Errorf("unexpected status %d", resp.StatusCode)
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("metrics server not ready after %v: %w", timeout, err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// newMetricsServer builds the metrics HTTP server with all its endpoints
+func newMetricsServer(addr string) *http.Server {
 	// Create HTTP server
 	mux := http.NewServeMux()
-	mux.Handle("/metrics", promhttp.Handler())
+	mux.Handle("/metrics", metricsHandler())
 
 	// Add a health check endpoint
 	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
@@ -57,6 +136,9 @@ func StartMetricsServer(addr string) error {
 		w.Write([]byte("OK"))
 	})
 
+	// Add the live configuration endpoint
+	mux.Handle("/config", ConfigHandler())
+
 	// Add a root endpoint with information
 	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "text/html")
@@ -71,25 +153,16 @@ func StartMetricsServer(addr string) error {
     <h1>GoRoutinesManager Metrics Exporter</h1>
     <p>Prometheus metrics are available at <a href="/metrics">/metrics</a></p>
     <p>Health check is available at <a href="/health">/health</a></p>
+    <p>Live configuration is available at <a href="/config">/config</a></p>
 </body>
 </html>
 		`))
 	})
 
-	metricsServer = &http.Server{
+	return &http.Server{
 		Addr:    addr,
 		Handler: mux,
 	}
-
-	// Start server in a goroutine
-	go func(server *http.Server) {
-		log.Printf("Starting metrics server on %s", addr)
-		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Printf("Metrics server error: %v", err)
-		}
-	}(metricsServer)
-
-	return nil
 }
 
 // UpdateMetricsUpdateInterval updates the metrics collection interval dynamically
@@ -131,11 +204,143 @@ func StopMetricsServer(ctx context.Context) error {
 
 // GetMetricsHandler returns the HTTP handler for Prometheus metrics
 // This allows users to integrate metrics into their own HTTP server
+// It serves the registry given to InitMetricsWithRegistry, or the default one
 func GetMetricsHandler() http.Handler {
 	// Initialize metrics if not already done
 	InitMetrics()
 
-	return promhttp.Handler()
+	return metricsHandler()
+}
+
+// metricsHandler serves the registry the built-in metrics live in
+// The default registry keeps promhttp.Handler, which also instruments the handler itself
+func metricsHandler() http.Handler {
+	if gatherer == prometheus.DefaultGatherer {
+		return promhttp.Handler()
+	}
+	return promhttp.HandlerFor(gatherer, promhttp.HandlerOpts{})
+}
+
+// WriteTo writes the current metrics in the Prometheus text exposition format to w,
+// the same output /metrics serves, without needing an HTTP server.
+// Useful for batch jobs that dump their metrics to a file or log on exit.
+func WriteTo(w io.Writer) error {
+	// Initialize metrics if not already done
+	InitMetrics()
+
+	families, err := gatherer.Gather()
+	if err != nil {
+		return fmt.Errorf("metrics: gather: %w", err)
+	}
+
+	encoder := expfmt.NewEncoder(w, expfmt.NewFormat(expfmt.TypeTextPlain))
+	for _, family := range families {
+		if err := encoder.Encode(family); err != nil {
+			return fmt.Errorf("metrics: encode %s: %w", family.GetName(), err)
+		}
+	}
+	if closer, ok := encoder.(expfmt.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// ConfigHandler returns an HTTP handler that serves the live global metadata as JSON
+// Durations are rendered as strings (e.g. "10s"), limits of 0 mean unlimited
+// Responds with 500 if the global manager isn't initialized yet
+func ConfigHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		globalMgr, err := types.GetGlobalManager()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		metadata := globalMgr.GetMetadata()
+		if metadata == nil {
+			http.Error(w, "metadata not initialized", http.StatusInternalServerError)
+			return
+		}
+
+		config := map[string]interface{}{
+			"metrics_enabled":      metadata.GetMetrics(),
+			"metrics_url":          metadata.GetMetricsURL(),
+			"max_routines":         metadata.GetMaxRoutines(),
+			"max_apps":             metadata.GetMaxApps(),
+			"max_locals_per_app":   metadata.GetMaxLocalsPerApp(),
+			"lock_instrumentation": metadata.GetLockInstrumentation(),
+			"panic_recovery":       metadata.GetPanicRecovery(),
+			"shutdown_timeout":     metadata.GetShutdownTimeout().String(),
+			"update_interval":      metadata.GetUpdateInterval().String(),
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(config)
+	})
+}
+
+// GetFilteredHandler returns an HTTP handler that serves only the series whose app/local labels match the filter
+// This lets a multi-tenant process expose one scoped endpoint per tenant from the same registry
+// Series without an app_name label (global and system metrics) are always served
+// localName is passed as "" for series that only carry an app_name label
+func GetFilteredHandler(filter func(appName, localName string) bool) http.Handler {
+	// Initialize metrics if not already done
+	InitMetrics()
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		families, err := gatherer.Gather()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		format := expfmt.Negotiate(r.Header)
+		w.Header().Set("Content-Type", string(format))
+		encoder := expfmt.NewEncoder(w, format)
+		for _, family := range filterMetricFamilies(families, filter) {
+			if err := encoder.Encode(family); err != nil {
+				Logging.Error("filtered metrics encode error", "error", err)
+				return
+			}
+		}
+		if closer, ok := encoder.(expfmt.Closer); ok {
+			_ = closer.Close()
+		}
+	})
+}
+
+// filterMetricFamilies drops the series whose app/local labels don't match the filter
+// Families left without any series are dropped entirely
+func filterMetricFamilies(families []*dto.MetricFamily, filter func(appName, localName string) bool) []*dto.MetricFamily {
+	result := make([]*dto.MetricFamily, 0, len(families))
+	for _, family := range families {
+		kept := make([]*dto.Metric, 0, len(family.GetMetric()))
+		for _, metric := range family.GetMetric() {
+			appName, localName, scoped := "", "", false
+			for _, label := range metric.GetLabel() {
+				switch label.GetName() {
+				case "app_name":
+					appName, scoped = label.GetValue(), true
+				case "local_name":
+					localName = label.GetValue()
+				}
+			}
+			if !scoped || filter(appName, localName) {
+				kept = append(kept, metric)
+			}
+		}
+		if len(kept) == 0 {
+			continue
+		}
+		result = append(result, &dto.MetricFamily{
+			Name:   family.Name,
+			Help:   family.Help,
+			Type:   family.Type,
+			Unit:   family.Unit,
+			Metric: kept,
+		})
+	}
+	return result
 }
 
 // StartCollector starts the metrics collector without starting an HTTP server
@@ -165,6 +370,25 @@ func StopCollector() {
 	}
 }
 
+// FlushNow runs one synchronous collection so the exported metrics reflect the current state immediately
+// Shutdown paths call this so post-shutdown metrics are accurate without waiting for the next tick
+// It is a no-op when metrics are not enabled
+func FlushNow() {
+	if !IsMetricsEnabled() {
+		return
+	}
+
+	collectorLock.Lock()
+	collector := defaultCollector
+	collectorLock.Unlock()
+
+	// Collectors share what they exported, so a temporary collector works when none is running
+	if collector == nil {
+		collector = NewCollector()
+	}
+	collector.Collect()
+}
+
 // IsServerRunning returns whether the metrics server is currently running
 func IsServerRunning() bool {
 	serverLock.Lock()
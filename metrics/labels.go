@@ -0,0 +1,48 @@
+package metrics
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/neerajchowdary889/GoRoutinesManager/types"
+)
+
+// MaxLabelCombinations caps how many distinct label sets LabeledOperationsTotal keeps a series for.
+// Label sets seen after the cap is reached are counted under OverflowLabels, so labels carrying
+// unbounded values such as request IDs can't blow up the series count.
+var MaxLabelCombinations = 100
+
+// OverflowLabels is the labels value of operations whose label set didn't fit under MaxLabelCombinations
+const OverflowLabels = "__overflow__"
+
+var (
+	// Label sets given a series so far, formatted label set -> struct{}
+	labelCombinations     sync.Map
+	labelCombinationCount atomic.Int64
+)
+
+// RecordLabeledOperation records a goroutine operation of a routine spawned with labels.
+// Routines without labels aren't counted, they are already in GoroutineOperationsTotal.
+func RecordLabeledOperation(operation, appName, localName, functionName string, labels map[string]string) {
+	if len(labels) == 0 || !IsAppMetricsEnabled(appName) {
+		return
+	}
+	LabeledOperationsTotal.WithLabelValues(operation, appName, localName, functionName, boundedLabels(labels)).Inc()
+}
+
+// boundedLabels returns the formatted label set, or OverflowLabels once MaxLabelCombinations distinct sets were seen
+func boundedLabels(labels map[string]string) string {
+	formatted := types.FormatLabels(labels)
+	if _, ok := labelCombinations.Load(formatted); ok {
+		return formatted
+	}
+	// Reserve a slot before publishing the set so concurrent new sets can't go over the cap
+	if labelCombinationCount.Add(1) > int64(MaxLabelCombinations) {
+		labelCombinationCount.Add(-1)
+		return OverflowLabels
+	}
+	if _, loaded := labelCombinations.LoadOrStore(formatted, struct{}{}); loaded {
+		labelCombinationCount.Add(-1)
+	}
+	return formatted
+}
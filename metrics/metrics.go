@@ -4,9 +4,9 @@ import (
 	"sync"
 	"time"
 
+	"github.com/neerajchowdary889/GoRoutinesManager/Logging"
 	"github.com/neerajchowdary889/GoRoutinesManager/types"
 	"github.com/prometheus/client_golang/prometheus"
-	"github.com/prometheus/client_golang/prometheus/promauto"
 )
 
 var (
@@ -16,6 +16,10 @@ var (
 	// metricsInitialized tracks whether metrics have been initialized
 	metricsInitialized bool
 	metricsLock        sync.RWMutex
+
+	// Apps opted out of metrics, app name -> struct{}.
+	// Kept here so recording a metric doesn't look the app up in the manager tree.
+	disabledApps sync.Map
 )
 
 // Global Manager Metrics
@@ -34,6 +38,15 @@ var (
 
 	// ShutdownTimeoutSeconds tracks the configured shutdown timeout
 	ShutdownTimeoutSeconds prometheus.Gauge
+
+	// GoroutinesByState tracks the number of tracked goroutines in each lifecycle state
+	GoroutinesByState *prometheus.GaugeVec
+
+	// AppsDraining tracks the number of app managers currently shutting down
+	AppsDraining prometheus.Gauge
+
+	// LocalsDraining tracks the number of local managers currently shutting down
+	LocalsDraining prometheus.Gauge
 )
 
 // App Manager Metrics (with labels)
@@ -67,6 +80,18 @@ var (
 
 	// GoroutineAge tracks the age of currently running goroutines
 	GoroutineAge *prometheus.GaugeVec
+
+	// GoroutineScheduleLatency tracks the delay between spawning a goroutine and its worker starting
+	GoroutineScheduleLatency *prometheus.HistogramVec
+
+	// GoroutinesLeakedTotal tracks goroutines that kept running after their timeout and grace period
+	GoroutinesLeakedTotal *prometheus.CounterVec
+
+	// GoroutineReconnectAttemptsTotal tracks connect attempts made by reconnecting workers
+	GoroutineReconnectAttemptsTotal *prometheus.CounterVec
+
+	// GoroutinesContextIgnoredTotal tracks goroutines cancelled before they ever looked at their context
+	GoroutinesContextIgnoredTotal *prometheus.CounterVec
 )
 
 // Metadata Metrics
@@ -109,12 +134,35 @@ var (
 
 	// ShutdownGoroutinesRemaining tracks goroutines remaining after shutdown
 	ShutdownGoroutinesRemaining *prometheus.GaugeVec
+
+	// LockWaitDuration tracks how long acquiring manager mutexes took, only with lock instrumentation enabled
+	LockWaitDuration *prometheus.HistogramVec
+
+	// LabeledOperationsTotal tracks goroutine operations of routines spawned WithLabels, per label set
+	LabeledOperationsTotal *prometheus.CounterVec
 )
 
 // InitMetrics initializes and registers all Prometheus metrics
 // This function is safe to call multiple times (uses sync.Once)
+// A metric whose name is already registered by the host is left out of the registry instead of panicking,
+// see RegistrationErrors.
 func InitMetrics() {
+	InitMetricsWithRegistry(prometheus.DefaultRegisterer)
+}
+
+// InitMetricsWithRegistry is InitMetrics with the built-in metrics registered into reg instead of
+// the default Prometheus registry, so they don't collide with the host's own metrics (e.g. in tests).
+// GetMetricsHandler, WriteTo and GetFilteredHandler then serve from reg, which should also be a
+// prometheus.Gatherer as *prometheus.Registry is.
+// Only the first initialization picks the registry, call it before anything else initializes the metrics.
+func InitMetricsWithRegistry(reg prometheus.Registerer) {
+	if reg == nil {
+		reg = prometheus.DefaultRegisterer
+	}
+	initialized := false
 	once.Do(func() {
+		initialized = true
+		useRegistry(reg)
 		initGlobalMetrics()
 		initAppMetrics()
 		initLocalMetrics()
@@ -127,6 +175,9 @@ func InitMetrics() {
 		metricsInitialized = true
 		metricsLock.Unlock()
 	})
+	if !initialized && reg != registerer {
+		Logging.Warn("metrics: already initialized, ignoring the registry passed to InitMetricsWithRegistry")
+	}
 }
 
 // IsInitialized returns whether metrics have been initialized
@@ -137,44 +188,68 @@ func IsInitialized() bool {
 }
 
 func initGlobalMetrics() {
-	GlobalInitialized = promauto.NewGauge(prometheus.GaugeOpts{
+	GlobalInitialized = factory.NewGauge(prometheus.GaugeOpts{
 		Namespace: "goroutine_manager",
 		Subsystem: "global",
 		Name:      "initialized",
 		Help:      "Whether the global manager is initialized (1 = yes, 0 = no)",
 	})
 
-	AppManagersTotal = promauto.NewGauge(prometheus.GaugeOpts{
+	AppManagersTotal = factory.NewGauge(prometheus.GaugeOpts{
 		Namespace: "goroutine_manager",
 		Subsystem: "global",
 		Name:      "app_managers_total",
 		Help:      "Total number of app managers",
 	})
 
-	LocalManagersTotal = promauto.NewGauge(prometheus.GaugeOpts{
+	LocalManagersTotal = factory.NewGauge(prometheus.GaugeOpts{
 		Namespace: "goroutine_manager",
 		Subsystem: "global",
 		Name:      "local_managers_total",
 		Help:      "Total number of local managers across all apps",
 	})
 
-	GoroutinesTotal = promauto.NewGauge(prometheus.GaugeOpts{
+	GoroutinesTotal = factory.NewGauge(prometheus.GaugeOpts{
 		Namespace: "goroutine_manager",
 		Subsystem: "global",
 		Name:      "goroutines_total",
 		Help:      "Total number of tracked goroutines",
 	})
 
-	ShutdownTimeoutSeconds = promauto.NewGauge(prometheus.GaugeOpts{
+	ShutdownTimeoutSeconds = factory.NewGauge(prometheus.GaugeOpts{
 		Namespace: "goroutine_manager",
 		Subsystem: "global",
 		Name:      "shutdown_timeout_seconds",
 		Help:      "Configured shutdown timeout in seconds",
 	})
+
+	AppsDraining = factory.NewGauge(prometheus.GaugeOpts{
+		Namespace: "goroutine_manager",
+		Subsystem: "global",
+		Name:      "apps_draining",
+		Help:      "Number of app managers currently shutting down",
+	})
+
+	LocalsDraining = factory.NewGauge(prometheus.GaugeOpts{
+		Namespace: "goroutine_manager",
+		Subsystem: "global",
+		Name:      "locals_draining",
+		Help:      "Number of local managers currently shutting down",
+	})
+
+	GoroutinesByState = factory.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "goroutine_manager",
+			Subsystem: "global",
+			Name:      "goroutines_by_state",
+			Help:      "Number of tracked goroutines in each lifecycle state",
+		},
+		[]string{"state"},
+	)
 }
 
 func initAppMetrics() {
-	AppLocalManagers = promauto.NewGaugeVec(
+	AppLocalManagers = factory.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Namespace: "goroutine_manager",
 			Subsystem: "app",
@@ -184,7 +259,7 @@ func initAppMetrics() {
 		[]string{"app_name"},
 	)
 
-	AppGoroutines = promauto.NewGaugeVec(
+	AppGoroutines = factory.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Namespace: "goroutine_manager",
 			Subsystem: "app",
@@ -194,7 +269,7 @@ func initAppMetrics() {
 		[]string{"app_name"},
 	)
 
-	AppInitialized = promauto.NewGaugeVec(
+	AppInitialized = factory.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Namespace: "goroutine_manager",
 			Subsystem: "app",
@@ -206,7 +281,7 @@ func initAppMetrics() {
 }
 
 func initLocalMetrics() {
-	LocalGoroutines = promauto.NewGaugeVec(
+	LocalGoroutines = factory.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Namespace: "goroutine_manager",
 			Subsystem: "local",
@@ -216,7 +291,7 @@ func initLocalMetrics() {
 		[]string{"app_name", "local_name"},
 	)
 
-	LocalFunctionWaitgroups = promauto.NewGaugeVec(
+	LocalFunctionWaitgroups = factory.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Namespace: "goroutine_manager",
 			Subsystem: "local",
@@ -228,7 +303,7 @@ func initLocalMetrics() {
 }
 
 func initGoroutineMetrics() {
-	GoroutinesByFunction = promauto.NewGaugeVec(
+	GoroutinesByFunction = factory.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Namespace: "goroutine_manager",
 			Subsystem: "goroutine",
@@ -238,7 +313,7 @@ func initGoroutineMetrics() {
 		[]string{"app_name", "local_name", "function_name"},
 	)
 
-	GoroutineDuration = promauto.NewHistogramVec(
+	GoroutineDuration = factory.NewHistogramVec(
 		prometheus.HistogramOpts{
 			Namespace: "goroutine_manager",
 			Subsystem: "goroutine",
@@ -249,7 +324,18 @@ func initGoroutineMetrics() {
 		[]string{"app_name", "local_name", "function_name"},
 	)
 
-	GoroutineAge = promauto.NewGaugeVec(
+	GoroutineScheduleLatency = factory.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "goroutine_manager",
+			Subsystem: "goroutine",
+			Name:      "schedule_latency_seconds",
+			Help:      "Delay between spawning a goroutine and its worker starting",
+			Buckets:   []float64{.00001, .00005, .0001, .0005, .001, .005, .01, .05, .1, .5, 1},
+		},
+		[]string{"app_name", "local_name", "function_name"},
+	)
+
+	GoroutineAge = factory.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Namespace: "goroutine_manager",
 			Subsystem: "goroutine",
@@ -258,17 +344,47 @@ func initGoroutineMetrics() {
 		},
 		[]string{"app_name", "local_name", "function_name", "routine_id"},
 	)
+
+	GoroutinesLeakedTotal = factory.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "goroutine_manager",
+			Subsystem: "goroutine",
+			Name:      "leaked_total",
+			Help:      "Total number of goroutines that ignored their context after a timeout",
+		},
+		[]string{"app_name", "local_name", "function_name"},
+	)
+
+	GoroutineReconnectAttemptsTotal = factory.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "goroutine_manager",
+			Subsystem: "goroutine",
+			Name:      "reconnect_attempts_total",
+			Help:      "Total number of connect attempts made by reconnecting goroutines",
+		},
+		[]string{"app_name", "local_name", "function_name", "result"}, // result: success, failure
+	)
+
+	GoroutinesContextIgnoredTotal = factory.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "goroutine_manager",
+			Subsystem: "goroutine",
+			Name:      "context_ignored_total",
+			Help:      "Total number of goroutines cancelled before they ever observed their context",
+		},
+		[]string{"app_name", "local_name", "function_name"},
+	)
 }
 
 func initMetadataMetrics() {
-	MaxRoutines = promauto.NewGauge(prometheus.GaugeOpts{
+	MaxRoutines = factory.NewGauge(prometheus.GaugeOpts{
 		Namespace: "goroutine_manager",
 		Subsystem: "metadata",
 		Name:      "max_routines",
 		Help:      "Configured maximum routines limit (0 = unlimited)",
 	})
 
-	MetricsEnabled = promauto.NewGauge(prometheus.GaugeOpts{
+	MetricsEnabled = factory.NewGauge(prometheus.GaugeOpts{
 		Namespace: "goroutine_manager",
 		Subsystem: "metadata",
 		Name:      "enabled",
@@ -277,7 +393,7 @@ func initMetadataMetrics() {
 }
 
 func initSystemMetrics() {
-	BuildInfo = promauto.NewGaugeVec(
+	BuildInfo = factory.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Namespace: "goroutine_manager",
 			Subsystem: "system",
@@ -289,7 +405,7 @@ func initSystemMetrics() {
 }
 
 func initOperationMetrics() {
-	GoroutineOperationsTotal = promauto.NewCounterVec(
+	GoroutineOperationsTotal = factory.NewCounterVec(
 		prometheus.CounterOpts{
 			Namespace: "goroutine_manager",
 			Subsystem: "operations",
@@ -299,7 +415,7 @@ func initOperationMetrics() {
 		[]string{"operation", "app_name", "local_name", "function_name"},
 	)
 
-	ManagerOperationsTotal = promauto.NewCounterVec(
+	ManagerOperationsTotal = factory.NewCounterVec(
 		prometheus.CounterOpts{
 			Namespace: "goroutine_manager",
 			Subsystem: "operations",
@@ -309,7 +425,7 @@ func initOperationMetrics() {
 		[]string{"manager_type", "operation", "app_name"},
 	)
 
-	FunctionOperationsTotal = promauto.NewCounterVec(
+	FunctionOperationsTotal = factory.NewCounterVec(
 		prometheus.CounterOpts{
 			Namespace: "goroutine_manager",
 			Subsystem: "operations",
@@ -319,7 +435,7 @@ func initOperationMetrics() {
 		[]string{"operation", "app_name", "local_name", "function_name"},
 	)
 
-	OperationErrorsTotal = promauto.NewCounterVec(
+	OperationErrorsTotal = factory.NewCounterVec(
 		prometheus.CounterOpts{
 			Namespace: "goroutine_manager",
 			Subsystem: "operations",
@@ -329,7 +445,7 @@ func initOperationMetrics() {
 		[]string{"operation_type", "operation", "error_type"},
 	)
 
-	GoroutineOperationDuration = promauto.NewHistogramVec(
+	GoroutineOperationDuration = factory.NewHistogramVec(
 		prometheus.HistogramOpts{
 			Namespace: "goroutine_manager",
 			Subsystem: "operations",
@@ -340,7 +456,7 @@ func initOperationMetrics() {
 		[]string{"operation", "app_name", "local_name", "function_name"},
 	)
 
-	ManagerOperationDuration = promauto.NewHistogramVec(
+	ManagerOperationDuration = factory.NewHistogramVec(
 		prometheus.HistogramOpts{
 			Namespace: "goroutine_manager",
 			Subsystem: "operations",
@@ -351,7 +467,7 @@ func initOperationMetrics() {
 		[]string{"manager_type", "operation", "app_name"},
 	)
 
-	ShutdownDuration = promauto.NewHistogramVec(
+	ShutdownDuration = factory.NewHistogramVec(
 		prometheus.HistogramOpts{
 			Namespace: "goroutine_manager",
 			Subsystem: "operations",
@@ -362,7 +478,7 @@ func initOperationMetrics() {
 		[]string{"manager_type", "shutdown_type", "app_name", "local_name"},
 	)
 
-	ShutdownGoroutinesRemaining = promauto.NewGaugeVec(
+	ShutdownGoroutinesRemaining = factory.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Namespace: "goroutine_manager",
 			Subsystem: "operations",
@@ -371,6 +487,27 @@ func initOperationMetrics() {
 		},
 		[]string{"manager_type", "app_name", "local_name"},
 	)
+
+	LockWaitDuration = factory.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "goroutine_manager",
+			Subsystem: "operations",
+			Name:      "lock_wait_duration_seconds",
+			Help:      "Time spent waiting to acquire manager mutexes in seconds",
+			Buckets:   []float64{.000001, .00001, .0001, .001, .01, .1, 1},
+		},
+		[]string{"manager", "app_name", "local_name"},
+	)
+
+	LabeledOperationsTotal = factory.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "goroutine_manager",
+			Subsystem: "operations",
+			Name:      "labeled_total",
+			Help:      "Total number of goroutine operations of labeled routines, per label set",
+		},
+		[]string{"operation", "app_name", "local_name", "function_name", "labels"},
+	)
 }
 
 // IsMetricsEnabled checks if metrics are enabled in the metadata
@@ -393,10 +530,33 @@ func IsMetricsEnabled() bool {
 	return metadata.Metrics
 }
 
+// IsAppMetricsEnabled checks if metrics are enabled globally and the app hasn't opted out.
+// An empty app name is for series that don't belong to an app.
+func IsAppMetricsEnabled(appName string) bool {
+	if !IsMetricsEnabled() {
+		return false
+	}
+	if appName == "" {
+		return true
+	}
+	_, disabled := disabledApps.Load(appName)
+	return !disabled
+}
+
+// SetAppMetricsEnabled records whether the app records metrics, the app manager calls it when the app opts in or out.
+// Apps never set record metrics.
+func SetAppMetricsEnabled(appName string, enabled bool) {
+	if enabled {
+		disabledApps.Delete(appName)
+		return
+	}
+	disabledApps.Store(appName, struct{}{})
+}
+
 // RecordGoroutineCompletion records the completion of a goroutine
 // This should be called when a goroutine finishes execution
 func RecordGoroutineCompletion(appName, localName, functionName string, startTime int64) {
-	if !IsMetricsEnabled() {
+	if !IsAppMetricsEnabled(appName) {
 		return
 	}
 
@@ -404,9 +564,20 @@ func RecordGoroutineCompletion(appName, localName, functionName string, startTim
 	GoroutineDuration.WithLabelValues(appName, localName, functionName).Observe(duration)
 }
 
+// RecordGoroutineScheduleLatency records how long a goroutine waited to be scheduled.
+// Call it when the worker begins, with the time the goroutine was spawned.
+func RecordGoroutineScheduleLatency(appName, localName, functionName string, spawnTime int64) {
+	if !IsAppMetricsEnabled(appName) {
+		return
+	}
+
+	latency := time.Since(time.Unix(0, spawnTime)).Seconds()
+	GoroutineScheduleLatency.WithLabelValues(appName, localName, functionName).Observe(latency)
+}
+
 // UpdateGoroutineAge updates the age metric for a specific goroutine
 func UpdateGoroutineAge(appName, localName, functionName, routineID string, startTime int64) {
-	if !IsMetricsEnabled() {
+	if !IsAppMetricsEnabled(appName) {
 		return
 	}
 
@@ -415,8 +586,9 @@ func UpdateGoroutineAge(appName, localName, functionName, routineID string, star
 }
 
 // RemoveGoroutineAge removes the age metric for a specific goroutine
+// The series is removed even if metrics were disabled since, so it can't be left behind
 func RemoveGoroutineAge(appName, localName, functionName, routineID string) {
-	if !IsMetricsEnabled() {
+	if !IsInitialized() {
 		return
 	}
 
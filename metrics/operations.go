@@ -2,11 +2,13 @@ package metrics
 
 import (
 	"time"
+
+	"github.com/neerajchowdary889/GoRoutinesManager/types"
 )
 
 // RecordGoroutineOperation records a goroutine operation
 func RecordGoroutineOperation(operation, appName, localName, functionName string) {
-	if !IsMetricsEnabled() {
+	if !IsAppMetricsEnabled(appName) {
 		return
 	}
 	GoroutineOperationsTotal.WithLabelValues(operation, appName, localName, functionName).Inc()
@@ -14,7 +16,7 @@ func RecordGoroutineOperation(operation, appName, localName, functionName string
 
 // RecordManagerOperation records a manager operation
 func RecordManagerOperation(managerType, operation, appName string) {
-	if !IsMetricsEnabled() {
+	if !IsAppMetricsEnabled(appName) {
 		return
 	}
 	ManagerOperationsTotal.WithLabelValues(managerType, operation, appName).Inc()
@@ -22,7 +24,7 @@ func RecordManagerOperation(managerType, operation, appName string) {
 
 // RecordFunctionOperation records a function operation
 func RecordFunctionOperation(operation, appName, localName, functionName string) {
-	if !IsMetricsEnabled() {
+	if !IsAppMetricsEnabled(appName) {
 		return
 	}
 	FunctionOperationsTotal.WithLabelValues(operation, appName, localName, functionName).Inc()
@@ -38,7 +40,7 @@ func RecordOperationError(operationType, operation, errorType string) {
 
 // RecordGoroutineOperationDuration records the duration of a goroutine operation
 func RecordGoroutineOperationDuration(operation string, duration time.Duration, appName, localName, functionName string) {
-	if !IsMetricsEnabled() {
+	if !IsAppMetricsEnabled(appName) {
 		return
 	}
 	GoroutineOperationDuration.WithLabelValues(operation, appName, localName, functionName).Observe(duration.Seconds())
@@ -46,7 +48,7 @@ func RecordGoroutineOperationDuration(operation string, duration time.Duration,
 
 // RecordManagerOperationDuration records the duration of a manager operation
 func RecordManagerOperationDuration(managerType, operation string, duration time.Duration, appName string) {
-	if !IsMetricsEnabled() {
+	if !IsAppMetricsEnabled(appName) {
 		return
 	}
 	ManagerOperationDuration.WithLabelValues(managerType, operation, appName).Observe(duration.Seconds())
@@ -54,7 +56,7 @@ func RecordManagerOperationDuration(managerType, operation string, duration time
 
 // RecordShutdownDuration records the duration of a shutdown operation
 func RecordShutdownDuration(managerType, shutdownType string, duration time.Duration, appName, localName string) {
-	if !IsMetricsEnabled() {
+	if !IsAppMetricsEnabled(appName) {
 		return
 	}
 	ShutdownDuration.WithLabelValues(managerType, shutdownType, appName, localName).Observe(duration.Seconds())
@@ -62,8 +64,70 @@ func RecordShutdownDuration(managerType, shutdownType string, duration time.Dura
 
 // RecordShutdownGoroutinesRemaining records the number of goroutines remaining after shutdown
 func RecordShutdownGoroutinesRemaining(managerType, appName, localName string, count int) {
-	if !IsMetricsEnabled() {
+	if !IsAppMetricsEnabled(appName) {
 		return
 	}
 	ShutdownGoroutinesRemaining.WithLabelValues(managerType, appName, localName).Set(float64(count))
 }
+
+// RecordAppDraining counts the app manager as draining until the returned func is called.
+// The decision is taken once so the gauge can't drift if metrics are toggled mid shutdown.
+func RecordAppDraining(appName string) (done func()) {
+	if !IsAppMetricsEnabled(appName) {
+		return func() {}
+	}
+	AppsDraining.Inc()
+	return AppsDraining.Dec
+}
+
+// RecordLocalDraining counts the local manager as draining until the returned func is called
+func RecordLocalDraining(appName string) (done func()) {
+	if !IsAppMetricsEnabled(appName) {
+		return func() {}
+	}
+	LocalsDraining.Inc()
+	return LocalsDraining.Dec
+}
+
+// RecordGoroutineLeak records a goroutine that kept running after its timeout and grace period
+func RecordGoroutineLeak(appName, localName, functionName string) {
+	if !IsAppMetricsEnabled(appName) {
+		return
+	}
+	GoroutinesLeakedTotal.WithLabelValues(appName, localName, functionName).Inc()
+}
+
+// RecordContextIgnored records a goroutine cancelled before it ever observed its context
+func RecordContextIgnored(appName, localName, functionName string) {
+	if !IsAppMetricsEnabled(appName) {
+		return
+	}
+	GoroutinesContextIgnoredTotal.WithLabelValues(appName, localName, functionName).Inc()
+}
+
+// RecordLockWait records how long acquiring a manager mutex took.
+// Not filtered per app: it runs while the mutex is held, looking the app up would take the global lock.
+func RecordLockWait(manager, appName, localName string, wait time.Duration) {
+	if !IsMetricsEnabled() {
+		return
+	}
+	LockWaitDuration.WithLabelValues(manager, appName, localName).Observe(wait.Seconds())
+}
+
+// EnableLockInstrumentation starts or stops timing manager mutex acquisition into LockWaitDuration.
+// Off by default: it adds a clock read around every manager lock.
+func EnableLockInstrumentation(enabled bool) {
+	if enabled {
+		types.SetLockWaitObserver(RecordLockWait)
+		return
+	}
+	types.SetLockWaitObserver(nil)
+}
+
+// RecordReconnectAttempt records a connect attempt made by a reconnecting goroutine
+func RecordReconnectAttempt(appName, localName, functionName, result string) {
+	if !IsAppMetricsEnabled(appName) {
+		return
+	}
+	GoroutineReconnectAttemptsTotal.WithLabelValues(appName, localName, functionName, result).Inc()
+}
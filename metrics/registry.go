@@ -1,55 +1,170 @@
 package metrics
 
 import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/neerajchowdary889/GoRoutinesManager/Logging"
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
 )
 
 var (
 	// defaultRegistry is the default Prometheus registry
 	defaultRegistry *prometheus.Registry
+
+	// registerer and gatherer are where the built-in metrics live, set once by InitMetricsWithRegistry
+	registerer prometheus.Registerer = prometheus.DefaultRegisterer
+	gatherer   prometheus.Gatherer   = prometheus.DefaultGatherer
+
+	// factory creates the built-in metrics, tolerating names the host already registered
+	factory = promauto.With(tolerantRegisterer{prometheus.DefaultRegisterer})
+
+	// registrationErrors holds why built-in metrics were left unregistered
+	registrationErrors   []error
+	registrationErrorsMu sync.Mutex
+
+	// registered holds the built-in collectors that made it into the registry, for ResetMetrics to unregister
+	registered   []prometheus.Collector
+	registeredMu sync.Mutex
 )
 
+// tolerantRegisterer registers the built-in metrics without panicking on conflicts.
+// A metric that can't be registered still works but isn't exported, the rest of the metrics are unaffected.
+type tolerantRegisterer struct {
+	prometheus.Registerer
+}
+
+func (r tolerantRegisterer) MustRegister(collectors ...prometheus.Collector) {
+	for _, c := range collectors {
+		if err := r.Register(c); err != nil {
+			var already prometheus.AlreadyRegisteredError
+			if errors.As(err, &already) {
+				// The error doesn't say which metric, unlike the other registration errors
+				err = fmt.Errorf("%w: %s", err, describe(c))
+			}
+			Logging.Warn("metrics: skipping a built-in metric that can't be registered", "error", err)
+			registrationErrorsMu.Lock()
+			registrationErrors = append(registrationErrors, err)
+			registrationErrorsMu.Unlock()
+			continue
+		}
+		registeredMu.Lock()
+		registered = append(registered, c)
+		registeredMu.Unlock()
+	}
+}
+
+// describe returns the descriptors of a collector, for error messages
+func describe(c prometheus.Collector) string {
+	descs := make(chan *prometheus.Desc)
+	go func() {
+		c.Describe(descs)
+		close(descs)
+	}()
+	var out []string
+	for desc := range descs {
+		out = append(out, desc.String())
+	}
+	return strings.Join(out, ", ")
+}
+
+// RegistrationErrors returns why built-in metrics were left out of the registry by InitMetrics,
+// usually because the host already registered a metric with the same name. Empty if all were registered.
+func RegistrationErrors() []error {
+	registrationErrorsMu.Lock()
+	defer registrationErrorsMu.Unlock()
+	return append([]error(nil), registrationErrors...)
+}
+
+// useRegistry points the built-in metrics at reg, it must run before they are created.
+// A Registerer that can't be gathered from (e.g. a wrapped one) leaves the handlers with nothing to serve.
+func useRegistry(reg prometheus.Registerer) {
+	registerer = reg
+	factory = promauto.With(tolerantRegisterer{reg})
+	switch g := reg.(type) {
+	case prometheus.Gatherer:
+		gatherer = g
+	default:
+		Logging.Warn("metrics: the registry passed to InitMetricsWithRegistry can't be gathered from, /metrics will be empty")
+		gatherer = prometheus.Gatherers{}
+	}
+}
+
 // GetRegistry returns the Prometheus registry
 // If metrics haven't been initialized, it returns the default registry
+// If InitMetricsWithRegistry was given a *prometheus.Registry, that registry is returned
 func GetRegistry() *prometheus.Registry {
+	if reg, ok := registerer.(*prometheus.Registry); ok {
+		return reg
+	}
 	if defaultRegistry == nil {
 		defaultRegistry = prometheus.DefaultRegisterer.(*prometheus.Registry)
 	}
 	return defaultRegistry
 }
 
-// ResetMetrics resets all metrics to their initial state
-// This is primarily useful for testing
+// GetGatherer returns where the built-in metrics are gathered from,
+// the default gatherer unless InitMetricsWithRegistry was given another registry
+func GetGatherer() prometheus.Gatherer {
+	return gatherer
+}
+
+// Register adds a custom collector to the registry the built-in metrics live in,
+// so it is served from the same /metrics endpoint.
+// Registering the same collector again is a no-op, a different collector with the
+// same descriptors is rejected with the underlying prometheus.AlreadyRegisteredError.
+func Register(c prometheus.Collector) error {
+	err := registerer.Register(c)
+	if err == nil {
+		return nil
+	}
+	var already prometheus.AlreadyRegisteredError
+	if errors.As(err, &already) && already.ExistingCollector == c {
+		return nil
+	}
+	return fmt.Errorf("metrics: register collector: %w", err)
+}
+
+// MustRegister is Register for setup code, it panics if a collector can't be registered
+func MustRegister(collectors ...prometheus.Collector) {
+	for _, c := range collectors {
+		if err := Register(c); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// ResetMetrics unregisters all built-in metrics and forgets the initialization,
+// so the next InitMetrics or InitMetricsWithRegistry registers fresh metrics without stale label series.
+// This is meant for tests, it must not run concurrently with the initialization or with recording.
+// Collectors added with Register are left registered, the registry goes back to the default one.
 func ResetMetrics() {
 	if !IsInitialized() {
 		return
 	}
 
-	// Reset global metrics
-	GlobalInitialized.Set(0)
-	AppManagersTotal.Set(0)
-	LocalManagersTotal.Set(0)
-	GoroutinesTotal.Set(0)
-	ShutdownTimeoutSeconds.Set(0)
-
-	// Reset app metrics (delete all label combinations)
-	AppLocalManagers.Reset()
-	AppGoroutines.Reset()
-	AppInitialized.Reset()
+	registeredMu.Lock()
+	for _, c := range registered {
+		registerer.Unregister(c)
+	}
+	registered = nil
+	registeredMu.Unlock()
 
-	// Reset local metrics
-	LocalGoroutines.Reset()
-	LocalFunctionWaitgroups.Reset()
+	registrationErrorsMu.Lock()
+	registrationErrors = nil
+	registrationErrorsMu.Unlock()
 
-	// Reset goroutine metrics
-	GoroutinesByFunction.Reset()
-	GoroutineDuration.Reset()
-	GoroutineAge.Reset()
+	labelCombinations.Clear()
+	labelCombinationCount.Store(0)
+	collectedSeries.reset()
 
-	// Reset metadata metrics
-	MaxRoutines.Set(0)
-	MetricsEnabled.Set(0)
+	metricsLock.Lock()
+	metricsInitialized = false
+	metricsLock.Unlock()
 
-	// Reset system metrics
-	BuildInfo.Reset()
+	useRegistry(prometheus.DefaultRegisterer)
+	once = sync.Once{}
 }
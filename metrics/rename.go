@@ -0,0 +1,123 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// RenameAppSeries moves the series labelled with oldApp over to newApp after an app rename.
+// Counters are carried over so totals don't reset. Gauges are dropped and re-emitted under
+// the new name by the next collection. Histograms can't be moved, their old series are dropped
+// and they start over under the new name.
+func RenameAppSeries(oldApp, newApp string) {
+	if !IsInitialized() {
+		return
+	}
+	match := prometheus.Labels{"app_name": oldApp}
+	rename := prometheus.Labels{"app_name": newApp}
+	renameSeries(match, rename)
+}
+
+// RenameLocalSeries moves the series of a local manager over to newLocal after a local rename.
+// See RenameAppSeries for how each metric type is handled.
+func RenameLocalSeries(appName, oldLocal, newLocal string) {
+	if !IsInitialized() {
+		return
+	}
+	match := prometheus.Labels{"app_name": appName, "local_name": oldLocal}
+	rename := prometheus.Labels{"local_name": newLocal}
+	renameSeries(match, rename)
+}
+
+func renameSeries(match, rename prometheus.Labels) {
+	_, perLocal := match["local_name"]
+
+	counters := []*prometheus.CounterVec{
+		GoroutineOperationsTotal,
+		FunctionOperationsTotal,
+		GoroutinesLeakedTotal,
+		GoroutineReconnectAttemptsTotal,
+		GoroutinesContextIgnoredTotal,
+		LabeledOperationsTotal,
+	}
+	if !perLocal {
+		// Only carries app_name, nothing to move for a local rename
+		counters = append(counters, ManagerOperationsTotal)
+	}
+	for _, vec := range counters {
+		moveCounterSeries(vec, match, rename)
+	}
+
+	gauges := []*prometheus.GaugeVec{
+		LocalGoroutines,
+		LocalFunctionWaitgroups,
+		GoroutinesByFunction,
+		GoroutineAge,
+		ShutdownGoroutinesRemaining,
+	}
+	histograms := []*prometheus.HistogramVec{
+		GoroutineDuration,
+		GoroutineScheduleLatency,
+		GoroutineOperationDuration,
+		ShutdownDuration,
+		LockWaitDuration,
+	}
+	if !perLocal {
+		gauges = append(gauges, AppLocalManagers, AppGoroutines, AppInitialized)
+		histograms = append(histograms, ManagerOperationDuration)
+	}
+	for _, vec := range gauges {
+		vec.DeletePartialMatch(match)
+	}
+	for _, vec := range histograms {
+		vec.DeletePartialMatch(match)
+	}
+}
+
+// moveCounterSeries adds the value of every series matching match to the same series with
+// the rename labels applied, then deletes the old series
+func moveCounterSeries(vec *prometheus.CounterVec, match, rename prometheus.Labels) {
+	ch := make(chan prometheus.Metric)
+	go func() {
+		vec.Collect(ch)
+		close(ch)
+	}()
+
+	type series struct {
+		labels prometheus.Labels
+		value  float64
+	}
+	var moved []series
+	for metric := range ch {
+		var m dto.Metric
+		if err := metric.Write(&m); err != nil {
+			continue
+		}
+		labels := make(prometheus.Labels, len(m.GetLabel()))
+		for _, pair := range m.GetLabel() {
+			labels[pair.GetName()] = pair.GetValue()
+		}
+		if !labelsMatch(labels, match) {
+			continue
+		}
+		moved = append(moved, series{labels: labels, value: m.GetCounter().GetValue()})
+	}
+
+	// Collect holds the vec's lock, so only mutate once it has finished
+	for _, s := range moved {
+		vec.Delete(s.labels)
+		for name, value := range rename {
+			s.labels[name] = value
+		}
+		vec.With(s.labels).Add(s.value)
+	}
+}
+
+func labelsMatch(labels, match prometheus.Labels) bool {
+	for name, value := range match {
+		if labels[name] != value {
+			return false
+		}
+	}
+	return true
+}
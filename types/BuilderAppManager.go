@@ -3,7 +3,10 @@ package types
 import (
 	"context"
 	"fmt"
+	"maps"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/neerajchowdary889/GoRoutinesManager/Context"
 	"github.com/neerajchowdary889/GoRoutinesManager/Manager/Errors"
@@ -22,16 +25,45 @@ func NewAppManager(appName string) *AppManager {
 		return appMgr
 	}
 
+	appMgr := buildAppManager(appName)
+
+	// Add the app manager to the global manager
+	SetAppManager(appName, appMgr)
+
+	return appMgr
+}
+
+// CreateAppManager builds the app manager and registers it unless that would exceed the configured MaxApps,
+// an app already registered is returned as is. The rejected app leaves no context behind.
+func CreateAppManager(appName string) (*AppManager, error) {
+	if IsIntilized().App(appName) {
+		return Global.GetAppManager(appName)
+	}
+
+	appMgr := buildAppManager(appName)
+	if err := Global.TryAddAppManager(appName, appMgr); err != nil {
+		appMgr.releaseAppContext()
+		return nil, err
+	}
+
+	// A concurrent create of the same name may have published first, everyone gets the published one
+	if published, err := Global.GetAppManager(appName); err == nil && published != appMgr {
+		appMgr.releaseAppContext()
+		return published, nil
+	}
+	return appMgr, nil
+}
+
+// buildAppManager builds the app manager with its mutex, wait group and context, it is not registered yet
+func buildAppManager(appName string) *AppManager {
 	appMgr := &AppManager{
+		appMu:         &sync.RWMutex{}, // Set before the app is published, swapping it later would split the lock
 		AppName:       appName,
 		LocalManagers: make(map[string]*LocalManager),
 		Wg:            &sync.WaitGroup{}, // Initialize wait group for safe shutdown
+		createdAt:     time.Now(),
 	}
 	appMgr.SetAppContext()
-
-	// Add the app manager to the global manager
-	SetAppManager(appName, appMgr)
-
 	return appMgr
 }
 
@@ -41,7 +73,9 @@ func (AM *AppManager) LockAppReadMutex() {
 	if AM.appMu == nil {
 		AM.SetAppMutex()
 	}
-	AM.appMu.RLock()
+	if observer, wait := timedLock(AM.appMu.RLock); observer != nil {
+		observer(LockManager_App, AM.AppName, "", wait)
+	}
 }
 
 // UnlockAppReadMutex unlocks the app read mutex for the app manager - This is used to read the app manager's data
@@ -57,7 +91,9 @@ func (AM *AppManager) LockAppWriteMutex() {
 	if AM.appMu == nil {
 		AM.SetAppMutex()
 	}
-	AM.appMu.Lock()
+	if observer, wait := timedLock(AM.appMu.Lock); observer != nil {
+		observer(LockManager_App, AM.AppName, "", wait)
+	}
 }
 
 // UnlockAppWriteMutex unlocks the app write mutex for the app manager - This is used to write the app manager's data
@@ -83,17 +119,31 @@ func (AM *AppManager) SetAppMutex() *AppManager {
 	return AM
 }
 
-// SetAppContext sets the context for the app manager
+// SetAppContext sets the context for the app manager.
+// The app holds a reference on it so it outlives its last local, Cancel ends it regardless of the locals' references.
 func (AM *AppManager) SetAppContext() *AppManager {
-	ctx := Context.GetAppContext(Prefix_AppManager + AM.AppName).Get()
+	ctx := Context.GetRefCountedAppContext(Prefix_AppManager + AM.AppName).Acquire()
 	Done := func() {
-		Context.GetAppContext(Prefix_AppManager + AM.AppName).Done(ctx)
+		Context.GetRefCountedAppContext(Prefix_AppManager + AM.AppName).ForceShutdown()
 	}
 	AM.Ctx = ctx
 	AM.Cancel = Done
 	return AM
 }
 
+// releaseAppContext drops the reference SetAppContext took for an app manager that didn't get registered,
+// the context is cancelled unless a registered app of the same name holds it too
+func (AM *AppManager) releaseAppContext() {
+	Context.GetRefCountedAppContext(Prefix_AppManager + AM.AppName).Release()
+}
+
+// CancelContext cancels the app's context, the contexts of its local managers are derived from it and cancelled with it.
+// The context registered for the app is cancelled even if it replaced AM.Ctx, locals created since derive from that one,
+// and the references the locals hold on it don't keep it alive.
+func (AM *AppManager) CancelContext() {
+	Context.GetRefCountedAppContext(Prefix_AppManager + AM.GetAppName()).ForceShutdown()
+}
+
 // SetAppWaitGroup sets the wait group for the app manager
 func (AM *AppManager) SetAppWaitGroup(wg *sync.WaitGroup) *AppManager {
 	AM.Wg = wg
@@ -118,12 +168,28 @@ func (AM *AppManager) CreateLocal(localName string) (*LocalManager, error) {
 		}
 		return LM, Errors.WrngLocalManagerAlreadyExists
 	}
-	// Set the parent contex before returning
-	LM := newLocalManager(localName, AM.AppName).SetParentContext(AM.ParentCtx)
+	// Built with its contexts and wait group before it is published
+	return newLocalManager(localName, AM.AppName, AM.ParentCtx)
+}
 
-	AM.AddLocalManager(LM.LocalName, LM)
+// TryAddLocalManager adds the local manager unless that would exceed the configured MaxLocalsPerApp.
+// The limit check and the insert happen under the same write lock so concurrent creates can't overshoot.
+func (AM *AppManager) TryAddLocalManager(localName string, local *LocalManager) error {
+	maxLocals := 0
+	if Global != nil && Global.GetMetadata() != nil {
+		maxLocals = Global.GetMetadata().GetMaxLocalsPerApp()
+	}
 
-	return LM, nil
+	AM.LockAppWriteMutex()
+	defer AM.UnlockAppWriteMutex()
+	if _, ok := AM.LocalManagers[localName]; ok {
+		return nil
+	}
+	if maxLocals > 0 && len(AM.LocalManagers) >= maxLocals {
+		return fmt.Errorf("%w: app %s, limit %d", Errors.ErrMaxLocalsExceeded, AM.AppName, maxLocals)
+	}
+	AM.LocalManagers[localName] = local
+	return nil
 }
 
 // AddLocalManager adds a new local manager to the app manager
@@ -137,20 +203,67 @@ func (AM *AppManager) AddLocalManager(localName string, local *LocalManager) *Ap
 	return AM
 }
 
-// RemoveLocalManager removes a local manager from the app manager
-func (AM *AppManager) RemoveLocalManager(localName string) *AppManager {
+// RenameLocalManager re-keys the local manager under newName and updates its name.
+// The map is re-keyed under the app write lock so no lookup sees both or neither name.
+func (AM *AppManager) RenameLocalManager(oldName, newName string) error {
 	AM.LockAppWriteMutex()
 	defer AM.UnlockAppWriteMutex()
+	local, ok := AM.LocalManagers[oldName]
+	if !ok {
+		return fmt.Errorf("%w: %s", Errors.ErrLocalManagerNotFound, oldName)
+	}
+	if _, exists := AM.LocalManagers[newName]; exists {
+		return fmt.Errorf("%w: %s", Errors.ErrLocalManagerExists, newName)
+	}
+	delete(AM.LocalManagers, oldName)
+	AM.LocalManagers[newName] = local
+	local.SetLocalName(newName)
+	return nil
+}
+
+// RemoveLocalManager removes a local manager from the app manager and releases its reference on the app's context
+func (AM *AppManager) RemoveLocalManager(localName string) *AppManager {
+	AM.LockAppWriteMutex()
+	local := AM.LocalManagers[localName]
 	delete(AM.LocalManagers, localName)
+	AM.UnlockAppWriteMutex()
+	if local != nil {
+		local.ReleaseAppContext()
+	}
+	return AM
+}
+
+// SetMetricsEnabled turns metrics on or off for this app only.
+// Metrics still have to be enabled globally, an app can only opt out of them.
+func (AM *AppManager) SetMetricsEnabled(enabled bool) *AppManager {
+	AM.metricsDisabled.Store(!enabled)
 	return AM
 }
 
 // >>> Get APIs
-// GetLocalManagers gets all the local managers for the app manager
+// GetMetricsEnabled reports whether the app records metrics when they are enabled globally
+func (AM *AppManager) GetMetricsEnabled() bool {
+	return !AM.metricsDisabled.Load()
+}
+
+// GetLocalManagers gets all the local managers for the app manager.
+// The map is copied under the read lock, so ranging over it is safe while locals are created or renamed.
 func (AM *AppManager) GetLocalManagers() map[string]*LocalManager {
 	AM.LockAppReadMutex()
 	defer AM.UnlockAppReadMutex()
-	return AM.LocalManagers
+	return maps.Clone(AM.LocalManagers)
+}
+
+// LocalManagersSnapshot copies the app's local managers into a slice under the read lock,
+// safe to iterate while local managers are created or removed concurrently
+func (AM *AppManager) LocalManagersSnapshot() []*LocalManager {
+	AM.LockAppReadMutex()
+	defer AM.UnlockAppReadMutex()
+	locals := make([]*LocalManager, 0, len(AM.LocalManagers))
+	for _, local := range AM.LocalManagers {
+		locals = append(locals, local)
+	}
+	return locals
 }
 
 // GetLocalManager gets a specific local manager for the app manager
@@ -190,3 +303,15 @@ func (AM *AppManager) GetParentContext() context.Context {
 	defer AM.UnlockAppReadMutex()
 	return AM.ParentCtx
 }
+
+// NextLocalName reserves the next free "prefix-N" local name, counting from 1 per prefix.
+// Each call gets its own N even when called concurrently, names already taken in the app are skipped.
+func (AM *AppManager) NextLocalName(prefix string) string {
+	counter, _ := AM.localSeq.LoadOrStore(prefix, new(atomic.Int64))
+	for {
+		localName := fmt.Sprintf("%s-%d", prefix, counter.(*atomic.Int64).Add(1))
+		if _, err := AM.GetLocalManager(localName); err != nil {
+			return localName
+		}
+	}
+}
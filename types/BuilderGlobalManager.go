@@ -2,7 +2,10 @@ package types
 
 import (
 	"context"
+	"fmt"
+	"maps"
 	"sync"
+	"time"
 
 	"github.com/neerajchowdary889/GoRoutinesManager/Context"
 	"github.com/neerajchowdary889/GoRoutinesManager/Manager/Errors"
@@ -16,6 +19,7 @@ func NewGlobalManager() *GlobalManager {
 	Global = &GlobalManager{
 		AppManagers: make(map[string]*AppManager),
 		Wg:          &sync.WaitGroup{}, // Initialize wait group for safe shutdown
+		createdAt:   time.Now(),
 	}
 
 	// Initialize metadata
@@ -30,7 +34,9 @@ func (GM *GlobalManager) LockGlobalReadMutex() {
 	if GM.globalMu == nil {
 		GM.SetGlobalMutex()
 	}
-	GM.globalMu.RLock()
+	if observer, wait := timedLock(GM.globalMu.RLock); observer != nil {
+		observer(LockManager_Global, "", "", wait)
+	}
 }
 
 // UnlockGlobalReadMutex unlocks the global read mutex for the global manager - This is used to read the global manager's data
@@ -46,7 +52,9 @@ func (GM *GlobalManager) LockGlobalWriteMutex() {
 	if GM.globalMu == nil {
 		GM.SetGlobalMutex()
 	}
-	GM.globalMu.Lock()
+	if observer, wait := timedLock(GM.globalMu.Lock); observer != nil {
+		observer(LockManager_Global, "", "", wait)
+	}
 }
 
 // UnlockGlobalWriteMutex unlocks the global write mutex for the global manager - This is used to write the global manager's data
@@ -110,6 +118,54 @@ func (GM *GlobalManager) AddAppManager(appName string, app *AppManager) *GlobalM
 	return GM
 }
 
+// TryAddAppManager adds the app manager unless that would exceed the configured MaxApps.
+// The limit check and the insert happen under the same write lock so concurrent creates can't overshoot.
+// Adding an already registered app is a no-op and never counts against the limit.
+func (GM *GlobalManager) TryAddAppManager(appName string, app *AppManager) error {
+	maxApps := 0
+	if md := GM.GetMetadata(); md != nil {
+		maxApps = md.GetMaxApps()
+	}
+
+	GM.LockGlobalWriteMutex()
+	defer GM.UnlockGlobalWriteMutex()
+	if _, ok := GM.AppManagers[appName]; ok {
+		return nil
+	}
+	if maxApps > 0 && len(GM.AppManagers) >= maxApps {
+		return fmt.Errorf("%w: %s, limit %d", Errors.ErrMaxAppsExceeded, appName, maxApps)
+	}
+	GM.AppManagers[appName] = app
+	return nil
+}
+
+// RenameAppManager re-keys the app manager under newName and updates its name.
+// The map is re-keyed under the global write lock so no lookup sees both or neither name.
+func (GM *GlobalManager) RenameAppManager(oldName, newName string) error {
+	GM.LockGlobalWriteMutex()
+	defer GM.UnlockGlobalWriteMutex()
+	app, ok := GM.AppManagers[oldName]
+	if !ok {
+		return fmt.Errorf("%w: %s", Errors.ErrAppManagerNotFound, oldName)
+	}
+	if _, exists := GM.AppManagers[newName]; exists {
+		return fmt.Errorf("%w: %s", Errors.ErrAppManagerExists, newName)
+	}
+	delete(GM.AppManagers, oldName)
+	GM.AppManagers[newName] = app
+
+	app.LockAppWriteMutex()
+	app.AppName = newName
+	// Local managers carry their app's name for lock instrumentation labels
+	for _, local := range app.LocalManagers {
+		local.lockLocalWriteMutex()
+		local.AppName = newName
+		local.unlockLocalWriteMutex()
+	}
+	app.UnlockAppWriteMutex()
+	return nil
+}
+
 // RemoveAppManager removes an app manager from the global manager
 func (GM *GlobalManager) RemoveAppManager(appName string) *GlobalManager {
 	GM.LockGlobalWriteMutex()
@@ -143,11 +199,12 @@ func (GM *GlobalManager) GetGlobalWaitGroup() *sync.WaitGroup {
 	return GM.Wg
 }
 
-// GetAppManagers gets all the app managers for the global manager
+// GetAppManagers gets all the app managers for the global manager.
+// The map is copied under the read lock, so ranging over it is safe while apps are created or renamed.
 func (GM *GlobalManager) GetAppManagers() map[string]*AppManager {
 	GM.LockGlobalReadMutex()
 	defer GM.UnlockGlobalReadMutex()
-	return GM.AppManagers
+	return maps.Clone(GM.AppManagers)
 }
 
 // GetAppManager gets a specific app manager for the global manager
@@ -166,3 +223,13 @@ func (GM *GlobalManager) GetAppManagerCount() int {
 	defer GM.UnlockGlobalReadMutex()
 	return len(GM.AppManagers)
 }
+
+// GetRoutineOwner returns the local manager tracking the routine, found through the global reverse index
+// kept up to date by AddRoutine and RemoveRoutine. Fails with ErrRoutineNotFound once the routine is untracked.
+func (GM *GlobalManager) GetRoutineOwner(routineID string) (*LocalManager, error) {
+	owner, ok := GM.routineOwners.Load(routineID)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", Errors.ErrRoutineNotFound, routineID)
+	}
+	return owner.(*LocalManager), nil
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/neerajchowdary889/GoRoutinesManager/Context"
 	"github.com/neerajchowdary889/GoRoutinesManager/Manager/Errors"
@@ -14,26 +15,54 @@ const (
 	Prefix_LocalManager = "LocalManager."
 )
 
-func newLocalManager(localName string, appName string) *LocalManager {
-	if IsIntilized().Local(localName, appName) {
-		LocalManager, err := NewAppManager(appName).GetLocalManager(localName)
-		if err != nil {
-			return nil
-		}
-		return LocalManager
+// LocalContextKey is the key the local manager's context is registered under.
+// It carries the app name, so same-named locals of different apps never share a context.
+func LocalContextKey(appName, localName string) string {
+	return Prefix_LocalManager + appName + "/" + localName
+}
+
+// newLocalManager builds the local manager and registers it with its app, a local already registered is returned as is.
+// It fails with ErrMaxLocalsExceeded when the app's local cap is reached or ErrAppManagerNotFound without the app,
+// the rejected local leaves no context behind.
+func newLocalManager(localName string, appName string, parentCtx context.Context) (*LocalManager, error) {
+	if IsIntilized().Local(appName, localName) {
+		return GetLocalManager(appName, localName)
+	}
+	// Checked before the local context is derived from the app's, which would create the app context
+	app, err := GetAppManager(appName)
+	if err != nil {
+		return nil, err
 	}
 
 	LocalManager := &LocalManager{
+		localMu:     &sync.RWMutex{}, // Set before the local is published, swapping it later would split the lock
 		LocalName:   localName,
-		Routines:    make(map[string]*Routine),
+		AppName:     appName,
+		Routines:    newRoutineStore(),
 		FunctionWgs: make(map[string]*sync.WaitGroup), // Initialize FunctionWgs map
 		Wg:          &sync.WaitGroup{},                // Initialize wait group for safe shutdown
+		ParentCtx:   parentCtx,
+		createdAt:   time.Now(),
 	}
+	// Everything is set before the local is published, readers never see a half built manager
+	LocalManager.SetLocalContext()
 
-	// Add the local manager to the app manager
-	SetLocalManager(appName, localName, LocalManager)
+	// Remember the scopes above so routine counts roll up without lookups
+	LocalManager.app = app
+	LocalManager.global = Global
 
-	return LocalManager
+	// Add the local manager to the app manager, unless the app's local cap is reached
+	if err := app.TryAddLocalManager(localName, LocalManager); err != nil {
+		LocalManager.Cancel()
+		return nil, err
+	}
+
+	// A concurrent create of the same name may have published first, everyone gets the published one
+	if published, err := GetLocalManager(appName, localName); err == nil && published != LocalManager {
+		return published, nil
+	}
+	LocalManager.AcquireAppContext()
+	return LocalManager, nil
 }
 
 // Lock APIs
@@ -42,7 +71,9 @@ func (LM *LocalManager) lockLocalReadMutex() {
 	if LM.localMu == nil {
 		LM.SetLocalMutex()
 	}
-	LM.localMu.RLock()
+	if observer, wait := timedLock(LM.localMu.RLock); observer != nil {
+		observer(LockManager_Local, LM.AppName, LM.LocalName, wait)
+	}
 }
 
 // UnlockAppReadMutex unlocks the app read mutex for the app manager - This is used to read the app manager's data
@@ -58,7 +89,9 @@ func (LM *LocalManager) lockLocalWriteMutex() {
 	if LM.localMu == nil {
 		LM.SetLocalMutex()
 	}
-	LM.localMu.Lock()
+	if observer, wait := timedLock(LM.localMu.Lock); observer != nil {
+		observer(LockManager_Local, LM.AppName, LM.LocalName, wait)
+	}
 }
 
 // UnlockAppWriteMutex unlocks the app write mutex for the app manager - This is used to write the app manager's data
@@ -85,20 +118,43 @@ func (LM *LocalManager) SetLocalName(localName string) *LocalManager {
 	return LM
 }
 
-// SetLocalContext sets the context for the local manager
+// SetLocalContext sets the context for the local manager, derived from its app's context
 func (LM *LocalManager) SetLocalContext() *LocalManager {
 	// Lock and update
 	LM.lockLocalWriteMutex()
 	defer LM.unlockLocalWriteMutex()
-	ctx := Context.GetAppContext(Prefix_LocalManager + LM.LocalName).Get()
+	ctx := Context.GetChildAppContext(LocalContextKey(LM.AppName, LM.LocalName), Prefix_AppManager+LM.AppName).Get()
 	Done := func() {
-		Context.GetAppContext(Prefix_LocalManager + LM.LocalName).Done(ctx)
+		Context.GetAppContext(LocalContextKey(LM.AppName, LM.LocalName)).Done(ctx)
 	}
 	LM.Ctx = ctx
 	LM.Cancel = Done
 	return LM
 }
 
+// AcquireAppContext takes a reference on the app's context for the local, so the local's siblings can't
+// cancel it under the local. Taking it again while holding it is a no-op.
+func (LM *LocalManager) AcquireAppContext() {
+	if LM.appContextRef.CompareAndSwap(false, true) {
+		Context.GetRefCountedAppContext(Prefix_AppManager + LM.AppName).Acquire()
+	}
+}
+
+// ReleaseAppContext drops the reference taken with AcquireAppContext, releasing without holding one is a no-op
+func (LM *LocalManager) ReleaseAppContext() {
+	if LM.appContextRef.CompareAndSwap(true, false) {
+		Context.GetRefCountedAppContext(Prefix_AppManager + LM.AppName).Release()
+	}
+}
+
+// CancelContext cancels the local manager's context, routines following it are cancelled with it
+func (LM *LocalManager) CancelContext() {
+	LM.lockLocalReadMutex()
+	ctx := LM.Ctx
+	LM.unlockLocalReadMutex()
+	Context.CancelAppContext(LocalContextKey(LM.AppName, LM.LocalName), ctx)
+}
+
 // SetLocalWaitGroup sets the wait group for the local manager
 func (LM *LocalManager) SetLocalWaitGroup() *LocalManager {
 	// Lock and update
@@ -134,9 +190,13 @@ func (LM *LocalManager) AddRoutine(routine *Routine) *LocalManager {
 	LM.lockLocalWriteMutex()
 	defer LM.unlockLocalWriteMutex()
 
-	LM.Routines[routine.ID] = routine
+	LM.Routines.Add(routine)
 	// Atomically increment routine count for lock-free reads
 	atomic.AddInt64(&LM.routineCount, 1)
+	LM.trackRoutines(1)
+	if LM.global != nil {
+		LM.global.routineOwners.Store(routine.ID, LM)
+	}
 	return LM
 }
 
@@ -155,11 +215,35 @@ func (LM *LocalManager) RemoveRoutine(routine *Routine, safe bool) *LocalManager
 	// TODO: safe or unsafe terminate is based on the flag
 
 	// Remove from the map
-	if _, exists := LM.Routines[routine.ID]; exists {
-		delete(LM.Routines, routine.ID)
+	if LM.Routines.Remove(routine.ID) {
 		// Atomically decrement routine count for lock-free reads
 		atomic.AddInt64(&LM.routineCount, -1)
+		LM.trackRoutines(-1)
+		if LM.global != nil {
+			LM.global.routineOwners.Delete(routine.ID)
+		}
+		// Remember routines that finished on their own, force removed ones are not completed
+		if routine.GetState() == RoutineStateCompleted {
+			LM.retainCompleted(routine)
+		}
+	}
+	return LM
+}
+
+// SetRoutineStore swaps the routine store, moving every tracked routine into the new one
+func (LM *LocalManager) SetRoutineStore(store RoutineStore) *LocalManager {
+	// Lock and update
+	LM.lockLocalWriteMutex()
+	defer LM.unlockLocalWriteMutex()
+
+	if LM.Routines != nil {
+		LM.Routines.Range(func(routine *Routine) bool {
+			store.Add(routine)
+			return true
+		})
 	}
+	LM.Routines = store
+	atomic.StoreInt64(&LM.routineCount, int64(store.Count()))
 	return LM
 }
 
@@ -170,6 +254,9 @@ func (LM *LocalManager) AddFunctionWg(functionName string) *LocalManager {
 	defer LM.unlockLocalWriteMutex()
 
 	LM.FunctionWgs[functionName] = &sync.WaitGroup{}
+	// A fresh group starts with nothing pending, and is kept until shutdown
+	delete(LM.functionWgStates, functionName)
+	LM.functionWgState(functionName).explicit = true
 	return LM
 }
 
@@ -181,6 +268,7 @@ func (LM *LocalManager) RemoveFunctionWg(functionName string) *LocalManager {
 	defer LM.unlockLocalWriteMutex()
 
 	delete(LM.FunctionWgs, functionName)
+	delete(LM.functionWgStates, functionName)
 	return LM
 }
 
@@ -190,10 +278,14 @@ func (LM *LocalManager) GetRoutine(routineID string) (*Routine, error) {
 	LM.lockLocalReadMutex()
 	defer LM.unlockLocalReadMutex()
 
-	if _, ok := LM.Routines[routineID]; !ok {
+	routine, ok := LM.Routines.Get(routineID)
+	if !ok {
+		if _, completed := LM.getCompletedRoutine(routineID); completed {
+			return nil, fmt.Errorf("%w: %s", Errors.ErrRoutineCompleted, routineID)
+		}
 		return nil, fmt.Errorf("%w: %s", Errors.ErrRoutineNotFound, routineID)
 	}
-	return LM.Routines[routineID], nil
+	return routine, nil
 }
 
 // GetRoutines gets all the routines for the local manager
@@ -201,12 +293,26 @@ func (LM *LocalManager) GetRoutines() map[string]*Routine {
     LM.lockLocalReadMutex()
     defer LM.unlockLocalReadMutex()
     
-	routinesCopy := make(map[string]*Routine, len(LM.Routines))
-	for k, v := range LM.Routines {
-		routinesCopy[k] = v
-	}
+	routinesCopy := make(map[string]*Routine, LM.Routines.Count())
+	LM.Routines.Range(func(routine *Routine) bool {
+		routinesCopy[routine.ID] = routine
+		return true
+	})
 	return routinesCopy
 }
+
+// AppendRoutines appends the tracked routines to dst without the intermediate map GetRoutines builds
+func (LM *LocalManager) AppendRoutines(dst []*Routine) []*Routine {
+	LM.lockLocalReadMutex()
+	defer LM.unlockLocalReadMutex()
+
+	LM.Routines.Range(func(routine *Routine) bool {
+		dst = append(dst, routine)
+		return true
+	})
+	return dst
+}
+
 // GetLocalContext gets the context for the local manager
 func (LM *LocalManager) GetLocalContext() (context.Context, context.CancelFunc) {
 	return LM.Ctx, LM.Cancel
@@ -236,7 +342,7 @@ func (LM *LocalManager) GetRoutineCount() int {
 		LM.lockLocalReadMutex()
 		defer LM.unlockLocalReadMutex()
 		// Reset atomic counter to actual value
-		actualCount := len(LM.Routines)
+		actualCount := LM.Routines.Count()
 		atomic.StoreInt64(&LM.routineCount, int64(actualCount))
 		return actualCount
 	}
@@ -266,4 +372,58 @@ func (LM *LocalManager) GetLocalWaitGroup() *sync.WaitGroup {
 	LM.lockLocalReadMutex()
 	defer LM.unlockLocalReadMutex()
 	return LM.Wg
-}
\ No newline at end of file
+}
+
+// functionCounters returns the accumulators for the function, creating them on first use
+func (LM *LocalManager) functionCounters(functionName string) *functionCounters {
+	if counters, ok := LM.functionStats.Load(functionName); ok {
+		return counters.(*functionCounters)
+	}
+	counters, _ := LM.functionStats.LoadOrStore(functionName, &functionCounters{})
+	return counters.(*functionCounters)
+}
+
+// RecordFunctionSpawn counts a routine spawned for the function
+func (LM *LocalManager) RecordFunctionSpawn(functionName string) {
+	atomic.AddInt64(&LM.functionCounters(functionName).spawned, 1)
+}
+
+// RecordFunctionFinish counts a finished routine of the function by outcome and adds its duration.
+// Expected stops are counted apart and don't add to the error rate.
+func (LM *LocalManager) RecordFunctionFinish(functionName string, duration time.Duration, class ErrorClass, panicked bool) {
+	counters := LM.functionCounters(functionName)
+	switch {
+	case panicked:
+		atomic.AddInt64(&counters.panicked, 1)
+	case class == ErrorClassFailure:
+		atomic.AddInt64(&counters.failed, 1)
+	case class == ErrorClassExpectedStop:
+		atomic.AddInt64(&counters.stopped, 1)
+	default:
+		atomic.AddInt64(&counters.completed, 1)
+	}
+	atomic.AddInt64(&counters.totalDuration, int64(duration))
+	LM.recordErrorRate(functionName, panicked || class == ErrorClassFailure)
+}
+
+// GetFunctionStats returns the aggregate stats for the function, all zero if it was never spawned
+func (LM *LocalManager) GetFunctionStats(functionName string) FunctionStats {
+	stats := FunctionStats{FunctionName: functionName}
+	value, ok := LM.functionStats.Load(functionName)
+	if !ok {
+		return stats
+	}
+	counters := value.(*functionCounters)
+
+	stats.Spawned = atomic.LoadInt64(&counters.spawned)
+	stats.Completed = atomic.LoadInt64(&counters.completed)
+	stats.Failed = atomic.LoadInt64(&counters.failed)
+	stats.Panicked = atomic.LoadInt64(&counters.panicked)
+	stats.Stopped = atomic.LoadInt64(&counters.stopped)
+	finished := stats.Completed + stats.Failed + stats.Panicked + stats.Stopped
+	stats.Live = stats.Spawned - finished
+	if finished > 0 {
+		stats.AverageDuration = time.Duration(atomic.LoadInt64(&counters.totalDuration) / finished)
+	}
+	return stats
+}
@@ -2,6 +2,8 @@ package types
 
 import (
 	"context"
+	"strings"
+	"sync"
 	"time"
 
 	Helper "github.com/neerajchowdary889/GoRoutinesManager/Helper/Routine"
@@ -9,8 +11,15 @@ import (
 
 const (
 	Prefix_Routine = "Routine."
+	// Function names starting with this prefix are reserved for internal bookkeeping routines
+	Prefix_ReservedFunction = "__"
 )
 
+// IsReservedFunctionName reports whether the function name falls in the reserved internal namespace
+func IsReservedFunctionName(functionName string) bool {
+	return strings.HasPrefix(functionName, Prefix_ReservedFunction)
+}
+
 // This should be reviewed again carefully to ensure goroutines are spawned quick and efficient
 // TODO
 
@@ -78,6 +87,60 @@ func (r *Routine) SetStartedAt(timestamp int64) *Routine {
 	return r
 }
 
+// SetSpawnSite sets the file:line where the routine was spawned
+func (r *Routine) SetSpawnSite(site string) *Routine {
+	r.SpawnSite = site
+	return r
+}
+
+// MarkLeaked flags the routine as leaked: it outlived its timeout without observing its context
+// Returns false if the routine was already marked
+func (r *Routine) MarkLeaked() bool {
+	return r.leaked.CompareAndSwap(false, true)
+}
+
+// SetShutdownDeadline announces the time by which the routine has to wrap up.
+// The earliest deadline wins, so a later ShutdownFunction can't extend a budget already given by Shutdown.
+func (r *Routine) SetShutdownDeadline(deadline time.Time) *Routine {
+	next := deadline.UnixNano()
+	for {
+		current := r.shutdownDeadline.Load()
+		if current != 0 && current <= next {
+			return r
+		}
+		if r.shutdownDeadline.CompareAndSwap(current, next) {
+			return r
+		}
+	}
+}
+
+// SetWaitGroups records the wait groups the routine was added to (after their Add(1))
+func (r *Routine) SetWaitGroups(waitGroups ...*sync.WaitGroup) *Routine {
+	r.waitGroups = waitGroups
+	return r
+}
+
+// ReleaseWaitGroups calls Done on every wait group of the routine.
+// Only the first call does anything, so completion and force-removal can both call it
+// without driving a counter negative. Returns true if this call released them.
+func (r *Routine) ReleaseWaitGroups() bool {
+	if !r.wgsReleased.CompareAndSwap(false, true) {
+		return false
+	}
+	// No longer pending by the time a waiter on the group wakes up
+	lastPending := r.functionWg != nil && r.functionWg.release()
+	for _, wg := range r.waitGroups {
+		if wg != nil {
+			wg.Done()
+		}
+	}
+	// Reclaim the group of a function that has nothing left running, dynamic function names would pile up otherwise
+	if lastPending && AutoCleanupFunctionWgs {
+		r.functionWg.cleanupIfIdle()
+	}
+	return true
+}
+
 // DoneChan returns the done channel for the routine (read-only).
 // The channel should be closed (not sent to) when the routine completes.
 // Consumers can select on this channel to detect routine completion.
@@ -106,3 +169,20 @@ func (r *Routine) GetStartedAt() int64 {
 	return r.StartedAt
 }
 
+func (r *Routine) GetSpawnSite() string {
+	return r.SpawnSite
+}
+
+// GetShutdownDeadline returns the shutdown deadline, ok is false until shutdown has begun
+func (r *Routine) GetShutdownDeadline() (time.Time, bool) {
+	deadline := r.shutdownDeadline.Load()
+	if deadline == 0 {
+		return time.Time{}, false
+	}
+	return time.Unix(0, deadline), true
+}
+
+// IsLeaked reports whether the routine kept running after its timeout and grace period
+func (r *Routine) IsLeaked() bool {
+	return r.leaked.Load()
+}
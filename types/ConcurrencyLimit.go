@@ -0,0 +1,67 @@
+package types
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/neerajchowdary889/GoRoutinesManager/Manager/Errors"
+)
+
+// concurrencyLimit returns the semaphore capping the routines spawned under name, created with n slots on first use.
+// Later calls get the existing semaphore whatever n they pass.
+func (LM *LocalManager) concurrencyLimit(name string, n int) (*Semaphore, error) {
+	if existing, ok := LM.concurrency.Load(name); ok {
+		return existing.(*Semaphore), nil
+	}
+	if n <= 0 {
+		return nil, fmt.Errorf("%w: %d", Errors.ErrInvalidSemaphoreSize, n)
+	}
+	semaphore, _ := LM.concurrency.LoadOrStore(name, newSemaphore(name, n))
+	return semaphore.(*Semaphore), nil
+}
+
+// AcquireConcurrency takes a slot of the per function limit name for a routine of functionName, allowing n routines at once.
+// Functions sharing a limit get slots in proportion to their weights under contention, see SetFunctionWeight.
+// With wait it blocks until a slot frees up or the local context is done, otherwise it fails right away
+// with ErrMaxConcurrencyExceeded. The caller releases the slot with ReleaseFor(functionName) when the routine is untracked.
+func (LM *LocalManager) AcquireConcurrency(name, functionName string, n int, wait bool) (*Semaphore, error) {
+	semaphore, err := LM.concurrencyLimit(name, n)
+	if err != nil {
+		return nil, err
+	}
+	if !wait {
+		if !semaphore.TryAcquireFor(functionName) {
+			return nil, fmt.Errorf("%w: %s, limit %d", Errors.ErrMaxConcurrencyExceeded, name, semaphore.Size())
+		}
+		return semaphore, nil
+	}
+
+	LM.lockLocalReadMutex()
+	ctx := LM.Ctx
+	LM.unlockLocalReadMutex()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if err := semaphore.AcquireFor(ctx, functionName, LM.GetFunctionWeight(functionName)); err != nil {
+		return nil, err
+	}
+	return semaphore, nil
+}
+
+// SetFunctionWeight sets the share of contended semaphore slots the routines of functionName get,
+// relative to the other functions waiting on the same semaphore. Functions default to a weight of 1.
+func (LM *LocalManager) SetFunctionWeight(functionName string, weight int) error {
+	if weight <= 0 {
+		return fmt.Errorf("%w: %d", Errors.ErrInvalidWeight, weight)
+	}
+	LM.weights.Store(functionName, weight)
+	return nil
+}
+
+// GetFunctionWeight returns the weight of functionName, 1 unless set with SetFunctionWeight
+func (LM *LocalManager) GetFunctionWeight(functionName string) int {
+	if weight, ok := LM.weights.Load(functionName); ok {
+		return weight.(int)
+	}
+	return 1
+}
@@ -0,0 +1,44 @@
+package types
+
+// DetectIgnoredContexts turns on the context misuse diagnostic for routines spawned afterwards.
+// Workers then get a context recording whether they ever called Done, Err or Value on it, and a routine
+// still running when its context is cancelled without having done so is flagged as ignoring its context.
+// Off by default, every context access of the worker pays for an atomic load.
+var DetectIgnoredContexts = false
+
+// MarkContextObserved records that the worker looked at its context
+func (r *Routine) MarkContextObserved() {
+	if !r.ctxObserved.Load() {
+		r.ctxObserved.Store(true)
+	}
+}
+
+// ContextObserved reports whether the worker ever called Done, Err or Value on its context
+func (r *Routine) ContextObserved() bool {
+	return r.ctxObserved.Load()
+}
+
+// MarkIgnoringContext flags the routine as ignoring its context, returns false if it already was
+func (r *Routine) MarkIgnoringContext() bool {
+	return r.ignoringCtx.CompareAndSwap(false, true)
+}
+
+// IsIgnoringContext reports whether the routine was cancelled before it ever looked at its context
+func (r *Routine) IsIgnoringContext() bool {
+	return r.ignoringCtx.Load()
+}
+
+// GetContextIgnoringRoutines returns the tracked routines flagged as ignoring their context
+func (LM *LocalManager) GetContextIgnoringRoutines() []*Routine {
+	LM.lockLocalReadMutex()
+	defer LM.unlockLocalReadMutex()
+
+	var routines []*Routine
+	LM.Routines.Range(func(routine *Routine) bool {
+		if routine.IsIgnoringContext() {
+			routines = append(routines, routine)
+		}
+		return true
+	})
+	return routines
+}
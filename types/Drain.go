@@ -0,0 +1,13 @@
+package types
+
+// SetDraining puts the local manager in or out of drain mode. While draining, spawns are rejected with
+// ErrDraining and routines already running are left alone.
+func (LM *LocalManager) SetDraining(draining bool) *LocalManager {
+	LM.draining.Store(draining)
+	return LM
+}
+
+// IsDraining reports whether the local manager is rejecting new spawns
+func (LM *LocalManager) IsDraining() bool {
+	return LM.draining.Load()
+}
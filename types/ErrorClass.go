@@ -0,0 +1,44 @@
+package types
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrorClass is how a worker's return value counts in the function stats and error rates
+type ErrorClass int32
+
+const (
+	// ErrorClassSuccess: the worker did its job
+	ErrorClassSuccess ErrorClass = iota
+	// ErrorClassExpectedStop: the worker stopped because it was asked to, e.g. context.Canceled during shutdown
+	ErrorClassExpectedStop
+	// ErrorClassFailure: the worker failed
+	ErrorClassFailure
+)
+
+func (c ErrorClass) String() string {
+	switch c {
+	case ErrorClassSuccess:
+		return "success"
+	case ErrorClassExpectedStop:
+		return "expected_stop"
+	case ErrorClassFailure:
+		return "failure"
+	default:
+		return "unknown"
+	}
+}
+
+// DefaultErrorClassifier treats nil as success, context.Canceled and context.DeadlineExceeded as expected stops
+// and any other error as a failure
+func DefaultErrorClassifier(err error) ErrorClass {
+	switch {
+	case err == nil:
+		return ErrorClassSuccess
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return ErrorClassExpectedStop
+	default:
+		return ErrorClassFailure
+	}
+}
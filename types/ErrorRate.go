@@ -0,0 +1,113 @@
+package types
+
+import (
+	"sync"
+	"time"
+
+	"github.com/neerajchowdary889/GoRoutinesManager/Logging"
+)
+
+// errorRateBuckets is the number of time buckets a rolling error rate window is split into
+const errorRateBuckets = 10
+
+// errorRateBucket counts the finished routines of one slice of the window
+type errorRateBucket struct {
+	start  int64 // unix nano start of the slice, 0 if unused
+	total  int64
+	failed int64
+}
+
+// errorRateMonitor keeps a rolling success/failure window for a function and reports threshold breaches.
+// The window is split into fixed width buckets in a ring, a bucket is reset when its slot comes around again.
+type errorRateMonitor struct {
+	mu          sync.Mutex
+	ratio       float64
+	window      time.Duration
+	bucketWidth int64
+	buckets     [errorRateBuckets]errorRateBucket
+	onBreach    func(functionName string, rate float64)
+	breached    bool // true while above the threshold, the callback fires once per breach
+}
+
+func newErrorRateMonitor(ratio float64, window time.Duration, onBreach func(functionName string, rate float64)) *errorRateMonitor {
+	bucketWidth := int64(window) / errorRateBuckets
+	if bucketWidth <= 0 {
+		bucketWidth = 1
+	}
+	return &errorRateMonitor{
+		ratio:       ratio,
+		window:      window,
+		bucketWidth: bucketWidth,
+		onBreach:    onBreach,
+	}
+}
+
+// record adds a finished routine and returns the current rate and whether it just crossed the threshold
+func (m *errorRateMonitor) record(now time.Time, failed bool) (float64, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	nowNano := now.UnixNano()
+	start := nowNano - nowNano%m.bucketWidth
+	bucket := &m.buckets[(start/m.bucketWidth)%errorRateBuckets]
+	if bucket.start != start {
+		*bucket = errorRateBucket{start: start}
+	}
+	bucket.total++
+	if failed {
+		bucket.failed++
+	}
+
+	var total, failures int64
+	oldest := nowNano - int64(m.window)
+	for _, b := range m.buckets {
+		if b.start != 0 && b.start > oldest {
+			total += b.total
+			failures += b.failed
+		}
+	}
+	rate := float64(failures) / float64(total)
+
+	if rate <= m.ratio {
+		// Back under the threshold, the next breach is reported again
+		m.breached = false
+		return rate, false
+	}
+	if m.breached {
+		return rate, false
+	}
+	m.breached = true
+	return rate, true
+}
+
+// SetErrorRateThreshold watches the error rate of the function over a rolling window.
+// onBreach is called when the ratio of failed (error or panic) to finished routines goes above ratio,
+// and again only after the rate has dropped back to or below it. A nil onBreach removes the threshold.
+func (LM *LocalManager) SetErrorRateThreshold(functionName string, ratio float64, window time.Duration, onBreach func(functionName string, rate float64)) *LocalManager {
+	if onBreach == nil {
+		LM.errorRates.Delete(functionName)
+		return LM
+	}
+	LM.errorRates.Store(functionName, newErrorRateMonitor(ratio, window, onBreach))
+	return LM
+}
+
+// recordErrorRate feeds a finished routine into the function's error rate window, if one is set.
+// The breach callback runs on the calling goroutine, a panic in it is recovered.
+func (LM *LocalManager) recordErrorRate(functionName string, failed bool) {
+	value, ok := LM.errorRates.Load(functionName)
+	if !ok {
+		return
+	}
+	monitor := value.(*errorRateMonitor)
+	rate, breached := monitor.record(time.Now(), failed)
+	if !breached {
+		return
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			Logging.Error("error rate callback panicked", "function", functionName, "panic", r)
+		}
+	}()
+	monitor.onBreach(functionName, rate)
+}
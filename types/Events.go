@@ -0,0 +1,158 @@
+package types
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/neerajchowdary889/GoRoutinesManager/Logging"
+)
+
+// EventType is the kind of lifecycle event published on the EventBus.
+type EventType int
+
+const (
+	EventAppCreated       EventType = iota // an app manager was created
+	EventLocalCreated                      // a local manager was created
+	EventRoutineStarted                    // a routine started running its worker
+	EventRoutineCompleted                  // a routine finished, Err holds what the worker returned or why it panicked
+	EventShutdownStarted                   // a global, app, local or function shutdown began
+)
+
+func (t EventType) String() string {
+	switch t {
+	case EventAppCreated:
+		return "app-created"
+	case EventLocalCreated:
+		return "local-created"
+	case EventRoutineStarted:
+		return "routine-started"
+	case EventRoutineCompleted:
+		return "routine-completed"
+	case EventShutdownStarted:
+		return "shutdown-started"
+	default:
+		return "unknown"
+	}
+}
+
+// Event is a lifecycle event of the managers.
+// Names are empty above the scope the event describes: a global shutdown has all of them empty,
+// a created local manager has an empty Function and RoutineID.
+type Event struct {
+	Type      EventType
+	Time      time.Time
+	App       string
+	Local     string
+	Function  string
+	RoutineID string
+	Err       error
+}
+
+// EventBufferSize is how many events a subscriber can fall behind before further events are dropped for it
+const EventBufferSize = 1024
+
+// EventBus fans lifecycle events out to subscribers.
+// Delivery is asynchronous: each subscriber has its own goroutine and buffer and receives events in
+// publish order, a subscriber whose buffer is full misses events instead of stalling the publisher.
+type EventBus struct {
+	mu          sync.RWMutex
+	subscribers map[*eventSubscriber]struct{}
+	count       atomic.Int32
+}
+
+// eventSubscriber is one subscription of an EventBus
+type eventSubscriber struct {
+	fn      func(Event)
+	events  chan Event
+	dropped atomic.Int64
+}
+
+var defaultEventBus EventBus
+
+// Events returns the event bus the managers publish their lifecycle events on
+func Events() *EventBus {
+	return &defaultEventBus
+}
+
+// Subscribe calls fn for every event published from now on until the returned unsubscribe is called.
+// Events already buffered for the subscriber are still delivered after unsubscribing.
+func (b *EventBus) Subscribe(fn func(Event)) (unsubscribe func()) {
+	sub := &eventSubscriber{
+		fn:     fn,
+		events: make(chan Event, EventBufferSize),
+	}
+	b.mu.Lock()
+	if b.subscribers == nil {
+		b.subscribers = make(map[*eventSubscriber]struct{})
+	}
+	b.subscribers[sub] = struct{}{}
+	b.count.Add(1)
+	b.mu.Unlock()
+
+	go sub.deliver()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.subscribers, sub)
+			b.count.Add(-1)
+			b.mu.Unlock()
+			// Publishers send under the read lock, so none is sending anymore
+			close(sub.events)
+		})
+	}
+}
+
+// Publish hands the event to every subscriber without blocking, a zero Time is set to now.
+func (b *EventBus) Publish(event Event) {
+	if b.count.Load() == 0 {
+		return
+	}
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for sub := range b.subscribers {
+		select {
+		case sub.events <- event:
+		default:
+			// Log the first drop of every buffer's worth so a slow subscriber doesn't flood the log
+			if sub.dropped.Add(1)%EventBufferSize == 1 {
+				Logging.Warn("event subscriber is falling behind", "dropped", sub.dropped.Load())
+			}
+		}
+	}
+}
+
+// deliver runs the subscriber over its events until it unsubscribes
+func (s *eventSubscriber) deliver() {
+	for event := range s.events {
+		s.call(event)
+	}
+}
+
+// call runs the subscriber, a panicking subscriber is recovered so it keeps receiving events
+func (s *eventSubscriber) call(event Event) {
+	defer func() {
+		if r := recover(); r != nil {
+			Logging.Error("event subscriber panicked", "event", event.Type, "app", event.App, "local", event.Local,
+				"function", event.Function, "panic", r)
+		}
+	}()
+	s.fn(event)
+}
+
+// PublishEvent publishes an event on the default bus, it costs an atomic load when nobody subscribed
+func PublishEvent(eventType EventType, app, local, function, routineID string, err error) {
+	defaultEventBus.Publish(Event{
+		Type:      eventType,
+		App:       app,
+		Local:     local,
+		Function:  function,
+		RoutineID: routineID,
+		Err:       err,
+	})
+}
@@ -0,0 +1,58 @@
+package types
+
+import (
+	"context"
+	"sync"
+)
+
+// functionStarts counts per function how many routines have begun running their worker.
+// Waiters block on changed, which is closed and replaced on every start.
+type functionStarts struct {
+	mu      sync.Mutex
+	counts  map[string]int
+	changed chan struct{}
+}
+
+// RecordFunctionStart counts a routine of the function that is about to run its worker
+func (LM *LocalManager) RecordFunctionStart(functionName string) {
+	LM.starts.mu.Lock()
+	defer LM.starts.mu.Unlock()
+	if LM.starts.counts == nil {
+		LM.starts.counts = make(map[string]int)
+	}
+	LM.starts.counts[functionName]++
+	if LM.starts.changed != nil {
+		close(LM.starts.changed)
+		LM.starts.changed = nil
+	}
+}
+
+// GetFunctionStarted returns how many routines of the function have started since the local manager was created
+func (LM *LocalManager) GetFunctionStarted(functionName string) int {
+	LM.starts.mu.Lock()
+	defer LM.starts.mu.Unlock()
+	return LM.starts.counts[functionName]
+}
+
+// WaitFunctionStarted blocks until at least n routines of the function have started or ctx is done.
+// Starts are counted since the local manager was created, routines that already finished still count.
+func (LM *LocalManager) WaitFunctionStarted(ctx context.Context, functionName string, n int) error {
+	for {
+		LM.starts.mu.Lock()
+		if LM.starts.counts[functionName] >= n {
+			LM.starts.mu.Unlock()
+			return nil
+		}
+		if LM.starts.changed == nil {
+			LM.starts.changed = make(chan struct{})
+		}
+		changed := LM.starts.changed
+		LM.starts.mu.Unlock()
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package types
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// FunctionWaitGroupInfo describes a function wait group of a local manager
+type FunctionWaitGroupInfo struct {
+	FunctionName string
+	Pending      int64 // routines added to the group that haven't released it yet
+	Draining     bool  // a ShutdownFunction for the group is in progress
+}
+
+// functionWaitGroupState is the bookkeeping kept next to a function wait group
+type functionWaitGroupState struct {
+	owner        *LocalManager
+	functionName string
+	pending      atomic.Int64
+	draining     atomic.Bool
+	// Created explicitly rather than on a routine's join, such a group is kept until shutdown.
+	// Only read and written under the write lock.
+	explicit bool
+}
+
+// release drops a pending routine, returning true if it was the last one
+func (s *functionWaitGroupState) release() bool {
+	return s.pending.Add(-1) == 0
+}
+
+// cleanupIfIdle removes the function wait group once nothing is pending in it, unless it was created explicitly.
+// The check is repeated under the lock since a routine may have joined in the meantime,
+// and the group is left alone if it was replaced or removed by someone else.
+func (s *functionWaitGroupState) cleanupIfIdle() {
+	LM := s.owner
+	LM.lockLocalWriteMutex()
+	defer LM.unlockLocalWriteMutex()
+
+	if LM.functionWgStates[s.functionName] != s || s.explicit || s.pending.Load() != 0 {
+		return
+	}
+	delete(LM.FunctionWgs, s.functionName)
+	delete(LM.functionWgStates, s.functionName)
+}
+
+// JoinFunctionWg adds a slot for the routine to the function wait group, creating the group if needed,
+// and counts the routine as pending until it releases its wait groups.
+// Both happen under the lock so the group can't be swapped out in between.
+// created reports whether the wait group was created by this call.
+func (LM *LocalManager) JoinFunctionWg(routine *Routine, functionName string) (wg *sync.WaitGroup, created bool) {
+	LM.lockLocalWriteMutex()
+	defer LM.unlockLocalWriteMutex()
+
+	wg, ok := LM.FunctionWgs[functionName]
+	if !ok {
+		wg = &sync.WaitGroup{}
+		LM.FunctionWgs[functionName] = wg
+	}
+	state := LM.functionWgState(functionName)
+	state.pending.Add(1)
+	wg.Add(1)
+	routine.functionWg = state
+	return wg, !ok
+}
+
+// GetOrCreateFunctionWg returns the function wait group, creating it if needed.
+// The lookup and the insert happen under one lock so concurrent callers always share a single group,
+// created reports whether the wait group was created by this call.
+// The group is asked for explicitly, so it isn't reclaimed when its routines finish.
+func (LM *LocalManager) GetOrCreateFunctionWg(functionName string) (wg *sync.WaitGroup, created bool) {
+	LM.lockLocalWriteMutex()
+	defer LM.unlockLocalWriteMutex()
+
+	wg, ok := LM.FunctionWgs[functionName]
+	if !ok {
+		wg = &sync.WaitGroup{}
+		LM.FunctionWgs[functionName] = wg
+	}
+	LM.functionWgState(functionName).explicit = true
+	return wg, !ok
+}
+
+// SetFunctionWgDraining marks the function wait group as being drained by a shutdown
+func (LM *LocalManager) SetFunctionWgDraining(functionName string, draining bool) *LocalManager {
+	LM.lockLocalWriteMutex()
+	defer LM.unlockLocalWriteMutex()
+	if _, ok := LM.FunctionWgs[functionName]; ok {
+		LM.functionWgState(functionName).draining.Store(draining)
+	}
+	return LM
+}
+
+// GetFunctionWaitGroups lists the function wait groups, sorted by function name
+func (LM *LocalManager) GetFunctionWaitGroups() []FunctionWaitGroupInfo {
+	LM.lockLocalReadMutex()
+	defer LM.unlockLocalReadMutex()
+
+	infos := make([]FunctionWaitGroupInfo, 0, len(LM.FunctionWgs))
+	for functionName := range LM.FunctionWgs {
+		info := FunctionWaitGroupInfo{FunctionName: functionName}
+		if state, ok := LM.functionWgStates[functionName]; ok {
+			info.Pending = state.pending.Load()
+			info.Draining = state.draining.Load()
+		}
+		infos = append(infos, info)
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].FunctionName < infos[j].FunctionName
+	})
+	return infos
+}
+
+// functionWgState returns the state of the function wait group, creating it on first use.
+// Must be called with the write lock held.
+func (LM *LocalManager) functionWgState(functionName string) *functionWaitGroupState {
+	if LM.functionWgStates == nil {
+		LM.functionWgStates = make(map[string]*functionWaitGroupState)
+	}
+	state, ok := LM.functionWgStates[functionName]
+	if !ok {
+		state = &functionWaitGroupState{owner: LM, functionName: functionName}
+		LM.functionWgStates[functionName] = state
+	}
+	return state
+}
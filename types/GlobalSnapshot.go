@@ -0,0 +1,156 @@
+package types
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+)
+
+// GlobalSnapshot is a serializable point-in-time view of every app, local manager and routine, returned by Snapshot.
+// Unlike TreeSnapshot it lists the routines themselves, but levels are read one after the other,
+// so routines spawned or finished during the traversal may or may not appear.
+type GlobalSnapshot struct {
+	TakenAt time.Time
+	Apps    []AppManagerSnapshot // sorted by name
+}
+
+// AppManagerSnapshot is the part of a GlobalSnapshot for one app manager
+type AppManagerSnapshot struct {
+	Name   string
+	Locals []LocalManagerSnapshot // sorted by name
+}
+
+// LocalManagerSnapshot is the part of a GlobalSnapshot for one local manager
+type LocalManagerSnapshot struct {
+	Name               string
+	Draining           bool
+	FunctionWaitGroups []FunctionWaitGroupInfo
+	Routines           []RoutineSnapshot // oldest first
+}
+
+// RoutineSnapshot describes one tracked routine when the snapshot was taken
+type RoutineSnapshot struct {
+	ID               string
+	FunctionName     string
+	State            RoutineState
+	StartedAt        time.Time
+	Uptime           time.Duration
+	ContextCancelled bool
+}
+
+// Snapshot walks the tree taking each app and local manager's read lock only while copying it,
+// so spawns elsewhere aren't blocked for the whole traversal.
+func (GM *GlobalManager) Snapshot() GlobalSnapshot {
+	now := time.Now()
+	snapshot := GlobalSnapshot{TakenAt: now, Apps: []AppManagerSnapshot{}}
+
+	GM.LockGlobalReadMutex()
+	apps := make([]*AppManager, 0, len(GM.AppManagers))
+	for _, app := range GM.AppManagers {
+		apps = append(apps, app)
+	}
+	GM.UnlockGlobalReadMutex()
+	sort.Slice(apps, func(i, j int) bool { return apps[i].AppName < apps[j].AppName })
+
+	for _, app := range apps {
+		locals := app.LocalManagersSnapshot()
+		sort.Slice(locals, func(i, j int) bool { return locals[i].LocalName < locals[j].LocalName })
+		appSnapshot := AppManagerSnapshot{Name: app.AppName, Locals: make([]LocalManagerSnapshot, 0, len(locals))}
+		for _, local := range locals {
+			appSnapshot.Locals = append(appSnapshot.Locals, local.snapshot(now))
+		}
+		snapshot.Apps = append(snapshot.Apps, appSnapshot)
+	}
+	return snapshot
+}
+
+func (LM *LocalManager) snapshot(now time.Time) LocalManagerSnapshot {
+	routines := LM.AppendRoutines(nil)
+	localSnapshot := LocalManagerSnapshot{
+		Name:               LM.LocalName,
+		Draining:           LM.IsDraining(),
+		FunctionWaitGroups: LM.GetFunctionWaitGroups(),
+		Routines:           make([]RoutineSnapshot, 0, len(routines)),
+	}
+	for _, routine := range routines {
+		startedAt := time.Unix(0, routine.GetStartedAt())
+		cancelled := false
+		if ctx := routine.GetContext(); ctx != nil {
+			cancelled = ctx.Err() != nil
+		}
+		localSnapshot.Routines = append(localSnapshot.Routines, RoutineSnapshot{
+			ID:               routine.GetID(),
+			FunctionName:     routine.GetFunctionName(),
+			State:            routine.GetState(),
+			StartedAt:        startedAt,
+			Uptime:           now.Sub(startedAt),
+			ContextCancelled: cancelled,
+		})
+	}
+	sort.Slice(localSnapshot.Routines, func(i, j int) bool {
+		return localSnapshot.Routines[i].StartedAt.Before(localSnapshot.Routines[j].StartedAt)
+	})
+	return localSnapshot
+}
+
+// MarshalJSON encodes the snapshot with snake_case keys, ready to be served from an HTTP handler
+func (GS GlobalSnapshot) MarshalJSON() ([]byte, error) {
+	type app struct {
+		Name   string                 `json:"name"`
+		Locals []LocalManagerSnapshot `json:"locals"`
+	}
+	apps := make([]app, 0, len(GS.Apps))
+	routines := 0
+	for _, a := range GS.Apps {
+		locals := a.Locals
+		if locals == nil {
+			locals = []LocalManagerSnapshot{}
+		}
+		for _, local := range locals {
+			routines += len(local.Routines)
+		}
+		apps = append(apps, app{Name: a.Name, Locals: locals})
+	}
+	return json.Marshal(struct {
+		TakenAt  time.Time `json:"taken_at"`
+		Routines int       `json:"routines"`
+		Apps     []app     `json:"apps"`
+	}{GS.TakenAt, routines, apps})
+}
+
+// MarshalJSON encodes the local manager with snake_case keys
+func (LS LocalManagerSnapshot) MarshalJSON() ([]byte, error) {
+	type waitGroup struct {
+		FunctionName string `json:"function_name"`
+		Pending      int64  `json:"pending"`
+		Draining     bool   `json:"draining"`
+	}
+	waitGroups := make([]waitGroup, 0, len(LS.FunctionWaitGroups))
+	for _, wg := range LS.FunctionWaitGroups {
+		waitGroups = append(waitGroups, waitGroup{wg.FunctionName, wg.Pending, wg.Draining})
+	}
+	routines := LS.Routines
+	if routines == nil {
+		routines = []RoutineSnapshot{}
+	}
+	return json.Marshal(struct {
+		Name               string            `json:"name"`
+		Draining           bool              `json:"draining"`
+		FunctionWaitGroups []waitGroup       `json:"function_wait_groups"`
+		Routines           []RoutineSnapshot `json:"routines"`
+	}{LS.Name, LS.Draining, waitGroups, routines})
+}
+
+// MarshalJSON encodes the routine with snake_case keys, the state as its name and the uptime
+// both as a duration string and in milliseconds
+func (RS RoutineSnapshot) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		ID               string    `json:"id"`
+		FunctionName     string    `json:"function_name"`
+		State            string    `json:"state"`
+		StartedAt        time.Time `json:"started_at"`
+		Uptime           string    `json:"uptime"`
+		UptimeMs         int64     `json:"uptime_ms"`
+		ContextCancelled bool      `json:"context_cancelled"`
+	}{RS.ID, RS.FunctionName, RS.State.String(), RS.StartedAt, RS.Uptime.String(), RS.Uptime.Milliseconds(), RS.ContextCancelled})
+}
@@ -71,7 +71,7 @@ func (Is Initializer) Routine(appName, localName, routineID string) bool {
 
 	// Local RLock and RUnlock
 	localMgr.lockLocalReadMutex()
-	_, ok = localMgr.Routines[routineID]
+	_, ok = localMgr.Routines.Get(routineID)
 	localMgr.unlockLocalReadMutex()
 	return ok
 }
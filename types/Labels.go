@@ -0,0 +1,56 @@
+package types
+
+import (
+	"sort"
+	"strings"
+)
+
+// SetLabels attaches the key/value tags the routine was spawned with, the map is copied
+func (r *Routine) SetLabels(labels map[string]string) *Routine {
+	if len(labels) == 0 {
+		return r
+	}
+	copied := make(map[string]string, len(labels))
+	for key, value := range labels {
+		copied[key] = value
+	}
+	r.labels.Store(&copied)
+	return r
+}
+
+// GetLabels returns a copy of the routine's labels, nil if it was spawned without any
+func (r *Routine) GetLabels() map[string]string {
+	labels := r.labels.Load()
+	if labels == nil {
+		return nil
+	}
+	copied := make(map[string]string, len(*labels))
+	for key, value := range *labels {
+		copied[key] = value
+	}
+	return copied
+}
+
+// HasLabel reports whether the routine carries the label key with the given value
+func (r *Routine) HasLabel(key, value string) bool {
+	labels := r.labels.Load()
+	if labels == nil {
+		return false
+	}
+	got, ok := (*labels)[key]
+	return ok && got == value
+}
+
+// FormatLabels renders a label set as "k1=v1,k2=v2" sorted by key, so the same set always gives the same string
+func FormatLabels(labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, len(keys))
+	for i, key := range keys {
+		pairs[i] = key + "=" + labels[key]
+	}
+	return strings.Join(pairs, ",")
+}
@@ -0,0 +1,42 @@
+package types
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Manager labels reported to the lock wait observer
+const (
+	LockManager_Global = "global"
+	LockManager_App    = "app"
+	LockManager_Local  = "local"
+)
+
+// LockWaitObserver receives how long acquiring a manager mutex took.
+// appName and localName are empty for managers above them.
+type LockWaitObserver func(manager, appName, localName string, wait time.Duration)
+
+var lockWaitObserver atomic.Pointer[LockWaitObserver]
+
+// SetLockWaitObserver enables lock wait instrumentation on every manager mutex, nil disables it.
+// Without an observer locking isn't timed at all.
+func SetLockWaitObserver(observer LockWaitObserver) {
+	if observer == nil {
+		lockWaitObserver.Store(nil)
+		return
+	}
+	lockWaitObserver.Store(&observer)
+}
+
+// timedLock runs lock and returns the observer to report to with the time it took, nil if instrumentation is off.
+// Reporting is left to the caller so the labels can be read once the lock is held.
+func timedLock(lock func()) (LockWaitObserver, time.Duration) {
+	observer := lockWaitObserver.Load()
+	if observer == nil {
+		lock()
+		return nil, 0
+	}
+	start := time.Now()
+	lock()
+	return *observer, time.Since(start)
+}
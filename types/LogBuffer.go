@@ -0,0 +1,53 @@
+package types
+
+import "sync"
+
+// LogBuffer keeps the last lines a routine logged, dropping the oldest once full
+type LogBuffer struct {
+	mu    sync.Mutex
+	lines []string
+	next  int
+	full  bool
+}
+
+// NewLogBuffer returns a buffer holding up to size lines, at least one
+func NewLogBuffer(size int) *LogBuffer {
+	if size < 1 {
+		size = 1
+	}
+	return &LogBuffer{lines: make([]string, size)}
+}
+
+// Append adds a line, overwriting the oldest one when the buffer is full
+func (b *LogBuffer) Append(line string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.lines[b.next] = line
+	b.next = (b.next + 1) % len(b.lines)
+	if b.next == 0 {
+		b.full = true
+	}
+}
+
+// Lines returns a copy of the buffered lines, oldest first
+func (b *LogBuffer) Lines() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.full {
+		return append([]string(nil), b.lines[:b.next]...)
+	}
+	out := make([]string, 0, len(b.lines))
+	out = append(out, b.lines[b.next:]...)
+	return append(out, b.lines[:b.next]...)
+}
+
+// SetLogBuffer attaches the buffer Local.Logf writes the routine's lines to
+func (r *Routine) SetLogBuffer(buffer *LogBuffer) *Routine {
+	r.logs.Store(buffer)
+	return r
+}
+
+// GetLogBuffer returns the routine's log buffer, nil if it wasn't spawned with one
+func (r *Routine) GetLogBuffer() *LogBuffer {
+	return r.logs.Load()
+}
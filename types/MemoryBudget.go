@@ -0,0 +1,83 @@
+package types
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"github.com/neerajchowdary889/GoRoutinesManager/Manager/Errors"
+)
+
+// memoryBudget is an admission counter for the estimated memory of running routines.
+// The estimates are what callers declared, nothing is measured. A limit of 0 means unlimited.
+type memoryBudget struct {
+	limit atomic.Int64
+	used  atomic.Int64
+}
+
+// reserve adds bytes to the used estimate unless that would go over the limit
+func (b *memoryBudget) reserve(bytes int64) bool {
+	for {
+		used := b.used.Load()
+		limit := b.limit.Load()
+		if limit > 0 && used+bytes > limit {
+			return false
+		}
+		if b.used.CompareAndSwap(used, used+bytes) {
+			return true
+		}
+	}
+}
+
+func (b *memoryBudget) release(bytes int64) {
+	b.used.Add(-bytes)
+}
+
+// SetMemoryBudget sets the total estimated memory the routines of the local manager may hold, 0 means unlimited.
+// Routines already running keep their reservation even if the new budget is lower.
+func (LM *LocalManager) SetMemoryBudget(bytes int64) error {
+	if bytes < 0 {
+		return fmt.Errorf("%w: %d", Errors.ErrInvalidMemoryBudget, bytes)
+	}
+	LM.memory.limit.Store(bytes)
+	return nil
+}
+
+// GetMemoryInUse returns the estimated memory reserved by the running routines of the local manager
+func (LM *LocalManager) GetMemoryInUse() int64 {
+	return LM.memory.used.Load()
+}
+
+// SetMemoryBudget sets the total estimated memory routines across the whole tree may hold, 0 means unlimited
+func (GM *GlobalManager) SetMemoryBudget(bytes int64) error {
+	if bytes < 0 {
+		return fmt.Errorf("%w: %d", Errors.ErrInvalidMemoryBudget, bytes)
+	}
+	GM.memory.limit.Store(bytes)
+	return nil
+}
+
+// GetMemoryInUse returns the estimated memory reserved by running routines across the whole tree
+func (GM *GlobalManager) GetMemoryInUse() int64 {
+	return GM.memory.used.Load()
+}
+
+// ReserveMemory admits a routine with the given memory estimate against the local and the global budget.
+// Nothing is reserved if either budget would be exceeded.
+func (LM *LocalManager) ReserveMemory(bytes int64) error {
+	if !LM.memory.reserve(bytes) {
+		return fmt.Errorf("%w: %d bytes over the budget of local %s", Errors.ErrMemoryBudgetExceeded, bytes, LM.LocalName)
+	}
+	if global, err := GetGlobalManager(); err == nil && !global.memory.reserve(bytes) {
+		LM.memory.release(bytes)
+		return fmt.Errorf("%w: %d bytes over the global budget", Errors.ErrMemoryBudgetExceeded, bytes)
+	}
+	return nil
+}
+
+// ReleaseMemory returns a reservation made with ReserveMemory
+func (LM *LocalManager) ReleaseMemory(bytes int64) {
+	LM.memory.release(bytes)
+	if global, err := GetGlobalManager(); err == nil {
+		global.memory.release(bytes)
+	}
+}
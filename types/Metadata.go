@@ -15,6 +15,7 @@ func (GM *GlobalManager) NewMetadata() *Metadata {
 	md := &Metadata{
 		metadataMu:              &sync.RWMutex{},
 		Metrics:         false,
+		PanicRecovery:   true,
 		ShutdownTimeout: 10 * time.Second,
 		UpdateInterval:  UpdateInterval,
 	}
@@ -22,7 +23,8 @@ func (GM *GlobalManager) NewMetadata() *Metadata {
 	return md
 }
 
-// Right now our codebase not respecting the max routines limit, will set it
+// SetMaxRoutines caps the number of routines running across the whole tree, 0 disables the cap.
+// Spawns over the cap fail with ErrMaxRoutinesExceeded.
 func (MD *Metadata) SetMaxRoutines(maxroutines int) *Metadata {
 	// Lock and update
 	MD.metadataMu.Lock()
@@ -31,6 +33,24 @@ func (MD *Metadata) SetMaxRoutines(maxroutines int) *Metadata {
 	return MD
 }
 
+// SetMaxApps caps the number of app managers the global manager accepts, 0 disables the cap
+func (MD *Metadata) SetMaxApps(maxApps int) *Metadata {
+	// Lock and update
+	MD.metadataMu.Lock()
+	defer MD.metadataMu.Unlock()
+	MD.MaxApps = maxApps
+	return MD
+}
+
+// SetMaxLocalsPerApp caps the number of local managers each app manager accepts, 0 disables the cap
+func (MD *Metadata) SetMaxLocalsPerApp(maxLocals int) *Metadata {
+	// Lock and update
+	MD.metadataMu.Lock()
+	defer MD.metadataMu.Unlock()
+	MD.MaxLocalsPerApp = maxLocals
+	return MD
+}
+
 func (MD *Metadata) SetShutdownTimeout(timeout time.Duration) *Metadata {
 	// Lock and update
 	MD.metadataMu.Lock()
@@ -103,4 +123,46 @@ func (MD *Metadata) GetMetricsURL() string {
     MD.metadataMu.RLock()
     defer MD.metadataMu.RUnlock()
     return MD.MetricsURL
-}
\ No newline at end of file
+}
+
+// SetLockInstrumentation records whether manager mutex acquisition is being timed
+func (MD *Metadata) SetLockInstrumentation(enabled bool) *Metadata {
+	// Lock and update
+	MD.metadataMu.Lock()
+	defer MD.metadataMu.Unlock()
+	MD.LockInstrumentation = enabled
+	return MD
+}
+
+func (MD *Metadata) GetLockInstrumentation() bool {
+	MD.metadataMu.RLock()
+	defer MD.metadataMu.RUnlock()
+	return MD.LockInstrumentation
+}
+
+// SetPanicRecovery sets whether goroutines recover from panics when WithPanicRecovery isn't passed
+func (MD *Metadata) SetPanicRecovery(enabled bool) *Metadata {
+	// Lock and update
+	MD.metadataMu.Lock()
+	defer MD.metadataMu.Unlock()
+	MD.PanicRecovery = enabled
+	return MD
+}
+
+func (MD *Metadata) GetPanicRecovery() bool {
+	MD.metadataMu.RLock()
+	defer MD.metadataMu.RUnlock()
+	return MD.PanicRecovery
+}
+
+func (MD *Metadata) GetMaxApps() int {
+	MD.metadataMu.RLock()
+	defer MD.metadataMu.RUnlock()
+	return MD.MaxApps
+}
+
+func (MD *Metadata) GetMaxLocalsPerApp() int {
+	MD.metadataMu.RLock()
+	defer MD.metadataMu.RUnlock()
+	return MD.MaxLocalsPerApp
+}
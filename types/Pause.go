@@ -0,0 +1,82 @@
+package types
+
+import "sync"
+
+// pauseGate is the per function gate behind Pause and Resume.
+// resumed is closed while the function runs normally and replaced by an open channel while it is paused.
+type pauseGate struct {
+	mu      sync.Mutex
+	resumed chan struct{}
+}
+
+// closedResumed is handed out for routines without a gate, they are never paused
+var closedResumed = func() chan struct{} {
+	ch := make(chan struct{})
+	close(ch)
+	return ch
+}()
+
+// functionPauseGate returns the pause gate of a function, creating it on first use
+func (LM *LocalManager) functionPauseGate(functionName string) *pauseGate {
+	if gate, ok := LM.pauses.Load(functionName); ok {
+		return gate.(*pauseGate)
+	}
+	gate, _ := LM.pauses.LoadOrStore(functionName, &pauseGate{resumed: closedResumed})
+	return gate.(*pauseGate)
+}
+
+// AttachPauseGate links the routine to its function's pause gate, called once while spawning
+func (LM *LocalManager) AttachPauseGate(routine *Routine) *LocalManager {
+	routine.pause = LM.functionPauseGate(routine.GetFunctionName())
+	return LM
+}
+
+// PauseFunction pauses the routines of a function, including ones spawned while paused.
+// Returns false if the function was already paused.
+func (LM *LocalManager) PauseFunction(functionName string) bool {
+	gate := LM.functionPauseGate(functionName)
+	gate.mu.Lock()
+	defer gate.mu.Unlock()
+	if gate.resumed != closedResumed {
+		return false
+	}
+	gate.resumed = make(chan struct{})
+	return true
+}
+
+// ResumeFunction releases the routines of a paused function. Returns false if it wasn't paused.
+func (LM *LocalManager) ResumeFunction(functionName string) bool {
+	gate := LM.functionPauseGate(functionName)
+	gate.mu.Lock()
+	defer gate.mu.Unlock()
+	if gate.resumed == closedResumed {
+		return false
+	}
+	close(gate.resumed)
+	gate.resumed = closedResumed
+	return true
+}
+
+// IsFunctionPaused reports whether the function is paused
+func (LM *LocalManager) IsFunctionPaused(functionName string) bool {
+	gate, ok := LM.pauses.Load(functionName)
+	if !ok {
+		return false
+	}
+	return gate.(*pauseGate).current() != closedResumed
+}
+
+func (g *pauseGate) current() chan struct{} {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.resumed
+}
+
+// Resumed returns a channel that is closed while the routine's function isn't paused.
+// Receiving from it blocks for as long as the function is paused. A nil routine is never paused.
+func (r *Routine) Resumed() <-chan struct{} {
+	if r == nil || r.pause == nil {
+		return closedResumed
+	}
+	return r.pause.current()
+}
@@ -0,0 +1,119 @@
+package types
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/neerajchowdary889/GoRoutinesManager/Manager/Errors"
+)
+
+// RateLimiter is a token bucket refilled at perSecond tokens a second, holding up to burst tokens.
+// Waiters reserve their token up front, so they are served in arrival order at the configured rate.
+type RateLimiter struct {
+	mu        sync.Mutex
+	perSecond float64
+	burst     float64
+	tokens    float64 // goes negative while waiters have reserved tokens not refilled yet
+	last      time.Time
+}
+
+// NewRateLimiter returns a limiter allowing perSecond events a second with bursts of burst, starting full
+func NewRateLimiter(perSecond float64, burst int) (*RateLimiter, error) {
+	if perSecond <= 0 || burst < 1 {
+		return nil, fmt.Errorf("%w: %v/s, burst %d", Errors.ErrInvalidRateLimit, perSecond, burst)
+	}
+	return &RateLimiter{perSecond: perSecond, burst: float64(burst), tokens: float64(burst), last: time.Now()}, nil
+}
+
+// Wait blocks until a token is available or ctx is done, in which case the token is given back and ctx.Err() returned
+func (r *RateLimiter) Wait(ctx context.Context) error {
+	r.mu.Lock()
+	now := time.Now()
+	r.tokens = min(r.burst, r.tokens+now.Sub(r.last).Seconds()*r.perSecond)
+	r.last = now
+	r.tokens--
+	if r.tokens >= 0 {
+		r.mu.Unlock()
+		return nil
+	}
+	delay := time.Duration(-r.tokens / r.perSecond * float64(time.Second))
+	r.mu.Unlock()
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		r.mu.Lock()
+		r.tokens++
+		r.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// Allow takes a token if one is available right now, without blocking and without jumping ahead of waiters
+func (r *RateLimiter) Allow() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	r.tokens = min(r.burst, r.tokens+now.Sub(r.last).Seconds()*r.perSecond)
+	r.last = now
+	if r.tokens < 1 {
+		return false
+	}
+	r.tokens--
+	return true
+}
+
+// Return gives back a token taken for an event that didn't happen after all
+func (r *RateLimiter) Return() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.tokens = min(r.burst, r.tokens+1)
+}
+
+// rateLimit returns the limiter of the routines spawned under name, created with the given rate on first use.
+// Later calls get the existing limiter whatever rate they pass.
+func (LM *LocalManager) rateLimit(name string, perSecond float64, burst int) (*RateLimiter, error) {
+	if existing, ok := LM.rateLimits.Load(name); ok {
+		return existing.(*RateLimiter), nil
+	}
+	limiter, err := NewRateLimiter(perSecond, burst)
+	if err != nil {
+		return nil, err
+	}
+	shared, _ := LM.rateLimits.LoadOrStore(name, limiter)
+	return shared.(*RateLimiter), nil
+}
+
+// WaitRateLimit waits for a token of the per function rate limit name, or until ctx is done
+func (LM *LocalManager) WaitRateLimit(ctx context.Context, name string, perSecond float64, burst int) error {
+	limiter, err := LM.rateLimit(name, perSecond, burst)
+	if err != nil {
+		return err
+	}
+	return limiter.Wait(ctx)
+}
+
+// TryRateLimit takes a token of the per function rate limit name if one is available right now,
+// it returns Errors.ErrRateLimited instead of waiting
+func (LM *LocalManager) TryRateLimit(name string, perSecond float64, burst int) error {
+	limiter, err := LM.rateLimit(name, perSecond, burst)
+	if err != nil {
+		return err
+	}
+	if !limiter.Allow() {
+		return fmt.Errorf("%w: %s", Errors.ErrRateLimited, name)
+	}
+	return nil
+}
+
+// ReturnRateLimit gives back a token taken with TryRateLimit when the spawn it was taken for didn't start
+func (LM *LocalManager) ReturnRateLimit(name string) {
+	if limiter, ok := LM.rateLimits.Load(name); ok {
+		limiter.(*RateLimiter).Return()
+	}
+}
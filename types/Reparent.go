@@ -0,0 +1,78 @@
+package types
+
+import "context"
+
+// parentLink cancels a routine's context once its current parent context is done
+type parentLink struct {
+	stop   func() bool
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// SpawnLinkedChild creates the context of a routine.
+// Unlike SpawnChild, the context isn't a child of the local context: it carries the local context's values
+// and is cancelled through a link to it, which Routine.Reparent can later swap for another parent.
+// The returned cancel also drops the link so finished routines don't stay registered on the parent.
+func (LM *LocalManager) SpawnLinkedChild(routine *Routine) (context.Context, context.CancelFunc) {
+	LM.lockLocalReadMutex()
+	parent := LM.Ctx
+	LM.unlockLocalReadMutex()
+
+	return linkedChild(context.WithoutCancel(parent), parent, routine)
+}
+
+// SpawnLinkedChildOf creates the context of a routine from a caller supplied base context.
+// The context carries the values of base and is cancelled with it, and like SpawnLinkedChild it is also
+// cancelled through a link to the local context, so shutdown and Routine.Reparent work the same.
+func (LM *LocalManager) SpawnLinkedChildOf(base context.Context, routine *Routine) (context.Context, context.CancelFunc) {
+	LM.lockLocalReadMutex()
+	parent := LM.Ctx
+	LM.unlockLocalReadMutex()
+
+	return linkedChild(base, parent, routine)
+}
+
+// linkedChild derives the routine's context from base and links its cancellation to parent
+func linkedChild(base, parent context.Context, routine *Routine) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(base)
+	routine.linkParent(parent, ctx, cancel)
+	return ctx, func() {
+		cancel()
+		if link := routine.parentLink.Swap(nil); link != nil {
+			link.stop()
+		}
+	}
+}
+
+// linkParent makes cancel fire once parent is done, replacing the routine's previous link.
+// Returns false if the routine's context is already cancelled, it stays unlinked then.
+func (r *Routine) linkParent(parent, ctx context.Context, cancel context.CancelFunc) bool {
+	link := &parentLink{stop: context.AfterFunc(parent, cancel), ctx: ctx, cancel: cancel}
+	if old := r.parentLink.Swap(link); old != nil && !old.stop() {
+		// The old parent fired, its cancel may still be on the way
+		cancel()
+	}
+	// Checked after the swap, a routine finishing concurrently has cancelled ctx before dropping its link
+	if ctx.Err() != nil {
+		if r.parentLink.CompareAndSwap(link, nil) {
+			link.stop()
+		}
+		return false
+	}
+	// AfterFunc runs cancel in its own goroutine for a parent that is already done, cancel right away instead
+	if parent.Err() != nil {
+		cancel()
+	}
+	return true
+}
+
+// Reparent moves the routine's cancellation over to newParent, the routine keeps running.
+// The context keeps the values of the local context it was spawned from, only cancellation follows newParent.
+// Returns false if the routine wasn't spawned with a linked context or its context is already cancelled.
+func (r *Routine) Reparent(newParent context.Context) bool {
+	link := r.parentLink.Load()
+	if link == nil {
+		return false
+	}
+	return r.linkParent(newParent, link.ctx, link.cancel)
+}
@@ -0,0 +1,78 @@
+package types
+
+import (
+	"sync"
+	"time"
+)
+
+// RequestTracker counts the units of work a long-lived worker has in flight.
+// Shutdown waits for the count to drop to zero before cancelling the worker's context.
+type RequestTracker struct {
+	mu       sync.Mutex
+	inFlight int
+	idle     chan struct{} // closed while nothing is in flight
+}
+
+// NewRequestTracker returns an idle tracker
+func NewRequestTracker() *RequestTracker {
+	idle := make(chan struct{})
+	close(idle)
+	return &RequestTracker{idle: idle}
+}
+
+// Begin marks the start of a request
+func (t *RequestTracker) Begin() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.inFlight == 0 {
+		t.idle = make(chan struct{})
+	}
+	t.inFlight++
+}
+
+// End marks the end of a request, unbalanced calls are ignored
+func (t *RequestTracker) End() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.inFlight == 0 {
+		return
+	}
+	t.inFlight--
+	if t.inFlight == 0 {
+		close(t.idle)
+	}
+}
+
+// InFlight returns the number of requests that began and haven't ended yet
+func (t *RequestTracker) InFlight() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.inFlight
+}
+
+// WaitIdle blocks until no request is in flight or the timeout passes, reporting whether it drained
+func (t *RequestTracker) WaitIdle(timeout time.Duration) bool {
+	t.mu.Lock()
+	idle := t.idle
+	t.mu.Unlock()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-idle:
+		return true
+	case <-timer.C:
+		return false
+	}
+}
+
+// SetRequestTracker attaches the tracker shutdown waits on before cancelling the routine
+func (r *Routine) SetRequestTracker(tracker *RequestTracker) *Routine {
+	r.requests.Store(tracker)
+	return r
+}
+
+// GetRequestTracker returns the routine's request tracker, nil if it wasn't spawned with one
+func (r *Routine) GetRequestTracker() *RequestTracker {
+	return r.requests.Load()
+}
@@ -0,0 +1,45 @@
+package types
+
+import "time"
+
+// RestartMode tells when a supervised worker is run again after it returns
+type RestartMode int
+
+const (
+	RestartNever     RestartMode = iota // the routine finishes with its worker, the default
+	RestartOnFailure                    // run again after an error or a recovered panic
+	RestartAlways                       // run again whatever the worker returned
+)
+
+// String returns the name of the restart mode
+func (m RestartMode) String() string {
+	switch m {
+	case RestartNever:
+		return "never"
+	case RestartOnFailure:
+		return "on_failure"
+	case RestartAlways:
+		return "always"
+	default:
+		return "unknown"
+	}
+}
+
+// RestartPolicy configures how a worker spawned WithRestart is supervised.
+// Restarts stop once MaxRestarts is reached or the routine's context is cancelled.
+type RestartPolicy struct {
+	Mode        RestartMode
+	MaxRestarts int           // restarts allowed, 0 means no limit
+	ResetWindow time.Duration // a run lasting at least this long gives back the full MaxRestarts, 0 means never
+	Delay       time.Duration // wait before each restart
+}
+
+// IncrementRestarts counts a restart of the routine's worker and returns the new count
+func (r *Routine) IncrementRestarts() int {
+	return int(r.restarts.Add(1))
+}
+
+// GetRestartCount returns how many times the routine's worker was restarted
+func (r *Routine) GetRestartCount() int {
+	return int(r.restarts.Load())
+}
@@ -0,0 +1,63 @@
+package types
+
+// completedRoutines remembers the most recently completed routines of a local manager after they
+// are untracked, so lookups can tell a finished routine from one that never existed.
+// It is a fixed size ring, the oldest entry is evicted once it is full.
+// Calls are made under the local manager's lock.
+type completedRoutines struct {
+	order    []string // ring of routine IDs in completion order
+	next     int      // slot the next completed routine is written to
+	routines map[string]*Routine
+}
+
+func newCompletedRoutines(size int) *completedRoutines {
+	return &completedRoutines{
+		order:    make([]string, size),
+		routines: make(map[string]*Routine, size),
+	}
+}
+
+// add retains the routine, evicting the oldest one if the ring is full
+func (c *completedRoutines) add(routine *Routine) {
+	if evicted := c.order[c.next]; evicted != "" {
+		delete(c.routines, evicted)
+	}
+	c.order[c.next] = routine.ID
+	c.routines[routine.ID] = routine
+	c.next = (c.next + 1) % len(c.order)
+}
+
+func (c *completedRoutines) get(routineID string) (*Routine, bool) {
+	routine, ok := c.routines[routineID]
+	return routine, ok
+}
+
+func (c *completedRoutines) count() int {
+	return len(c.routines)
+}
+
+// retainCompleted keeps a routine that finished on its own, must be called with the write lock held
+func (LM *LocalManager) retainCompleted(routine *Routine) {
+	if CompletedRetention <= 0 {
+		return
+	}
+	if LM.completed == nil {
+		LM.completed = newCompletedRoutines(CompletedRetention)
+	}
+	LM.completed.add(routine)
+}
+
+// GetCompletedRoutine returns a routine that completed and is still in the retention buffer
+func (LM *LocalManager) GetCompletedRoutine(routineID string) (*Routine, bool) {
+	LM.lockLocalReadMutex()
+	defer LM.unlockLocalReadMutex()
+	return LM.getCompletedRoutine(routineID)
+}
+
+// getCompletedRoutine is GetCompletedRoutine for callers already holding the lock
+func (LM *LocalManager) getCompletedRoutine(routineID string) (*Routine, bool) {
+	if LM.completed == nil {
+		return nil, false
+	}
+	return LM.completed.get(routineID)
+}
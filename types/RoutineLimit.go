@@ -0,0 +1,47 @@
+package types
+
+import (
+	"fmt"
+
+	"github.com/neerajchowdary889/GoRoutinesManager/Manager/Errors"
+)
+
+// ReserveRoutineSlot admits one more running routine against Metadata.MaxRoutines, 0 meaning unlimited.
+// The slot is counted on the global manager the local manager was created under, so the check holds
+// across every app and two concurrent spawns can't both take the last slot.
+// While spawns wait in the spawn queue new ones are rejected too, so they can't overtake the queue.
+func (LM *LocalManager) ReserveRoutineSlot() error {
+	global := LM.global
+	if global == nil {
+		return nil
+	}
+	if global.spawnQueue.waiting.Load() > 0 {
+		return fmt.Errorf("%w: spawns are queued", Errors.ErrMaxRoutinesExceeded)
+	}
+	return global.reserveRoutineSlot()
+}
+
+// reserveRoutineSlot takes a slot against Metadata.MaxRoutines, regardless of the spawn queue
+func (GM *GlobalManager) reserveRoutineSlot() error {
+	maxRoutines := 0
+	if md := GM.GetMetadata(); md != nil {
+		maxRoutines = md.GetMaxRoutines()
+	}
+	for {
+		running := GM.running.Load()
+		if maxRoutines > 0 && running >= int64(maxRoutines) {
+			return fmt.Errorf("%w: limit %d", Errors.ErrMaxRoutinesExceeded, maxRoutines)
+		}
+		if GM.running.CompareAndSwap(running, running+1) {
+			return nil
+		}
+	}
+}
+
+// ReleaseRoutineSlot returns a slot taken with ReserveRoutineSlot, handing it to the spawn queue if spawns wait in it
+func (LM *LocalManager) ReleaseRoutineSlot() {
+	if LM.global != nil {
+		LM.global.running.Add(-1)
+		LM.global.spawnQueue.notify()
+	}
+}
@@ -0,0 +1,22 @@
+package types
+
+// RoutineResult is what a worker spawned with GoWithResult returned
+type RoutineResult struct {
+	Value any
+	Err   error
+}
+
+// SetResult stores the worker's result, it is written before the done channel is closed
+func (r *Routine) SetResult(value any, err error) *Routine {
+	r.result.Store(&RoutineResult{Value: value, Err: err})
+	return r
+}
+
+// GetResult returns the worker's result, false until it was stored
+func (r *Routine) GetResult() (RoutineResult, bool) {
+	result := r.result.Load()
+	if result == nil {
+		return RoutineResult{}, false
+	}
+	return *result, true
+}
@@ -0,0 +1,78 @@
+package types
+
+// RoutineState is the lifecycle stage of a tracked routine
+type RoutineState int32
+
+const (
+	// RoutineStateStarting: tracked, the goroutine hasn't begun running the worker yet
+	RoutineStateStarting RoutineState = iota
+	// RoutineStateRunning: the worker is running with a live context
+	RoutineStateRunning
+	// RoutineStateCancelling: the worker is still running but its context was cancelled or timed out
+	RoutineStateCancelling
+	// RoutineStateCompleted: the worker returned, the routine is about to be untracked
+	RoutineStateCompleted
+)
+
+// RoutineStates lists every state, in lifecycle order
+var RoutineStates = []RoutineState{
+	RoutineStateStarting,
+	RoutineStateRunning,
+	RoutineStateCancelling,
+	RoutineStateCompleted,
+}
+
+func (s RoutineState) String() string {
+	switch s {
+	case RoutineStateStarting:
+		return "starting"
+	case RoutineStateRunning:
+		return "running"
+	case RoutineStateCancelling:
+		return "cancelling"
+	case RoutineStateCompleted:
+		return "completed"
+	default:
+		return "unknown"
+	}
+}
+
+// SetState records the stage the routine moved to
+func (r *Routine) SetState(state RoutineState) *Routine {
+	r.state.Store(int32(state))
+	return r
+}
+
+// GetState returns the routine's current stage.
+// Cancelling isn't stored, a running routine whose context is done is reported as cancelling.
+func (r *Routine) GetState() RoutineState {
+	state := RoutineState(r.state.Load())
+	if state == RoutineStateRunning && r.Ctx != nil && r.Ctx.Err() != nil {
+		return RoutineStateCancelling
+	}
+	return state
+}
+
+// GetStateCounts counts the routines of the whole tree by state.
+// Completed includes the routines still in the retention buffers.
+// Every state is present in the result, with 0 if no routine is in it.
+func (GM *GlobalManager) GetStateCounts() map[RoutineState]int {
+	counts := make(map[RoutineState]int, len(RoutineStates))
+	for _, state := range RoutineStates {
+		counts[state] = 0
+	}
+	for _, appMgr := range GM.GetAppManagers() {
+		for _, localMgr := range appMgr.GetLocalManagers() {
+			localMgr.lockLocalReadMutex()
+			localMgr.Routines.Range(func(routine *Routine) bool {
+				counts[routine.GetState()]++
+				return true
+			})
+			if localMgr.completed != nil {
+				counts[RoutineStateCompleted] += localMgr.completed.count()
+			}
+			localMgr.unlockLocalReadMutex()
+		}
+	}
+	return counts
+}
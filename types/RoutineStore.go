@@ -0,0 +1,87 @@
+package types
+
+import "sync"
+
+// RoutineStore holds the routines tracked by a local manager.
+// The default is an in-memory map; sharded, off-heap or instrumented implementations can be
+// plugged in per local manager with SetRoutineStore, or for every new local manager with
+// SetRoutineStoreFactory.
+//
+// Calls are made under the local manager's lock, so implementations don't need their own
+// synchronization unless they are shared between local managers.
+type RoutineStore interface {
+	// Add stores the routine under its ID, replacing any routine with the same ID
+	Add(routine *Routine)
+	// Remove deletes the routine with the given ID, reporting whether it was present
+	Remove(routineID string) bool
+	// Get returns the routine with the given ID
+	Get(routineID string) (*Routine, bool)
+	// Range calls fn for every routine until fn returns false
+	Range(fn func(routine *Routine) bool)
+	// Count returns the number of stored routines
+	Count() int
+}
+
+// MapRoutineStore is the default RoutineStore backed by a plain map
+type MapRoutineStore struct {
+	routines map[string]*Routine
+}
+
+// NewMapRoutineStore returns an empty map-backed routine store
+func NewMapRoutineStore() *MapRoutineStore {
+	return &MapRoutineStore{routines: make(map[string]*Routine)}
+}
+
+func (s *MapRoutineStore) Add(routine *Routine) {
+	s.routines[routine.ID] = routine
+}
+
+func (s *MapRoutineStore) Remove(routineID string) bool {
+	if _, ok := s.routines[routineID]; !ok {
+		return false
+	}
+	delete(s.routines, routineID)
+	return true
+}
+
+func (s *MapRoutineStore) Get(routineID string) (*Routine, bool) {
+	routine, ok := s.routines[routineID]
+	return routine, ok
+}
+
+func (s *MapRoutineStore) Range(fn func(routine *Routine) bool) {
+	for _, routine := range s.routines {
+		if !fn(routine) {
+			return
+		}
+	}
+}
+
+func (s *MapRoutineStore) Count() int {
+	return len(s.routines)
+}
+
+var (
+	routineStoreFactory   func() RoutineStore
+	routineStoreFactoryMu sync.RWMutex
+)
+
+// SetRoutineStoreFactory sets the constructor used for the routine store of local managers created from now on.
+// Passing nil restores the default map-backed store. Existing local managers keep their store.
+func SetRoutineStoreFactory(factory func() RoutineStore) {
+	routineStoreFactoryMu.Lock()
+	defer routineStoreFactoryMu.Unlock()
+	routineStoreFactory = factory
+}
+
+// newRoutineStore builds the store for a new local manager
+func newRoutineStore() RoutineStore {
+	routineStoreFactoryMu.RLock()
+	factory := routineStoreFactory
+	routineStoreFactoryMu.RUnlock()
+
+	if factory == nil {
+		return NewMapRoutineStore()
+	}
+	return factory()
+}
@@ -0,0 +1,164 @@
+package types
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/neerajchowdary889/GoRoutinesManager/Manager/Errors"
+)
+
+// Semaphore is a named concurrency gate shared by workers of every app in the process.
+// Slots are taken on behalf of a group, usually a function. Under contention a freed slot goes to the
+// waiting group holding the fewest slots for its weight, so groups share the slots in proportion to
+// their weights instead of first come first served. Waiters of the same group are served in arrival order.
+type Semaphore struct {
+	Name    string
+	size    int
+	mu      sync.Mutex
+	inUse   int
+	held    map[string]int // slots held per group
+	waiters []*semaphoreWaiter
+}
+
+// semaphoreWaiter is a blocked AcquireFor call
+type semaphoreWaiter struct {
+	group  string
+	weight int
+	ready  chan struct{} // closed once a slot was handed over to the waiter
+}
+
+func newSemaphore(name string, n int) *Semaphore {
+	return &Semaphore{Name: name, size: n, held: make(map[string]int)}
+}
+
+// Acquire blocks until a slot is free or ctx is done, in which case ctx.Err() is returned
+func (s *Semaphore) Acquire(ctx context.Context) error {
+	return s.AcquireFor(ctx, "", 1)
+}
+
+// AcquireFor is Acquire on behalf of group, weighted by weight when slots are contended.
+// A weight below 1 counts as 1. The slot must be given back with ReleaseFor and the same group.
+func (s *Semaphore) AcquireFor(ctx context.Context, group string, weight int) error {
+	s.mu.Lock()
+	if s.inUse < s.size && len(s.waiters) == 0 {
+		s.take(group)
+		s.mu.Unlock()
+		return nil
+	}
+	waiter := &semaphoreWaiter{group: group, weight: max(weight, 1), ready: make(chan struct{})}
+	s.waiters = append(s.waiters, waiter)
+	s.mu.Unlock()
+
+	select {
+	case <-waiter.ready:
+		return nil
+	case <-ctx.Done():
+	}
+
+	s.mu.Lock()
+	select {
+	case <-waiter.ready:
+		// The slot was handed over while giving up, pass it on
+		s.release(group)
+	default:
+		for i, w := range s.waiters {
+			if w == waiter {
+				s.waiters = append(s.waiters[:i], s.waiters[i+1:]...)
+				break
+			}
+		}
+	}
+	s.mu.Unlock()
+	return ctx.Err()
+}
+
+// TryAcquire takes a slot if one is free, without blocking
+func (s *Semaphore) TryAcquire() bool {
+	return s.TryAcquireFor("")
+}
+
+// TryAcquireFor is TryAcquire on behalf of group. It never jumps ahead of blocked waiters.
+func (s *Semaphore) TryAcquireFor(group string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.inUse >= s.size || len(s.waiters) > 0 {
+		return false
+	}
+	s.take(group)
+	return true
+}
+
+// Release frees a slot taken with Acquire
+func (s *Semaphore) Release() {
+	s.ReleaseFor("")
+}
+
+// ReleaseFor frees a slot taken with AcquireFor or TryAcquireFor by group
+func (s *Semaphore) ReleaseFor(group string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.release(group)
+}
+
+// Size returns the number of slots
+func (s *Semaphore) Size() int {
+	return s.size
+}
+
+// InUse returns the number of slots currently held
+func (s *Semaphore) InUse() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.inUse
+}
+
+// take gives a slot to group, must be called with mu held
+func (s *Semaphore) take(group string) {
+	s.inUse++
+	s.held[group]++
+}
+
+// release frees a slot of group and hands the free slots over to the waiters, must be called with mu held
+func (s *Semaphore) release(group string) {
+	s.inUse--
+	if s.held[group]--; s.held[group] <= 0 {
+		delete(s.held, group)
+	}
+	for s.inUse < s.size && len(s.waiters) > 0 {
+		// Pick the group furthest below its weighted share, held/weight compared without division.
+		// Strictly less keeps the earliest waiter on ties.
+		next := 0
+		for i, w := range s.waiters[1:] {
+			best := s.waiters[next]
+			if s.held[w.group]*best.weight < s.held[best.group]*w.weight {
+				next = i + 1
+			}
+		}
+		waiter := s.waiters[next]
+		s.waiters = append(s.waiters[:next], s.waiters[next+1:]...)
+		s.take(waiter.group)
+		close(waiter.ready)
+	}
+}
+
+// NewSemaphore registers a semaphore with n slots under name
+func (GM *GlobalManager) NewSemaphore(name string, n int) (*Semaphore, error) {
+	if n <= 0 {
+		return nil, fmt.Errorf("%w: %d", Errors.ErrInvalidSemaphoreSize, n)
+	}
+	semaphore := newSemaphore(name, n)
+	if _, loaded := GM.semaphores.LoadOrStore(name, semaphore); loaded {
+		return nil, fmt.Errorf("%w: %s", Errors.ErrSemaphoreExists, name)
+	}
+	return semaphore, nil
+}
+
+// GetSemaphore returns the semaphore registered under name
+func (GM *GlobalManager) GetSemaphore(name string) (*Semaphore, error) {
+	semaphore, ok := GM.semaphores.Load(name)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", Errors.ErrSemaphoreNotFound, name)
+	}
+	return semaphore.(*Semaphore), nil
+}
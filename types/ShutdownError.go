@@ -0,0 +1,91 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/neerajchowdary889/GoRoutinesManager/Manager/Errors"
+)
+
+// StuckFunction is a function whose goroutines were still running when a safe shutdown timed out
+type StuckFunction struct {
+	AppName      string
+	LocalName    string
+	FunctionName string
+	Remaining    int // goroutines force cancelled after the timeout
+}
+
+// ShutdownError is returned by a safe shutdown when goroutines didn't stop within the shutdown timeout.
+// Functions lists the offending functions of every local manager the shutdown covered. Unwrap returns one
+// error per function wrapping Errors.ErrShutdownTimedOut, followed by any other error a nested shutdown returned.
+type ShutdownError struct {
+	Functions []StuckFunction
+	others    []error
+}
+
+// NewShutdownError returns the ShutdownError for the given stuck functions
+func NewShutdownError(functions []StuckFunction) *ShutdownError {
+	return &ShutdownError{Functions: functions}
+}
+
+// Remaining returns how many goroutines were force cancelled across all stuck functions
+func (SE *ShutdownError) Remaining() int {
+	remaining := 0
+	for _, function := range SE.Functions {
+		remaining += function.Remaining
+	}
+	return remaining
+}
+
+func (SE *ShutdownError) Error() string {
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "%v: %d goroutines of %d functions", Errors.ErrShutdownTimedOut, SE.Remaining(), len(SE.Functions))
+	for i, function := range SE.Functions {
+		if i == 0 {
+			msg.WriteString(" (")
+		} else {
+			msg.WriteString(", ")
+		}
+		fmt.Fprintf(&msg, "%s/%s/%s: %d", function.AppName, function.LocalName, function.FunctionName, function.Remaining)
+	}
+	if len(SE.Functions) > 0 {
+		msg.WriteString(")")
+	}
+	for _, err := range SE.others {
+		msg.WriteString("; ")
+		msg.WriteString(err.Error())
+	}
+	return msg.String()
+}
+
+func (SE *ShutdownError) Unwrap() []error {
+	errs := make([]error, 0, len(SE.Functions)+len(SE.others))
+	for _, function := range SE.Functions {
+		errs = append(errs, fmt.Errorf("%w: %s/%s/%s, %d goroutines remaining", Errors.ErrShutdownTimedOut,
+			function.AppName, function.LocalName, function.FunctionName, function.Remaining))
+	}
+	return append(errs, SE.others...)
+}
+
+// JoinShutdownErrors merges the errors returned by the nested shutdowns of an app or the global manager.
+// ShutdownErrors are flattened into one listing all their stuck functions, other errors are kept alongside.
+// Returns nil if every error is nil and errors.Join of them if none is a ShutdownError.
+func JoinShutdownErrors(errs ...error) error {
+	joined := &ShutdownError{}
+	for _, err := range errs {
+		var shutdownErr *ShutdownError
+		switch {
+		case err == nil:
+		case errors.As(err, &shutdownErr):
+			joined.Functions = append(joined.Functions, shutdownErr.Functions...)
+			joined.others = append(joined.others, shutdownErr.others...)
+		default:
+			joined.others = append(joined.others, err)
+		}
+	}
+	if len(joined.Functions) == 0 {
+		return errors.Join(joined.others...)
+	}
+	return joined
+}
@@ -0,0 +1,103 @@
+package types
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/neerajchowdary889/GoRoutinesManager/Manager/Errors"
+)
+
+// ShutdownProgress is a snapshot of a shutdown started with ShutdownWithProgress.
+// The counts only move towards completion, the last snapshot has Done set and carries the shutdown error.
+type ShutdownProgress struct {
+	AppsTotal         int
+	AppsRemaining     int
+	LocalsTotal       int
+	LocalsDrained     int
+	RoutinesTotal     int
+	RoutinesCancelled int // routines no longer tracked since the shutdown began
+	Done              bool
+	Err               error
+}
+
+// shutdownProgress collects the stages fired while a progress shutdown runs and streams snapshots
+type shutdownProgress struct {
+	mu       sync.Mutex
+	global   *GlobalManager
+	progress ShutdownProgress
+	events   chan ShutdownProgress
+}
+
+// activeShutdownProgress is the shutdown FireShutdownStage reports to, nil when none is running
+var activeShutdownProgress atomic.Pointer[shutdownProgress]
+
+// BeginShutdownProgress starts streaming the progress of a shutdown of the whole tree.
+// The channel has room for a snapshot per app and local manager, snapshots that don't fit because
+// the reader is slow are dropped, the final one always fits. Call EndShutdownProgress when the shutdown returns.
+func (GM *GlobalManager) BeginShutdownProgress() (<-chan ShutdownProgress, error) {
+	progress := ShutdownProgress{
+		AppsTotal:     GM.GetAppManagerCount(),
+		RoutinesTotal: int(GM.routines.live.Load()),
+	}
+	for _, app := range GM.GetAppManagers() {
+		progress.LocalsTotal += app.GetLocalManagerCount()
+	}
+	progress.AppsRemaining = progress.AppsTotal
+
+	tracker := &shutdownProgress{
+		global:   GM,
+		progress: progress,
+		// The initial and the final snapshot plus one per app and local manager
+		events: make(chan ShutdownProgress, progress.AppsTotal+progress.LocalsTotal+2),
+	}
+	if !activeShutdownProgress.CompareAndSwap(nil, tracker) {
+		return nil, fmt.Errorf("%w: a progress shutdown is already running", Errors.ErrShutdownInProgress)
+	}
+	tracker.events <- progress
+	return tracker.events, nil
+}
+
+// EndShutdownProgress sends the final snapshot with the shutdown error and closes the channel
+func (GM *GlobalManager) EndShutdownProgress(err error) {
+	tracker := activeShutdownProgress.Load()
+	if tracker == nil || tracker.global != GM || !activeShutdownProgress.CompareAndSwap(tracker, nil) {
+		return
+	}
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+	tracker.refreshRoutines()
+	tracker.progress.Done = true
+	tracker.progress.Err = err
+	tracker.events <- tracker.progress
+	close(tracker.events)
+}
+
+// observe counts the local and app managers that finished shutting down
+func (p *shutdownProgress) observe(stage ShutdownStage, app, local, function string) {
+	if stage != ShutdownStageDone || app == "" || function != "" {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if local != "" {
+		if p.progress.LocalsDrained < p.progress.LocalsTotal {
+			p.progress.LocalsDrained++
+		}
+	} else if p.progress.AppsRemaining > 0 {
+		p.progress.AppsRemaining--
+	}
+	p.refreshRoutines()
+	// Keep the last slot for the final snapshot
+	if len(p.events) < cap(p.events)-1 {
+		p.events <- p.progress
+	}
+}
+
+// refreshRoutines updates the cancelled count from the live routines, never moving it backwards
+func (p *shutdownProgress) refreshRoutines() {
+	cancelled := p.progress.RoutinesTotal - int(p.global.routines.live.Load())
+	if cancelled > p.progress.RoutinesCancelled {
+		p.progress.RoutinesCancelled = cancelled
+	}
+}
@@ -0,0 +1,84 @@
+package types
+
+import (
+	"sync/atomic"
+
+	"github.com/neerajchowdary889/GoRoutinesManager/Logging"
+)
+
+// ShutdownStage is a step of a shutdown, reported to the hook registered with OnShutdownStage.
+//
+// Every shutdown scope (global, app, local or a single function) reports its stages in this order:
+//
+//	ShutdownStageBeginDrain -> ShutdownStageGracefulComplete -> ShutdownStageDone
+//	ShutdownStageBeginDrain -> ShutdownStageCancel -> ShutdownStageForceRemove -> ShutdownStageDone
+//
+// The first line is a drain that finished in time, the second one a drain that timed out or an unsafe
+// shutdown. A function shutdown cancels its routines up front, so a function reports ShutdownStageCancel
+// right after ShutdownStageBeginDrain and then either ShutdownStageGracefulComplete or ShutdownStageForceRemove.
+// Global and app scopes only report ShutdownStageBeginDrain and ShutdownStageDone around their children.
+type ShutdownStage int
+
+const (
+	ShutdownStageBeginDrain       ShutdownStage = iota // the scope started shutting down
+	ShutdownStageGracefulComplete                      // every routine of the scope finished within the timeout
+	ShutdownStageCancel                                // routine contexts of the scope are being cancelled
+	ShutdownStageForceRemove                           // routines still running were removed from tracking
+	ShutdownStageDone                                  // the scope finished shutting down
+)
+
+func (s ShutdownStage) String() string {
+	switch s {
+	case ShutdownStageBeginDrain:
+		return "begin-drain"
+	case ShutdownStageGracefulComplete:
+		return "graceful-complete"
+	case ShutdownStageCancel:
+		return "cancel"
+	case ShutdownStageForceRemove:
+		return "force-remove"
+	case ShutdownStageDone:
+		return "done"
+	default:
+		return "unknown"
+	}
+}
+
+// ShutdownStageHook receives shutdown stage transitions.
+// app, local and function are empty above the scope they describe: a global stage has all three empty,
+// a local stage has an empty function.
+type ShutdownStageHook func(stage ShutdownStage, app, local, function string)
+
+var shutdownStageHook atomic.Pointer[ShutdownStageHook]
+
+// OnShutdownStage registers the hook called at every shutdown stage transition, replacing any previous one.
+// Pass nil to remove it. The hook is called synchronously from the shutting down goroutine, and app
+// shutdowns run their local managers in parallel, so it has to be safe for concurrent use.
+func OnShutdownStage(hook ShutdownStageHook) {
+	if hook == nil {
+		shutdownStageHook.Store(nil)
+		return
+	}
+	shutdownStageHook.Store(&hook)
+}
+
+// FireShutdownStage reports a stage transition to the registered hook, if any.
+// A panicking hook is recovered so it can't abort the shutdown.
+func FireShutdownStage(stage ShutdownStage, app, local, function string) {
+	if stage == ShutdownStageBeginDrain {
+		PublishEvent(EventShutdownStarted, app, local, function, "", nil)
+	}
+	if progress := activeShutdownProgress.Load(); progress != nil {
+		progress.observe(stage, app, local, function)
+	}
+	hook := shutdownStageHook.Load()
+	if hook == nil {
+		return
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			Logging.Error("shutdown stage hook panicked", "stage", stage, "app", app, "local", local, "function", function, "panic", r)
+		}
+	}()
+	(*hook)(stage, app, local, function)
+}
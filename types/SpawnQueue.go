@@ -0,0 +1,177 @@
+package types
+
+import (
+	"container/heap"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/neerajchowdary889/GoRoutinesManager/Manager/Errors"
+)
+
+// spawnQueue holds the spawns waiting for a routine slot once Metadata.MaxRoutines is reached.
+// A dispatcher goroutine, started with the first SetSpawnQueueSize, starts them highest priority first
+// as routine slots are released. Spawns of the same priority start in the order they were queued.
+type spawnQueue struct {
+	mu      sync.Mutex
+	size    int // 0 means spawns over the limit are rejected instead of queued
+	pending pendingSpawns
+	seq     uint64
+	// Spawns queued and not started yet, read without the lock by ReserveRoutineSlot
+	waiting atomic.Int64
+	wake    chan struct{} // buffered 1, poked when a slot may have been freed
+	stop    chan struct{} // closed by StopSpawnQueue
+	done    chan struct{} // closed once the dispatcher returned
+	started bool
+	stopped bool
+}
+
+// pendingSpawn is a spawn waiting in the queue. start is called with a routine slot already reserved,
+// drop if the queue is stopped before it started.
+type pendingSpawn struct {
+	priority int
+	seq      uint64
+	start    func()
+	drop     func(err error)
+}
+
+// pendingSpawns is a max heap on priority, then FIFO on seq
+type pendingSpawns []*pendingSpawn
+
+func (p pendingSpawns) Len() int { return len(p) }
+func (p pendingSpawns) Less(i, j int) bool {
+	if p[i].priority != p[j].priority {
+		return p[i].priority > p[j].priority
+	}
+	return p[i].seq < p[j].seq
+}
+func (p pendingSpawns) Swap(i, j int)       { p[i], p[j] = p[j], p[i] }
+func (p *pendingSpawns) Push(x interface{}) { *p = append(*p, x.(*pendingSpawn)) }
+func (p *pendingSpawns) Pop() interface{} {
+	old := *p
+	last := old[len(old)-1]
+	old[len(old)-1] = nil
+	*p = old[:len(old)-1]
+	return last
+}
+
+// SetSpawnQueueSize lets up to n spawns over Metadata.MaxRoutines wait for a slot instead of failing, 0 turns queueing off.
+// Spawns already queued stay queued when the size is lowered. The dispatcher is started on first use.
+func (GM *GlobalManager) SetSpawnQueueSize(n int) error {
+	if n < 0 {
+		return fmt.Errorf("%w: %d", Errors.ErrInvalidSpawnQueueSize, n)
+	}
+	q := &GM.spawnQueue
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.stopped {
+		return Errors.ErrSpawnQueueClosed
+	}
+	q.size = n
+	if n > 0 && !q.started {
+		q.started = true
+		q.wake = make(chan struct{}, 1)
+		q.stop = make(chan struct{})
+		q.done = make(chan struct{})
+		go GM.dispatchSpawns(q)
+	}
+	return nil
+}
+
+// GetSpawnQueueLength returns how many spawns are waiting for a routine slot
+func (GM *GlobalManager) GetSpawnQueueLength() int {
+	return int(GM.spawnQueue.waiting.Load())
+}
+
+// StopSpawnQueue drops the queued spawns and waits for the dispatcher to return. Spawns over the limit
+// are rejected from then on, the queue can't be restarted. Called by the global shutdown.
+func (GM *GlobalManager) StopSpawnQueue() {
+	q := &GM.spawnQueue
+	q.mu.Lock()
+	if q.stopped {
+		q.mu.Unlock()
+		return
+	}
+	q.stopped = true
+	dropped := q.pending
+	q.pending = nil
+	q.waiting.Store(0)
+	started := q.started
+	if started {
+		close(q.stop)
+	}
+	q.mu.Unlock()
+
+	for _, spawn := range dropped {
+		spawn.drop(Errors.ErrSpawnQueueClosed)
+	}
+	if started {
+		<-q.done
+	}
+}
+
+// WakeSpawnQueue makes the dispatcher retry the queued spawns, for when the routine limit was raised
+func (GM *GlobalManager) WakeSpawnQueue() {
+	GM.spawnQueue.notify()
+}
+
+// EnqueueSpawn queues a spawn rejected by ReserveRoutineSlot. start runs on the dispatcher goroutine once a slot
+// was reserved for it, drop if the queue is stopped first. Fails with the limitErr ReserveRoutineSlot returned
+// while queueing is off, and with ErrSpawnQueueFull when the queue is full.
+func (LM *LocalManager) EnqueueSpawn(priority int, limitErr error, start func(), drop func(err error)) error {
+	if LM.global == nil {
+		return limitErr
+	}
+	q := &LM.global.spawnQueue
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.stopped {
+		return Errors.ErrSpawnQueueClosed
+	}
+	if q.size == 0 {
+		return limitErr
+	}
+	if len(q.pending) >= q.size {
+		return fmt.Errorf("%w: %d queued", Errors.ErrSpawnQueueFull, len(q.pending))
+	}
+	q.seq++
+	heap.Push(&q.pending, &pendingSpawn{priority: priority, seq: q.seq, start: start, drop: drop})
+	q.waiting.Add(1)
+	// A slot may have been released between the rejection and now
+	q.notify()
+	return nil
+}
+
+// notify pokes the dispatcher without blocking, a pending poke already covers this one
+func (q *spawnQueue) notify() {
+	if q.waiting.Load() == 0 {
+		return
+	}
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+// dispatchSpawns starts queued spawns while routine slots are available, until StopSpawnQueue
+func (GM *GlobalManager) dispatchSpawns(q *spawnQueue) {
+	defer close(q.done)
+	for {
+		select {
+		case <-q.stop:
+			return
+		case <-q.wake:
+		}
+		for {
+			q.mu.Lock()
+			if q.stopped || len(q.pending) == 0 || GM.reserveRoutineSlot() != nil {
+				q.mu.Unlock()
+				break
+			}
+			next := heap.Pop(&q.pending).(*pendingSpawn)
+			q.waiting.Add(-1)
+			q.mu.Unlock()
+			next.start()
+		}
+	}
+}
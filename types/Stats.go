@@ -0,0 +1,99 @@
+package types
+
+import (
+	"sort"
+	"sync/atomic"
+	"time"
+)
+
+// ManagerStats is a snapshot of one manager level, returned by Stats.
+// Fields that don't apply to a level are left zero.
+type ManagerStats struct {
+	AppManagers    int             // global only
+	LocalManagers  int             // global and app
+	Goroutines     int             // routines tracked right now
+	PeakGoroutines int64           // most routines tracked at once since the manager was created
+	TotalSpawned   int64           // routines spawned since the manager was created
+	Functions      []FunctionStats // local only, sorted by function name
+	Uptime         time.Duration   // since the manager was created
+}
+
+// routineGauge counts the routines of a scope and remembers the most seen at once
+type routineGauge struct {
+	live atomic.Int64
+	peak atomic.Int64
+}
+
+func (g *routineGauge) add(delta int64) {
+	live := g.live.Add(delta)
+	for {
+		peak := g.peak.Load()
+		if live <= peak || g.peak.CompareAndSwap(peak, live) {
+			return
+		}
+	}
+}
+
+// trackRoutines updates the gauges of the local manager and the scopes above it
+func (LM *LocalManager) trackRoutines(delta int64) {
+	LM.routines.add(delta)
+	if LM.app != nil {
+		LM.app.routines.add(delta)
+	}
+	if LM.global != nil {
+		LM.global.routines.add(delta)
+	}
+}
+
+// GetPeakRoutines returns the most routines the local manager tracked at once
+func (LM *LocalManager) GetPeakRoutines() int64 {
+	return LM.routines.peak.Load()
+}
+
+// GetPeakRoutines returns the most routines the app tracked at once across its local managers
+func (AM *AppManager) GetPeakRoutines() int64 {
+	return AM.routines.peak.Load()
+}
+
+// GetPeakRoutines returns the most routines tracked at once across the whole tree
+func (GM *GlobalManager) GetPeakRoutines() int64 {
+	return GM.routines.peak.Load()
+}
+
+// GetUptime returns how long ago the local manager was created
+func (LM *LocalManager) GetUptime() time.Duration {
+	return time.Since(LM.createdAt)
+}
+
+// GetUptime returns how long ago the app manager was created
+func (AM *AppManager) GetUptime() time.Duration {
+	return time.Since(AM.createdAt)
+}
+
+// GetUptime returns how long ago the global manager was created
+func (GM *GlobalManager) GetUptime() time.Duration {
+	return time.Since(GM.createdAt)
+}
+
+// GetAllFunctionStats returns the stats of every function spawned on the local manager, sorted by name
+func (LM *LocalManager) GetAllFunctionStats() []FunctionStats {
+	var stats []FunctionStats
+	LM.functionStats.Range(func(key, _ any) bool {
+		stats = append(stats, LM.GetFunctionStats(key.(string)))
+		return true
+	})
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].FunctionName < stats[j].FunctionName
+	})
+	return stats
+}
+
+// GetTotalSpawned returns how many routines were spawned on the local manager
+func (LM *LocalManager) GetTotalSpawned() int64 {
+	var total int64
+	LM.functionStats.Range(func(_, value any) bool {
+		total += atomic.LoadInt64(&value.(*functionCounters).spawned)
+		return true
+	})
+	return total
+}
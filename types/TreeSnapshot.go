@@ -0,0 +1,81 @@
+package types
+
+import (
+	"sort"
+	"time"
+)
+
+// TreeSnapshot is a point-in-time view of the whole tree, returned by ConsistentSnapshot.
+// Every count was read while the whole tree was locked, so they agree with each other:
+// Goroutines is the sum over the apps and AppManagers the number of entries in Apps.
+type TreeSnapshot struct {
+	AppManagers   int
+	LocalManagers int
+	Goroutines    int
+	Apps          map[string]AppSnapshot
+	TakenAt       time.Time
+}
+
+// AppSnapshot is the part of a TreeSnapshot for one app manager
+type AppSnapshot struct {
+	LocalManagers int
+	Goroutines    int
+	Locals        map[string]int // local name -> routines tracked
+}
+
+// ConsistentSnapshot counts the app managers, local managers and routines of the whole tree at one instant.
+//
+// It takes read locks in the tree's lock order, the one writers like RenameAppManager use:
+// the global mutex, then every app mutex, then every local mutex, each level by ascending name, and releases
+// them all in reverse once counted. Holding the whole tree blocks spawns and completions for the duration,
+// so it is meant for diagnostics, not hot paths. Code holding a lock of the tree must not call it.
+func (GM *GlobalManager) ConsistentSnapshot() TreeSnapshot {
+	GM.LockGlobalReadMutex()
+	defer GM.UnlockGlobalReadMutex()
+
+	apps := make([]*AppManager, 0, len(GM.AppManagers))
+	for _, app := range GM.AppManagers {
+		apps = append(apps, app)
+	}
+	sort.Slice(apps, func(i, j int) bool { return apps[i].AppName < apps[j].AppName })
+	for _, app := range apps {
+		app.LockAppReadMutex()
+		defer app.UnlockAppReadMutex()
+	}
+
+	locals := make([][]*LocalManager, len(apps))
+	for i, app := range apps {
+		locals[i] = make([]*LocalManager, 0, len(app.LocalManagers))
+		for _, local := range app.LocalManagers {
+			locals[i] = append(locals[i], local)
+		}
+		sort.Slice(locals[i], func(a, b int) bool { return locals[i][a].LocalName < locals[i][b].LocalName })
+	}
+	for _, appLocals := range locals {
+		for _, local := range appLocals {
+			local.lockLocalReadMutex()
+			defer local.unlockLocalReadMutex()
+		}
+	}
+
+	snapshot := TreeSnapshot{
+		AppManagers: len(apps),
+		Apps:        make(map[string]AppSnapshot, len(apps)),
+		TakenAt:     time.Now(),
+	}
+	for i, app := range apps {
+		appSnapshot := AppSnapshot{
+			LocalManagers: len(locals[i]),
+			Locals:        make(map[string]int, len(locals[i])),
+		}
+		for _, local := range locals[i] {
+			count := local.Routines.Count()
+			appSnapshot.Locals[local.LocalName] = count
+			appSnapshot.Goroutines += count
+		}
+		snapshot.Apps[app.AppName] = appSnapshot
+		snapshot.LocalManagers += appSnapshot.LocalManagers
+		snapshot.Goroutines += appSnapshot.Goroutines
+	}
+	return snapshot
+}
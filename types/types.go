@@ -3,6 +3,7 @@ package types
 import (
 	"context"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -12,6 +13,11 @@ var (
 	ShutdownTimeout = 10 * time.Second
 	// Default update interval is 5 seconds - can be changed using Metadata
 	UpdateInterval = 5 * time.Second
+	// Number of completed routines each local manager remembers after untracking them, 0 disables it
+	CompletedRetention = 128
+	// Remove a function wait group created on a routine's join once its last pending routine releases it,
+	// set to false to keep them until shutdown. Groups created with NewFunctionWaitGroup are always kept.
+	AutoCleanupFunctionWgs = true
 )
 
 // Singleton pattern to not repeat the same managers again
@@ -27,6 +33,19 @@ type GlobalManager struct {
 	Cancel      context.CancelFunc
 	Wg          *sync.WaitGroup
 	Metadata    *Metadata
+	// Named process-wide semaphores, name -> *Semaphore
+	semaphores sync.Map
+	// Estimated memory of running routines across the tree
+	memory memoryBudget
+	// Routines tracked across the tree and their peak
+	routines  routineGauge
+	createdAt time.Time
+	// Running routines admitted against Metadata.MaxRoutines
+	running atomic.Int64
+	// Reverse index of the tracked routines, routine ID -> owning *LocalManager
+	routineOwners sync.Map
+	// Spawns waiting for a slot once MaxRoutines is reached, see SetSpawnQueueSize
+	spawnQueue spawnQueue
 }
 
 // AppManager manages local-level managers for a specific app/module
@@ -38,21 +57,97 @@ type AppManager struct {
 	Cancel        context.CancelFunc
 	Wg            *sync.WaitGroup
 	ParentCtx     context.Context
+	// Per app opt-out of metrics, the zero value follows the global metrics switch
+	metricsDisabled atomic.Bool
+	// Sequence counters for generated local names, prefix -> *atomic.Int64
+	localSeq sync.Map
+	// Routines tracked across the app's local managers and their peak
+	routines  routineGauge
+	createdAt time.Time
 }
 
 // LocalManager manages goroutines for a specific file/module within an app
 type LocalManager struct {
 	localMu     *sync.RWMutex
 	LocalName   string
-	Routines    map[string]*Routine
+	AppName     string // name of the owning app manager
+	Routines    RoutineStore
 	Ctx         context.Context
 	Cancel      context.CancelFunc
 	Wg          *sync.WaitGroup
 	FunctionWgs map[string]*sync.WaitGroup // Per function name for selective shutdown
+	// Pending counts and drain flags of the function wait groups, same keys as FunctionWgs
+	functionWgStates map[string]*functionWaitGroupState
 	ParentCtx   context.Context
 	// Atomic counter for lock-free reads of routine count
 	// Updated atomically when routines are added/removed
 	routineCount int64 // Use sync/atomic for operations
+	// Per function accumulators, functionName -> *functionCounters
+	functionStats sync.Map
+	// Per function error rate windows, functionName -> *errorRateMonitor
+	errorRates sync.Map
+	// Recently completed routines, created on the first completion
+	completed *completedRoutines
+	// Estimated memory of the running routines
+	memory memoryBudget
+	// Per function count of routines that began running their worker
+	starts functionStarts
+	// Concurrency limits set WithMaxConcurrency, name -> *Semaphore
+	concurrency sync.Map
+	// Spawn rate limits set WithRateLimit, name -> *RateLimiter
+	rateLimits sync.Map
+	// Semaphore weights set with SetFunctionWeight, functionName -> int
+	weights sync.Map
+	// Set by Drain, spawns are rejected with ErrDraining while in-flight routines keep running
+	draining atomic.Bool
+	// Set while the local holds a reference on its app's context, see AcquireAppContext
+	appContextRef atomic.Bool
+	// Pause gates of the functions, functionName -> *pauseGate
+	pauses sync.Map
+	// Routines tracked and their peak, also counted in the owning app and global manager
+	routines  routineGauge
+	app       *AppManager
+	global    *GlobalManager
+	createdAt time.Time
+}
+
+// FunctionStats is an aggregate view of all routines spawned for a function in a local manager.
+// Completed, Failed and Panicked are disjoint: a finished routine counts in exactly one of them.
+type FunctionStats struct {
+	FunctionName    string
+	Live            int64
+	Spawned         int64
+	Completed       int64 // returned nil
+	Failed          int64 // returned an error classified as a failure
+	Panicked        int64
+	Stopped         int64 // returned an expected stop, e.g. context.Canceled during shutdown
+	AverageDuration time.Duration // over finished routines
+}
+
+// ReconnectConfig configures the backoff between connection attempts of a reconnecting worker.
+// Zero values fall back to the defaults: 100ms initial backoff, 30s max backoff, multiplier 2.
+type ReconnectConfig struct {
+	InitialBackoff time.Duration // wait after the first failure
+	MaxBackoff     time.Duration // upper bound for the wait between attempts
+	Multiplier     float64       // growth factor applied after each consecutive failure
+}
+
+// ScheduleConfig configures a periodic worker spawned with GoEvery
+type ScheduleConfig struct {
+	Interval time.Duration // time between runs, must be positive
+	// RunImmediately runs the first iteration synchronously inside GoEvery, before the first tick.
+	// Its error is returned from GoEvery and the schedule isn't started.
+	RunImmediately bool
+}
+
+// functionCounters holds the raw accumulators behind FunctionStats, updated with sync/atomic
+type functionCounters struct {
+	spawned       int64
+	completed     int64
+	failed        int64
+	panicked      int64
+	stopped       int64
+	totalDuration int64 // nanoseconds
 }
 
 // Routine represents a tracked goroutine
@@ -62,12 +157,52 @@ type Routine struct {
 	Ctx          context.Context
 	Cancel       context.CancelFunc
 	Done         <-chan struct{}
-	StartedAt    int64 // Unix timestamp or monotonic time
+	StartedAt    int64  // Unix timestamp or monotonic time
+	SpawnSite    string // file:line of the Go call, only captured when leak detection is enabled
+	leaked       atomic.Bool
+	// Context misuse diagnostic, only updated while DetectIgnoredContexts is on
+	ctxObserved atomic.Bool
+	ignoringCtx atomic.Bool
+	state        atomic.Int32 // RoutineState, read through GetState
+	// Unix nano deadline announced to the worker when shutdown begins, 0 while running normally
+	shutdownDeadline atomic.Int64
+	// In-flight request counter shutdown drains before cancelling, nil unless spawned WithRequestTracker
+	requests atomic.Pointer[RequestTracker]
+	// Lines logged with Local.Logf, nil unless spawned WithLogBuffer
+	logs atomic.Pointer[LogBuffer]
+	// Value and error of a worker spawned with GoWithResult, nil until it returns
+	result atomic.Pointer[RoutineResult]
+	// Times the worker was run again under a restart policy
+	restarts atomic.Int32
+	// Key/value tags set WithLabels, nil unless the routine was spawned with labels
+	labels atomic.Pointer[map[string]string]
+	// Pause gate of the routine's function, set while spawning
+	pause *pauseGate
+	// Wait groups the routine holds a slot in, released exactly once by whoever gets there first:
+	// the routine completing or a shutdown force-removing it
+	waitGroups   []*sync.WaitGroup
+	wgsReleased  atomic.Bool
+	// Function wait group the routine is pending in, nil unless spawned with AddToWaitGroup
+	functionWg   *functionWaitGroupState
+	// Link cancelling the routine when its parent context is done, swapped by Reparent
+	parentLink atomic.Pointer[parentLink]
+}
+
+// RoutineRef references a routine together with the managers that own it.
+// It is used by app-wide lookups where the local manager is not known up front.
+type RoutineRef struct {
+	AppName   string
+	LocalName string
+	Routine   *Routine
 }
 
 type Metadata struct {
 	metadataMu *sync.RWMutex
 	MaxRoutines     int
+	MaxApps         int // 0 means unlimited
+	MaxLocalsPerApp int // 0 means unlimited
+	LockInstrumentation bool // time manager mutex acquisition
+	PanicRecovery   bool // default for goroutines spawned without WithPanicRecovery
 	Metrics         bool
 	MetricsURL      string
 	UpdateInterval  time.Duration